@@ -0,0 +1,133 @@
+// cmd/apikeys/main.go
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"currency-service/internal/config"
+	"currency-service/internal/database"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+	"currency-service/internal/service"
+
+	_ "github.com/lib/pq" // DB driver
+)
+
+const usage = `Управление API-ключами.
+
+Использование:
+  apikeys issue -name <имя> -scopes <право,право,...>   выпустить ключ (значение печатается один раз)
+  apikeys list                                          список ключей
+  apikeys revoke -id <id>                               отозвать ключ
+
+Права: rates:write, wallets:read, wallets:write, admin.
+Первый ключ с правом admin выпускается этой командой, дальше ключами можно управлять через /api/v1/admin/api-keys.
+`
+
+// Управление API-ключами напрямую через БД, в том числе выпуск первого административного ключа:
+//
+//	go run ./cmd/apikeys issue -name ops -scopes admin
+func main() {
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.LoadConfig()
+	db, err := database.NewPostgresConnection(cfg.DB)
+	if err != nil {
+		log.Printf("Не удалось подключиться к базе данных: %v", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+	if err := database.MigrateSchema(db); err != nil {
+		log.Printf("Не удалось применить миграции схемы: %v", err)
+		os.Exit(1)
+	}
+
+	apiKeySvc := service.NewAPIKeyService(repository.NewPostgresAPIKeyRepository(), db)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	command, args := os.Args[1], os.Args[2:]
+	switch command {
+	case "issue":
+		err = issue(ctx, apiKeySvc, args)
+	case "list":
+		err = list(ctx, apiKeySvc)
+	case "revoke":
+		err = revoke(ctx, apiKeySvc, args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		err = fmt.Errorf("неизвестная команда %q", command)
+	}
+	if err != nil {
+		log.Printf("Ошибка: %v", err)
+		db.Close()
+		os.Exit(1)
+	}
+}
+
+func issue(ctx context.Context, svc service.APIKeyService, args []string) error {
+	flags := flag.NewFlagSet("issue", flag.ExitOnError)
+	name := flags.String("name", "", "назначение ключа (например, имя сервиса-клиента)")
+	scopes := flags.String("scopes", "", "права через запятую")
+	flags.Parse(args)
+
+	req := models.IssueAPIKeyRequest{Name: *name}
+	for _, scope := range strings.Split(*scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			req.Scopes = append(req.Scopes, scope)
+		}
+	}
+	issued, err := svc.IssueAPIKey(ctx, req)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Ключ #%d (%s), права: %s\n", issued.ID, issued.Name, strings.Join(issued.Scopes, ", "))
+	fmt.Println("Сохраните значение - повторно его показать нельзя:")
+	fmt.Println(issued.Key)
+	return nil
+}
+
+func list(ctx context.Context, svc service.APIKeyService) error {
+	resp, err := svc.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+	for _, key := range resp.Keys {
+		status := "действует"
+		if key.RevokedAt != nil {
+			status = "отозван " + key.RevokedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("#%d\t%s...\t%s\t%s\t%s\n", key.ID, key.Prefix, key.Name, strings.Join(key.Scopes, ","), status)
+	}
+	return nil
+}
+
+func revoke(ctx context.Context, svc service.APIKeyService, args []string) error {
+	flags := flag.NewFlagSet("revoke", flag.ExitOnError)
+	idFlag := flags.String("id", "", "ID ключа")
+	flags.Parse(args)
+
+	id, err := strconv.ParseInt(*idFlag, 10, 64)
+	if err != nil || id <= 0 {
+		return errors.New("укажите ID ключа: -id <id>")
+	}
+	key, err := svc.RevokeAPIKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Ключ #%d (%s) отозван\n", key.ID, key.Name)
+	return nil
+}
@@ -0,0 +1,60 @@
+// cmd/reconcile/main.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"currency-service/internal/config"
+	"currency-service/internal/database"
+	"currency-service/internal/repository"
+	"currency-service/internal/service"
+
+	_ "github.com/lib/pq" // DB driver
+)
+
+// Разовая сверка балансов для аудита.
+// Печатает отчет и завершается с кодом 1, если найдены расхождения, и с кодом 2 при ошибке,
+// поэтому ее удобно запускать из cron или CI:
+//
+//	go run ./cmd/reconcile
+func main() {
+	cfg := config.LoadConfig()
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	db, err := database.NewPostgresConnection(cfg.DB)
+	if err != nil {
+		log.Printf("Не удалось подключиться к базе данных: %v", err)
+		os.Exit(2)
+	}
+	defer db.Close()
+	if err := database.MigrateSchema(db); err != nil {
+		log.Printf("Не удалось применить миграции схемы: %v", err)
+		os.Exit(2)
+	}
+
+	reconciliationSvc := service.NewReconciliationService(repository.NewPostgresReconciliationRepository(), db, cfg.Reconciliation.Tolerance)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	report, err := reconciliationSvc.Reconcile(ctx)
+	if err != nil {
+		log.Printf("Ошибка сверки: %v", err)
+		os.Exit(2)
+	}
+
+	fmt.Printf("Сверка #%d: проверено кошельков - %d, расхождений - %d\n",
+		report.Run.ID, report.Run.WalletsChecked, report.Run.DiscrepanciesFound)
+	for _, d := range report.Discrepancies {
+		fmt.Printf("  %s: баланс %.2f, по движениям %.2f, разница %+.2f\n",
+			d.WalletNumber, d.StoredBalance, d.ComputedBalance, d.Difference)
+	}
+	if len(report.Discrepancies) > 0 {
+		db.Close()
+		os.Exit(1)
+	}
+}
@@ -6,15 +6,21 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"currency-service/internal/auth"
 	"currency-service/internal/config"
 	"currency-service/internal/database"
+	"currency-service/internal/grpcserver"
 	"currency-service/internal/handlers"
+	"currency-service/internal/jobs"
+	"currency-service/internal/models"
+	"currency-service/internal/ratelimit"
 	"currency-service/internal/repository"
 	"currency-service/internal/service"
 
@@ -24,6 +30,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	_ "github.com/lib/pq" // DB driver
+	"google.golang.org/grpc"
 
 	// (!!!) Используем стандартный http-swagger
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -51,6 +58,7 @@ import (
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
+// @description API-ключ ("Bearer csk_...") или JWT пользователя ("Bearer <токен>"). Ключи выпускаются командой apikeys или через /admin/api-keys.
 
 //go:generate swag init -g cmd/server/main.go -o ./docs --parseDependency --parseInternal
 // Обновленная директива go:generate:
@@ -75,13 +83,130 @@ func main() {
 	}
 	log.Println("Миграции схемы успешно применены.")
 
+	isolation, err := service.ParseIsolationLevel(cfg.Tx.Isolation)
+	if err != nil {
+		log.Fatalf("Некорректные настройки транзакций: %v", err)
+	}
+	service.ConfigureTransactions(service.TxSettings{
+		Isolation:   isolation,
+		MaxAttempts: cfg.Tx.MaxAttempts,
+		BaseDelay:   cfg.Tx.RetryBaseDelay,
+		MaxDelay:    cfg.Tx.RetryMaxDelay,
+	})
+
+	jwtSettings := auth.JWTSettings{
+		HS256SecretFile:    cfg.Auth.JWTHS256SecretFile,
+		RS256PublicKeyFile: cfg.Auth.JWTRS256PublicKeyFile,
+		JWKSFile:           cfg.Auth.JWTJWKSFile,
+		Issuer:             cfg.Auth.JWTIssuer,
+		Audience:           cfg.Auth.JWTAudience,
+		Leeway:             cfg.Auth.JWTLeeway,
+	}
+	var jwtVerifier *auth.JWTVerifier
+	if jwtSettings.Enabled() {
+		jwtVerifier, err = auth.NewJWTVerifier(jwtSettings)
+		if err != nil {
+			log.Fatalf("Некорректные настройки JWT: %v", err)
+		}
+		log.Println("Проверка JWT включена")
+	}
+
 	// --- Инициализация слоев (без изменений) ---
 	rateRepo := repository.NewPostgresRateRepository()
 	walletRepo := repository.NewPostgresWalletRepository()
+	userRepo := repository.NewPostgresUserRepository()
+	holdRepo := repository.NewPostgresHoldRepository()
+	movementRepo := repository.NewPostgresMovementRepository()
+	limitRepo := repository.NewPostgresLimitRepository()
+	scheduleRepo := repository.NewPostgresScheduleRepository()
+	reconciliationRepo := repository.NewPostgresReconciliationRepository()
+	feeRepo := repository.NewPostgresFeeRepository()
+	interestRepo := repository.NewPostgresInterestRepository()
+	apiKeyRepo := repository.NewPostgresAPIKeyRepository()
+	rateLimitRepo := repository.NewPostgresRateLimitRepository()
+	conversion := service.ConversionSettings{Pair: cfg.Conversion.Pair, FeeWalletNumber: cfg.Conversion.FeeWalletNumber}
+	if err := conversion.Validate(); err != nil {
+		log.Fatalf("Некорректные настройки конвертации: %v", err)
+	}
 	rateSvc := service.NewRateService(rateRepo, db)
-	walletSvc := service.NewWalletService(walletRepo, rateRepo, db)
+	walletSvc := service.NewWalletService(walletRepo, rateRepo, userRepo, movementRepo, limitRepo, feeRepo, db, cfg.Wallets.ImplicitCreate, conversion)
+	if err := walletSvc.EnsureFeeWallet(context.Background()); err != nil {
+		log.Fatalf("Ошибка подготовки кошелька комиссий: %v", err)
+	}
+	userSvc := service.NewUserService(userRepo, walletRepo, db)
+	limitSvc := service.NewLimitService(limitRepo, walletRepo, db)
+	holdSvc := service.NewHoldService(holdRepo, walletRepo, movementRepo, limitRepo, db, cfg.Holds.DefaultTTL)
+	scheduleSvc := service.NewScheduleService(scheduleRepo, walletSvc, db, cfg.Scheduler.RetryDelay, cfg.Scheduler.MaxFailures)
+	reconciliationSvc := service.NewReconciliationService(reconciliationRepo, db, cfg.Reconciliation.Tolerance)
+	reversalSvc := service.NewReversalService(walletRepo, movementRepo, db)
+	feeSvc := service.NewFeeService(feeRepo, db)
+	interestSvc := service.NewInterestService(interestRepo, walletRepo, movementRepo, db)
+	apiKeySvc := service.NewAPIKeyService(apiKeyRepo, db)
 	rateHandler := handlers.NewRateHandler(rateSvc)
 	walletHandler := handlers.NewWalletHandler(walletSvc)
+	userHandler := handlers.NewUserHandler(userSvc)
+	holdHandler := handlers.NewHoldHandler(holdSvc)
+	limitHandler := handlers.NewLimitHandler(limitSvc)
+	reconciliationHandler := handlers.NewReconciliationHandler(reconciliationSvc)
+	movementHandler := handlers.NewMovementHandler(reversalSvc)
+	feeHandler := handlers.NewFeeHandler(feeSvc)
+	interestHandler := handlers.NewInterestHandler(interestSvc)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeySvc)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleSvc)
+	graphQLHandler, err := handlers.NewGraphQLHandler(rateSvc, walletSvc)
+	if err != nil {
+		log.Fatalf("Ошибка инициализации GraphQL: %v", err)
+	}
+
+	defaultLimit, err := ratelimit.ParseLimit(cfg.RateLimit.Default)
+	if err != nil {
+		log.Fatalf("Некорректные настройки ограничения запросов: %v", err)
+	}
+	ipLimit, err := ratelimit.ParseLimit(cfg.RateLimit.IP)
+	if err != nil {
+		log.Fatalf("Некорректные настройки ограничения запросов: %v", err)
+	}
+	rateLimitRules, err := ratelimit.ParseRules(cfg.RateLimit.Routes)
+	if err != nil {
+		log.Fatalf("Некорректные настройки ограничения запросов: %v", err)
+	}
+	var rateLimitStore ratelimit.Store
+	switch cfg.RateLimit.Store {
+	case "memory":
+		rateLimitStore = ratelimit.NewMemoryStore()
+	case "postgres":
+		rateLimitStore = ratelimit.NewPostgresStore(db, rateLimitRepo)
+	default:
+		log.Fatalf("Некорректное хранилище ограничения запросов %q (ожидается memory или postgres)", cfg.RateLimit.Store)
+	}
+	limiter := ratelimit.NewLimiter(rateLimitStore, defaultLimit, ipLimit, rateLimitRules)
+
+	cacheControlRules, err := handlers.ParseCacheControlRules(cfg.HTTPCache.Routes)
+	if err != nil {
+		log.Fatalf("Некорректные настройки Cache-Control: %v", err)
+	}
+	if err := handlers.ValidateCacheControl(cfg.HTTPCache.Default); err != nil {
+		log.Fatalf("Некорректные настройки Cache-Control: %v", err)
+	}
+
+	// --- Фоновые задачи ---
+	// Останавливаются отменой jobsCtx при graceful shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go jobs.RunPeriodic(jobsCtx, "hold-expiry", cfg.Holds.ReleaseInterval, func(ctx context.Context) error {
+		_, err := holdSvc.ReleaseExpiredHolds(ctx)
+		return err
+	})
+	go jobs.RunPeriodic(jobsCtx, "scheduler", cfg.Scheduler.Interval, func(ctx context.Context) error {
+		_, err := scheduleSvc.RunDueSchedules(ctx)
+		return err
+	})
+	go jobs.RunPeriodic(jobsCtx, "reconciliation", cfg.Reconciliation.Interval, func(ctx context.Context) error {
+		_, err := reconciliationSvc.Reconcile(ctx)
+		return err
+	})
+	go jobs.RunPeriodic(jobsCtx, "interest", cfg.Interest.Interval, interestSvc.RunDailyInterest)
+	go jobs.RunPeriodic(jobsCtx, "rate-limit-cleanup", cfg.RateLimit.CleanupInterval, limiter.Cleanup)
 
 	// --- Настройка роутера (chi) ---
 	r := chi.NewRouter()
@@ -89,7 +214,7 @@ func main() {
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(handlers.Timeout(60 * time.Second)) // Выгрузкам CSV и NDJSON дается больше времени
 
 	// --- (!!!) Маршрут для Swagger UI ---
 	// Используем стандартный httpSwagger.WrapHandler
@@ -100,14 +225,98 @@ func main() {
 
 	// --- Маршруты API v1 (без изменений в логике) ---
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(handlers.RateLimitByIP(limiter)) // До проверки ключа, чтобы перебор ключей тоже ограничивался
+		r.Use(handlers.Authenticate(apiKeySvc, jwtVerifier))
+		r.Use(handlers.RateLimit(limiter))
+		r.Use(handlers.CacheControl(cfg.HTTPCache.Default, cacheControlRules))
 		r.Route("/rates", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes("", models.ScopeRatesWrite))
 			r.Post("/", rateHandler.CreateRate)
+			r.Get("/", rateHandler.ListRates)
 			r.Get("/average", rateHandler.GetAverageRate)
 		})
 		r.Route("/wallets", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeWalletsWrite))
+			// Клиентам доступны только чтение и списания со своих кошельков (владелец проверяется в обработчиках)
 			r.Post("/balance", walletHandler.UpdateBalance)
-			r.Get("/", walletHandler.ListWallets)
 			r.Post("/convert", walletHandler.ConvertAndDeduct)
+			r.Get("/{number}", walletHandler.GetWallet)
+			r.Group(func(r chi.Router) {
+				r.Use(handlers.RejectCustomers)
+				r.Post("/", walletHandler.CreateWallet)
+				r.Get("/", walletHandler.ListWallets)
+				r.Post("/batch", walletHandler.ExecuteBatch)
+				r.Post("/{number}/holds", holdHandler.CreateHold)
+				r.Get("/{number}/limits", limitHandler.GetWalletLimits)
+				r.Get("/{number}/interest", interestHandler.GetWalletInterest)
+				// Индивидуальные лимиты, кредитный лимит, процентный план и статус меняют правила для кошелька
+				// (в том числе позволяют уйти в минус), поэтому их устанавливает только администратор
+				r.Group(func(r chi.Router) {
+					r.Use(handlers.RequireScope(models.ScopeAdmin))
+					r.Put("/{number}/limits", limitHandler.SetWalletLimits)
+					r.Put("/{number}/credit-limit", walletHandler.SetCreditLimit)
+					r.Put("/{number}/status", walletHandler.SetWalletStatus)
+					r.Put("/{number}/interest", interestHandler.SetWalletInterestPlan)
+				})
+			})
+		})
+		r.Route("/limits/tiers", func(r chi.Router) {
+			// Лимиты уровня действуют на все кошельки уровня, поэтому их изменение - административное действие
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeAdmin), handlers.RejectCustomers)
+			r.Get("/{tier}", limitHandler.GetTierLimits)
+			r.Put("/{tier}", limitHandler.SetTierLimits)
+		})
+		r.Route("/holds", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeWalletsWrite), handlers.RejectCustomers)
+			r.Get("/{id}", holdHandler.GetHold)
+			r.Post("/{id}/capture", holdHandler.CaptureHold)
+			r.Post("/{id}/void", holdHandler.VoidHold)
+		})
+		r.Route("/users", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeWalletsWrite))
+			r.With(handlers.RejectCustomers).Post("/", userHandler.CreateUser)
+			r.Get("/{id}", userHandler.GetUser)
+			r.Get("/{id}/wallets", userHandler.ListUserWallets)
+		})
+		r.Route("/schedules", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeWalletsWrite), handlers.RejectCustomers)
+			r.Post("/", scheduleHandler.CreateSchedule)
+			r.Get("/{id}", scheduleHandler.GetSchedule)
+			r.Get("/{id}/runs", scheduleHandler.ListScheduleRuns)
+			r.Delete("/{id}", scheduleHandler.CancelSchedule)
+		})
+		// Права на отдельные поля GraphQL проверяются в резолверах
+		r.Get("/graphql", graphQLHandler.ExecuteQuery)
+		r.Post("/graphql", graphQLHandler.Execute)
+		r.Route("/movements", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeWalletsWrite), handlers.RejectCustomers)
+			r.Get("/{id}", movementHandler.GetMovement)
+			// Отмена возвращает деньги в обход обычных проверок операций, поэтому доступна только администратору
+			r.With(handlers.RequireScope(models.ScopeAdmin)).Post("/{id}/reverse", movementHandler.ReverseMovement)
+		})
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(handlers.RequireScope(models.ScopeAdmin))
+			r.Route("/api-keys", func(r chi.Router) {
+				r.Get("/", apiKeyHandler.ListAPIKeys)
+				r.Post("/", apiKeyHandler.IssueAPIKey)
+				r.Delete("/{id}", apiKeyHandler.RevokeAPIKey)
+			})
+			r.Route("/reconciliation", func(r chi.Router) {
+				r.Post("/runs", reconciliationHandler.RunReconciliation)
+				r.Get("/runs", reconciliationHandler.ListReconciliationRuns)
+				r.Get("/runs/{id}", reconciliationHandler.GetReconciliationReport)
+			})
+			r.Route("/fees", func(r chi.Router) {
+				r.Get("/", feeHandler.ListFeeRules)
+				r.Put("/", feeHandler.SetFeeRule)
+				r.Delete("/{id}", feeHandler.DeleteFeeRule)
+			})
+			r.Route("/interest", func(r chi.Router) {
+				r.Get("/plans", interestHandler.ListInterestPlans)
+				r.Post("/plans", interestHandler.CreateInterestPlan)
+				r.Post("/accruals", interestHandler.AccrueInterest)
+				r.Post("/postings", interestHandler.PostInterest)
+			})
 		})
 	})
 
@@ -143,18 +352,53 @@ func main() {
 		}
 	}()
 
+	// --- gRPC-сервер на отдельном порту, с теми же сервисами ---
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort > 0 {
+		grpcAddr := fmt.Sprintf(":%d", cfg.Server.GRPCPort)
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			log.Fatalf("Не удалось открыть порт gRPC %s: %v", grpcAddr, err)
+		}
+		grpcServer = grpcserver.NewServer(rateSvc, walletSvc, apiKeySvc, jwtVerifier, limiter)
+		go func() {
+			log.Printf("gRPC-сервер запускается на %s\n", grpcAddr)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Ошибка запуска gRPC-сервера: %v", err)
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	// ... (код graceful shutdown)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	sig := <-quit
 	log.Printf("Получен сигнал %s, начинаем graceful shutdown...", sig)
+	stopJobs()
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// HTTP и gRPC останавливаются одновременно: новые запросы не принимаются, начатые дорабатывают
+	grpcStopped := make(chan struct{})
+	go func() {
+		defer close(grpcStopped)
+		if grpcServer != nil {
+			grpcServer.GracefulStop()
+		}
+	}()
+
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Fatalf("Ошибка при graceful shutdown: %v", err)
 	}
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		if grpcServer != nil {
+			log.Println("gRPC-вызовы не завершились за отведенное время и будут прерваны")
+			grpcServer.Stop()
+		}
+	}
 
 	log.Println("Сервер успешно остановлен.")
 }
@@ -24,9 +24,55 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/rates": {
+        "/admin/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все ключи, включая отозванные, без их значений. Ключ можно опознать по prefix - первым символам значения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Список API-ключей",
+                "responses": {
+                    "200": {
+                        "description": "API-ключи",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ListAPIKeysResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет действующего API-ключа",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Принимает значение курса в теле запроса и сохраняет его.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Выпускает ключ с указанными правами: rates:write, wallets:read, wallets:write, admin. Значение ключа возвращается только в этом ответе - сервис хранит лишь его хэш.",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,29 +80,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Rates"
+                    "API Keys"
                 ],
-                "summary": "Добавить новый курс валюты",
+                "summary": "Выпустить API-ключ",
                 "parameters": [
                     {
-                        "description": "Данные для создания курса (нужно только поле 'value')",
-                        "name": "rate",
+                        "description": "Имя и права ключа",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/currency-service_internal_models.Rate"
+                            "$ref": "#/definitions/currency-service_internal_models.IssueAPIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Курс успешно добавлен",
+                        "description": "Ключ выпущен",
                         "schema": {
-                            "$ref": "#/definitions/currency-service_internal_models.SuccessResponse"
+                            "$ref": "#/definitions/currency-service_internal_models.IssueAPIKeyResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный формат запроса или значение курса",
+                        "description": "Некорректный запрос, имя или право",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет действующего API-ключа",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет права admin",
                         "schema": {
                             "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
                         }
@@ -70,34 +128,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/rates/average": {
-            "get": {
-                "description": "Возвращает среднее значение для последних N курсов валют.",
+        "/admin/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает ключ: запросы с ним сразу перестают приниматься. Повторный отзыв не меняет время отзыва.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rates"
+                    "API Keys"
                 ],
-                "summary": "Получить средний курс",
+                "summary": "Отозвать API-ключ",
                 "parameters": [
                     {
-                        "minimum": 1,
                         "type": "integer",
-                        "description": "Количество последних курсов для расчета (по умолчанию 10)",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "ID ключа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Средний курс и количество записей",
+                        "description": "Ключ отозван",
                         "schema": {
-                            "$ref": "#/definitions/currency-service_internal_models.AverageResponse"
+                            "$ref": "#/definitions/currency-service_internal_models.APIKey"
                         }
                     },
                     "400": {
-                        "description": "Некорректное значение параметра 'limit'",
+                        "description": "Некорректный ID ключа",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет действующего API-ключа",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Ключ не найден",
                         "schema": {
                             "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
                         }
@@ -111,21 +192,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/wallets": {
+        "/admin/fees": {
             "get": {
-                "description": "Возвращает массив всех зарегистрированных кошельков с их балансами.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все правила комиссий за конвертацию. При конвертации применяется самое точное правило: сначала по валютной паре, затем по уровню кошелька (* - любое значение).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Wallets"
+                    "Fees"
                 ],
-                "summary": "Получить список всех кошельков",
+                "summary": "Список правил комиссий",
                 "responses": {
                     "200": {
-                        "description": "Список кошельков",
+                        "description": "Правила комиссий",
                         "schema": {
-                            "$ref": "#/definitions/currency-service_internal_models.ListWalletsResponse"
+                            "$ref": "#/definitions/currency-service_internal_models.ListFeeRulesResponse"
                         }
                     },
                     "500": {
@@ -135,11 +221,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/wallets/balance": {
-            "post": {
-                "description": "Создает новый кошелек с указанным балансом (если сумма положительная) или обновляет баланс существующего кошелька. Положительная сумма - пополнение, отрицательная - списание. Списание с несуществующего кошелька или до отрицательного баланса невозможно.",
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает или заменяет правило для пары и уровня кошелька (пустые значения означают *). Комиссия = сумма по курсу × percent / 100 + fixed, ограниченная min_fee и max_fee, округляется до сотых.",
                 "consumes": [
                     "application/json"
                 ],
@@ -147,37 +236,77 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Wallets"
+                    "Fees"
                 ],
-                "summary": "Создать кошелек или обновить баланс",
+                "summary": "Установить правило комиссии",
                 "parameters": [
                     {
-                        "description": "Данные для обновления баланса",
-                        "name": "balance_update",
+                        "description": "Правило комиссии (id и updated_at игнорируются)",
+                        "name": "rule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/currency-service_internal_models.UpdateBalanceRequest"
+                            "$ref": "#/definitions/currency-service_internal_models.FeeRule"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Баланс успешно обновлен",
+                        "description": "Правило сохранено",
                         "schema": {
-                            "$ref": "#/definitions/currency-service_internal_models.UpdateBalanceResponse"
+                            "$ref": "#/definitions/currency-service_internal_models.FeeRule"
                         }
                     },
                     "400": {
-                        "description": "Некорректный формат запроса, номера кошелька или суммы",
+                        "description": "Некорректный запрос, пара, уровень или параметры комиссии",
                         "schema": {
                             "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Конфликт бизнес-логики (например, недостаточно средств)",
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
-                            "$ref": "#/definitions/currency-service_internal_models.UpdateBalanceResponse"
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/fees/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет правило комиссии. Если подходящих правил не остается, конвертация выполняется без комиссии.",
+                "tags": [
+                    "Fees"
+                ],
+                "summary": "Удалить правило комиссии",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID правила",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Правило удалено"
+                    },
+                    "400": {
+                        "description": "Некорректный ID правила",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правило не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
                         }
                     },
                     "500": {
@@ -189,9 +318,94 @@ const docTemplate = `{
                 }
             }
         },
-        "/wallets/convert": {
+        "/admin/interest/accruals": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Начисляет проценты за завершившийся день (UTC) на баланс на конец дня каждого кошелька с процентным планом. Повторный запуск за тот же день ничего не начисляет. Фоновая задача делает это ежедневно за вчерашний день.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Interest"
+                ],
+                "summary": "Начислить проценты за день",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "День в формате YYYY-MM-DD (по умолчанию вчера)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат начисления",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.InterestAccrualRunResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректная дата",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "День еще не закончился",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/interest/plans": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все процентные планы в порядке создания.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Interest"
+                ],
+                "summary": "Список процентных планов",
+                "responses": {
+                    "200": {
+                        "description": "Процентные планы",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ListInterestPlansResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Получает самый свежий курс, конвертирует указанную сумму и списывает ее с баланса указанного кошелька. Возвращает остаток на счете и результат конвертации.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает план с годовой ставкой (в процентах) и конвенцией подсчета дней: ACT/365 (по умолчанию), ACT/360, ACT/ACT или 30/360. План не изменяется; чтобы изменить ставку, кошелькам назначают новый план.",
                 "consumes": [
                     "application/json"
                 ],
@@ -199,149 +413,3388 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Wallets"
+                    "Interest"
                 ],
-                "summary": "Конвертировать и списать сумму с кошелька",
+                "summary": "Создать процентный план",
                 "parameters": [
                     {
-                        "description": "Данные для конвертации и списания",
-                        "name": "conversion_request",
+                        "description": "Параметры плана",
+                        "name": "plan",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/currency-service_internal_models.ConvertRequest"
+                            "$ref": "#/definitions/currency-service_internal_models.CreateInterestPlanRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Конвертация и списание прошли успешно",
+                    "201": {
+                        "description": "План создан",
                         "schema": {
-                            "$ref": "#/definitions/currency-service_internal_models.ConvertResponse"
+                            "$ref": "#/definitions/currency-service_internal_models.InterestPlan"
                         }
                     },
                     "400": {
-                        "description": "Некорректный формат запроса, номера кошелька или суммы",
+                        "description": "Некорректный запрос, ставка или конвенция",
                         "schema": {
                             "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Указанный кошелек не найден",
+                    "409": {
+                        "description": "План с таким названием уже существует",
                         "schema": {
                             "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Конфликт: недостаточно средств на кошельке",
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
-                            "$ref": "#/definitions/currency-service_internal_models.ConvertResponse"
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/interest/postings": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Зачисляет на кошельки все незачисленные начисления по указанный завершившийся месяц включительно: одно движение interest на кошелек, сумма округляется до сотых. Повторный запуск ничего не зачисляет. Фоновая задача делает это за прошлый месяц.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Interest"
+                ],
+                "summary": "Зачислить начисленные проценты",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Месяц в формате YYYY-MM (по умолчанию прошлый)",
+                        "name": "month",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат зачисления",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.InterestPostingResponse"
                         }
                     },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
+                    "400": {
+                        "description": "Некорректный месяц",
                         "schema": {
                             "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
                         }
                     },
-                    "503": {
-                        "description": "Не удалось получить актуальный курс валют",
+                    "422": {
+                        "description": "Месяц еще не закончился",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "currency-service_internal_models.AverageResponse": {
-            "type": "object",
-            "properties": {
-                "average": {
-                    "type": "number"
-                },
-                "count": {
-                    "type": "integer"
-                }
-            }
         },
-        "currency-service_internal_models.ConvertRequest": {
+        "/admin/reconciliation/runs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает последние сверки балансов (новые первыми) с количеством проверенных кошельков и найденных расхождений.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Список сверок",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество сверок (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список сверок",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ListReconciliationRunsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное значение параметра 'limit'",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Пересчитывает баланс каждого кошелька по истории движений, сравнивает с сохраненным балансом и сохраняет найденные расхождения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Запустить сверку балансов",
+                "responses": {
+                    "200": {
+                        "description": "Результат сверки",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ReconciliationReportResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reconciliation/runs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает сверку и найденные ею расхождения. Вместо ID можно указать latest для последней сверки.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Отчет о сверке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID сверки или latest",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отчет о сверке",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ReconciliationReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID сверки",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сверка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "То же, что POST /graphql, но только для запросов: мутации через GET не выполняются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL"
+                ],
+                "summary": "Выполнить запрос GraphQL (GET)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Запрос GraphQL",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Значения переменных (JSON-объект)",
+                        "name": "variables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Какую операцию выполнить, если в запросе их несколько",
+                        "name": "operationName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык сообщений: ru (по умолчанию), en, kk",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат запроса (в том числе с ошибками в errors)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.GraphQLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное значение параметра variables",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет действительного API-ключа или токена",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Запросы: latestRate, rates(limit), averageRate(limit), wallets(limit, cursor, status, sortBy, order), wallet(number) с полем movements(limit). Мутации: updateBalance, convert (только POST). Права те же, что у соответствующих REST-эндпоинтов. В одном запросе допускается одна мутация; запросы стоимостью больше 10 000 (каждое поле стоит 1, поле с limit умножает стоимость вложенных полей на limit) отклоняются с кодом INVALID_REQUEST. Ошибки возвращаются в errors со статусом 200, код ошибки - в extensions.code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL"
+                ],
+                "summary": "Выполнить запрос GraphQL",
+                "parameters": [
+                    {
+                        "description": "Запрос GraphQL",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.GraphQLRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык сообщений: ru (по умолчанию), en, kk",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат запроса (в том числе с ошибками в errors)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.GraphQLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный формат запроса",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет действительного API-ключа или токена",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает холд по ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Holds"
+                ],
+                "summary": "Получить холд",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID холда",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Холд",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Hold"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID холда",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Холд не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds/{id}/capture": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Списывает с кошелька всю зарезервированную сумму или ее часть. Остаток резерва освобождается.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Holds"
+                ],
+                "summary": "Списать зарезервированные средства",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID холда",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Сумма списания (по умолчанию - вся сумма холда)",
+                        "name": "capture",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.CaptureHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Холд списан",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Hold"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос или сумма больше зарезервированной",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Холд не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Холд уже списан, отменен или истек",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Списание превышает лимит на списания",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.LimitExceededResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds/{id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отменяет холд и освобождает зарезервированные средства без списания.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Holds"
+                ],
+                "summary": "Отменить холд",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID холда",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Холд отменен",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Hold"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID холда",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Холд не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Холд уже списан, отменен или истек",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/limits/tiers/{tier}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает лимиты на списания, действующие для всех кошельков указанного уровня. Отсутствующее поле означает, что лимит не установлен.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Limits"
+                ],
+                "summary": "Получить лимиты уровня кошельков",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уровень кошелька (например, standard)",
+                        "name": "tier",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лимиты уровня",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.TierLimitsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное название уровня",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Полностью заменяет лимиты уровня. Не указанные поля снимают соответствующий лимит.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Limits"
+                ],
+                "summary": "Установить лимиты уровня кошельков",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уровень кошелька (например, standard)",
+                        "name": "tier",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Лимиты уровня",
+                        "name": "limits",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Limits"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лимиты сохранены",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.TierLimitsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, уровень или отрицательный лимит",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movements/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает движение по кошельку по ID. Для конвертаций указаны курс и исходная сумма, для отмен - ID отмененного движения (reversal_of), для отмененных движений - ID отмены (reversed_by).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movements"
+                ],
+                "summary": "Получить движение",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID движения",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Движение",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Movement"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID движения",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Движение не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movements/{id}/reverse": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Записывает движение, компенсирующее пополнение, списание или конвертацию, и связывает его с исходным. Отмена конвертации возвращает сумму по курсу исходной конвертации и в той же транзакции отменяет зачисление получателю и комиссию (linked_reversals); эти движения по отдельности не отменяются. Движение можно отменить только один раз; отмену зачисления нельзя выполнить, если средств на кошельке уже недостаточно. Требует права admin.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movements"
+                ],
+                "summary": "Отменить движение",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID отменяемого движения",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык сообщений: ru (по умолчанию), en, kk",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Движение отменено",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ReversalResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID движения",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Движение не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Движение уже отменено или недостаточно средств для отмены",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ReversalResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Движение этого вида нельзя отменить или оно отменяется только вместе с конвертацией",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rates": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает последние курсы, начиная с самого свежего. Формат ответа выбирается параметром format или заголовком Accept (text/csv, application/x-ndjson); CSV и NDJSON отдаются потоком по мере чтения из БД и по умолчанию содержат всю историю. Выгрузка может длиться до 10 минут; в трейлерах X-Export-Status (complete или truncated) и X-Export-Rows сообщается, полный ли файл: без трейлера complete выгрузка неполная.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Rates"
+                ],
+                "summary": "Получить историю курсов",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество последних курсов (по умолчанию 10, максимум 500; для CSV и NDJSON - все, без ограничения)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Формат ответа (приоритетнее заголовка Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из предыдущего ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified из предыдущего ответа",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Курсы, начиная с самого свежего",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/currency-service_internal_models.Rate"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия курсов и формат ответа"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Время самого свежего курса"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Курсы не изменились"
+                    },
+                    "400": {
+                        "description": "Некорректное значение параметра 'limit' или 'format'",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Принимает значение курса в теле запроса и сохраняет его.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rates"
+                ],
+                "summary": "Добавить новый курс валюты",
+                "parameters": [
+                    {
+                        "description": "Данные для создания курса (нужно только поле 'value')",
+                        "name": "rate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Rate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Курс успешно добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный формат запроса или значение курса",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rates/average": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает среднее значение для последних N курсов валют.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rates"
+                ],
+                "summary": "Получить средний курс",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество последних курсов для расчета (по умолчанию 10, максимум 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из предыдущего ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified из предыдущего ответа",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Средний курс и количество записей",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.AverageResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия курсов"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Время самого свежего курса"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Курсы не изменились"
+                    },
+                    "400": {
+                        "description": "Некорректное значение параметра 'limit'",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает однократную или повторяющуюся операцию с кошельком (balance_update, transfer или convert). Повторение: once, daily, weekly, monthly (опорная точка - start_at) или cron (5 полей, UTC). Операции выполняет фоновая задача через сервис кошельков; при нехватке средств запуск повторяется, а после нескольких неудач подряд расписание отключается. Запуски выполняются с правами создателя расписания и заново проверяют владельца кошелька.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Создать запланированную операцию",
+                "parameters": [
+                    {
+                        "description": "Операция, сумма и правило повторения",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.CreateScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Расписание создано",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, операция, сумма или правило повторения",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Для списания с кошелька владельца нужен user_id владельца",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает расписание по ID, включая время следующего запуска, статус и число неудач подряд.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Получить расписание",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID расписания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расписание",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID расписания",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Расписание не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отключает активное расписание. История запусков сохраняется.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Отменить расписание",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID расписания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расписание отключено",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID расписания",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Расписание не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Расписание уже выполнено или отключено",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{id}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает результаты всех запусков расписания, новые первыми.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "История запусков расписания",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID расписания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "История запусков",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ListScheduleRunsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID расписания",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Расписание не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает владельца кошельков. ID пользователя генерируется сервером.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Создать пользователя",
+                "parameters": [
+                    {
+                        "description": "Имя и фамилия пользователя",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Пользователь создан",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный формат запроса или пустое имя",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает пользователя по его ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/wallets": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все кошельки, владельцем которых является пользователь.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить кошельки пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Кошельки пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ListWalletsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает страницу кошельков с их балансами: balance - учетный баланс, available_balance - доступный баланс за вычетом активных холдов. Для получения следующей страницы передайте next_cursor из ответа в параметре cursor с теми же фильтрами и сортировкой.\nФормат ответа выбирается параметром format или заголовком Accept (text/csv, application/x-ndjson). CSV и NDJSON отдаются потоком по мере чтения из БД, содержат время создания и обновления кошельков и не разбиваются на страницы: выгружаются все подходящие кошельки (или первые limit). Выгрузка может длиться до 10 минут; в трейлерах X-Export-Status (complete или truncated) и X-Export-Rows сообщается, полный ли файл: без трейлера complete выгрузка неполная.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Получить список кошельков",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 500; для CSV и NDJSON - без ограничения)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor из предыдущего ответа)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Баланс не меньше",
+                        "name": "min_balance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Баланс не больше",
+                        "name": "max_balance",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "frozen",
+                            "closed"
+                        ],
+                        "type": "string",
+                        "description": "Статус кошелька",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создан не раньше (RFC 3339, включительно)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создан раньше (RFC 3339, не включительно)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "balance",
+                            "wallet_number"
+                        ],
+                        "type": "string",
+                        "description": "Ключ сортировки (по умолчанию created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Порядок сортировки (по умолчанию asc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Формат ответа (приоритетнее заголовка Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из предыдущего ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified из предыдущего ответа",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница кошельков",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ListWalletsResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия списка кошельков и формат ответа"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Время последнего изменения кошельков или их холдов"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Кошельки не изменились"
+                    },
+                    "400": {
+                        "description": "Некорректные параметры фильтрации, сортировки, формата или курсор",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает кошелек с нулевым балансом. Номер выделяет сервер: 7 цифр, последняя - контрольная цифра по алгоритму Луна. Если указан user_id, кошелек привязывается к этому пользователю.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Создать кошелек",
+                "parameters": [
+                    {
+                        "description": "Владелец и уровень кошелька",
+                        "name": "wallet",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.CreateWalletRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Кошелек создан",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Wallet"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный формат запроса, ID пользователя или уровень",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Указанный пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/balance": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает новый кошелек с указанным балансом (если сумма положительная и включено неявное создание WALLET_IMPLICIT_CREATE, по умолчанию выключено; номер нового кошелька должен иметь корректную контрольную цифру по алгоритму Луна) или обновляет баланс существующего кошелька. Положительная сумма - пополнение, отрицательная - списание. Списание с несуществующего кошелька или ниже кредитного лимита (без лимита - ниже нуля) невозможно. Если указан user_id, новый кошелек привязывается к этому пользователю, а для существующего проверяется, что пользователь - его владелец. Списание с кошелька, у которого есть владелец, без user_id доступно только сотрудникам (роль operator или admin, API-ключ с правом admin).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Создать кошелек или обновить баланс",
+                "parameters": [
+                    {
+                        "description": "Данные для обновления баланса",
+                        "name": "balance_update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.UpdateBalanceRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag кошелька из GET /wallets/{number}. Если версия изменилась, обновление отклоняется с 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык сообщений: ru (по умолчанию), en, kk",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Баланс успешно обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.UpdateBalanceResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия кошелька после обновления"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный формат запроса, номера кошелька (в том числе контрольной цифры нового номера) или суммы",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Кошелек не принадлежит указанному пользователю или для списания нужен user_id владельца",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Указанный пользователь не найден или кошелек не найден (если неявное создание кошельков выключено)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Конфликт бизнес-логики (например, недостаточно средств)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.UpdateBalanceResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Версия из If-Match не совпадает с текущей (в ответе актуальные баланс и версия)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.UpdateBalanceResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Превышен лимит на списания",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.LimitExceededResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Выполняет список пополнений (deposit), списаний (withdrawal) и переводов (transfer) в одной транзакции и возвращает результат каждой операции. Все кошельки должны существовать. Операции выполняются по порядку и видят результат предыдущих. Для списаний и переводов с кошелька, у которого есть владелец, user_id должен совпадать с владельцем; применяются лимиты. В режиме atomic (по умолчанию) ошибка любой операции отменяет весь пакет; в режиме best_effort неудачные операции пропускаются, а остальные фиксируются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Выполнить пакет операций с кошельками",
+                "parameters": [
+                    {
+                        "description": "Операции пакета и режим выполнения",
+                        "name": "batch_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.BatchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык сообщений: ru (по умолчанию), en, kk",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пакет выполнен (в best_effort - возможно, частично)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректная операция в режиме atomic (для ошибок пакета целиком - ErrorResponse)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.BatchResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Кошелек операции не принадлежит указанному пользователю (atomic)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.BatchResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек операции не найден (atomic)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.BatchResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Недостаточно средств для операции (atomic)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.BatchResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Превышен лимит на списания (atomic)",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.BatchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/convert": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает самый свежий курс, конвертирует указанную сумму и списывает ее с баланса указанного кошелька. Если указан destination_wallet_number, сконвертированная сумма в той же транзакции зачисляется на этот кошелек (он должен существовать и принадлежать тому же владельцу или не иметь владельца), и в ответе возвращаются балансы обоих кошельков. Если у кошелька есть владелец, user_id (и first_name/last_name, если указаны) должны совпадать с ним. Возвращает остаток на счете и результат конвертации.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Конвертировать и списать сумму с кошелька",
+                "parameters": [
+                    {
+                        "description": "Данные для конвертации и списания",
+                        "name": "conversion_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ConvertRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык сообщений: ru (по умолчанию), en, kk",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Конвертация и списание прошли успешно",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ConvertResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный формат запроса, номера кошелька или суммы",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Кошелек-источник не принадлежит указанному пользователю или получатель принадлежит другому владельцу",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек-источник или получатель не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Конфликт: недостаточно средств на кошельке",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ConvertResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Превышен лимит на списания или количество конвертаций",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.LimitExceededResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Не удалось получить актуальный курс валют",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{number}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает кошелек по номеру с временем создания и последнего обновления. Если указан as_of, дополнительно возвращает учетный баланс на этот момент, восстановленный по истории движений.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Получить кошелек",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Номер кошелька",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Момент, на который нужен баланс (RFC 3339)",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Кошелек",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.WalletDetailsResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия кошелька для If-Match в POST /wallets/balance"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный номер кошелька или as_of",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{number}/credit-limit": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Разрешает кошельку овердрафт: баланс может опускаться до -credit_limit. 0 возвращает нулевой порог. Лимит нельзя уменьшить ниже уже использованного кредита. В ответе available_credit - неиспользованная часть лимита. Требует права admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Установить кредитный лимит кошелька",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Номер кошелька",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Кредитный лимит",
+                        "name": "credit_limit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.SetCreditLimitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лимит установлен",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Wallet"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия кошелька"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, номер кошелька или отрицательный лимит",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Лимит меньше уже использованного кредита",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{number}/holds": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает холд: сумма уменьшает доступный баланс кошелька, но не списывается. Холд освобождается автоматически по истечении срока.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Holds"
+                ],
+                "summary": "Зарезервировать средства на кошельке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Номер кошелька",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Сумма, reference и срок действия холда",
+                        "name": "hold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.CreateHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Холд создан",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Hold"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный формат запроса, номера кошелька или суммы",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Недостаточно доступных средств или дублирующийся reference",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Сумма холда превышает лимит на списания",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.LimitExceededResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{number}/interest": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает процентный план кошелька, начисленные, но еще не зачисленные проценты и последние ежедневные начисления.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Interest"
+                ],
+                "summary": "Проценты по кошельку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Номер кошелька",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Проценты по кошельку",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.WalletInterestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный номер кошелька",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Назначает кошельку процентный план или отключает начисление (plan_id: null). Назначения хранятся в истории: за каждый день проценты начисляются по плану, действовавшему на конец дня, в том числе при начислении за пропущенные дни. Требует права admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Interest"
+                ],
+                "summary": "Назначить процентный план кошельку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Номер кошелька",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ID плана",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.SetWalletInterestPlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "План назначен",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.WalletInterestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос или номер кошелька",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек или план не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{number}/limits": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает уровень кошелька, лимиты уровня, индивидуальные лимиты и итоговые лимиты, которые применяются к списаниям.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Limits"
+                ],
+                "summary": "Получить лимиты кошелька",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Номер кошелька",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лимиты кошелька",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.WalletLimitsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный номер кошелька",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Полностью заменяет индивидуальные лимиты кошелька (они имеют приоритет над лимитами уровня) и, если указан, меняет уровень кошелька. Требует права admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Limits"
+                ],
+                "summary": "Установить лимиты кошелька",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Номер кошелька",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Уровень и индивидуальные лимиты",
+                        "name": "limits",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.UpdateWalletLimitsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лимиты сохранены",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.WalletLimitsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, номер кошелька, уровень или отрицательный лимит",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{number}/status": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Замораживает (frozen), размораживает (active) или закрывает (closed) кошелек. С замороженным кошельком нельзя проводить операции (пополнения, списания, переводы, конвертации, холды, отмены движений) до разморозки. Закрыть можно только кошелек с нулевым балансом и без активных холдов; закрытие окончательно. Требует права admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Изменить статус кошелька",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Номер кошелька",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый статус",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.SetWalletStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статус изменен",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.Wallet"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия кошелька"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, номер кошелька или статус",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "У ключа нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Кошелек не найден",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Кошелек уже закрыт или при закрытии на нем остались средства или холды",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/currency-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "currency-service_internal_models.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Время выпуска",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Назначение ключа (например, имя сервиса-клиента)",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Начало ключа, чтобы его можно было опознать",
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "description": "Время отзыва (nil - ключ действует)",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Права ключа",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "currency-service_internal_models.AverageResponse": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "currency-service_internal_models.BatchOperation": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Положительная сумма",
+                    "type": "number"
+                },
+                "target_wallet_number": {
+                    "description": "Получатель (только для transfer)",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "deposit, withdrawal или transfer",
+                    "type": "string"
+                },
+                "wallet_number": {
+                    "description": "Кошелек для пополнения/списания или источник перевода",
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.BatchOperationResult": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "balance": {
+                    "description": "Баланс кошелька после операции",
+                    "type": "number"
+                },
+                "code": {
+                    "description": "Код ошибки (для failed)",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Причина неудачи (для failed)",
+                    "type": "string"
+                },
+                "index": {
+                    "description": "Позиция операции в запросе (с 0)",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "succeeded, failed, rolled_back или skipped",
+                    "type": "string"
+                },
+                "target_balance": {
+                    "description": "Баланс получателя после перевода",
+                    "type": "number"
+                },
+                "target_wallet_number": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "wallet_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.BatchRequest": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "atomic (по умолчанию) или best_effort",
+                    "type": "string"
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.BatchOperation"
+                    }
+                },
+                "user_id": {
+                    "description": "Должен совпадать с владельцем кошельков, с которых списываются средства",
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "description": "Зафиксированы ли изменения (в atomic - все или ничего)",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "Количество операций со статусом failed",
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.BatchOperationResult"
+                    }
+                },
+                "succeeded": {
+                    "description": "Количество зафиксированных операций",
+                    "type": "integer"
+                }
+            }
+        },
+        "currency-service_internal_models.CaptureHoldRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Если не указано - списывается вся зарезервированная сумма",
+                    "type": "number"
+                }
+            }
+        },
+        "currency-service_internal_models.ConvertRequest": {
+            "type": "object",
+            "properties": {
+                "amount_to_convert": {
+                    "type": "number"
+                },
+                "destination_wallet_number": {
+                    "description": "Кошелек, на который зачисляется сконвертированная сумма (например, кошелек того же владельца в другой валюте).\nЕсли не указан, сумма только списывается.",
+                    "type": "string"
+                },
+                "first_name": {
+                    "description": "Если указано, должно совпадать с именем владельца кошелька",
+                    "type": "string"
+                },
+                "last_name": {
+                    "description": "Если указано, должно совпадать с фамилией владельца кошелька",
+                    "type": "string"
+                },
+                "source_wallet_number": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Должен совпадать с владельцем кошелька-источника",
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.ConvertResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Код ошибки (при 409)",
+                    "type": "string"
+                },
+                "converted_amount": {
+                    "description": "Поле будет заполнено при успехе",
+                    "type": "number"
+                },
+                "destination_balance": {
+                    "description": "Баланс кошелька-получателя после зачисления",
+                    "type": "number"
+                },
+                "destination_movement_id": {
+                    "description": "ID движения зачисления",
+                    "type": "integer"
+                },
+                "destination_wallet_number": {
+                    "description": "Поля кошелька-получателя заполняются, если он указан в запросе",
+                    "type": "string"
+                },
+                "fee": {
+                    "description": "Комиссия за конвертацию (списывается сверх суммы по курсу)",
+                    "type": "number"
+                },
+                "message": {
+                    "description": "Сообщение об успехе или ошибке",
+                    "type": "string"
+                },
+                "movement_id": {
+                    "description": "ID движения списания (для отмены через POST /movements/{id}/reverse)",
+                    "type": "integer"
+                },
+                "rate_used": {
+                    "description": "Поле будет заполнено при успехе",
+                    "type": "number"
+                },
+                "remaining_balance": {
+                    "description": "Поле будет заполнено при успехе",
+                    "type": "number"
+                },
+                "source_wallet_number": {
+                    "type": "string"
+                },
+                "total_debited": {
+                    "description": "Всего списано: сумма по курсу и комиссия",
+                    "type": "number"
+                }
+            }
+        },
+        "currency-service_internal_models.CreateHoldRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "expires_in_seconds": {
+                    "description": "Если не указано, используется срок по умолчанию из конфигурации",
+                    "type": "integer"
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.CreateInterestPlanRequest": {
+            "type": "object",
+            "properties": {
+                "annual_rate": {
+                    "description": "Годовая ставка в процентах",
+                    "type": "number"
+                },
+                "day_count": {
+                    "description": "По умолчанию ACT/365",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.CreateScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "cron_expr": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "type": "string"
+                },
+                "start_at": {
+                    "description": "По умолчанию - текущее время",
+                    "type": "string"
+                },
+                "target_wallet_number": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "wallet_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.CreateUserRequest": {
+            "type": "object",
+            "properties": {
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.CreateWalletRequest": {
+            "type": "object",
+            "properties": {
+                "tier": {
+                    "description": "Уровень кошелька (по умолчанию standard)",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Владелец кошелька (необязательно)",
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.Discrepancy": {
+            "type": "object",
+            "properties": {
+                "computed_balance": {
+                    "description": "Сумма движений по кошельку",
+                    "type": "number"
+                },
+                "detected_at": {
+                    "type": "string"
+                },
+                "difference": {
+                    "description": "stored_balance - computed_balance",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "run_id": {
+                    "type": "integer"
+                },
+                "stored_balance": {
+                    "description": "wallets.balance",
+                    "type": "number"
+                },
+                "wallet_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Стабильный код ошибки (см. internal/apperrors)",
+                    "type": "string",
+                    "example": "INSUFFICIENT_FUNDS"
+                },
+                "details": {
+                    "description": "Дополнительные сведения (зависят от кода)",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "error": {
+                    "description": "Содержит текст ошибки для клиента",
+                    "type": "string",
+                    "example": "Сообщение об ошибке"
+                },
+                "request_id": {
+                    "description": "ID запроса для поиска в логах",
+                    "type": "string",
+                    "example": "host/abc-000001"
+                }
+            }
+        },
+        "currency-service_internal_models.FeeRule": {
+            "type": "object",
+            "properties": {
+                "fixed": {
+                    "description": "Фиксированная часть комиссии",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_fee": {
+                    "description": "Максимальная комиссия (nil - без ограничения)",
+                    "type": "number"
+                },
+                "min_fee": {
+                    "description": "Минимальная комиссия (nil - без ограничения)",
+                    "type": "number"
+                },
+                "pair": {
+                    "description": "Валютная пара (например, USD/RUB) или * для любой",
+                    "type": "string"
+                },
+                "percent": {
+                    "description": "Процент от суммы списания по курсу",
+                    "type": "number"
+                },
+                "tier": {
+                    "description": "Уровень кошелька или * для любого",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Время последнего изменения",
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.GraphQLError": {
+            "type": "object",
+            "properties": {
+                "extensions": {
+                    "description": "code - стабильный код ошибки, details - дополнительные сведения",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "message": {
+                    "description": "Текст ошибки на языке запроса",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Путь к полю, при разрешении которого произошла ошибка",
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "currency-service_internal_models.GraphQLRequest": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "description": "Какую операцию выполнить, если в запросе их несколько",
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "{ latestRate { value timestamp } wallet(number: \"1234566\") { balance movements(limit: 5) { kind amount } } }"
+                },
+                "variables": {
+                    "description": "Значения переменных запроса",
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "currency-service_internal_models.GraphQLResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.GraphQLError"
+                    }
+                }
+            }
+        },
+        "currency-service_internal_models.Hold": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Зарезервированная сумма",
+                    "type": "number"
+                },
+                "captured_amount": {
+                    "description": "Фактически списанная сумма (после capture)",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "description": "Внешний идентификатор операции (например, ID авторизации карты)",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "wallet_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.InterestAccrual": {
+            "type": "object",
+            "properties": {
+                "accrual_date": {
+                    "description": "День начисления (UTC)",
+                    "type": "string"
+                },
+                "amount": {
+                    "description": "Начислено за день (без округления)",
+                    "type": "number"
+                },
+                "annual_rate": {
+                    "description": "Ставка плана на момент начисления",
+                    "type": "number"
+                },
+                "balance": {
+                    "description": "Баланс на конец дня",
+                    "type": "number"
+                },
+                "day_count": {
+                    "type": "string"
+                },
+                "movement_id": {
+                    "description": "Движение, которым зачислены проценты",
+                    "type": "integer"
+                },
+                "plan_id": {
+                    "type": "integer"
+                },
+                "posted_at": {
+                    "description": "Когда начисление зачислено на кошелек",
+                    "type": "string"
+                },
+                "wallet_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.InterestAccrualRunResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "День начисления (YYYY-MM-DD)",
+                    "type": "string"
+                },
+                "total_accrued": {
+                    "type": "number"
+                },
+                "wallets_accrued": {
+                    "description": "Скольким кошелькам начислено (повторный запуск за тот же день дает 0)",
+                    "type": "integer"
+                }
+            }
+        },
+        "currency-service_internal_models.InterestPlan": {
+            "type": "object",
+            "properties": {
+                "annual_rate": {
+                    "description": "Годовая ставка в процентах (5 - это 5% годовых)",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "day_count": {
+                    "description": "ACT/365, ACT/360, ACT/ACT или 30/360",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.InterestPostingResponse": {
+            "type": "object",
+            "properties": {
+                "month": {
+                    "description": "Зачислены начисления по этот месяц включительно (YYYY-MM)",
+                    "type": "string"
+                },
+                "total_posted": {
+                    "type": "number"
+                },
+                "wallets_posted": {
+                    "description": "Сколько кошельков получили движение interest",
+                    "type": "integer"
+                }
+            }
+        },
+        "currency-service_internal_models.IssueAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "billing-service"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "wallets:read",
+                        "wallets:write"
+                    ]
+                }
+            }
+        },
+        "currency-service_internal_models.IssueAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Время выпуска",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "csk_Xb3kq9ZtWm1Ua7cR2vLp0sYdEf4gHi5jKl6nOo8pQr9"
+                },
+                "name": {
+                    "description": "Назначение ключа (например, имя сервиса-клиента)",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Начало ключа, чтобы его можно было опознать",
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "description": "Время отзыва (nil - ключ действует)",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Права ключа",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "currency-service_internal_models.LimitExceededResponse": {
+            "type": "object",
+            "properties": {
+                "attempted": {
+                    "description": "Значение с учетом отклоненной операции",
+                    "type": "number"
+                },
+                "code": {
+                    "description": "Стабильный код ошибки (см. internal/apperrors)",
+                    "type": "string",
+                    "example": "INSUFFICIENT_FUNDS"
+                },
+                "details": {
+                    "description": "Дополнительные сведения (зависят от кода)",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "error": {
+                    "description": "Содержит текст ошибки для клиента",
+                    "type": "string",
+                    "example": "Сообщение об ошибке"
+                },
+                "limit": {
+                    "description": "Какой лимит превышен",
+                    "type": "string",
+                    "example": "daily_withdrawal_total"
+                },
+                "limit_value": {
+                    "description": "Значение лимита",
+                    "type": "number"
+                },
+                "request_id": {
+                    "description": "ID запроса для поиска в логах",
+                    "type": "string",
+                    "example": "host/abc-000001"
+                },
+                "resets_at": {
+                    "description": "Когда лимит обнулится (нет для лимита одного списания)",
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.Limits": {
+            "type": "object",
+            "properties": {
+                "daily_conversion_count": {
+                    "description": "Максимальное количество конвертаций за сутки (UTC)",
+                    "type": "integer"
+                },
+                "daily_withdrawal_total": {
+                    "description": "Максимальная сумма списаний за сутки (UTC)",
+                    "type": "number"
+                },
+                "max_single_withdrawal": {
+                    "description": "Максимальная сумма одного списания",
+                    "type": "number"
+                },
+                "monthly_withdrawal_total": {
+                    "description": "Максимальная сумма списаний за календарный месяц (UTC)",
+                    "type": "number"
+                }
+            }
+        },
+        "currency-service_internal_models.ListAPIKeysResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.APIKey"
+                    }
+                }
+            }
+        },
+        "currency-service_internal_models.ListFeeRulesResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.FeeRule"
+                    }
+                }
+            }
+        },
+        "currency-service_internal_models.ListInterestPlansResponse": {
+            "type": "object",
+            "properties": {
+                "plans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.InterestPlan"
+                    }
+                }
+            }
+        },
+        "currency-service_internal_models.ListReconciliationRunsResponse": {
+            "type": "object",
+            "properties": {
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.ReconciliationRun"
+                    }
+                }
+            }
+        },
+        "currency-service_internal_models.ListScheduleRunsResponse": {
+            "type": "object",
+            "properties": {
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.ScheduleRun"
+                    }
+                }
+            }
+        },
+        "currency-service_internal_models.ListWalletsResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "Курсор следующей страницы (пусто на последней странице)",
+                    "type": "string"
+                },
+                "wallets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.Wallet"
+                    }
+                }
+            }
+        },
+        "currency-service_internal_models.Movement": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "balance_after": {
+                    "description": "Учетный баланс кошелька после движения",
+                    "type": "number"
+                },
+                "conversion_id": {
+                    "description": "ID списания конвертации, частью которой является движение (conversion_in, conversion_fee, fee_income)",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Курс конвертации (только для conversion, conversion_in и их отмены)",
+                    "type": "number"
+                },
+                "reversal_of": {
+                    "description": "ID отмененного движения (только для reversal)",
+                    "type": "integer"
+                },
+                "reversed_by": {
+                    "description": "ID движения, которым отменено это движение",
+                    "type": "integer"
+                },
+                "source_amount": {
+                    "description": "Сумма до конвертации (только для conversion, conversion_in и их отмены)",
+                    "type": "number"
+                },
+                "wallet_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.Rate": {
             "type": "object",
             "properties": {
-                "amount_to_convert": {
+                "timestamp": {
+                    "type": "string"
+                },
+                "value": {
                     "type": "number"
+                }
+            }
+        },
+        "currency-service_internal_models.ReconciliationReportResponse": {
+            "type": "object",
+            "properties": {
+                "discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.Discrepancy"
+                    }
                 },
-                "first_name": {
-                    "description": "Пока не используется в логике, но есть в запросе",
-                    "type": "string"
+                "run": {
+                    "$ref": "#/definitions/currency-service_internal_models.ReconciliationRun"
+                }
+            }
+        },
+        "currency-service_internal_models.ReconciliationRun": {
+            "type": "object",
+            "properties": {
+                "discrepancies_found": {
+                    "description": "Сколько расхождений найдено",
+                    "type": "integer"
                 },
-                "last_name": {
-                    "description": "Пока не используется в логике, но есть в запросе",
+                "error": {
+                    "description": "Ошибка, если сверка не завершилась",
                     "type": "string"
                 },
-                "source_wallet_number": {
+                "finished_at": {
+                    "description": "Нет у незавершенной сверки",
                     "type": "string"
                 },
-                "user_id": {
-                    "description": "Пока не используется в логике, но есть в запросе",
+                "id": {
+                    "type": "integer"
+                },
+                "started_at": {
                     "type": "string"
+                },
+                "wallets_checked": {
+                    "description": "Сколько кошельков проверено",
+                    "type": "integer"
                 }
             }
         },
-        "currency-service_internal_models.ConvertResponse": {
+        "currency-service_internal_models.ReversalResponse": {
             "type": "object",
             "properties": {
-                "converted_amount": {
-                    "description": "Поле будет заполнено при успехе",
-                    "type": "number"
+                "code": {
+                    "description": "Код ошибки (при 409)",
+                    "type": "string"
+                },
+                "linked_reversals": {
+                    "description": "Отмены остальных движений конвертации (зачисление получателю, комиссия), выполненные вместе с отменой списания",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.Movement"
+                    }
                 },
                 "message": {
-                    "description": "Сообщение об успехе или ошибке",
                     "type": "string"
                 },
-                "rate_used": {
-                    "description": "Поле будет заполнено при успехе",
+                "new_balance": {
+                    "description": "Учетный баланс кошелька после отмены",
                     "type": "number"
                 },
-                "remaining_balance": {
-                    "description": "Поле будет заполнено при успехе",
+                "original": {
+                    "description": "Отмененное движение",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/currency-service_internal_models.Movement"
+                        }
+                    ]
+                },
+                "reversal": {
+                    "description": "Компенсирующее движение",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/currency-service_internal_models.Movement"
+                        }
+                    ]
+                }
+            }
+        },
+        "currency-service_internal_models.Schedule": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Сумма операции (для balance_update может быть отрицательной)",
                     "type": "number"
                 },
-                "source_wallet_number": {
+                "consecutive_failures": {
+                    "description": "Количество неудачных запусков подряд",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "Кто создал расписание: key:\u003cID\u003e API-ключа или user:\u003csub\u003e пользователя",
+                    "type": "string"
+                },
+                "created_by_staff": {
+                    "description": "Создатель - сотрудник: запуски могут списывать с кошелька владельца без user_id",
+                    "type": "boolean"
+                },
+                "cron_expr": {
+                    "description": "Только для recurrence = cron",
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "description": "Почему расписание отключено",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_run_at": {
+                    "description": "Время последнего запуска",
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "description": "Время следующего запуска (нет у завершенных)",
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "description": "once, daily, weekly, monthly или cron",
+                    "type": "string"
+                },
+                "start_at": {
+                    "description": "Первый запуск и опорная точка для daily/weekly/monthly",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "active, completed или disabled",
+                    "type": "string"
+                },
+                "target_wallet_number": {
+                    "description": "Кошелек получателя (только для transfer)",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "Пользователь, от имени которого выполняется операция",
+                    "type": "string"
+                },
+                "wallet_number": {
+                    "description": "Кошелек, с которым выполняется операция (источник перевода)",
                     "type": "string"
                 }
             }
         },
-        "currency-service_internal_models.ErrorResponse": {
+        "currency-service_internal_models.ScheduleRun": {
             "type": "object",
             "properties": {
                 "error": {
-                    "description": "Содержит текст ошибки для клиента",
-                    "type": "string",
-                    "example": "Сообщение об ошибке"
+                    "description": "Текст ошибки при неудаче",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ran_at": {
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "succeeded или failed",
+                    "type": "string"
                 }
             }
         },
-        "currency-service_internal_models.ListWalletsResponse": {
+        "currency-service_internal_models.SetCreditLimitRequest": {
             "type": "object",
             "properties": {
-                "wallets": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/currency-service_internal_models.Wallet"
-                    }
+                "credit_limit": {
+                    "description": "0 - без овердрафта",
+                    "type": "number"
                 }
             }
         },
-        "currency-service_internal_models.Rate": {
+        "currency-service_internal_models.SetWalletInterestPlanRequest": {
             "type": "object",
             "properties": {
-                "timestamp": {
-                    "type": "string"
-                },
-                "value": {
-                    "type": "number"
+                "plan_id": {
+                    "description": "null - отключить начисление процентов",
+                    "type": "integer"
+                }
+            }
+        },
+        "currency-service_internal_models.SetWalletStatusRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "description": "active, frozen или closed",
+                    "type": "string",
+                    "example": "frozen"
                 }
             }
         },
@@ -355,6 +3808,17 @@ const docTemplate = `{
                 }
             }
         },
+        "currency-service_internal_models.TierLimitsResponse": {
+            "type": "object",
+            "properties": {
+                "limits": {
+                    "$ref": "#/definitions/currency-service_internal_models.Limits"
+                },
+                "tier": {
+                    "type": "string"
+                }
+            }
+        },
         "currency-service_internal_models.UpdateBalanceRequest": {
             "type": "object",
             "properties": {
@@ -362,6 +3826,10 @@ const docTemplate = `{
                     "description": "Может быть положительным (пополнение) или отрицательным (списание)",
                     "type": "number"
                 },
+                "user_id": {
+                    "description": "Владелец кошелька (необязательно). При создании кошелек привязывается к нему",
+                    "type": "string"
+                },
                 "wallet_number": {
                     "type": "string"
                 }
@@ -370,34 +3838,222 @@ const docTemplate = `{
         "currency-service_internal_models.UpdateBalanceResponse": {
             "type": "object",
             "properties": {
+                "code": {
+                    "description": "Код ошибки (при 409 и 412)",
+                    "type": "string"
+                },
                 "message": {
                     "description": "Сообщение об успехе или ошибке (например, недостаточно средств)",
                     "type": "string"
                 },
+                "movement_id": {
+                    "description": "ID записанного движения (для отмены через POST /movements/{id}/reverse)",
+                    "type": "integer"
+                },
                 "new_balance": {
                     "type": "number"
                 },
+                "version": {
+                    "description": "Версия кошелька после обновления",
+                    "type": "integer"
+                },
                 "wallet_number": {
                     "type": "string"
                 }
             }
         },
+        "currency-service_internal_models.UpdateWalletLimitsRequest": {
+            "type": "object",
+            "properties": {
+                "overrides": {
+                    "description": "Индивидуальные лимиты (полностью заменяют прежние)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/currency-service_internal_models.Limits"
+                        }
+                    ]
+                },
+                "tier": {
+                    "description": "Новый уровень кошелька (если не указан - не меняется)",
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "UUID пользователя, генерируется БД",
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                }
+            }
+        },
         "currency-service_internal_models.Wallet": {
             "type": "object",
             "properties": {
+                "available_balance": {
+                    "description": "Доступный баланс: учетный минус активные холды (может быть отрицательным в пределах кредита)",
+                    "type": "number"
+                },
+                "available_credit": {
+                    "description": "Неиспользованная часть кредитного лимита",
+                    "type": "number"
+                },
+                "balance": {
+                    "description": "Учетный баланс кошелька (включая зарезервированные средства)",
+                    "type": "number"
+                },
+                "credit_limit": {
+                    "description": "Разрешенный овердрафт: баланс может опускаться до -credit_limit",
+                    "type": "number"
+                },
+                "number": {
+                    "description": "Номер кошелька (7 знаков)",
+                    "type": "string"
+                },
+                "owner_id": {
+                    "description": "ID владельца (пусто у кошельков, созданных без пользователя)",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "active, frozen или closed",
+                    "type": "string"
+                },
+                "tier": {
+                    "description": "Уровень кошелька, определяет лимиты по умолчанию",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Увеличивается при каждом изменении кошелька, отдается как ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "currency-service_internal_models.WalletDetailsResponse": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "description": "Момент, на который рассчитан balance_as_of",
+                    "type": "string"
+                },
+                "available_balance": {
+                    "description": "Доступный баланс: учетный минус активные холды (может быть отрицательным в пределах кредита)",
+                    "type": "number"
+                },
+                "available_credit": {
+                    "description": "Неиспользованная часть кредитного лимита",
+                    "type": "number"
+                },
                 "balance": {
-                    "description": "Баланс кошелька",
+                    "description": "Учетный баланс кошелька (включая зарезервированные средства)",
+                    "type": "number"
+                },
+                "balance_as_of": {
+                    "description": "Учетный баланс на момент as_of",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credit_limit": {
+                    "description": "Разрешенный овердрафт: баланс может опускаться до -credit_limit",
                     "type": "number"
                 },
                 "number": {
                     "description": "Номер кошелька (7 знаков)",
                     "type": "string"
+                },
+                "owner_id": {
+                    "description": "ID владельца (пусто у кошельков, созданных без пользователя)",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "active, frozen или closed",
+                    "type": "string"
+                },
+                "tier": {
+                    "description": "Уровень кошелька, определяет лимиты по умолчанию",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Увеличивается при каждом изменении кошелька, отдается как ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "currency-service_internal_models.WalletInterestResponse": {
+            "type": "object",
+            "properties": {
+                "accruals": {
+                    "description": "Последние начисления (новые первыми)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/currency-service_internal_models.InterestAccrual"
+                    }
+                },
+                "accrued_unposted": {
+                    "description": "Начислено, но еще не зачислено на кошелек",
+                    "type": "number"
+                },
+                "plan": {
+                    "description": "Нет, если проценты не начисляются",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/currency-service_internal_models.InterestPlan"
+                        }
+                    ]
+                },
+                "wallet_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "currency-service_internal_models.WalletLimitsResponse": {
+            "type": "object",
+            "properties": {
+                "effective": {
+                    "description": "Лимиты, которые фактически применяются",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/currency-service_internal_models.Limits"
+                        }
+                    ]
+                },
+                "overrides": {
+                    "description": "Индивидуальные лимиты кошелька, имеют приоритет над лимитами уровня",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/currency-service_internal_models.Limits"
+                        }
+                    ]
+                },
+                "tier": {
+                    "type": "string"
+                },
+                "tier_limits": {
+                    "$ref": "#/definitions/currency-service_internal_models.Limits"
+                },
+                "wallet_number": {
+                    "type": "string"
                 }
             }
         }
     },
     "securityDefinitions": {
         "ApiKeyAuth": {
+            "description": "API-ключ (\"Bearer csk_...\") или JWT пользователя (\"Bearer \u003cтокен\u003e\"). Ключи выпускаются командой apikeys или через /admin/api-keys.",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
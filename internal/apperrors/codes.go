@@ -0,0 +1,132 @@
+// internal/apperrors/codes.go
+package apperrors
+
+// Code - стабильный машиночитаемый код ошибки. Коды являются частью API: их нельзя переименовывать,
+// можно только добавлять новые.
+type Code string
+
+// Общие коды
+const (
+	CodeInternal       Code = "INTERNAL_ERROR"  // Внутренняя ошибка (подробности клиенту не раскрываются)
+	CodeInvalidRequest Code = "INVALID_REQUEST" // Некорректное тело запроса или параметр
+)
+
+// Доступ
+const (
+	CodeUnauthorized      Code = "UNAUTHORIZED"
+	CodeInsufficientScope Code = "INSUFFICIENT_SCOPE"
+	CodeAPIKeyNotFound    Code = "API_KEY_NOT_FOUND"
+	CodeInvalidAPIKeyName Code = "INVALID_API_KEY_NAME"
+	CodeInvalidScope      Code = "INVALID_SCOPE"
+	CodeRateLimited       Code = "RATE_LIMITED"
+)
+
+// Курсы валют
+const (
+	CodeRateNotPositive  Code = "RATE_NOT_POSITIVE"
+	CodeNoRates          Code = "NO_RATES"
+	CodeRateNotAvailable Code = "RATE_NOT_AVAILABLE"
+)
+
+// Кошельки
+const (
+	CodeWalletNotFound             Code = "WALLET_NOT_FOUND"
+	CodeWalletExists               Code = "WALLET_EXISTS"
+	CodeInsufficientFunds          Code = "INSUFFICIENT_FUNDS"
+	CodeInvalidWalletNumber        Code = "INVALID_WALLET_NUMBER"
+	CodeInvalidCheckDigit          Code = "INVALID_CHECK_DIGIT"
+	CodeInvalidDepositAmount       Code = "INVALID_DEPOSIT_AMOUNT"
+	CodeWithdrawNonExistent        Code = "WITHDRAW_FROM_NONEXISTENT_WALLET"
+	CodeInvalidTransferAmount      Code = "INVALID_TRANSFER_AMOUNT"
+	CodeSameWalletTransfer         Code = "SAME_WALLET_TRANSFER"
+	CodeTargetWalletNotFound       Code = "TARGET_WALLET_NOT_FOUND"
+	CodeInvalidConvertAmount       Code = "INVALID_CONVERT_AMOUNT"
+	CodeSameWalletConversion       Code = "SAME_WALLET_CONVERSION"
+	CodeDestinationOwnerMismatch   Code = "DESTINATION_OWNER_MISMATCH"
+	CodeInvalidPageSize            Code = "INVALID_PAGE_SIZE"
+	CodeInvalidCursor              Code = "INVALID_CURSOR"
+	CodeInvalidSort                Code = "INVALID_SORT"
+	CodeInvalidWalletStatus        Code = "INVALID_WALLET_STATUS"
+	CodeInvalidListFilter          Code = "INVALID_LIST_FILTER"
+	CodeAsOfInFuture               Code = "AS_OF_IN_FUTURE"
+	CodeAsOfBeforeCreation         Code = "AS_OF_BEFORE_CREATION"
+	CodeWalletNumberSpaceExhausted Code = "WALLET_NUMBER_SPACE_EXHAUSTED"
+	CodeVersionMismatch            Code = "VERSION_MISMATCH"
+	CodeInvalidCreditLimit         Code = "INVALID_CREDIT_LIMIT"
+	CodeCreditLimitInUse           Code = "CREDIT_LIMIT_IN_USE"
+	CodeWalletFrozen               Code = "WALLET_FROZEN"
+	CodeWalletClosed               Code = "WALLET_CLOSED"
+	CodeWalletNotEmpty             Code = "WALLET_NOT_EMPTY"
+)
+
+// Пользователи
+const (
+	CodeUserNotFound        Code = "USER_NOT_FOUND"
+	CodeInvalidUserID       Code = "INVALID_USER_ID"
+	CodeInvalidUserName     Code = "INVALID_USER_NAME"
+	CodeWalletOwnerMismatch Code = "WALLET_OWNER_MISMATCH"
+	CodeWalletOwnerRequired Code = "WALLET_OWNER_REQUIRED"
+)
+
+// Пакетные операции
+const (
+	CodeEmptyBatch            Code = "EMPTY_BATCH"
+	CodeBatchTooLarge         Code = "BATCH_TOO_LARGE"
+	CodeInvalidBatchMode      Code = "INVALID_BATCH_MODE"
+	CodeInvalidBatchOperation Code = "INVALID_BATCH_OPERATION"
+	CodeInvalidBatchAmount    Code = "INVALID_BATCH_AMOUNT"
+)
+
+// Холды
+const (
+	CodeHoldNotFound           Code = "HOLD_NOT_FOUND"
+	CodeHoldNotActive          Code = "HOLD_NOT_ACTIVE"
+	CodeInvalidHoldAmount      Code = "INVALID_HOLD_AMOUNT"
+	CodeInvalidHoldTTL         Code = "INVALID_HOLD_TTL"
+	CodeCaptureExceedsHold     Code = "CAPTURE_EXCEEDS_HOLD"
+	CodeDuplicateHoldReference Code = "DUPLICATE_HOLD_REFERENCE"
+)
+
+// Лимиты
+const (
+	CodeLimitExceeded Code = "LIMIT_EXCEEDED"
+	CodeInvalidTier   Code = "INVALID_TIER"
+	CodeInvalidLimit  Code = "INVALID_LIMIT"
+)
+
+// Расписания
+const (
+	CodeScheduleNotFound         Code = "SCHEDULE_NOT_FOUND"
+	CodeScheduleNotActive        Code = "SCHEDULE_NOT_ACTIVE"
+	CodeInvalidScheduleOperation Code = "INVALID_SCHEDULE_OPERATION"
+	CodeInvalidScheduleAmount    Code = "INVALID_SCHEDULE_AMOUNT"
+	CodeInvalidRecurrence        Code = "INVALID_RECURRENCE"
+	CodeInvalidCronExpr          Code = "INVALID_CRON_EXPR"
+	CodeScheduleHasNoRuns        Code = "SCHEDULE_HAS_NO_RUNS"
+)
+
+// Движения и сверка
+const (
+	CodeMovementNotFound         Code = "MOVEMENT_NOT_FOUND"
+	CodeMovementAlreadyReversed  Code = "MOVEMENT_ALREADY_REVERSED"
+	CodeMovementNotReversible    Code = "MOVEMENT_NOT_REVERSIBLE"
+	CodeMovementPartOfConversion Code = "MOVEMENT_PART_OF_CONVERSION"
+	CodeReconciliationNotFound   Code = "RECONCILIATION_NOT_FOUND"
+)
+
+// Комиссии
+const (
+	CodeFeeRuleNotFound Code = "FEE_RULE_NOT_FOUND"
+	CodeInvalidPair     Code = "INVALID_PAIR"
+	CodeInvalidFeeRule  Code = "INVALID_FEE_RULE"
+)
+
+// Проценты
+const (
+	CodeInterestPlanNotFound   Code = "INTEREST_PLAN_NOT_FOUND"
+	CodeInterestPlanExists     Code = "INTEREST_PLAN_EXISTS"
+	CodeInvalidInterestPlan    Code = "INVALID_INTEREST_PLAN"
+	CodeInvalidDayCount        Code = "INVALID_DAY_COUNT"
+	CodeInterestDayNotClosed   Code = "INTEREST_DAY_NOT_CLOSED"
+	CodeInterestMonthNotClosed Code = "INTEREST_MONTH_NOT_CLOSED"
+)
@@ -0,0 +1,68 @@
+// internal/apperrors/errors.go
+package apperrors
+
+import "errors"
+
+// Error - доменная ошибка со стабильным кодом. Текст ошибки предназначен для человека и может меняться,
+// код - для программ: по нему клиенты и транспортный слой (HTTP) различают ошибки.
+type Error struct {
+	Code    Code
+	Message string
+	wrapped error // Более общая ошибка, с которой совпадает errors.Is
+}
+
+// New создает ошибку с кодом. Обычно используется для объявления ошибок-значений пакета.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap создает уточнение ошибки parent со своим кодом: errors.Is(err, parent) остается истинным,
+// а текст ошибки дополняется к тексту parent.
+func Wrap(parent error, code Code, message string) *Error {
+	return &Error{Code: code, Message: parent.Error() + ": " + message, wrapped: parent}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.wrapped
+}
+
+// ErrorCode возвращает код ошибки.
+func (e *Error) ErrorCode() Code {
+	return e.Code
+}
+
+// coder реализуют ошибки, у которых есть код (*Error и типы ошибок сервисов с собственными полями).
+type coder interface {
+	ErrorCode() Code
+}
+
+// detailer реализуют ошибки с дополнительными сведениями для клиента (например, service.LimitExceededError).
+type detailer interface {
+	ErrorDetails() map[string]any
+}
+
+// CodeOf возвращает код первой ошибки с кодом в цепочке err и CodeInternal, если такой нет.
+func CodeOf(err error) Code {
+	var c coder
+	if errors.As(err, &c) {
+		return c.ErrorCode()
+	}
+	return CodeInternal
+}
+
+// DetailsOf возвращает дополнительные сведения первой ошибки в цепочке err, у которой они есть.
+func DetailsOf(err error) map[string]any {
+	for err != nil {
+		if d, ok := err.(detailer); ok {
+			if details := d.ErrorDetails(); len(details) > 0 {
+				return details
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
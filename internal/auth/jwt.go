@@ -0,0 +1,154 @@
+// internal/auth/jwt.go
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken возвращается для любого токена, который не прошел проверку.
+var ErrInvalidToken = errors.New("недействительный токен")
+
+// minHS256SecretLength - минимальная длина секрета HS256 в байтах (RFC 7518, 3.2).
+const minHS256SecretLength = 32
+
+// JWTSettings - настройки проверки JWT. Должен быть задан хотя бы один источник ключей.
+type JWTSettings struct {
+	HS256SecretFile    string        // Файл с общим секретом HS256
+	RS256PublicKeyFile string        // PEM-файл с открытым ключом RS256
+	JWKSFile           string        // JWKS-файл с открытыми ключами RS256 (выбираются по kid)
+	Issuer             string        // Ожидаемый iss (пусто - не проверяется)
+	Audience           string        // Ожидаемый aud (пусто - не проверяется)
+	Leeway             time.Duration // Допустимое расхождение часов при проверке exp и nbf
+}
+
+// Enabled сообщает, задан ли хотя бы один источник ключей.
+func (s JWTSettings) Enabled() bool {
+	return s.HS256SecretFile != "" || s.RS256PublicKeyFile != "" || s.JWKSFile != ""
+}
+
+// Claims - утверждения токена, которые использует сервис. Subject - ID пользователя.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// JWTVerifier проверяет подпись и срок действия токенов и роль в них.
+type JWTVerifier struct {
+	hmacSecret []byte
+	rsaKeys    map[string]*rsa.PublicKey // По kid; ключ из PEM-файла хранится с пустым kid
+	parser     *jwt.Parser
+}
+
+// NewJWTVerifier загружает ключи из файлов настроек.
+func NewJWTVerifier(settings JWTSettings) (*JWTVerifier, error) {
+	if !settings.Enabled() {
+		return nil, errors.New("не задан ни один источник ключей JWT")
+	}
+	v := &JWTVerifier{rsaKeys: make(map[string]*rsa.PublicKey)}
+	var methods []string
+
+	if settings.HS256SecretFile != "" {
+		data, err := os.ReadFile(settings.HS256SecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать секрет HS256: %w", err)
+		}
+		v.hmacSecret = []byte(strings.TrimSpace(string(data)))
+		if len(v.hmacSecret) < minHS256SecretLength {
+			return nil, fmt.Errorf("секрет HS256 короче %d байт", minHS256SecretLength)
+		}
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if settings.RS256PublicKeyFile != "" {
+		data, err := os.ReadFile(settings.RS256PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать открытый ключ RS256: %w", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный открытый ключ RS256: %w", err)
+		}
+		v.rsaKeys[""] = key
+	}
+	if settings.JWKSFile != "" {
+		keys, err := loadJWKS(settings.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		for kid, key := range keys {
+			v.rsaKeys[kid] = key
+		}
+	}
+	if len(v.rsaKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	// Допустимы только настроенные алгоритмы: токен не может выбрать, как его проверять
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired(), jwt.WithLeeway(settings.Leeway)}
+	if settings.Issuer != "" {
+		options = append(options, jwt.WithIssuer(settings.Issuer))
+	}
+	if settings.Audience != "" {
+		options = append(options, jwt.WithAudience(settings.Audience))
+	}
+	v.parser = jwt.NewParser(options...)
+	return v, nil
+}
+
+// Verify проверяет токен и возвращает его утверждения. У токена должны быть срок действия,
+// субъект и известная роль.
+func (v *JWTVerifier) Verify(token string) (Claims, error) {
+	var claims Claims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.key); err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("%w: нет субъекта (sub)", ErrInvalidToken)
+	}
+	if _, ok := ScopesForRole(claims.Role); !ok {
+		return Claims{}, fmt.Errorf("%w: неизвестная роль %q", ErrInvalidToken, claims.Role)
+	}
+	return claims, nil
+}
+
+// key выбирает ключ проверки подписи по алгоритму и kid токена.
+func (v *JWTVerifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return v.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.rsaKeys[kid]; ok {
+			return key, nil
+		}
+		// Токен без kid подходит, если ключ один
+		if kid == "" && len(v.rsaKeys) == 1 {
+			for _, key := range v.rsaKeys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("неизвестный ключ подписи %q", kid)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый алгоритм %s", token.Method.Alg())
+	}
+}
+
+// SignHS256 подписывает токен общим секретом. Нужен для выпуска токенов в тестах и при локальной разработке.
+func SignHS256(secret []byte, claims Claims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
+
+// SignRS256 подписывает токен закрытым ключом RSA; kid попадает в заголовок токена, если не пустой.
+func SignRS256(key *rsa.PrivateKey, kid string, claims Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	return token.SignedString(key)
+}
@@ -0,0 +1,24 @@
+// internal/auth/roles.go
+package auth
+
+import "currency-service/internal/models"
+
+// Роли пользователей в JWT (claim role)
+const (
+	RoleAdmin    = "admin"    // Доступ ко всем эндпоинтам
+	RoleOperator = "operator" // Сотрудник: все, кроме административных эндпоинтов
+	RoleCustomer = "customer" // Клиент: чтение и списания только со своих кошельков
+)
+
+// roleScopes - права, которые дает роль. Они проверяются так же, как права API-ключей.
+var roleScopes = map[string][]string{
+	RoleAdmin:    models.APIKeyScopes,
+	RoleOperator: {models.ScopeRatesWrite, models.ScopeWalletsRead, models.ScopeWalletsWrite},
+	RoleCustomer: {models.ScopeWalletsRead, models.ScopeWalletsWrite},
+}
+
+// ScopesForRole возвращает права роли и false, если роль неизвестна.
+func ScopesForRole(role string) ([]string, bool) {
+	scopes, ok := roleScopes[role]
+	return scopes, ok
+}
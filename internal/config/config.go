@@ -5,11 +5,13 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 	// "github.com/spf13/viper" // Пример с Viper
 )
 
 type ServerConfig struct {
-	Port int
+	Port     int
+	GRPCPort int // Порт gRPC API (0 - gRPC API отключен)
 }
 
 type DBConfig struct {
@@ -20,9 +22,89 @@ type DBConfig struct {
 	Name     string
 }
 
+// HoldsConfig - настройки холдов (резервирования средств).
+type HoldsConfig struct {
+	DefaultTTL      time.Duration // Срок жизни холда, если клиент его не указал
+	ReleaseInterval time.Duration // Как часто фоновая задача освобождает истекшие холды
+}
+
+// SchedulerConfig - настройки выполнения запланированных операций.
+type SchedulerConfig struct {
+	Interval    time.Duration // Как часто фоновая задача ищет расписания, готовые к запуску
+	RetryDelay  time.Duration // Через сколько повторить неудачный запуск (например, при нехватке средств)
+	MaxFailures int           // После скольких неудач подряд расписание отключается
+}
+
+// WalletsConfig - настройки создания кошельков.
+type WalletsConfig struct {
+	ImplicitCreate bool // Создавать кошелек при пополнении несуществующего номера (POST /wallets/balance)
+}
+
+// ReconciliationConfig - настройки сверки балансов с историей движений.
+type ReconciliationConfig struct {
+	Interval  time.Duration // Как часто фоновая задача выполняет сверку (0 - не выполнять)
+	Tolerance float64       // Допустимое расхождение баланса с суммой движений
+}
+
+// ConversionConfig - настройки конвертации и комиссий.
+type ConversionConfig struct {
+	Pair            string // Валютная пара, к которой относятся курсы (используется для выбора правила комиссии)
+	FeeWalletNumber string // Кошелек, на который зачисляются комиссии (создается при запуске; пусто - комиссии не взимаются)
+}
+
+// InterestConfig - настройки начисления процентов.
+type InterestConfig struct {
+	Interval time.Duration // Как часто фоновая задача начисляет проценты за вчера и зачисляет прошлые месяцы (0 - не выполнять)
+}
+
+// TxConfig - настройки транзакций БД и повтора при конфликтах.
+type TxConfig struct {
+	Isolation      string        // Уровень изоляции: read_committed, repeatable_read, serializable (пусто - уровень сервера БД)
+	MaxAttempts    int           // Сколько раз выполнять транзакцию при конфликте сериализации, взаимоблокировке или гонке создания
+	RetryBaseDelay time.Duration // Пауза перед первым повтором (удваивается с каждой попыткой)
+	RetryMaxDelay  time.Duration // Максимальная пауза между попытками
+}
+
+// AuthConfig - настройки проверки JWT пользователей. Если не задан ни один файл ключей, JWT не принимаются
+// и доступ возможен только по API-ключам.
+type AuthConfig struct {
+	JWTHS256SecretFile    string        // Файл с общим секретом HS256
+	JWTRS256PublicKeyFile string        // PEM-файл с открытым ключом RS256
+	JWTJWKSFile           string        // JWKS-файл с открытыми ключами RS256
+	JWTIssuer             string        // Ожидаемый iss (пусто - не проверяется)
+	JWTAudience           string        // Ожидаемый aud (пусто - не проверяется)
+	JWTLeeway             time.Duration // Допустимое расхождение часов при проверке срока действия
+}
+
+// RateLimitConfig - настройки ограничения частоты запросов к API (квоты вида "N/s", "N/m" или "N/h").
+type RateLimitConfig struct {
+	Default         string        // Квота клиента на маршруты без своей квоты (пусто или "0" - без ограничения)
+	IP              string        // Квота одного IP-адреса на все запросы, расходуется до проверки ключа (пусто или "0" - без ограничения)
+	Routes          string        // Квоты маршрутов: "POST /api/v1/wallets/convert=30/m; GET /api/v1/wallets/{number}=120/m"
+	Store           string        // Где хранить счетчики: memory (в каждой реплике свои) или postgres (общие для реплик)
+	CleanupInterval time.Duration // Как часто удалять счетчики неактивных клиентов
+}
+
+// HTTPCacheConfig - заголовки Cache-Control для GET-запросов к API.
+type HTTPCacheConfig struct {
+	Default string // Значение для маршрутов без своего правила (пусто - заголовок не добавляется)
+	Routes  string // Значения маршрутов: "GET /api/v1/rates/average=private, max-age=30; GET /api/v1/wallets=private, no-cache" (public недопустим)
+}
+
 type Config struct {
-	Server ServerConfig
-	DB     DBConfig
+	Server    ServerConfig
+	DB        DBConfig
+	Holds     HoldsConfig
+	Scheduler SchedulerConfig
+	Wallets   WalletsConfig
+
+	Reconciliation ReconciliationConfig
+	Conversion     ConversionConfig
+	Interest       InterestConfig
+	Tx             TxConfig
+	Auth           AuthConfig
+	RateLimit      RateLimitConfig
+	HTTPCache      HTTPCacheConfig
 }
 
 // LoadConfig загружает конфигурацию из переменных окружения (простой пример).
@@ -30,10 +112,32 @@ func LoadConfig() Config {
 	// Используйте Viper или аналоги для более надежной загрузки
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	serverPort, _ := strconv.Atoi(getEnv("SERVER_PORT", "8080"))
+	grpcPort, _ := strconv.Atoi(getEnv("GRPC_PORT", "9090"))
+	holdTTL, _ := strconv.Atoi(getEnv("HOLD_DEFAULT_TTL_SECONDS", "604800")) // 7 дней
+	holdReleaseInterval, _ := strconv.Atoi(getEnv("HOLD_RELEASE_INTERVAL_SECONDS", "60"))
+	schedulerInterval, _ := strconv.Atoi(getEnv("SCHEDULER_INTERVAL_SECONDS", "30"))
+	scheduleRetryDelay, _ := strconv.Atoi(getEnv("SCHEDULE_RETRY_DELAY_SECONDS", "3600"))
+	scheduleMaxFailures, _ := strconv.Atoi(getEnv("SCHEDULE_MAX_FAILURES", "3"))
+	reconciliationInterval, _ := strconv.Atoi(getEnv("RECONCILIATION_INTERVAL_SECONDS", "3600"))
+	interestInterval, _ := strconv.Atoi(getEnv("INTEREST_INTERVAL_SECONDS", "3600"))
+	txMaxAttempts, _ := strconv.Atoi(getEnv("TX_MAX_ATTEMPTS", "3"))
+	txRetryBaseDelay, _ := strconv.Atoi(getEnv("TX_RETRY_BASE_DELAY_MS", "10"))
+	txRetryMaxDelay, _ := strconv.Atoi(getEnv("TX_RETRY_MAX_DELAY_MS", "200"))
+	jwtLeeway, _ := strconv.Atoi(getEnv("JWT_LEEWAY_SECONDS", "30"))
+	rateLimitCleanupInterval, _ := strconv.Atoi(getEnv("RATE_LIMIT_CLEANUP_INTERVAL_SECONDS", "300"))
+	reconciliationTolerance, err := strconv.ParseFloat(getEnv("RECONCILIATION_TOLERANCE", "0.01"), 64)
+	if err != nil || reconciliationTolerance < 0 {
+		reconciliationTolerance = 0.01
+	}
+	implicitCreate, err := strconv.ParseBool(getEnv("WALLET_IMPLICIT_CREATE", "false"))
+	if err != nil {
+		implicitCreate = false
+	}
 
 	return Config{
 		Server: ServerConfig{
-			Port: serverPort,
+			Port:     serverPort,
+			GRPCPort: grpcPort,
 		},
 		DB: DBConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -42,6 +146,54 @@ func LoadConfig() Config {
 			Password: getEnv("DB_PASSWORD", "password"),
 			Name:     getEnv("DB_NAME", "currency_db"),
 		},
+		Holds: HoldsConfig{
+			DefaultTTL:      time.Duration(holdTTL) * time.Second,
+			ReleaseInterval: time.Duration(holdReleaseInterval) * time.Second,
+		},
+		Scheduler: SchedulerConfig{
+			Interval:    time.Duration(schedulerInterval) * time.Second,
+			RetryDelay:  time.Duration(scheduleRetryDelay) * time.Second,
+			MaxFailures: scheduleMaxFailures,
+		},
+		Wallets: WalletsConfig{
+			ImplicitCreate: implicitCreate,
+		},
+		Reconciliation: ReconciliationConfig{
+			Interval:  time.Duration(reconciliationInterval) * time.Second,
+			Tolerance: reconciliationTolerance,
+		},
+		Conversion: ConversionConfig{
+			Pair:            getEnv("CONVERSION_PAIR", "USD/RUB"),
+			FeeWalletNumber: getEnv("FEE_WALLET_NUMBER", "9999996"),
+		},
+		Interest: InterestConfig{
+			Interval: time.Duration(interestInterval) * time.Second,
+		},
+		Tx: TxConfig{
+			Isolation:      getEnv("TX_ISOLATION_LEVEL", "read_committed"),
+			MaxAttempts:    txMaxAttempts,
+			RetryBaseDelay: time.Duration(txRetryBaseDelay) * time.Millisecond,
+			RetryMaxDelay:  time.Duration(txRetryMaxDelay) * time.Millisecond,
+		},
+		Auth: AuthConfig{
+			JWTHS256SecretFile:    getEnv("JWT_HS256_SECRET_FILE", ""),
+			JWTRS256PublicKeyFile: getEnv("JWT_RS256_PUBLIC_KEY_FILE", ""),
+			JWTJWKSFile:           getEnv("JWT_JWKS_FILE", ""),
+			JWTIssuer:             getEnv("JWT_ISSUER", ""),
+			JWTAudience:           getEnv("JWT_AUDIENCE", ""),
+			JWTLeeway:             time.Duration(jwtLeeway) * time.Second,
+		},
+		RateLimit: RateLimitConfig{
+			Default:         getEnv("RATE_LIMIT_DEFAULT", "600/m"),
+			IP:              getEnv("RATE_LIMIT_IP", "1200/m"),
+			Routes:          getEnv("RATE_LIMIT_ROUTES", ""),
+			Store:           getEnv("RATE_LIMIT_STORE", "memory"),
+			CleanupInterval: time.Duration(rateLimitCleanupInterval) * time.Second,
+		},
+		HTTPCache: HTTPCacheConfig{
+			Default: getEnv("CACHE_CONTROL_DEFAULT", "private, no-cache"),
+			Routes:  getEnv("CACHE_CONTROL_ROUTES", ""),
+		},
 	}
 }
 
@@ -54,7 +54,7 @@ func MigrateSchema(db *sql.DB) error {
 	queryWallets := `
     CREATE TABLE IF NOT EXISTS wallets (
         wallet_number VARCHAR(7) PRIMARY KEY, -- Номер кошелька как строка из 7 символов, первичный ключ
-        balance REAL NOT NULL DEFAULT 0 CHECK (balance >= 0), -- Баланс, не может быть отрицательным
+        balance REAL NOT NULL DEFAULT 0 CHECK (balance >= 0), -- Баланс (ниже нуля - только в пределах кредитного лимита, см. ниже)
         created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
         updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
     );
@@ -83,5 +83,390 @@ func MigrateSchema(db *sql.DB) error {
 	}
 	log.Println("Таблица 'wallets' инициализирована (или уже существует)")
 
+	// Таблица пользователей (владельцев кошельков) и привязка кошельков к владельцу
+	queryUsers := `
+    CREATE TABLE IF NOT EXISTS users (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+        first_name VARCHAR(100) NOT NULL,
+        last_name VARCHAR(100) NOT NULL,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+
+    -- Владелец кошелька. NULL допустим для кошельков, созданных до появления пользователей
+    ALTER TABLE wallets ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(id);
+    CREATE INDEX IF NOT EXISTS idx_wallets_owner_id ON wallets (owner_id);
+    `
+	_, err = db.Exec(queryUsers)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (users): %w", err)
+	}
+	log.Println("Таблица 'users' инициализирована (или уже существует)")
+
+	// Таблица холдов (резервирование средств без списания)
+	queryHolds := `
+    CREATE TABLE IF NOT EXISTS wallet_holds (
+        id BIGSERIAL PRIMARY KEY,
+        wallet_number VARCHAR(7) NOT NULL REFERENCES wallets(wallet_number),
+        amount REAL NOT NULL CHECK (amount > 0),
+        captured_amount REAL NOT NULL DEFAULT 0 CHECK (captured_amount >= 0),
+        reference VARCHAR(100) NOT NULL DEFAULT '',
+        status VARCHAR(16) NOT NULL DEFAULT 'active',
+        expires_at TIMESTAMPTZ NOT NULL,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+
+    -- Индекс для расчета доступного баланса и поиска истекших холдов
+    CREATE INDEX IF NOT EXISTS idx_wallet_holds_active ON wallet_holds (wallet_number, expires_at) WHERE status = 'active';
+    -- Reference уникален в пределах кошелька (если указан)
+    CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_holds_reference ON wallet_holds (wallet_number, reference) WHERE reference <> '';
+
+    DROP TRIGGER IF EXISTS update_wallet_holds_updated_at ON wallet_holds;
+    CREATE TRIGGER update_wallet_holds_updated_at
+    BEFORE UPDATE ON wallet_holds
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+    `
+	_, err = db.Exec(queryHolds)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (wallet_holds): %w", err)
+	}
+	log.Println("Таблица 'wallet_holds' инициализирована (или уже существует)")
+
+	// История движений по кошелькам (каждое изменение баланса)
+	queryMovements := `
+    CREATE TABLE IF NOT EXISTS wallet_movements (
+        id BIGSERIAL PRIMARY KEY,
+        wallet_number VARCHAR(7) NOT NULL REFERENCES wallets(wallet_number),
+        kind VARCHAR(32) NOT NULL,
+        amount REAL NOT NULL, -- Со знаком: > 0 зачисление, < 0 списание
+        balance_after REAL NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_wallet_movements_wallet_created ON wallet_movements (wallet_number, created_at);
+
+    -- Кошельки без истории (созданные до ее появления) получают движение с начальным балансом.
+    -- Оно датируется созданием кошелька: иначе баланс на момент между созданием и последним
+    -- изменением (as_of) был бы нулевым
+    INSERT INTO wallet_movements (wallet_number, kind, amount, balance_after, created_at)
+    SELECT w.wallet_number, 'opening_balance', w.balance, w.balance, COALESCE(w.created_at, w.updated_at, CURRENT_TIMESTAMP)
+    FROM wallets w
+    WHERE NOT EXISTS (SELECT 1 FROM wallet_movements m WHERE m.wallet_number = w.wallet_number);
+    `
+	_, err = db.Exec(queryMovements)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (wallet_movements): %w", err)
+	}
+	log.Println("Таблица 'wallet_movements' инициализирована (или уже существует)")
+
+	// Лимиты на списания: по уровню кошелька и индивидуальные
+	queryLimits := `
+    ALTER TABLE wallets ADD COLUMN IF NOT EXISTS tier VARCHAR(32) NOT NULL DEFAULT 'standard';
+
+    -- NULL в колонке лимита означает, что лимит не установлен
+    CREATE TABLE IF NOT EXISTS tier_limits (
+        tier VARCHAR(32) PRIMARY KEY,
+        max_single_withdrawal REAL CHECK (max_single_withdrawal >= 0),
+        daily_withdrawal_total REAL CHECK (daily_withdrawal_total >= 0),
+        monthly_withdrawal_total REAL CHECK (monthly_withdrawal_total >= 0),
+        daily_conversion_count INTEGER CHECK (daily_conversion_count >= 0)
+    );
+
+    CREATE TABLE IF NOT EXISTS wallet_limit_overrides (
+        wallet_number VARCHAR(7) PRIMARY KEY REFERENCES wallets(wallet_number),
+        max_single_withdrawal REAL CHECK (max_single_withdrawal >= 0),
+        daily_withdrawal_total REAL CHECK (daily_withdrawal_total >= 0),
+        monthly_withdrawal_total REAL CHECK (monthly_withdrawal_total >= 0),
+        daily_conversion_count INTEGER CHECK (daily_conversion_count >= 0)
+    );
+    `
+	_, err = db.Exec(queryLimits)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (limits): %w", err)
+	}
+	log.Println("Таблицы лимитов инициализированы (или уже существуют)")
+
+	// Запланированные операции (постоянные поручения) и история их запусков
+	querySchedules := `
+    CREATE TABLE IF NOT EXISTS wallet_schedules (
+        id BIGSERIAL PRIMARY KEY,
+        operation VARCHAR(32) NOT NULL,
+        wallet_number VARCHAR(7) NOT NULL,
+        target_wallet_number VARCHAR(7) NOT NULL DEFAULT '',
+        amount REAL NOT NULL,
+        user_id VARCHAR(36) NOT NULL DEFAULT '',
+        recurrence VARCHAR(16) NOT NULL,
+        cron_expr VARCHAR(100) NOT NULL DEFAULT '',
+        start_at TIMESTAMPTZ NOT NULL,
+        next_run_at TIMESTAMPTZ,
+        last_run_at TIMESTAMPTZ,
+        status VARCHAR(16) NOT NULL DEFAULT 'active',
+        consecutive_failures INTEGER NOT NULL DEFAULT 0,
+        disabled_reason TEXT NOT NULL DEFAULT '',
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+
+    -- Создатель расписания: запуски выполняются с его правами. У расписаний, созданных раньше,
+    -- создатель неизвестен, поэтому они считаются созданными не сотрудником
+    ALTER TABLE wallet_schedules ADD COLUMN IF NOT EXISTS created_by VARCHAR(64) NOT NULL DEFAULT '';
+    ALTER TABLE wallet_schedules ADD COLUMN IF NOT EXISTS created_by_staff BOOLEAN NOT NULL DEFAULT FALSE;
+
+    -- Индекс для поиска расписаний, готовых к запуску
+    CREATE INDEX IF NOT EXISTS idx_wallet_schedules_due ON wallet_schedules (next_run_at) WHERE status = 'active';
+
+    DROP TRIGGER IF EXISTS update_wallet_schedules_updated_at ON wallet_schedules;
+    CREATE TRIGGER update_wallet_schedules_updated_at
+    BEFORE UPDATE ON wallet_schedules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+    CREATE TABLE IF NOT EXISTS wallet_schedule_runs (
+        id BIGSERIAL PRIMARY KEY,
+        schedule_id BIGINT NOT NULL REFERENCES wallet_schedules(id),
+        status VARCHAR(16) NOT NULL,
+        error TEXT NOT NULL DEFAULT '',
+        ran_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_wallet_schedule_runs_schedule ON wallet_schedule_runs (schedule_id, ran_at);
+    `
+	_, err = db.Exec(querySchedules)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (wallet_schedules): %w", err)
+	}
+	log.Println("Таблицы расписаний инициализированы (или уже существуют)")
+
+	// Статус кошелька и индексы для постраничного списка кошельков (фильтры и keyset-пагинация)
+	queryWalletListing := `
+    ALTER TABLE wallets ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'frozen', 'closed'));
+
+    CREATE INDEX IF NOT EXISTS idx_wallets_created_at_number ON wallets (created_at, wallet_number);
+    CREATE INDEX IF NOT EXISTS idx_wallets_balance_number ON wallets (balance, wallet_number);
+    CREATE INDEX IF NOT EXISTS idx_wallets_status ON wallets (status);
+    `
+	_, err = db.Exec(queryWalletListing)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (wallets listing): %w", err)
+	}
+	log.Println("Индексы списка кошельков инициализированы (или уже существуют)")
+
+	// Сверка балансов кошельков с историей движений
+	queryReconciliation := `
+    CREATE TABLE IF NOT EXISTS reconciliation_runs (
+        id BIGSERIAL PRIMARY KEY,
+        started_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        finished_at TIMESTAMPTZ,
+        wallets_checked INTEGER NOT NULL DEFAULT 0,
+        discrepancies_found INTEGER NOT NULL DEFAULT 0,
+        error TEXT NOT NULL DEFAULT ''
+    );
+
+    CREATE TABLE IF NOT EXISTS reconciliation_discrepancies (
+        id BIGSERIAL PRIMARY KEY,
+        run_id BIGINT NOT NULL REFERENCES reconciliation_runs(id),
+        wallet_number VARCHAR(7) NOT NULL REFERENCES wallets(wallet_number),
+        stored_balance DOUBLE PRECISION NOT NULL,
+        computed_balance DOUBLE PRECISION NOT NULL,
+        difference DOUBLE PRECISION NOT NULL,
+        detected_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_reconciliation_discrepancies_run ON reconciliation_discrepancies (run_id);
+    CREATE INDEX IF NOT EXISTS idx_reconciliation_discrepancies_wallet ON reconciliation_discrepancies (wallet_number);
+    `
+	_, err = db.Exec(queryReconciliation)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (reconciliation): %w", err)
+	}
+	log.Println("Таблицы сверки инициализированы (или уже существуют)")
+
+	// Версия кошелька для оптимистичной блокировки (ETag / If-Match).
+	// Увеличивается триггером при любом изменении строки, поэтому ее не нужно обновлять в каждом запросе.
+	queryWalletVersion := `
+    ALTER TABLE wallets ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+    CREATE OR REPLACE FUNCTION increment_wallet_version()
+    RETURNS TRIGGER AS $$
+    BEGIN
+       NEW.version = OLD.version + 1;
+       RETURN NEW;
+    END;
+    $$ language 'plpgsql';
+
+    DROP TRIGGER IF EXISTS increment_wallets_version ON wallets;
+    CREATE TRIGGER increment_wallets_version
+    BEFORE UPDATE ON wallets
+    FOR EACH ROW
+    EXECUTE FUNCTION increment_wallet_version();
+    `
+	_, err = db.Exec(queryWalletVersion)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (wallet version): %w", err)
+	}
+	log.Println("Версионирование кошельков инициализировано")
+
+	// Отмена движений: курс и исходная сумма конвертации для точной компенсации и связь отмены с оригиналом.
+	// conversion_id связывает зачисление получателю и комиссию со списанием конвертации: они отменяются только вместе.
+	// Уникальный индекс не дает отменить одно движение дважды даже при гонке.
+	queryReversals := `
+    ALTER TABLE wallet_movements ADD COLUMN IF NOT EXISTS rate DOUBLE PRECISION;
+    ALTER TABLE wallet_movements ADD COLUMN IF NOT EXISTS source_amount DOUBLE PRECISION;
+    ALTER TABLE wallet_movements ADD COLUMN IF NOT EXISTS reversal_of BIGINT REFERENCES wallet_movements(id);
+    ALTER TABLE wallet_movements ADD COLUMN IF NOT EXISTS conversion_id BIGINT REFERENCES wallet_movements(id);
+
+    CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_movements_reversal_of ON wallet_movements (reversal_of)
+        WHERE reversal_of IS NOT NULL;
+    CREATE INDEX IF NOT EXISTS idx_wallet_movements_conversion_id ON wallet_movements (conversion_id)
+        WHERE conversion_id IS NOT NULL;
+    `
+	_, err = db.Exec(queryReversals)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (movement reversals): %w", err)
+	}
+	log.Println("Колонки отмены движений инициализированы (или уже существуют)")
+
+	// Комиссии за конвертацию по валютной паре и уровню кошелька ('*' - любые)
+	queryFees := `
+    CREATE TABLE IF NOT EXISTS conversion_fee_rules (
+        id BIGSERIAL PRIMARY KEY,
+        pair VARCHAR(16) NOT NULL DEFAULT '*',
+        tier VARCHAR(32) NOT NULL DEFAULT '*',
+        percent DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (percent >= 0 AND percent < 100),
+        fixed DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (fixed >= 0),
+        min_fee DOUBLE PRECISION CHECK (min_fee >= 0),
+        max_fee DOUBLE PRECISION CHECK (max_fee >= 0),
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (pair, tier)
+    );
+    `
+	_, err = db.Exec(queryFees)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (conversion_fee_rules): %w", err)
+	}
+	log.Println("Таблица 'conversion_fee_rules' инициализирована (или уже существует)")
+
+	// Проценты: планы, назначение плана кошельку (текущее и история) и ежедневные начисления.
+	// Первичный ключ (wallet_number, accrual_date) не дает начислить проценты за один день дважды.
+	queryInterest := `
+    CREATE TABLE IF NOT EXISTS interest_plans (
+        id BIGSERIAL PRIMARY KEY,
+        name VARCHAR(64) NOT NULL UNIQUE,
+        annual_rate DOUBLE PRECISION NOT NULL CHECK (annual_rate >= 0 AND annual_rate <= 100),
+        day_count VARCHAR(8) NOT NULL CHECK (day_count IN ('ACT/365', 'ACT/360', 'ACT/ACT', '30/360')),
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+    ALTER TABLE wallets ADD COLUMN IF NOT EXISTS interest_plan_id BIGINT REFERENCES interest_plans(id);
+    ALTER TABLE wallets ADD COLUMN IF NOT EXISTS interest_plan_assigned_at TIMESTAMPTZ;
+
+    CREATE TABLE IF NOT EXISTS interest_accruals (
+        wallet_number VARCHAR(7) NOT NULL REFERENCES wallets(wallet_number),
+        accrual_date DATE NOT NULL,
+        plan_id BIGINT NOT NULL REFERENCES interest_plans(id),
+        balance DOUBLE PRECISION NOT NULL,
+        annual_rate DOUBLE PRECISION NOT NULL,
+        day_count VARCHAR(8) NOT NULL,
+        amount DOUBLE PRECISION NOT NULL,
+        posted_at TIMESTAMPTZ,
+        movement_id BIGINT REFERENCES wallet_movements(id),
+        PRIMARY KEY (wallet_number, accrual_date)
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_interest_accruals_unposted ON interest_accruals (accrual_date)
+        WHERE posted_at IS NULL;
+
+    -- История назначений: за каждый день начисляется план, действовавший на конец дня (plan_id NULL - проценты отключены)
+    CREATE TABLE IF NOT EXISTS wallet_interest_plan_history (
+        id BIGSERIAL PRIMARY KEY,
+        wallet_number VARCHAR(7) NOT NULL REFERENCES wallets(wallet_number),
+        plan_id BIGINT REFERENCES interest_plans(id),
+        assigned_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_wallet_interest_plan_history_wallet
+        ON wallet_interest_plan_history (wallet_number, assigned_at);
+
+    -- Планы, назначенные до появления истории, переносятся в нее со временем назначения
+    INSERT INTO wallet_interest_plan_history (wallet_number, plan_id, assigned_at)
+    SELECT w.wallet_number, w.interest_plan_id, COALESCE(w.interest_plan_assigned_at, CURRENT_TIMESTAMP)
+    FROM wallets w
+    WHERE w.interest_plan_id IS NOT NULL
+      AND NOT EXISTS (SELECT 1 FROM wallet_interest_plan_history h WHERE h.wallet_number = w.wallet_number);
+    `
+	_, err = db.Exec(queryInterest)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (interest): %w", err)
+	}
+	log.Println("Таблицы процентов инициализированы (или уже существуют)")
+
+	// Кредитные лимиты: баланс может опускаться до -credit_limit. Без лимита (0) остается нулевой порог.
+	// Тип REAL, как у balance, чтобы сравнение на границе лимита не зависело от округления.
+	queryCredit := `
+    ALTER TABLE wallets ADD COLUMN IF NOT EXISTS credit_limit REAL NOT NULL DEFAULT 0 CHECK (credit_limit >= 0);
+    ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_balance_check;
+
+    DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'wallets_balance_within_credit_limit') THEN
+            ALTER TABLE wallets ADD CONSTRAINT wallets_balance_within_credit_limit CHECK (balance >= -credit_limit);
+        END IF;
+    END $$;
+    `
+	_, err = db.Exec(queryCredit)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (credit limits): %w", err)
+	}
+	log.Println("Кредитные лимиты кошельков инициализированы")
+
+	// API-ключи: хранится только SHA-256 хэш ключа, prefix - для опознания ключа в списке
+	queryAPIKeys := `
+    CREATE TABLE IF NOT EXISTS api_keys (
+        id BIGSERIAL PRIMARY KEY,
+        name VARCHAR(64) NOT NULL,
+        prefix VARCHAR(16) NOT NULL,
+        key_hash CHAR(64) NOT NULL UNIQUE,
+        scopes TEXT[] NOT NULL DEFAULT '{}',
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        revoked_at TIMESTAMPTZ
+    );
+    `
+	_, err = db.Exec(queryAPIKeys)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (api_keys): %w", err)
+	}
+	log.Println("Таблица 'api_keys' инициализирована (или уже существует)")
+
+	// Ведра токенов ограничения частоты запросов, общие для всех реплик сервиса
+	queryRateLimits := `
+    CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+        key TEXT PRIMARY KEY,
+        tokens DOUBLE PRECISION NOT NULL,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+    );
+    CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets(updated_at);
+    `
+	_, err = db.Exec(queryRateLimits)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (rate_limit_buckets): %w", err)
+	}
+	log.Println("Таблица 'rate_limit_buckets' инициализирована (или уже существует)")
+
+	// Версии данных для ETag считаются по самим таблицам (см. GetRatesVersion и GetWalletsVersion).
+	// Общий счетчик версий в одной строке сериализовал все записи в кошельки, поэтому он удаляется
+	queryDropDataVersions := `
+    DROP TRIGGER IF EXISTS bump_rates_data_version ON rates;
+    DROP TRIGGER IF EXISTS bump_wallets_data_version ON wallets;
+    DROP TRIGGER IF EXISTS bump_wallet_holds_data_version ON wallet_holds;
+    DROP FUNCTION IF EXISTS bump_data_version();
+    DROP TABLE IF EXISTS data_versions;
+    `
+	_, err = db.Exec(queryDropDataVersions)
+	if err != nil {
+		return fmt.Errorf("ошибка инициализации схемы БД (data_versions): %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,133 @@
+// internal/grpcserver/auth.go
+package grpcserver
+
+import (
+	"context"
+	"currency-service/internal/auth"
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"fmt"
+	"log"
+	"strings"
+
+	currencyv1 "currency-service/api/currency/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// methodScopes - права, которые требуются для вызова unary-методов (пусто - достаточно любого ключа или токена).
+// Методы, которых здесь нет, недоступны. Потоковый сервис reflection авторизации не требует.
+var methodScopes = map[string]string{
+	currencyv1.RateService_CreateRate_FullMethodName:     models.ScopeRatesWrite,
+	currencyv1.RateService_GetLatestRate_FullMethodName:  "",
+	currencyv1.RateService_GetAverageRate_FullMethodName: "",
+
+	currencyv1.WalletService_GetWallet_FullMethodName:        models.ScopeWalletsRead,
+	currencyv1.WalletService_ListWallets_FullMethodName:      models.ScopeWalletsRead,
+	currencyv1.WalletService_CreateWallet_FullMethodName:     models.ScopeWalletsWrite,
+	currencyv1.WalletService_UpdateBalance_FullMethodName:    models.ScopeWalletsWrite,
+	currencyv1.WalletService_ConvertAndDeduct_FullMethodName: models.ScopeWalletsWrite,
+}
+
+// credentialsFromContext возвращает значение метаданных authorization без схемы (как заголовок Authorization в REST).
+func credentialsFromContext(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+	header := strings.TrimSpace(values[0])
+	if scheme, value, ok := strings.Cut(header, " "); ok {
+		if strings.EqualFold(scheme, "Bearer") || strings.EqualFold(scheme, "ApiKey") {
+			return strings.TrimSpace(value)
+		}
+	}
+	return header
+}
+
+// caller - кто выполняет вызов.
+type caller struct {
+	scopes []string // Права ключа или роли
+	client string   // Чью квоту запросов расходует вызов: key:<ID> или user:<sub>, как в REST
+	staff  bool     // Сотрудник (роль operator или admin, API-ключ с правом admin - как в REST)
+}
+
+// callerContextKey - ключ контекста вызова, под которым authInterceptor сохраняет caller.
+type callerContextKey struct{}
+
+// callerFromContext возвращает, кто выполняет вызов (false - вызов не прошел authInterceptor).
+func callerFromContext(ctx context.Context) (caller, bool) {
+	c, ok := ctx.Value(callerContextKey{}).(caller)
+	return c, ok
+}
+
+// isStaff сообщает, что вызов выполняет сотрудник: он может списывать с кошельков клиентов без user_id владельца.
+func isStaff(ctx context.Context) bool {
+	c, ok := callerFromContext(ctx)
+	return ok && c.staff
+}
+
+// authenticate проверяет API-ключ или JWT вызова и возвращает, кто его выполняет.
+// gRPC API предназначен для внутренних сервисов, поэтому клиентам (роль customer) он недоступен:
+// проверка владельца кошелька есть только в REST API.
+func authenticate(ctx context.Context, apiKeys service.APIKeyService, verifier *auth.JWTVerifier) (caller, error) {
+	credentials := credentialsFromContext(ctx)
+	if credentials == "" {
+		return caller{}, service.ErrUnauthorized
+	}
+	if service.IsAPIKeyToken(credentials) || verifier == nil {
+		key, err := apiKeys.Authenticate(ctx, credentials)
+		if err != nil {
+			return caller{}, err
+		}
+		return caller{scopes: key.Scopes, client: fmt.Sprintf("key:%d", key.ID), staff: hasScope(key.Scopes, models.ScopeAdmin)}, nil
+	}
+
+	claims, err := verifier.Verify(credentials)
+	if err != nil {
+		log.Printf("Отклонен вызов gRPC: %v\n", err)
+		return caller{}, service.ErrUnauthorized
+	}
+	if claims.Role == auth.RoleCustomer {
+		return caller{}, fmt.Errorf("%w: gRPC API недоступен клиентам", service.ErrInsufficientScope)
+	}
+	scopes, _ := auth.ScopesForRole(claims.Role)
+	return caller{
+		scopes: scopes,
+		client: "user:" + claims.Subject,
+		staff:  claims.Role == auth.RoleOperator || claims.Role == auth.RoleAdmin,
+	}, nil
+}
+
+// hasScope сообщает, есть ли scope среди scopes.
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// authInterceptor пропускает только вызовы с действующим API-ключом или JWT в метаданных authorization,
+// у которых есть право, требуемое методом (см. methodScopes), и сохраняет caller в контексте вызова.
+func authInterceptor(apiKeys service.APIKeyService, verifier *auth.JWTVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		scope, known := methodScopes[info.FullMethod]
+		if !known {
+			return nil, toStatus(ctx, fmt.Errorf("%w: метод %s не описан в правах доступа", service.ErrInsufficientScope, info.FullMethod))
+		}
+
+		c, err := authenticate(ctx, apiKeys, verifier)
+		if err != nil {
+			log.Printf("Отклонен вызов %s: %v\n", info.FullMethod, err)
+			return nil, toStatus(ctx, err)
+		}
+		if scope != "" && !hasScope(c.scopes, scope) {
+			log.Printf("Отклонен вызов %s: нет права %s\n", info.FullMethod, scope)
+			return nil, toStatus(ctx, fmt.Errorf("%w: требуется %s", service.ErrInsufficientScope, scope))
+		}
+		return handler(context.WithValue(ctx, callerContextKey{}, c), req)
+	}
+}
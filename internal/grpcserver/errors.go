@@ -0,0 +1,122 @@
+// internal/grpcserver/errors.go
+package grpcserver
+
+import (
+	"context"
+	"currency-service/internal/apperrors"
+	"currency-service/internal/i18n"
+	"fmt"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain - домен кодов ошибок в google.rpc.ErrorInfo.
+const errorDomain = "currency-service"
+
+// errorCodes сопоставляет коды доменных ошибок кодам статуса gRPC. Коды, которых здесь нет, отдаются как Internal.
+var errorCodes = map[apperrors.Code]codes.Code{
+	apperrors.CodeInvalidRequest: codes.InvalidArgument,
+
+	apperrors.CodeUnauthorized:      codes.Unauthenticated,
+	apperrors.CodeInsufficientScope: codes.PermissionDenied,
+	apperrors.CodeAPIKeyNotFound:    codes.NotFound,
+	apperrors.CodeInvalidAPIKeyName: codes.InvalidArgument,
+	apperrors.CodeInvalidScope:      codes.InvalidArgument,
+	apperrors.CodeRateLimited:       codes.ResourceExhausted,
+
+	apperrors.CodeRateNotPositive:  codes.InvalidArgument,
+	apperrors.CodeNoRates:          codes.NotFound,
+	apperrors.CodeRateNotAvailable: codes.Unavailable,
+
+	apperrors.CodeWalletNotFound:             codes.NotFound,
+	apperrors.CodeWalletExists:               codes.AlreadyExists,
+	apperrors.CodeInsufficientFunds:          codes.FailedPrecondition,
+	apperrors.CodeInvalidWalletNumber:        codes.InvalidArgument,
+	apperrors.CodeInvalidCheckDigit:          codes.InvalidArgument,
+	apperrors.CodeInvalidDepositAmount:       codes.InvalidArgument,
+	apperrors.CodeWithdrawNonExistent:        codes.InvalidArgument,
+	apperrors.CodeInvalidTransferAmount:      codes.InvalidArgument,
+	apperrors.CodeSameWalletTransfer:         codes.InvalidArgument,
+	apperrors.CodeTargetWalletNotFound:       codes.NotFound,
+	apperrors.CodeInvalidConvertAmount:       codes.InvalidArgument,
+	apperrors.CodeSameWalletConversion:       codes.InvalidArgument,
+	apperrors.CodeDestinationOwnerMismatch:   codes.PermissionDenied,
+	apperrors.CodeInvalidPageSize:            codes.InvalidArgument,
+	apperrors.CodeInvalidCursor:              codes.InvalidArgument,
+	apperrors.CodeInvalidSort:                codes.InvalidArgument,
+	apperrors.CodeInvalidWalletStatus:        codes.InvalidArgument,
+	apperrors.CodeInvalidListFilter:          codes.InvalidArgument,
+	apperrors.CodeAsOfInFuture:               codes.InvalidArgument,
+	apperrors.CodeAsOfBeforeCreation:         codes.InvalidArgument,
+	apperrors.CodeWalletNumberSpaceExhausted: codes.ResourceExhausted,
+	apperrors.CodeVersionMismatch:            codes.Aborted,
+	apperrors.CodeInvalidCreditLimit:         codes.InvalidArgument,
+	apperrors.CodeCreditLimitInUse:           codes.FailedPrecondition,
+	apperrors.CodeWalletFrozen:               codes.FailedPrecondition,
+	apperrors.CodeWalletClosed:               codes.FailedPrecondition,
+	apperrors.CodeWalletNotEmpty:             codes.FailedPrecondition,
+
+	apperrors.CodeUserNotFound:        codes.NotFound,
+	apperrors.CodeInvalidUserID:       codes.InvalidArgument,
+	apperrors.CodeInvalidUserName:     codes.InvalidArgument,
+	apperrors.CodeWalletOwnerMismatch: codes.PermissionDenied,
+	apperrors.CodeWalletOwnerRequired: codes.PermissionDenied,
+
+	apperrors.CodeLimitExceeded: codes.FailedPrecondition,
+	apperrors.CodeInvalidTier:   codes.InvalidArgument,
+	apperrors.CodeInvalidLimit:  codes.InvalidArgument,
+
+	apperrors.CodeInvalidPair:    codes.InvalidArgument,
+	apperrors.CodeInvalidFeeRule: codes.InvalidArgument,
+}
+
+// errorCode возвращает код статуса gRPC для ошибки сервиса.
+func errorCode(err error) codes.Code {
+	if code, ok := errorCodes[apperrors.CodeOf(err)]; ok {
+		return code
+	}
+	return codes.Internal
+}
+
+// requestLang возвращает язык сообщений по метаданным accept-language.
+func requestLang(ctx context.Context) i18n.Lang {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("accept-language"); len(values) > 0 {
+		return i18n.FromAcceptLanguage(values[0])
+	}
+	return i18n.Default
+}
+
+// toStatus преобразует ошибку сервиса в статус gRPC с сообщением на языке запроса.
+// Машиночитаемый код ошибки и ее подробности передаются в google.rpc.ErrorInfo.
+// Текст внутренних ошибок клиенту не раскрывается.
+func toStatus(ctx context.Context, err error) error {
+	code, message := errorCode(err), err.Error()
+	reason, details := apperrors.CodeOf(err), apperrors.DetailsOf(err)
+	if code == codes.Internal {
+		log.Printf("Внутренняя ошибка gRPC: %v\n", err)
+		message, reason, details = "Внутренняя ошибка сервера", apperrors.CodeInternal, nil
+	}
+
+	st := status.New(code, i18n.Message(requestLang(ctx), string(reason), message))
+	info := &errdetails.ErrorInfo{Reason: string(reason), Domain: errorDomain}
+	if len(details) > 0 {
+		info.Metadata = make(map[string]string, len(details))
+		for key, value := range details {
+			info.Metadata[key] = fmt.Sprint(value)
+		}
+	}
+	if withDetails, detailsErr := st.WithDetails(info); detailsErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// requestError возвращает статус InvalidArgument с кодом INVALID_REQUEST (аналог writeRequestError в REST).
+func requestError(ctx context.Context, message string) error {
+	return toStatus(ctx, apperrors.New(apperrors.CodeInvalidRequest, message))
+}
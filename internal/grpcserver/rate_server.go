@@ -0,0 +1,53 @@
+// internal/grpcserver/rate_server.go
+package grpcserver
+
+import (
+	"context"
+	"currency-service/internal/service"
+	"log"
+
+	currencyv1 "currency-service/api/currency/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RateServer реализует currencyv1.RateServiceServer поверх RateService.
+type RateServer struct {
+	currencyv1.UnimplementedRateServiceServer
+	rateService service.RateService
+}
+
+// NewRateServer создает gRPC-сервис курсов валют.
+func NewRateServer(svc service.RateService) *RateServer {
+	return &RateServer{rateService: svc}
+}
+
+// CreateRate сохраняет новый курс.
+func (s *RateServer) CreateRate(ctx context.Context, req *currencyv1.CreateRateRequest) (*currencyv1.CreateRateResponse, error) {
+	if err := s.rateService.CreateRate(ctx, req.GetValue()); err != nil {
+		log.Printf("Ошибка при вызове сервиса CreateRate (gRPC): %v\n", err)
+		return nil, toStatus(ctx, err)
+	}
+	return &currencyv1.CreateRateResponse{Message: "Курс успешно добавлен"}, nil
+}
+
+// GetLatestRate возвращает самый свежий курс.
+func (s *RateServer) GetLatestRate(ctx context.Context, _ *currencyv1.GetLatestRateRequest) (*currencyv1.Rate, error) {
+	rate, err := s.rateService.GetLatestRate(ctx)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &currencyv1.Rate{Id: rate.ID, Value: rate.Value, Timestamp: timestamppb.New(rate.Timestamp)}, nil
+}
+
+// GetAverageRate возвращает средний курс по последним limit курсам.
+func (s *RateServer) GetAverageRate(ctx context.Context, req *currencyv1.GetAverageRateRequest) (*currencyv1.GetAverageRateResponse, error) {
+	if req.GetLimit() < 0 {
+		return nil, requestError(ctx, "Некорректное значение параметра 'limit'")
+	}
+	avg, err := s.rateService.GetAverageRate(ctx, int(req.GetLimit()))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &currencyv1.GetAverageRateResponse{Average: avg.Average, Count: int32(avg.Count)}, nil
+}
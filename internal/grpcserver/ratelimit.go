@@ -0,0 +1,93 @@
+// internal/grpcserver/ratelimit.go
+package grpcserver
+
+import (
+	"context"
+	"currency-service/internal/ratelimit"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// peerIP возвращает IP-адрес клиента вызова.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// ceilSeconds округляет длительность до целых секунд вверх для метаданных.
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// ipRateLimitInterceptor ограничивает частоту всех вызовов с одного IP-адреса по квоте адреса limiter
+// (аналог handlers.RateLimitByIP). Применяется до authInterceptor, чтобы перебор ключей тоже ограничивался.
+func ipRateLimitInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ip := peerIP(ctx)
+		return checkQuota(ctx, info, "ip:"+ip, func() (ratelimit.Result, error) {
+			return limiter.AllowIP(ctx, ip)
+		}, req, handler)
+	}
+}
+
+// rateLimitInterceptor ограничивает частоту вызовов каждого клиента по квотам limiter (аналог handlers.RateLimit):
+// квота общая с REST, потому что клиент определяется так же (key:<ID> или user:<sub>). Метод сопоставляется
+// с квотами маршрутов как POST /пакет.Сервис/Метод. Применяется после authInterceptor.
+func rateLimitInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		c, ok := callerFromContext(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+		return checkQuota(ctx, info, c.client, func() (ratelimit.Result, error) {
+			return limiter.AllowRoute(ctx, c.client, http.MethodPost, info.FullMethod)
+		}, req, handler)
+	}
+}
+
+// checkQuota списывает вызов клиента client через allow и передает метаданные ответа ratelimit-*
+// (как заголовки RateLimit-* в REST). При исчерпании квоты возвращает ResourceExhausted
+// с retry-after. Если хранилище квот недоступно, вызов пропускается.
+func checkQuota(ctx context.Context, info *grpc.UnaryServerInfo, client string, allow func() (ratelimit.Result, error), req any, handler grpc.UnaryHandler) (any, error) {
+	result, err := allow()
+	if err != nil {
+		log.Printf("Ошибка проверки квоты %s для %s, вызов пропущен: %v\n", client, info.FullMethod, err)
+		return handler(ctx, req)
+	}
+	md := metadata.MD{}
+	if !result.Limit.IsZero() {
+		md.Set("ratelimit-limit", strconv.Itoa(result.Limit.Requests))
+		md.Set("ratelimit-remaining", strconv.Itoa(result.Remaining))
+		md.Set("ratelimit-reset", ceilSeconds(result.Reset))
+		md.Set("ratelimit-policy", fmt.Sprintf("%d;w=%s", result.Limit.Requests, ceilSeconds(result.Limit.Period)))
+	}
+	if !result.Allowed {
+		md.Set("retry-after", ceilSeconds(result.RetryAfter))
+	}
+	if len(md) > 0 {
+		if err := grpc.SetHeader(ctx, md); err != nil {
+			log.Printf("Не удалось передать метаданные квоты вызова %s: %v\n", info.FullMethod, err)
+		}
+	}
+	if !result.Allowed {
+		log.Printf("Отклонен вызов %s: клиент %s превысил квоту %v\n", info.FullMethod, client, result.Limit)
+		return nil, toStatus(ctx, ratelimit.ErrRateLimited)
+	}
+	return handler(ctx, req)
+}
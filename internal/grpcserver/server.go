@@ -0,0 +1,55 @@
+// internal/grpcserver/server.go
+package grpcserver
+
+import (
+	"context"
+	"currency-service/internal/auth"
+	"currency-service/internal/ratelimit"
+	"currency-service/internal/service"
+	"log"
+	"runtime/debug"
+	"time"
+
+	currencyv1 "currency-service/api/currency/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer создает gRPC-сервер с сервисами курсов и кошельков поверх тех же экземпляров сервисов, что и REST API,
+// и с reflection для grpcurl и аналогов. Вызовы проверяются теми же API-ключами и JWT (verifier может быть nil)
+// и расходуют те же квоты limiter, что и запросы REST.
+func NewServer(rateSvc service.RateService, walletSvc service.WalletService, apiKeys service.APIKeyService, verifier *auth.JWTVerifier, limiter *ratelimit.Limiter) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		recoveryInterceptor,
+		loggingInterceptor,
+		ipRateLimitInterceptor(limiter),
+		authInterceptor(apiKeys, verifier),
+		rateLimitInterceptor(limiter),
+	))
+	currencyv1.RegisterRateServiceServer(server, NewRateServer(rateSvc))
+	currencyv1.RegisterWalletServiceServer(server, NewWalletServer(walletSvc))
+	reflection.Register(server)
+	return server
+}
+
+// loggingInterceptor логирует каждый вызов с кодом ответа и длительностью (аналог middleware.Logger).
+func loggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	log.Printf("gRPC %s %s за %v\n", info.FullMethod, status.Code(err), time.Since(start))
+	return resp, err
+}
+
+// recoveryInterceptor превращает панику обработчика в статус Internal (аналог middleware.Recoverer).
+func recoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("Паника в gRPC %s: %v\n%s", info.FullMethod, p, debug.Stack())
+			err = status.Error(codes.Internal, "Внутренняя ошибка сервера")
+		}
+	}()
+	return handler(ctx, req)
+}
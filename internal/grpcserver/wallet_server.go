@@ -0,0 +1,179 @@
+// internal/grpcserver/wallet_server.go
+package grpcserver
+
+import (
+	"context"
+	"currency-service/internal/i18n"
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"log"
+	"time"
+
+	currencyv1 "currency-service/api/currency/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WalletServer реализует currencyv1.WalletServiceServer поверх WalletService.
+type WalletServer struct {
+	currencyv1.UnimplementedWalletServiceServer
+	walletService service.WalletService
+}
+
+// NewWalletServer создает gRPC-сервис кошельков.
+func NewWalletServer(svc service.WalletService) *WalletServer {
+	return &WalletServer{walletService: svc}
+}
+
+// toProtoWallet преобразует кошелек в сообщение protobuf.
+func toProtoWallet(w models.Wallet) *currencyv1.Wallet {
+	return &currencyv1.Wallet{
+		Number:           w.Number,
+		Balance:          w.Balance,
+		AvailableBalance: w.AvailableBalance,
+		CreditLimit:      w.CreditLimit,
+		AvailableCredit:  w.AvailableCredit,
+		OwnerId:          w.OwnerID,
+		Tier:             w.Tier,
+		Status:           w.Status,
+		Version:          w.Version,
+		CreatedAt:        timestamppb.New(w.CreatedAt),
+		UpdatedAt:        timestamppb.New(w.UpdatedAt),
+	}
+}
+
+// localizeMessage переводит сообщение об успехе с кодом code на язык вызова.
+func localizeMessage(ctx context.Context, code, message string) string {
+	return i18n.Message(requestLang(ctx), code, message)
+}
+
+// GetWallet возвращает кошелек и, если указан as_of, его баланс на этот момент.
+func (s *WalletServer) GetWallet(ctx context.Context, req *currencyv1.GetWalletRequest) (*currencyv1.GetWalletResponse, error) {
+	var asOf *time.Time
+	if req.AsOf != nil {
+		if err := req.AsOf.CheckValid(); err != nil {
+			return nil, requestError(ctx, "Некорректное значение параметра 'as_of'")
+		}
+		t := req.AsOf.AsTime()
+		asOf = &t
+	}
+
+	details, err := s.walletService.GetWallet(ctx, req.GetNumber(), asOf)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	// В WalletDetailsResponse время создания и обновления вынесено из Wallet
+	details.Wallet.CreatedAt, details.Wallet.UpdatedAt = details.CreatedAt, details.UpdatedAt
+	resp := &currencyv1.GetWalletResponse{Wallet: toProtoWallet(details.Wallet)}
+	if details.AsOf != nil && details.BalanceAsOf != nil {
+		resp.AsOf = timestamppb.New(*details.AsOf)
+		resp.BalanceAsOf = *details.BalanceAsOf
+	}
+	return resp, nil
+}
+
+// ListWallets возвращает страницу кошельков с теми же фильтрами, что и GET /wallets.
+func (s *WalletServer) ListWallets(ctx context.Context, req *currencyv1.ListWalletsRequest) (*currencyv1.ListWalletsResponse, error) {
+	filter := models.ListWalletsFilter{
+		Limit:  int(req.GetLimit()),
+		Cursor: req.GetCursor(),
+		Status: req.GetStatus(),
+		SortBy: req.GetSortBy(),
+		Order:  req.GetOrder(),
+	}
+	if req.MinBalance != nil {
+		value := req.MinBalance.GetValue()
+		filter.MinBalance = &value
+	}
+	if req.MaxBalance != nil {
+		value := req.MaxBalance.GetValue()
+		filter.MaxBalance = &value
+	}
+	for name, field := range map[string]struct {
+		src *timestamppb.Timestamp
+		dst **time.Time
+	}{
+		"created_from": {req.CreatedFrom, &filter.CreatedFrom},
+		"created_to":   {req.CreatedTo, &filter.CreatedTo},
+	} {
+		if field.src == nil {
+			continue
+		}
+		if err := field.src.CheckValid(); err != nil {
+			return nil, requestError(ctx, "Некорректное значение параметра '"+name+"'")
+		}
+		t := field.src.AsTime()
+		*field.dst = &t
+	}
+
+	list, err := s.walletService.ListWallets(ctx, filter)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	resp := &currencyv1.ListWalletsResponse{Wallets: make([]*currencyv1.Wallet, 0, len(list.Wallets)), NextCursor: list.NextCursor}
+	for _, w := range list.Wallets {
+		resp.Wallets = append(resp.Wallets, toProtoWallet(w))
+	}
+	return resp, nil
+}
+
+// CreateWallet создает пустой кошелек с номером, выделенным сервером.
+func (s *WalletServer) CreateWallet(ctx context.Context, req *currencyv1.CreateWalletRequest) (*currencyv1.Wallet, error) {
+	wallet, err := s.walletService.CreateWallet(ctx, models.CreateWalletRequest{UserID: req.GetUserId(), Tier: req.GetTier()})
+	if err != nil {
+		log.Printf("Ошибка из сервиса CreateWallet (gRPC): %v\n", err)
+		return nil, toStatus(ctx, err)
+	}
+	return toProtoWallet(wallet), nil
+}
+
+// UpdateBalance пополняет кошелек или списывает с него.
+func (s *WalletServer) UpdateBalance(ctx context.Context, req *currencyv1.UpdateBalanceRequest) (*currencyv1.UpdateBalanceResponse, error) {
+	update := models.UpdateBalanceRequest{WalletNumber: req.GetWalletNumber(), Amount: req.GetAmount(), UserID: req.GetUserId(), Staff: isStaff(ctx)}
+	if req.GetExpectedVersion() != 0 {
+		version := req.GetExpectedVersion()
+		update.ExpectedVersion = &version
+	}
+
+	resp, err := s.walletService.UpdateBalance(ctx, update)
+	if err != nil {
+		log.Printf("Ошибка из сервиса UpdateBalance (gRPC): %v\n", err)
+		return nil, toStatus(ctx, err)
+	}
+	return &currencyv1.UpdateBalanceResponse{
+		WalletNumber: resp.WalletNumber,
+		NewBalance:   resp.NewBalance,
+		Version:      resp.Version,
+		MovementId:   resp.MovementID,
+		Message:      localizeMessage(ctx, resp.MessageCode, resp.Message),
+	}, nil
+}
+
+// ConvertAndDeduct конвертирует сумму по текущему курсу и списывает ее с кошелька-источника.
+func (s *WalletServer) ConvertAndDeduct(ctx context.Context, req *currencyv1.ConvertRequest) (*currencyv1.ConvertResponse, error) {
+	resp, err := s.walletService.ConvertAndDeduct(ctx, models.ConvertRequest{
+		FirstName:               req.GetFirstName(),
+		LastName:                req.GetLastName(),
+		UserID:                  req.GetUserId(),
+		AmountToConvert:         req.GetAmountToConvert(),
+		SourceWalletNumber:      req.GetSourceWalletNumber(),
+		DestinationWalletNumber: req.GetDestinationWalletNumber(),
+	})
+	if err != nil {
+		log.Printf("Ошибка из сервиса ConvertAndDeduct (gRPC): %v\n", err)
+		return nil, toStatus(ctx, err)
+	}
+	return &currencyv1.ConvertResponse{
+		SourceWalletNumber:      resp.SourceWalletNumber,
+		RemainingBalance:        resp.RemainingBalance,
+		ConvertedAmount:         resp.ConvertedAmount,
+		RateUsed:                resp.RateUsed,
+		Fee:                     resp.Fee,
+		TotalDebited:            resp.TotalDebited,
+		MovementId:              resp.MovementID,
+		DestinationWalletNumber: resp.DestinationWalletNumber,
+		DestinationBalance:      resp.DestinationBalance,
+		DestinationMovementId:   resp.DestinationMovementID,
+		Message:                 localizeMessage(ctx, resp.MessageCode, resp.Message),
+	}, nil
+}
@@ -0,0 +1,107 @@
+// internal/handlers/apikey_handler.go
+package handlers
+
+import (
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// APIKeyHandler обрабатывает HTTP-запросы управления API-ключами.
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyHandler создает новый экземпляр обработчика API-ключей.
+func NewAPIKeyHandler(svc service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: svc}
+}
+
+// IssueAPIKey godoc
+// @Summary      Выпустить API-ключ
+// @Description  Выпускает ключ с указанными правами: rates:write, wallets:read, wallets:write, admin. Значение ключа возвращается только в этом ответе - сервис хранит лишь его хэш.
+// @Tags         API Keys
+// @Accept       json
+// @Produce      json
+// @Param        key body models.IssueAPIKeyRequest true "Имя и права ключа"
+// @Success      201  {object}  models.IssueAPIKeyResponse "Ключ выпущен"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный запрос, имя или право"
+// @Failure      401  {object}  models.ErrorResponse "Нет действующего API-ключа"
+// @Failure      403  {object}  models.ErrorResponse "У ключа нет права admin"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/api-keys [post]
+func (h *APIKeyHandler) IssueAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req models.IssueAPIKeyRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (IssueAPIKey): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	resp, err := h.apiKeyService.IssueAPIKey(r.Context(), req)
+	if err != nil {
+		log.Printf("Ошибка из сервиса IssueAPIKey: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, resp)
+}
+
+// ListAPIKeys godoc
+// @Summary      Список API-ключей
+// @Description  Возвращает все ключи, включая отозванные, без их значений. Ключ можно опознать по prefix - первым символам значения.
+// @Tags         API Keys
+// @Produce      json
+// @Success      200  {object}  models.ListAPIKeysResponse "API-ключи"
+// @Failure      401  {object}  models.ErrorResponse "Нет действующего API-ключа"
+// @Failure      403  {object}  models.ErrorResponse "У ключа нет права admin"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.apiKeyService.ListAPIKeys(r.Context())
+	if err != nil {
+		log.Printf("Ошибка из сервиса ListAPIKeys: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// RevokeAPIKey godoc
+// @Summary      Отозвать API-ключ
+// @Description  Отзывает ключ: запросы с ним сразу перестают приниматься. Повторный отзыв не меняет время отзыва.
+// @Tags         API Keys
+// @Produce      json
+// @Param        id path int true "ID ключа"
+// @Success      200  {object}  models.APIKey "Ключ отозван"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID ключа"
+// @Failure      401  {object}  models.ErrorResponse "Нет действующего API-ключа"
+// @Failure      403  {object}  models.ErrorResponse "У ключа нет права admin"
+// @Failure      404  {object}  models.ErrorResponse "Ключ не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeRequestError(w, r, "Некорректный ID ключа")
+		return
+	}
+
+	key, err := h.apiKeyService.RevokeAPIKey(r.Context(), id)
+	if err != nil {
+		log.Printf("Ошибка из сервиса RevokeAPIKey: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, key)
+}
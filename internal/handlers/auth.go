@@ -0,0 +1,205 @@
+// internal/handlers/auth.go
+package handlers
+
+import (
+	"context"
+	"currency-service/internal/auth"
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Principal - от чьего имени выполняется запрос: API-ключ сервиса или пользователь с JWT.
+type Principal struct {
+	APIKey *models.APIKey // Ключ, если запрос с API-ключом
+	UserID string         // ID пользователя (sub токена), если запрос с JWT
+	Role   string         // Роль пользователя (auth.Role*), если запрос с JWT
+	Scopes []string       // Права ключа или роли
+}
+
+// HasScope сообщает, есть ли у запроса право scope.
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCustomer сообщает, что запрос выполняет клиент: ему доступны только свои кошельки.
+func (p Principal) IsCustomer() bool {
+	return p.Role == auth.RoleCustomer
+}
+
+// IsStaff сообщает, что запрос выполняет сотрудник: пользователь с ролью operator или admin
+// либо API-ключ с правом admin. Сотрудники могут списывать с кошельков клиентов без user_id владельца.
+func (p Principal) IsStaff() bool {
+	if p.APIKey != nil {
+		return p.HasScope(models.ScopeAdmin)
+	}
+	return p.Role == auth.RoleOperator || p.Role == auth.RoleAdmin
+}
+
+// principalContextKey - ключ контекста запроса, под которым хранится Principal.
+type principalContextKey struct{}
+
+// PrincipalFromContext возвращает, от чьего имени выполняется запрос (false - запрос без проверки доступа).
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(Principal)
+	return p, ok
+}
+
+// credentialsFromRequest возвращает значение заголовка Authorization без схемы.
+// Принимаются "Bearer <ключ или токен>", "ApiKey <ключ>" и просто ключ (так его отправляет Swagger UI).
+func credentialsFromRequest(r *http.Request) string {
+	header := strings.TrimSpace(r.Header.Get("Authorization"))
+	if scheme, value, ok := strings.Cut(header, " "); ok {
+		if strings.EqualFold(scheme, "Bearer") || strings.EqualFold(scheme, "ApiKey") {
+			return strings.TrimSpace(value)
+		}
+	}
+	return header
+}
+
+// writeUnauthorized отвечает 401 с заголовком WWW-Authenticate.
+func writeUnauthorized(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="currency-service"`)
+	writeError(w, r, service.ErrUnauthorized)
+}
+
+// Authenticate пропускает только запросы с действующим API-ключом или JWT в заголовке Authorization
+// и кладет Principal в контекст запроса. API-ключи отличаются от токенов по префиксу.
+// Если verifier равен nil, JWT не принимаются. Права проверяют RequireScope и RequireReadWriteScopes.
+func Authenticate(apiKeys service.APIKeyService, verifier *auth.JWTVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			credentials := credentialsFromRequest(r)
+			if credentials == "" {
+				writeUnauthorized(w, r)
+				return
+			}
+
+			var principal Principal
+			if service.IsAPIKeyToken(credentials) || verifier == nil {
+				key, err := apiKeys.Authenticate(r.Context(), credentials)
+				if err != nil {
+					if errorStatus(err) == http.StatusUnauthorized {
+						log.Printf("Отклонен запрос %s %s: недействительный API-ключ\n", r.Method, r.URL.Path)
+						writeUnauthorized(w, r)
+						return
+					}
+					writeError(w, r, err)
+					return
+				}
+				principal = Principal{APIKey: &key, Scopes: key.Scopes}
+			} else {
+				claims, err := verifier.Verify(credentials)
+				if err != nil {
+					log.Printf("Отклонен запрос %s %s: %v\n", r.Method, r.URL.Path, err)
+					writeUnauthorized(w, r)
+					return
+				}
+				scopes, _ := auth.ScopesForRole(claims.Role)
+				principal = Principal{UserID: claims.Subject, Role: claims.Role, Scopes: scopes}
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalContextKey{}, principal)))
+		})
+	}
+}
+
+// RequireScope пропускает только запросы с правом scope. Должен применяться после Authenticate.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return RequireReadWriteScopes(scope, scope)
+}
+
+// RequireReadWriteScopes требует право read для чтения (GET, HEAD) и право write для остальных методов.
+// Пустое право означает, что достаточно любого действующего ключа или токена.
+func RequireReadWriteScopes(read, write string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := write
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				scope = read
+			}
+			if scope != "" {
+				principal, ok := PrincipalFromContext(r.Context())
+				if !ok {
+					writeUnauthorized(w, r)
+					return
+				}
+				if !principal.HasScope(scope) {
+					log.Printf("Отклонен запрос %s %s: нет права %s\n", r.Method, r.URL.Path, scope)
+					writeError(w, r, fmt.Errorf("%w: требуется %s", service.ErrInsufficientScope, scope))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RejectCustomers закрывает эндпоинт для клиентов: им доступны только чтение и списания со своих кошельков.
+func RejectCustomers(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := PrincipalFromContext(r.Context()); ok && principal.IsCustomer() {
+			log.Printf("Отклонен запрос %s %s: эндпоинт недоступен клиентам\n", r.Method, r.URL.Path)
+			writeError(w, r, fmt.Errorf("%w: эндпоинт недоступен клиентам", service.ErrInsufficientScope))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// customerID возвращает ID пользователя, если запрос выполняет клиент, и пустую строку для остальных.
+func customerID(ctx context.Context) string {
+	if principal, ok := PrincipalFromContext(ctx); ok && principal.IsCustomer() {
+		return principal.UserID
+	}
+	return ""
+}
+
+// principalID возвращает, кто выполняет запрос: key:<ID> для API-ключа или user:<sub> для пользователя с JWT
+// (пусто - запрос без проверки доступа).
+func principalID(ctx context.Context) string {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		if principal.APIKey != nil {
+			return fmt.Sprintf("key:%d", principal.APIKey.ID)
+		}
+		if principal.UserID != "" {
+			return "user:" + principal.UserID
+		}
+	}
+	return ""
+}
+
+// isStaff сообщает, что запрос выполняет сотрудник (см. Principal.IsStaff).
+func isStaff(ctx context.Context) bool {
+	principal, ok := PrincipalFromContext(ctx)
+	return ok && principal.IsStaff()
+}
+
+// checkCustomerUser для клиентов проверяет, что запрошенный пользователь - это сам клиент.
+func checkCustomerUser(ctx context.Context, userID string) error {
+	if id := customerID(ctx); id != "" && !strings.EqualFold(id, userID) {
+		return service.ErrWalletOwnerMismatch
+	}
+	return nil
+}
+
+// bindCustomerOwner для клиентов подставляет в user_id запроса ID клиента (указанный user_id должен с ним совпадать)
+// и требует, чтобы у кошелька был владелец. Возвращает, нужно ли требовать владельца.
+func bindCustomerOwner(ctx context.Context, userID *string) (bool, error) {
+	id := customerID(ctx)
+	if id == "" {
+		return false, nil
+	}
+	if *userID != "" && !strings.EqualFold(*userID, id) {
+		return false, service.ErrWalletOwnerMismatch
+	}
+	*userID = id
+	return true, nil
+}
@@ -0,0 +1,145 @@
+// internal/handlers/cache.go
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CacheControlRule - значение Cache-Control для маршрута. Pattern - путь, в котором {параметр}
+// соответствует одному сегменту.
+type CacheControlRule struct {
+	Method  string
+	Pattern string
+	Value   string
+}
+
+func (c CacheControlRule) matches(method, path string) bool {
+	if c.Method != method && !(c.Method == http.MethodGet && method == http.MethodHead) {
+		return false
+	}
+	patternSegments := strings.Split(strings.Trim(c.Pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateCacheControl проверяет значение Cache-Control. Все ответы API зависят от ключа или токена,
+// поэтому разрешающие общие кэши директивы public и s-maxage недопустимы: прокси или CDN
+// отдали бы ответ одного клиента другому.
+func ValidateCacheControl(value string) error {
+	for _, directive := range strings.Split(value, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "public", "s-maxage":
+			return fmt.Errorf("директива Cache-Control %q недопустима: ответы API зависят от авторизации (используйте private)", strings.TrimSpace(directive))
+		}
+	}
+	return nil
+}
+
+// ParseCacheControlRules разбирает значения Cache-Control маршрутов вида
+// "GET /api/v1/rates/average=private, max-age=30; GET /api/v1/wallets/{number}=no-store".
+// Значения проверяются ValidateCacheControl.
+func ParseCacheControlRules(spec string) ([]CacheControlRule, error) {
+	var rules []CacheControlRule
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		route, value, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("некорректное правило Cache-Control %q (ожидается \"МЕТОД /путь=значение\")", part)
+		}
+		method, pattern, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !strings.HasPrefix(strings.TrimSpace(pattern), "/") {
+			return nil, fmt.Errorf("некорректный маршрут %q (ожидается \"МЕТОД /путь\")", route)
+		}
+		if err := ValidateCacheControl(value); err != nil {
+			return nil, fmt.Errorf("правило Cache-Control %q: %w", part, err)
+		}
+		rules = append(rules, CacheControlRule{Method: strings.ToUpper(method), Pattern: strings.TrimSpace(pattern), Value: strings.TrimSpace(value)})
+	}
+	return rules, nil
+}
+
+// CacheControl добавляет заголовок Cache-Control к успешным ответам (200 и 304) на GET и HEAD:
+// значение из правила маршрута или defaultValue (пустое - заголовок не добавляется).
+// Ответы с ошибками не кэшируются (no-store), чтобы кэш не запомнил временный сбой.
+func CacheControl(defaultValue string, rules []CacheControlRule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			value := defaultValue
+			for _, rule := range rules {
+				if rule.matches(r.Method, r.URL.Path) {
+					value = rule.Value
+					break
+				}
+			}
+			if value == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(&cacheControlWriter{ResponseWriter: w, value: value}, r)
+		})
+	}
+}
+
+// cacheControlWriter выставляет Cache-Control в момент отправки статуса ответа.
+type cacheControlWriter struct {
+	http.ResponseWriter
+	value       string
+	wroteHeader bool
+}
+
+func (w *cacheControlWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if w.Header().Get("Cache-Control") == "" {
+			if status == http.StatusOK || status == http.StatusNotModified {
+				w.Header().Set("Cache-Control", w.value)
+			} else {
+				w.Header().Set("Cache-Control", "no-store")
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush нужен потоковым выгрузкам (CSV и NDJSON).
+func (w *cacheControlWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap позволяет http.ResponseController добраться до исходного ResponseWriter.
+func (w *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,177 @@
+// internal/handlers/errors.go
+package handlers
+
+import (
+	"currency-service/internal/apperrors"
+	"currency-service/internal/i18n"
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// errorStatuses сопоставляет коды доменных ошибок HTTP-статусам. Коды, которых здесь нет, отдаются как 500.
+var errorStatuses = map[apperrors.Code]int{
+	apperrors.CodeInvalidRequest: http.StatusBadRequest,
+
+	apperrors.CodeUnauthorized:      http.StatusUnauthorized,
+	apperrors.CodeInsufficientScope: http.StatusForbidden,
+	apperrors.CodeAPIKeyNotFound:    http.StatusNotFound,
+	apperrors.CodeInvalidAPIKeyName: http.StatusBadRequest,
+	apperrors.CodeInvalidScope:      http.StatusBadRequest,
+	apperrors.CodeRateLimited:       http.StatusTooManyRequests,
+
+	apperrors.CodeRateNotPositive:  http.StatusBadRequest,
+	apperrors.CodeNoRates:          http.StatusNotFound,
+	apperrors.CodeRateNotAvailable: http.StatusServiceUnavailable,
+
+	apperrors.CodeWalletNotFound:             http.StatusNotFound,
+	apperrors.CodeWalletExists:               http.StatusConflict,
+	apperrors.CodeInsufficientFunds:          http.StatusConflict,
+	apperrors.CodeInvalidWalletNumber:        http.StatusBadRequest,
+	apperrors.CodeInvalidCheckDigit:          http.StatusBadRequest,
+	apperrors.CodeInvalidDepositAmount:       http.StatusBadRequest,
+	apperrors.CodeWithdrawNonExistent:        http.StatusBadRequest,
+	apperrors.CodeInvalidTransferAmount:      http.StatusBadRequest,
+	apperrors.CodeSameWalletTransfer:         http.StatusBadRequest,
+	apperrors.CodeTargetWalletNotFound:       http.StatusNotFound,
+	apperrors.CodeInvalidConvertAmount:       http.StatusBadRequest,
+	apperrors.CodeSameWalletConversion:       http.StatusBadRequest,
+	apperrors.CodeDestinationOwnerMismatch:   http.StatusForbidden,
+	apperrors.CodeInvalidPageSize:            http.StatusBadRequest,
+	apperrors.CodeInvalidCursor:              http.StatusBadRequest,
+	apperrors.CodeInvalidSort:                http.StatusBadRequest,
+	apperrors.CodeInvalidWalletStatus:        http.StatusBadRequest,
+	apperrors.CodeInvalidListFilter:          http.StatusBadRequest,
+	apperrors.CodeAsOfInFuture:               http.StatusBadRequest,
+	apperrors.CodeAsOfBeforeCreation:         http.StatusBadRequest,
+	apperrors.CodeWalletNumberSpaceExhausted: http.StatusServiceUnavailable,
+	apperrors.CodeVersionMismatch:            http.StatusPreconditionFailed,
+	apperrors.CodeInvalidCreditLimit:         http.StatusBadRequest,
+	apperrors.CodeCreditLimitInUse:           http.StatusConflict,
+	apperrors.CodeWalletFrozen:               http.StatusConflict,
+	apperrors.CodeWalletClosed:               http.StatusConflict,
+	apperrors.CodeWalletNotEmpty:             http.StatusConflict,
+
+	apperrors.CodeUserNotFound:        http.StatusNotFound,
+	apperrors.CodeInvalidUserID:       http.StatusBadRequest,
+	apperrors.CodeInvalidUserName:     http.StatusBadRequest,
+	apperrors.CodeWalletOwnerMismatch: http.StatusForbidden,
+	apperrors.CodeWalletOwnerRequired: http.StatusForbidden,
+
+	apperrors.CodeEmptyBatch:            http.StatusBadRequest,
+	apperrors.CodeBatchTooLarge:         http.StatusBadRequest,
+	apperrors.CodeInvalidBatchMode:      http.StatusBadRequest,
+	apperrors.CodeInvalidBatchOperation: http.StatusBadRequest,
+	apperrors.CodeInvalidBatchAmount:    http.StatusBadRequest,
+
+	apperrors.CodeHoldNotFound:           http.StatusNotFound,
+	apperrors.CodeHoldNotActive:          http.StatusConflict,
+	apperrors.CodeInvalidHoldAmount:      http.StatusBadRequest,
+	apperrors.CodeInvalidHoldTTL:         http.StatusBadRequest,
+	apperrors.CodeCaptureExceedsHold:     http.StatusBadRequest,
+	apperrors.CodeDuplicateHoldReference: http.StatusConflict,
+
+	apperrors.CodeLimitExceeded: http.StatusUnprocessableEntity,
+	apperrors.CodeInvalidTier:   http.StatusBadRequest,
+	apperrors.CodeInvalidLimit:  http.StatusBadRequest,
+
+	apperrors.CodeScheduleNotFound:         http.StatusNotFound,
+	apperrors.CodeScheduleNotActive:        http.StatusConflict,
+	apperrors.CodeInvalidScheduleOperation: http.StatusBadRequest,
+	apperrors.CodeInvalidScheduleAmount:    http.StatusBadRequest,
+	apperrors.CodeInvalidRecurrence:        http.StatusBadRequest,
+	apperrors.CodeInvalidCronExpr:          http.StatusBadRequest,
+	apperrors.CodeScheduleHasNoRuns:        http.StatusBadRequest,
+
+	apperrors.CodeMovementNotFound:         http.StatusNotFound,
+	apperrors.CodeMovementAlreadyReversed:  http.StatusConflict,
+	apperrors.CodeMovementNotReversible:    http.StatusUnprocessableEntity,
+	apperrors.CodeMovementPartOfConversion: http.StatusUnprocessableEntity,
+	apperrors.CodeReconciliationNotFound:   http.StatusNotFound,
+
+	apperrors.CodeFeeRuleNotFound: http.StatusNotFound,
+	apperrors.CodeInvalidPair:     http.StatusBadRequest,
+	apperrors.CodeInvalidFeeRule:  http.StatusBadRequest,
+
+	apperrors.CodeInterestPlanNotFound:   http.StatusNotFound,
+	apperrors.CodeInterestPlanExists:     http.StatusConflict,
+	apperrors.CodeInvalidInterestPlan:    http.StatusBadRequest,
+	apperrors.CodeInvalidDayCount:        http.StatusBadRequest,
+	apperrors.CodeInterestDayNotClosed:   http.StatusUnprocessableEntity,
+	apperrors.CodeInterestMonthNotClosed: http.StatusUnprocessableEntity,
+}
+
+// errorStatus возвращает HTTP-статус для ошибки сервиса.
+func errorStatus(err error) int {
+	if status, ok := errorStatuses[apperrors.CodeOf(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// errorCode возвращает код ошибки для полей code ответов.
+func errorCode(err error) string {
+	return string(apperrors.CodeOf(err))
+}
+
+// requestLang возвращает язык ответа по заголовку Accept-Language.
+func requestLang(r *http.Request) i18n.Lang {
+	return i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// localize переводит сообщение с кодом code на язык запроса. source - исходный текст сообщения.
+func localize(r *http.Request, code, source string) string {
+	return i18n.Message(requestLang(r), code, source)
+}
+
+// newErrorResponse формирует тело ответа об ошибке на языке запроса. Текст внутренних ошибок клиенту не раскрывается.
+func newErrorResponse(r *http.Request, err error) models.ErrorResponse {
+	resp := models.ErrorResponse{
+		Error:     err.Error(),
+		Code:      errorCode(err),
+		Details:   apperrors.DetailsOf(err),
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+	if errorStatus(err) == http.StatusInternalServerError {
+		resp.Error = "Внутренняя ошибка сервера"
+		resp.Code = string(apperrors.CodeInternal)
+		resp.Details = nil
+	}
+	resp.Error = localize(r, resp.Code, resp.Error)
+	return resp
+}
+
+// writeError отвечает ошибкой сервиса со статусом по ее коду.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("Внутренняя ошибка при обработке %s %s: %v\n", r.Method, r.URL.Path, err)
+	}
+
+	var limitErr *service.LimitExceededError
+	if errors.As(err, &limitErr) {
+		writeJSONResponse(w, status, models.LimitExceededResponse{
+			ErrorResponse: newErrorResponse(r, err),
+			Limit:         limitErr.Limit,
+			LimitValue:    limitErr.LimitValue,
+			Attempted:     limitErr.Attempted,
+			ResetsAt:      limitErr.ResetsAt,
+		})
+		return
+	}
+	writeJSONResponse(w, status, newErrorResponse(r, err))
+}
+
+// writeRequestError отвечает 400 с кодом INVALID_REQUEST: тело запроса или параметр не удалось разобрать.
+// Подробный текст message отдается только на языке по умолчанию, для остальных языков - общее сообщение.
+func writeRequestError(w http.ResponseWriter, r *http.Request, message string) {
+	writeJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{
+		Error:     localize(r, string(apperrors.CodeInvalidRequest), message),
+		Code:      string(apperrors.CodeInvalidRequest),
+		RequestID: middleware.GetReqID(r.Context()),
+	})
+}
@@ -0,0 +1,93 @@
+// internal/handlers/etag.go
+package handlers
+
+import (
+	"currency-service/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// walletETag формирует сильный ETag кошелька из его версии.
+func walletETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// setWalletETag добавляет в ответ заголовок ETag с версией кошелька.
+func setWalletETag(w http.ResponseWriter, version int64) {
+	if version > 0 {
+		w.Header().Set("ETag", walletETag(version))
+	}
+}
+
+// parseIfMatch разбирает заголовок If-Match с ETag кошелька.
+// Возвращает (nil, true), если заголовка нет или он равен "*" (подойдет любая версия),
+// и (nil, false), если ни один из ETag не является версией кошелька - такое условие не выполнится никогда.
+// Слабые ETag (W/"...") не подходят: If-Match использует строгое сравнение.
+// Если ETag в списке несколько, используется первый корректный.
+func parseIfMatch(r *http.Request) (*int64, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil || version <= 0 {
+			continue
+		}
+		return &version, true
+	}
+	return nil, false
+}
+
+// dataETag формирует слабый ETag ответа из версии данных и формата ответа (представления в разных
+// форматах различаются). Слабый, потому что одинаковые данные не обязаны давать побайтно одинаковый ответ.
+func dataETag(version models.DataVersion, format string) string {
+	return `W/"` + version.Tag + "-" + format + `"`
+}
+
+// checkNotModified добавляет в ответ ETag и Last-Modified и обрабатывает условный GET:
+// если представление у клиента актуально, отвечает 304 и возвращает true.
+// If-None-Match приоритетнее If-Modified-Since и сравнивается слабо (W/ не учитывается).
+func checkNotModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if header := strings.TrimSpace(r.Header.Get("If-None-Match")); header != "" {
+		if !etagMatches(header, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		// Last-Modified передается с точностью до секунды
+		if err != nil || lastModified.IsZero() || lastModified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches сообщает, есть ли etag в списке из заголовка If-None-Match ("*" подходит к любому).
+func etagMatches(header, etag string) bool {
+	if header == "*" {
+		return true
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+// internal/handlers/export.go
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Форматы ответа списочных эндпоинтов
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+// exportTimeout - сколько может длиться выгрузка. Обычные запросы ограничены Timeout и WriteTimeout сервера,
+// а выгрузка всех строк может идти дольше.
+const exportTimeout = 10 * time.Minute
+
+// Трейлеры выгрузки: по ним клиент отличает полный файл от оборванного.
+// Если X-Export-Status нет или он не complete, выгрузка неполная.
+const (
+	exportStatusTrailer = "X-Export-Status" // complete или truncated
+	exportRowsTrailer   = "X-Export-Rows"   // Число выгруженных строк (без заголовка CSV)
+)
+
+// exportContentTypes сопоставляет форматы выгрузки типам содержимого ответа.
+var exportContentTypes = map[string]string{
+	formatCSV:    "text/csv; charset=utf-8",
+	formatNDJSON: "application/x-ndjson",
+}
+
+// acceptFormats сопоставляет типы из заголовка Accept форматам ответа.
+var acceptFormats = map[string]string{
+	"application/json":     formatJSON,
+	"text/csv":             formatCSV,
+	"application/x-ndjson": formatNDJSON,
+	"application/ndjson":   formatNDJSON,
+}
+
+// responseFormat выбирает формат ответа: параметр format имеет приоритет над заголовком Accept.
+// Если в Accept нет поддерживаемых типов, ответ отдается в JSON.
+func responseFormat(r *http.Request) (string, error) {
+	switch format := strings.ToLower(r.URL.Query().Get("format")); format {
+	case "":
+	case formatJSON, formatCSV, formatNDJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("некорректное значение параметра 'format' (ожидается json, csv или ndjson)")
+	}
+
+	format, best := formatJSON, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		candidate, ok := acceptFormats[mediaType]
+		if !ok {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > best {
+			format, best = candidate, quality
+		}
+	}
+	return format, nil
+}
+
+// isExportRequest сообщает, что запрос просит выгрузку в CSV или NDJSON, а не JSON.
+func isExportRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	format, err := responseFormat(r)
+	return err == nil && format != formatJSON
+}
+
+// Timeout ограничивает время обработки запроса (как middleware.Timeout из chi).
+// Выгрузкам в CSV и NDJSON дается exportTimeout: они отправляют все строки и могут идти дольше.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		regular := middleware.Timeout(timeout)(next)
+		export := middleware.Timeout(exportTimeout)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isExportRequest(r) {
+				export.ServeHTTP(w, r)
+				return
+			}
+			regular.ServeHTTP(w, r)
+		})
+	}
+}
+
+// exportWriter пишет строки выгрузки прямо в ответ: CSV с заголовком из columns или NDJSON.
+// Заголовки ответа отправляются при первой строке, поэтому ошибку, возникшую до нее,
+// еще можно вернуть обычным JSON-ответом. Итог выгрузки передается в трейлерах
+// X-Export-Status и X-Export-Rows.
+type exportWriter struct {
+	w        http.ResponseWriter
+	format   string
+	filename string // Имя файла без расширения для Content-Disposition
+	columns  []string
+	csv      *csv.Writer
+	ndjson   *json.Encoder
+	started  bool
+	rows     int
+}
+
+// newExportWriter создает выгрузку и продлевает срок записи ответа до exportTimeout,
+// чтобы WriteTimeout сервера не оборвал большую выгрузку.
+func newExportWriter(w http.ResponseWriter, format, filename string, columns []string) *exportWriter {
+	err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportTimeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Не удалось продлить срок записи выгрузки %s: %v\n", filename, err)
+	}
+	return &exportWriter{w: w, format: format, filename: filename, columns: columns}
+}
+
+// start отправляет заголовки ответа и строку заголовка CSV.
+func (e *exportWriter) start() error {
+	e.started = true
+	e.w.Header().Set("Content-Type", exportContentTypes[e.format])
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, e.filename, e.format))
+	e.w.Header().Set("Trailer", exportStatusTrailer+", "+exportRowsTrailer)
+	e.w.WriteHeader(http.StatusOK)
+	if e.format == formatCSV {
+		e.csv = csv.NewWriter(e.w)
+		return e.csv.Write(e.columns)
+	}
+	e.ndjson = json.NewEncoder(e.w)
+	return nil
+}
+
+// writeRow пишет одну строку: record - значения колонок CSV, value - объект NDJSON.
+func (e *exportWriter) writeRow(record []string, value any) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	e.rows++
+	if e.format == formatCSV {
+		return e.csv.Write(record)
+	}
+	return e.ndjson.Encode(value)
+}
+
+// finish завершает выгрузку. Если строк еще не было, ошибка возвращается обычным ответом,
+// иначе выгрузка обрывается: статус уже отправлен, поэтому о неполном файле сообщает
+// трейлер X-Export-Status: truncated.
+func (e *exportWriter) finish(r *http.Request, err error) {
+	status := "complete"
+	if err != nil {
+		if !e.started {
+			writeError(e.w, r, err)
+			return
+		}
+		log.Printf("Выгрузка %s %s прервана: %v\n", r.Method, r.URL.Path, err)
+		status = "truncated"
+	} else if !e.started {
+		if err := e.start(); err != nil {
+			log.Printf("Ошибка записи выгрузки %s %s: %v\n", r.Method, r.URL.Path, err)
+			return
+		}
+	}
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			log.Printf("Ошибка записи выгрузки %s %s: %v\n", r.Method, r.URL.Path, err)
+			status = "truncated"
+		}
+	}
+	e.w.Header().Set(exportStatusTrailer, status)
+	e.w.Header().Set(exportRowsTrailer, strconv.Itoa(e.rows))
+}
+
+// formatExportFloat записывает число без экспоненты и лишних нулей.
+func formatExportFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// formatExportTime записывает время в RFC 3339 (как в JSON-ответах).
+func formatExportTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
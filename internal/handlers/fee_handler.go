@@ -0,0 +1,99 @@
+// internal/handlers/fee_handler.go
+package handlers
+
+import (
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// FeeHandler обрабатывает HTTP-запросы управления комиссиями за конвертацию.
+type FeeHandler struct {
+	feeService service.FeeService
+}
+
+// NewFeeHandler создает новый экземпляр обработчика комиссий.
+func NewFeeHandler(svc service.FeeService) *FeeHandler {
+	return &FeeHandler{feeService: svc}
+}
+
+// ListFeeRules godoc
+// @Summary      Список правил комиссий
+// @Description  Возвращает все правила комиссий за конвертацию. При конвертации применяется самое точное правило: сначала по валютной паре, затем по уровню кошелька (* - любое значение).
+// @Tags         Fees
+// @Produce      json
+// @Success      200  {object}  models.ListFeeRulesResponse "Правила комиссий"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/fees [get]
+func (h *FeeHandler) ListFeeRules(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.feeService.ListFeeRules(r.Context())
+	if err != nil {
+		log.Printf("Ошибка из сервиса ListFeeRules: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// SetFeeRule godoc
+// @Summary      Установить правило комиссии
+// @Description  Создает или заменяет правило для пары и уровня кошелька (пустые значения означают *). Комиссия = сумма по курсу × percent / 100 + fixed, ограниченная min_fee и max_fee, округляется до сотых.
+// @Tags         Fees
+// @Accept       json
+// @Produce      json
+// @Param        rule body models.FeeRule true "Правило комиссии (id и updated_at игнорируются)"
+// @Success      200  {object}  models.FeeRule "Правило сохранено"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный запрос, пара, уровень или параметры комиссии"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/fees [put]
+func (h *FeeHandler) SetFeeRule(w http.ResponseWriter, r *http.Request) {
+	var rule models.FeeRule
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rule); err != nil {
+		log.Printf("Ошибка декодирования JSON (SetFeeRule): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	saved, err := h.feeService.SetFeeRule(r.Context(), rule)
+	if err != nil {
+		log.Printf("Ошибка из сервиса SetFeeRule: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, saved)
+}
+
+// DeleteFeeRule godoc
+// @Summary      Удалить правило комиссии
+// @Description  Удаляет правило комиссии. Если подходящих правил не остается, конвертация выполняется без комиссии.
+// @Tags         Fees
+// @Param        id path int true "ID правила"
+// @Success      204  "Правило удалено"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID правила"
+// @Failure      404  {object}  models.ErrorResponse "Правило не найдено"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/fees/{id} [delete]
+func (h *FeeHandler) DeleteFeeRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeRequestError(w, r, "Некорректный ID правила")
+		return
+	}
+
+	if err := h.feeService.DeleteFeeRule(r.Context(), id); err != nil {
+		log.Printf("Ошибка из сервиса DeleteFeeRule: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,567 @@
+// internal/handlers/graphql_handler.go
+package handlers
+
+import (
+	"context"
+	"currency-service/internal/apperrors"
+	"currency-service/internal/i18n"
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// GraphQLHandler обрабатывает запросы GraphQL: чтение курсов и кошельков (с последними движениями)
+// и операции с балансом одним запросом. Права проверяются так же, как в REST API.
+type GraphQLHandler struct {
+	schema         graphql.Schema // Запросы и мутации (POST)
+	readOnlySchema graphql.Schema // Только запросы (GET)
+	rateService    service.RateService
+	walletService  service.WalletService
+}
+
+// NewGraphQLHandler создает обработчик GraphQL поверх сервисов курсов и кошельков.
+func NewGraphQLHandler(rateSvc service.RateService, walletSvc service.WalletService) (*GraphQLHandler, error) {
+	h := &GraphQLHandler{rateService: rateSvc, walletService: walletSvc}
+	query := h.queryType()
+	var err error
+	if h.schema, err = graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: h.mutationType()}); err != nil {
+		return nil, fmt.Errorf("ошибка построения схемы GraphQL: %w", err)
+	}
+	if h.readOnlySchema, err = graphql.NewSchema(graphql.SchemaConfig{Query: query}); err != nil {
+		return nil, fmt.Errorf("ошибка построения схемы GraphQL: %w", err)
+	}
+	return h, nil
+}
+
+// Execute godoc
+// @Summary      Выполнить запрос GraphQL
+// @Description  Запросы: latestRate, rates(limit), averageRate(limit), wallets(limit, cursor, status, sortBy, order), wallet(number) с полем movements(limit). Мутации: updateBalance, convert (только POST). Права те же, что у соответствующих REST-эндпоинтов. В одном запросе допускается одна мутация; запросы стоимостью больше 10 000 (каждое поле стоит 1, поле с limit умножает стоимость вложенных полей на limit) отклоняются с кодом INVALID_REQUEST. Ошибки возвращаются в errors со статусом 200, код ошибки - в extensions.code.
+// @Tags         GraphQL
+// @Accept       json
+// @Produce      json
+// @Param        request body models.GraphQLRequest true "Запрос GraphQL"
+// @Param        Accept-Language header string false "Язык сообщений: ru (по умолчанию), en, kk"
+// @Success      200  {object}  models.GraphQLResponse "Результат запроса (в том числе с ошибками в errors)"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный формат запроса"
+// @Failure      401  {object}  models.ErrorResponse "Нет действительного API-ключа или токена"
+// @Security     ApiKeyAuth
+// @Router       /graphql [post]
+func (h *GraphQLHandler) Execute(w http.ResponseWriter, r *http.Request) {
+	var req models.GraphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (GraphQL): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+	h.execute(w, r, h.schema, req)
+}
+
+// ExecuteQuery godoc
+// @Summary      Выполнить запрос GraphQL (GET)
+// @Description  То же, что POST /graphql, но только для запросов: мутации через GET не выполняются.
+// @Tags         GraphQL
+// @Produce      json
+// @Param        query query string true "Запрос GraphQL"
+// @Param        variables query string false "Значения переменных (JSON-объект)"
+// @Param        operationName query string false "Какую операцию выполнить, если в запросе их несколько"
+// @Param        Accept-Language header string false "Язык сообщений: ru (по умолчанию), en, kk"
+// @Success      200  {object}  models.GraphQLResponse "Результат запроса (в том числе с ошибками в errors)"
+// @Failure      400  {object}  models.ErrorResponse "Некорректное значение параметра variables"
+// @Failure      401  {object}  models.ErrorResponse "Нет действительного API-ключа или токена"
+// @Security     ApiKeyAuth
+// @Router       /graphql [get]
+func (h *GraphQLHandler) ExecuteQuery(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := models.GraphQLRequest{Query: q.Get("query"), OperationName: q.Get("operationName")}
+	if variables := q.Get("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+			writeRequestError(w, r, "Некорректное значение параметра 'variables': "+err.Error())
+			return
+		}
+	}
+	h.execute(w, r, h.readOnlySchema, req)
+}
+
+// execute выполняет запрос req по схеме schema и отвечает 200 с данными и ошибками.
+func (h *GraphQLHandler) execute(w http.ResponseWriter, r *http.Request, schema graphql.Schema, req models.GraphQLRequest) {
+	if strings.TrimSpace(req.Query) == "" {
+		writeRequestError(w, r, "Не указан запрос GraphQL (query)")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), graphQLLangContextKey{}, requestLang(r))
+	result := doGraphQL(ctx, schema, req)
+
+	resp := models.GraphQLResponse{Data: result.Data}
+	for _, e := range result.Errors {
+		extensions := e.Extensions
+		if extensions == nil {
+			// Ошибки разбора и проверки запроса по схеме
+			extensions = map[string]any{"code": string(apperrors.CodeInvalidRequest)}
+		}
+		resp.Errors = append(resp.Errors, models.GraphQLError{Message: e.Message, Path: e.Path, Extensions: extensions})
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// doGraphQL разбирает и проверяет запрос по схеме (как graphql.Do), затем проверяет ограничения
+// checkGraphQLLimits и только после этого выполняет запрос.
+func doGraphQL(ctx context.Context, schema graphql.Schema, req models.GraphQLRequest) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if validation := graphql.ValidateDocument(&schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+	if err := checkGraphQLLimits(schema, doc, req.OperationName, req.Variables); err != nil {
+		log.Printf("Отклонен запрос GraphQL: %v\n", err)
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+}
+
+// --- Ошибки ---
+
+// graphQLLangContextKey - ключ контекста, под которым хранится язык сообщений запроса GraphQL.
+type graphQLLangContextKey struct{}
+
+// graphQLError - ошибка поля GraphQL с кодом в extensions.
+type graphQLError struct {
+	message    string
+	extensions map[string]any
+}
+
+func (e *graphQLError) Error() string                      { return e.message }
+func (e *graphQLError) Extensions() map[string]interface{} { return e.extensions }
+
+// newGraphQLError переводит ошибку сервиса на язык запроса и добавляет ее код (и подробности) в extensions.
+// Текст внутренних ошибок клиенту не раскрывается.
+func newGraphQLError(ctx context.Context, err error) error {
+	lang, _ := ctx.Value(graphQLLangContextKey{}).(i18n.Lang)
+	code, message, details := apperrors.CodeOf(err), err.Error(), apperrors.DetailsOf(err)
+	if errorStatus(err) == http.StatusInternalServerError {
+		log.Printf("Внутренняя ошибка GraphQL: %v\n", err)
+		code, message, details = apperrors.CodeInternal, "Внутренняя ошибка сервера", nil
+	}
+	extensions := map[string]any{"code": string(code)}
+	if len(details) > 0 {
+		extensions["details"] = details
+	}
+	return &graphQLError{message: i18n.Message(lang, string(code), message), extensions: extensions}
+}
+
+// resolver оборачивает функцию разрешения поля: ошибки сервисов превращаются в ошибки GraphQL с кодом.
+func resolver(fn graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		value, err := fn(p)
+		if err != nil {
+			return nil, newGraphQLError(p.Context, err)
+		}
+		return value, nil
+	}
+}
+
+// requireGraphQLScope проверяет право scope, а при rejectCustomers еще и то, что запрос выполняет
+// не клиент (аналог RequireScope и RejectCustomers для отдельных полей).
+func requireGraphQLScope(ctx context.Context, scope string, rejectCustomers bool) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return service.ErrUnauthorized
+	}
+	if !principal.HasScope(scope) {
+		return fmt.Errorf("%w: требуется %s", service.ErrInsufficientScope, scope)
+	}
+	if rejectCustomers && principal.IsCustomer() {
+		return fmt.Errorf("%w: поле недоступно клиентам", service.ErrInsufficientScope)
+	}
+	return nil
+}
+
+// --- Типы схемы ---
+
+// optional возвращает значение по указателю или nil (null в ответе).
+func optional[T any](v *T) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func rateToGraphQL(rate models.Rate) map[string]any {
+	return map[string]any{"id": rate.ID, "value": rate.Value, "timestamp": rate.Timestamp}
+}
+
+func walletToGraphQL(wallet models.Wallet) map[string]any {
+	var ownerID any
+	if wallet.OwnerID != "" {
+		ownerID = wallet.OwnerID
+	}
+	return map[string]any{
+		"number":           wallet.Number,
+		"balance":          wallet.Balance,
+		"availableBalance": wallet.AvailableBalance,
+		"creditLimit":      wallet.CreditLimit,
+		"availableCredit":  wallet.AvailableCredit,
+		"ownerId":          ownerID,
+		"tier":             wallet.Tier,
+		"status":           wallet.Status,
+		"version":          wallet.Version,
+		"createdAt":        wallet.CreatedAt,
+		"updatedAt":        wallet.UpdatedAt,
+	}
+}
+
+func movementToGraphQL(movement models.Movement) map[string]any {
+	return map[string]any{
+		"id":           movement.ID,
+		"kind":         movement.Kind,
+		"amount":       movement.Amount,
+		"balanceAfter": movement.BalanceAfter,
+		"rate":         optional(movement.Rate),
+		"sourceAmount": optional(movement.SourceAmount),
+		"reversalOf":   optional(movement.ReversalOf),
+		"reversedBy":   optional(movement.ReversedBy),
+		"createdAt":    movement.CreatedAt,
+	}
+}
+
+var graphQLRateType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Rate",
+	Description: "Курс валюты",
+	Fields: graphql.Fields{
+		"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"value":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"timestamp": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var graphQLAverageRateType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AverageRate",
+	Description: "Средний курс по последним курсам",
+	Fields: graphql.Fields{
+		"average": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"count":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Сколько курсов усреднено"},
+	},
+})
+
+var graphQLMovementType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Movement",
+	Description: "Изменение баланса кошелька (положительная сумма - зачисление, отрицательная - списание)",
+	Fields: graphql.Fields{
+		"id":           &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"kind":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"amount":       &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"balanceAfter": &graphql.Field{Type: graphql.NewNonNull(graphql.Float), Description: "Учетный баланс кошелька после движения"},
+		"rate":         &graphql.Field{Type: graphql.Float, Description: "Курс конвертации (только для конвертаций)"},
+		"sourceAmount": &graphql.Field{Type: graphql.Float, Description: "Сумма до конвертации (только для конвертаций)"},
+		"reversalOf":   &graphql.Field{Type: graphql.ID, Description: "ID отмененного движения (только для reversal)"},
+		"reversedBy":   &graphql.Field{Type: graphql.ID, Description: "ID движения, которым отменено это движение"},
+		"createdAt":    &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+// walletType описывает кошелек с полем movements, которое загружает последние движения.
+func (h *GraphQLHandler) walletType() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Wallet",
+		Description: "Кошелек",
+		Fields: graphql.Fields{
+			"number":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"balance":          &graphql.Field{Type: graphql.NewNonNull(graphql.Float), Description: "Учетный баланс (включая зарезервированные средства)"},
+			"availableBalance": &graphql.Field{Type: graphql.NewNonNull(graphql.Float), Description: "Доступный баланс: учетный минус активные холды"},
+			"creditLimit":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"availableCredit":  &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"ownerId":          &graphql.Field{Type: graphql.String},
+			"tier":             &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"status":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"version":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"createdAt":        &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt":        &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"movements": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLMovementType))),
+				Description: "Последние движения, начиная с самого нового",
+				Args: graphql.FieldConfigArgument{
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+				},
+				Resolve: resolver(func(p graphql.ResolveParams) (any, error) {
+					number, _ := p.Source.(map[string]any)["number"].(string)
+					movements, err := h.walletService.ListWalletMovements(p.Context, number, p.Args["limit"].(int))
+					if err != nil {
+						return nil, err
+					}
+					result := make([]map[string]any, 0, len(movements))
+					for _, movement := range movements {
+						result = append(result, movementToGraphQL(movement))
+					}
+					return result, nil
+				}),
+			},
+		},
+	})
+}
+
+// queryType описывает запросы на чтение.
+func (h *GraphQLHandler) queryType() *graphql.Object {
+	walletType := h.walletType()
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"latestRate": &graphql.Field{
+				Type:        graphQLRateType,
+				Description: "Самый свежий курс (null, если курсов нет)",
+				Resolve: resolver(func(p graphql.ResolveParams) (any, error) {
+					rate, err := h.rateService.GetLatestRate(p.Context)
+					if errors.Is(err, service.ErrNoRates) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, err
+					}
+					return rateToGraphQL(rate), nil
+				}),
+			},
+			"rates": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLRateType))),
+				Description: "История курсов: последние limit курсов, начиная с самого свежего",
+				Args: graphql.FieldConfigArgument{
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+				},
+				Resolve: resolver(func(p graphql.ResolveParams) (any, error) {
+					rates, err := h.rateService.ListRates(p.Context, p.Args["limit"].(int))
+					if err != nil {
+						return nil, err
+					}
+					result := make([]map[string]any, 0, len(rates))
+					for _, rate := range rates {
+						result = append(result, rateToGraphQL(rate))
+					}
+					return result, nil
+				}),
+			},
+			"averageRate": &graphql.Field{
+				Type:        graphql.NewNonNull(graphQLAverageRateType),
+				Description: "Средний курс по последним limit курсам",
+				Args: graphql.FieldConfigArgument{
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+				},
+				Resolve: resolver(func(p graphql.ResolveParams) (any, error) {
+					avg, err := h.rateService.GetAverageRate(p.Context, p.Args["limit"].(int))
+					if err != nil {
+						return nil, err
+					}
+					return map[string]any{"average": avg.Average, "count": avg.Count}, nil
+				}),
+			},
+			"wallets": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+					Name:        "WalletPage",
+					Description: "Страница списка кошельков",
+					Fields: graphql.Fields{
+						"wallets":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(walletType)))},
+						"nextCursor": &graphql.Field{Type: graphql.String, Description: "Курсор следующей страницы (null на последней странице)"},
+					},
+				})),
+				Description: "Страница кошельков (недоступно клиентам)",
+				Args: graphql.FieldConfigArgument{
+					"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 50, Description: "Размер страницы (максимум 500)"},
+					"cursor": &graphql.ArgumentConfig{Type: graphql.String},
+					"status": &graphql.ArgumentConfig{Type: graphql.String, Description: "active, frozen или closed"},
+					"sortBy": &graphql.ArgumentConfig{Type: graphql.String, Description: "created_at, balance или wallet_number"},
+					"order":  &graphql.ArgumentConfig{Type: graphql.String, Description: "asc или desc"},
+				},
+				Resolve: resolver(func(p graphql.ResolveParams) (any, error) {
+					if err := requireGraphQLScope(p.Context, models.ScopeWalletsRead, true); err != nil {
+						return nil, err
+					}
+					filter := models.ListWalletsFilter{}
+					filter.Limit, _ = p.Args["limit"].(int)
+					filter.Cursor, _ = p.Args["cursor"].(string)
+					filter.Status, _ = p.Args["status"].(string)
+					filter.SortBy, _ = p.Args["sortBy"].(string)
+					filter.Order, _ = p.Args["order"].(string)
+					list, err := h.walletService.ListWallets(p.Context, filter)
+					if err != nil {
+						return nil, err
+					}
+					wallets := make([]map[string]any, 0, len(list.Wallets))
+					for _, wallet := range list.Wallets {
+						wallets = append(wallets, walletToGraphQL(wallet))
+					}
+					var nextCursor any
+					if list.NextCursor != "" {
+						nextCursor = list.NextCursor
+					}
+					return map[string]any{"wallets": wallets, "nextCursor": nextCursor}, nil
+				}),
+			},
+			"wallet": &graphql.Field{
+				Type:        walletType,
+				Description: "Кошелек по номеру (null, если не найден). Клиентам доступны только свои кошельки",
+				Args: graphql.FieldConfigArgument{
+					"number": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: resolver(func(p graphql.ResolveParams) (any, error) {
+					if err := requireGraphQLScope(p.Context, models.ScopeWalletsRead, false); err != nil {
+						return nil, err
+					}
+					details, err := h.walletService.GetWallet(p.Context, p.Args["number"].(string), nil)
+					if errors.Is(err, service.ErrWalletNotFound) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, err
+					}
+					if err := checkCustomerUser(p.Context, details.OwnerID); err != nil {
+						return nil, err
+					}
+					wallet := details.Wallet
+					wallet.CreatedAt, wallet.UpdatedAt = details.CreatedAt, details.UpdatedAt
+					return walletToGraphQL(wallet), nil
+				}),
+			},
+		},
+	})
+}
+
+// mutationType описывает операции с балансом.
+func (h *GraphQLHandler) mutationType() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"updateBalance": &graphql.Field{
+				Type: graphql.NewObject(graphql.ObjectConfig{
+					Name: "UpdateBalanceResult",
+					Fields: graphql.Fields{
+						"walletNumber": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+						"newBalance":   &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+						"version":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Версия кошелька после обновления"},
+						"movementId":   &graphql.Field{Type: graphql.ID, Description: "ID записанного движения"},
+						"message":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+					},
+				}),
+				Description: "Пополнение (amount > 0) или списание (amount < 0). Клиенты могут только списывать со своих кошельков",
+				Args: graphql.FieldConfigArgument{
+					"walletNumber":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"amount":          &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Float)},
+					"userId":          &graphql.ArgumentConfig{Type: graphql.String, Description: "Владелец кошелька (необязательно)"},
+					"expectedVersion": &graphql.ArgumentConfig{Type: graphql.Int, Description: "Ожидаемая версия кошелька (аналог If-Match)"},
+				},
+				Resolve: resolver(func(p graphql.ResolveParams) (any, error) {
+					if err := requireGraphQLScope(p.Context, models.ScopeWalletsWrite, false); err != nil {
+						return nil, err
+					}
+					req := models.UpdateBalanceRequest{WalletNumber: p.Args["walletNumber"].(string), Amount: p.Args["amount"].(float64)}
+					req.UserID, _ = p.Args["userId"].(string)
+					if version, ok := p.Args["expectedVersion"].(int); ok {
+						expected := int64(version)
+						req.ExpectedVersion = &expected
+					}
+					if customerID(p.Context) != "" && req.Amount > 0 {
+						return nil, fmt.Errorf("%w: пополнение недоступно клиентам", service.ErrInsufficientScope)
+					}
+					var err error
+					if req.RequireOwner, err = bindCustomerOwner(p.Context, &req.UserID); err != nil {
+						return nil, err
+					}
+					req.Staff = isStaff(p.Context)
+
+					resp, err := h.walletService.UpdateBalance(p.Context, req)
+					if err != nil {
+						return nil, err
+					}
+					return map[string]any{
+						"walletNumber": resp.WalletNumber,
+						"newBalance":   resp.NewBalance,
+						"version":      resp.Version,
+						"movementId":   resp.MovementID,
+						"message":      graphQLMessage(p.Context, resp.MessageCode, resp.Message),
+					}, nil
+				}),
+			},
+			"convert": &graphql.Field{
+				Type: graphql.NewObject(graphql.ObjectConfig{
+					Name: "ConvertResult",
+					Fields: graphql.Fields{
+						"sourceWalletNumber":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+						"remainingBalance":        &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+						"convertedAmount":         &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+						"rateUsed":                &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+						"fee":                     &graphql.Field{Type: graphql.NewNonNull(graphql.Float), Description: "Комиссия за конвертацию"},
+						"totalDebited":            &graphql.Field{Type: graphql.NewNonNull(graphql.Float), Description: "Всего списано: сумма по курсу и комиссия"},
+						"movementId":              &graphql.Field{Type: graphql.ID},
+						"destinationWalletNumber": &graphql.Field{Type: graphql.String},
+						"destinationBalance":      &graphql.Field{Type: graphql.Float},
+						"message":                 &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+					},
+				}),
+				Description: "Конвертация по текущему курсу со списанием с кошелька-источника. Клиенты могут конвертировать только со своих кошельков",
+				Args: graphql.FieldConfigArgument{
+					"sourceWalletNumber":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"amountToConvert":         &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Float)},
+					"destinationWalletNumber": &graphql.ArgumentConfig{Type: graphql.String, Description: "Кошелек для зачисления результата (необязательно)"},
+					"userId":                  &graphql.ArgumentConfig{Type: graphql.String},
+					"firstName":               &graphql.ArgumentConfig{Type: graphql.String},
+					"lastName":                &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: resolver(func(p graphql.ResolveParams) (any, error) {
+					if err := requireGraphQLScope(p.Context, models.ScopeWalletsWrite, false); err != nil {
+						return nil, err
+					}
+					req := models.ConvertRequest{SourceWalletNumber: p.Args["sourceWalletNumber"].(string), AmountToConvert: p.Args["amountToConvert"].(float64)}
+					req.DestinationWalletNumber, _ = p.Args["destinationWalletNumber"].(string)
+					req.UserID, _ = p.Args["userId"].(string)
+					req.FirstName, _ = p.Args["firstName"].(string)
+					req.LastName, _ = p.Args["lastName"].(string)
+					var err error
+					if req.RequireOwner, err = bindCustomerOwner(p.Context, &req.UserID); err != nil {
+						return nil, err
+					}
+
+					resp, err := h.walletService.ConvertAndDeduct(p.Context, req)
+					if err != nil {
+						return nil, err
+					}
+					var destinationWallet, destinationBalance any
+					if resp.DestinationWalletNumber != "" {
+						destinationWallet, destinationBalance = resp.DestinationWalletNumber, resp.DestinationBalance
+					}
+					return map[string]any{
+						"sourceWalletNumber":      resp.SourceWalletNumber,
+						"remainingBalance":        resp.RemainingBalance,
+						"convertedAmount":         resp.ConvertedAmount,
+						"rateUsed":                resp.RateUsed,
+						"fee":                     resp.Fee,
+						"totalDebited":            resp.TotalDebited,
+						"movementId":              resp.MovementID,
+						"destinationWalletNumber": destinationWallet,
+						"destinationBalance":      destinationBalance,
+						"message":                 graphQLMessage(p.Context, resp.MessageCode, resp.Message),
+					}, nil
+				}),
+			},
+		},
+	})
+}
+
+// graphQLMessage переводит сообщение об успехе с кодом code на язык запроса.
+func graphQLMessage(ctx context.Context, code, message string) string {
+	lang, _ := ctx.Value(graphQLLangContextKey{}).(i18n.Lang)
+	return i18n.Message(lang, code, message)
+}
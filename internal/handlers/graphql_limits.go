@@ -0,0 +1,174 @@
+// internal/handlers/graphql_limits.go
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Ограничения запроса GraphQL
+const (
+	// maxGraphQLComplexity - предельная стоимость запроса: каждое поле стоит 1, а поле с аргументом limit
+	// умножает стоимость вложенных полей на limit. Так wallets(limit: 500) { wallets { movements(limit: 500) { id } } }
+	// (до 250 000 движений за один запрос) стоит больше 250 000 и отклоняется.
+	maxGraphQLComplexity = 10000
+	// maxGraphQLMutations - сколько мутаций можно выполнить одним запросом: квота запросов
+	// списывает запрос один раз, поэтому каждая операция с балансом - отдельный запрос.
+	maxGraphQLMutations = 1
+)
+
+// graphQLLimitsChecker считает стоимость и число мутаций операции по схеме.
+type graphQLLimitsChecker struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	defaults  map[string]ast.Value // Значения переменных по умолчанию из определения операции
+}
+
+// checkGraphQLLimits проверяет выполняемые операции документа doc (уже прошедшего проверку по схеме):
+// стоимость не больше maxGraphQLComplexity, мутаций не больше maxGraphQLMutations.
+func checkGraphQLLimits(schema graphql.Schema, doc *ast.Document, operationName string, variables map[string]any) error {
+	c := graphQLLimitsChecker{fragments: map[string]*ast.FragmentDefinition{}, variables: variables}
+	var operations []*ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.FragmentDefinition:
+			c.fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if operationName == "" || (d.Name != nil && d.Name.Value == operationName) {
+				operations = append(operations, d)
+			}
+		}
+	}
+
+	for _, operation := range operations {
+		root := schema.QueryType()
+		if operation.Operation == ast.OperationTypeMutation {
+			root = schema.MutationType()
+			if n := c.countFields(operation.SelectionSet); n > maxGraphQLMutations {
+				return fmt.Errorf("в запросе %d мутаций, допускается не больше %d", n, maxGraphQLMutations)
+			}
+		}
+		if root == nil {
+			continue
+		}
+		c.defaults = map[string]ast.Value{}
+		for _, definition := range operation.VariableDefinitions {
+			if definition.DefaultValue != nil {
+				c.defaults[definition.Variable.Name.Value] = definition.DefaultValue
+			}
+		}
+		if cost := c.cost(root, operation.SelectionSet); cost > maxGraphQLComplexity {
+			return fmt.Errorf("запрос слишком сложный: стоимость %d, допускается не больше %d (уменьшите limit вложенных списков)", cost, maxGraphQLComplexity)
+		}
+	}
+	return nil
+}
+
+// countFields считает поля набора с учетом фрагментов.
+func (c *graphQLLimitsChecker) countFields(set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+	n := 0
+	for _, selection := range set.Selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			n++
+		case *ast.InlineFragment:
+			n += c.countFields(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := c.fragments[s.Name.Value]; ok {
+				n += c.countFields(fragment.SelectionSet)
+			}
+		}
+	}
+	return n
+}
+
+// cost считает стоимость полей набора set типа parent. В схеме нет интерфейсов и объединений,
+// поэтому фрагменты относятся к тому же типу, что и набор.
+func (c *graphQLLimitsChecker) cost(parent *graphql.Object, set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+	total := 0
+	for _, selection := range set.Selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			total += c.fieldCost(parent, s)
+		case *ast.InlineFragment:
+			total += c.cost(parent, s.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := c.fragments[s.Name.Value]; ok {
+				total += c.cost(parent, fragment.SelectionSet)
+			}
+		}
+		if total > maxGraphQLComplexity {
+			return total // Дальше считать незачем, а большие множители могут переполнить int
+		}
+	}
+	return total
+}
+
+// fieldCost возвращает стоимость поля: 1 и вложенные поля, умноженные на limit (если у поля есть такой аргумент).
+func (c *graphQLLimitsChecker) fieldCost(parent *graphql.Object, field *ast.Field) int {
+	definition, ok := parent.Fields()[field.Name.Value]
+	if !ok || field.SelectionSet == nil {
+		return 1 // Скалярное поле или служебное (__typename, интроспекция)
+	}
+	object, ok := graphql.GetNamed(definition.Type).(*graphql.Object)
+	if !ok {
+		return 1
+	}
+	return 1 + c.limit(definition, field)*c.cost(object, field.SelectionSet)
+}
+
+// limit возвращает значение аргумента limit поля: из запроса, из переменной или по умолчанию (1 - аргумента нет).
+// Значения больше maxGraphQLComplexity и так превышают предел, поэтому урезаются, чтобы произведение не переполнилось.
+func (c *graphQLLimitsChecker) limit(definition *graphql.FieldDefinition, field *ast.Field) int {
+	var argument *graphql.Argument
+	for _, arg := range definition.Args {
+		if arg.Name() == "limit" {
+			argument = arg
+		}
+	}
+	if argument == nil {
+		return 1
+	}
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+		if value, ok := c.intValue(arg.Value); ok && value > 0 {
+			return min(value, maxGraphQLComplexity+1)
+		}
+	}
+	if value, ok := argument.DefaultValue.(int); ok && value > 0 {
+		return value
+	}
+	return 1
+}
+
+// intValue возвращает целое значение аргумента: литерал или переменную запроса.
+func (c *graphQLLimitsChecker) intValue(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := c.variables[v.Name.Value].(type) {
+		case float64: // Переменные из JSON
+			return int(min(n, maxGraphQLComplexity+1)), true
+		case int:
+			return n, true
+		case nil:
+			if defaultValue, ok := c.defaults[v.Name.Value]; ok {
+				return c.intValue(defaultValue)
+			}
+		}
+	}
+	return 0, false
+}
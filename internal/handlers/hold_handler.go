@@ -0,0 +1,164 @@
+// internal/handlers/hold_handler.go
+package handlers
+
+import (
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HoldHandler обрабатывает HTTP-запросы, связанные с холдами (резервированием средств).
+type HoldHandler struct {
+	holdService service.HoldService
+}
+
+// NewHoldHandler создает новый экземпляр обработчика холдов.
+func NewHoldHandler(svc service.HoldService) *HoldHandler {
+	return &HoldHandler{holdService: svc}
+}
+
+// parseHoldID читает ID холда из пути. При ошибке сам отправляет ответ 400.
+func parseHoldID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeRequestError(w, r, "Некорректный ID холда")
+		return 0, false
+	}
+	return id, true
+}
+
+// CreateHold godoc
+// @Summary      Зарезервировать средства на кошельке
+// @Description  Создает холд: сумма уменьшает доступный баланс кошелька, но не списывается. Холд освобождается автоматически по истечении срока.
+// @Tags         Holds
+// @Accept       json
+// @Produce      json
+// @Param        number path string true "Номер кошелька"
+// @Param        hold body models.CreateHoldRequest true "Сумма, reference и срок действия холда"
+// @Success      201  {object}  models.Hold "Холд создан"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный формат запроса, номера кошелька или суммы"
+// @Failure      404  {object}  models.ErrorResponse "Кошелек не найден"
+// @Failure      409  {object}  models.ErrorResponse "Недостаточно доступных средств или дублирующийся reference"
+// @Failure      422  {object}  models.LimitExceededResponse "Сумма холда превышает лимит на списания"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /wallets/{number}/holds [post]
+func (h *HoldHandler) CreateHold(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateHoldRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (CreateHold): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	hold, err := h.holdService.AuthorizeHold(r.Context(), chi.URLParam(r, "number"), req)
+	if err != nil {
+		log.Printf("Ошибка из сервиса AuthorizeHold: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, hold)
+}
+
+// GetHold godoc
+// @Summary      Получить холд
+// @Description  Возвращает холд по ID.
+// @Tags         Holds
+// @Produce      json
+// @Param        id path int true "ID холда"
+// @Success      200  {object}  models.Hold "Холд"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID холда"
+// @Failure      404  {object}  models.ErrorResponse "Холд не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /holds/{id} [get]
+func (h *HoldHandler) GetHold(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseHoldID(w, r)
+	if !ok {
+		return
+	}
+	hold, err := h.holdService.GetHold(r.Context(), id)
+	if err != nil {
+		log.Printf("Ошибка из сервиса GetHold: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, hold)
+}
+
+// CaptureHold godoc
+// @Summary      Списать зарезервированные средства
+// @Description  Списывает с кошелька всю зарезервированную сумму или ее часть. Остаток резерва освобождается.
+// @Tags         Holds
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID холда"
+// @Param        capture body models.CaptureHoldRequest false "Сумма списания (по умолчанию - вся сумма холда)"
+// @Success      200  {object}  models.Hold "Холд списан"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный запрос или сумма больше зарезервированной"
+// @Failure      404  {object}  models.ErrorResponse "Холд не найден"
+// @Failure      409  {object}  models.ErrorResponse "Холд уже списан, отменен или истек"
+// @Failure      422  {object}  models.LimitExceededResponse "Списание превышает лимит на списания"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /holds/{id}/capture [post]
+func (h *HoldHandler) CaptureHold(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseHoldID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CaptureHoldRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	// Тело необязательно: пустое тело означает полное списание
+	if err := decoder.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Printf("Ошибка декодирования JSON (CaptureHold): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	hold, err := h.holdService.CaptureHold(r.Context(), id, req)
+	if err != nil {
+		log.Printf("Ошибка из сервиса CaptureHold: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, hold)
+}
+
+// VoidHold godoc
+// @Summary      Отменить холд
+// @Description  Отменяет холд и освобождает зарезервированные средства без списания.
+// @Tags         Holds
+// @Produce      json
+// @Param        id path int true "ID холда"
+// @Success      200  {object}  models.Hold "Холд отменен"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID холда"
+// @Failure      404  {object}  models.ErrorResponse "Холд не найден"
+// @Failure      409  {object}  models.ErrorResponse "Холд уже списан, отменен или истек"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /holds/{id}/void [post]
+func (h *HoldHandler) VoidHold(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseHoldID(w, r)
+	if !ok {
+		return
+	}
+	hold, err := h.holdService.VoidHold(r.Context(), id)
+	if err != nil {
+		log.Printf("Ошибка из сервиса VoidHold: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, hold)
+}
@@ -0,0 +1,195 @@
+// internal/handlers/interest_handler.go
+package handlers
+
+import (
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// InterestHandler обрабатывает HTTP-запросы, связанные с начислением процентов.
+type InterestHandler struct {
+	interestService service.InterestService
+}
+
+// NewInterestHandler создает новый экземпляр обработчика процентов.
+func NewInterestHandler(svc service.InterestService) *InterestHandler {
+	return &InterestHandler{interestService: svc}
+}
+
+// CreateInterestPlan godoc
+// @Summary      Создать процентный план
+// @Description  Создает план с годовой ставкой (в процентах) и конвенцией подсчета дней: ACT/365 (по умолчанию), ACT/360, ACT/ACT или 30/360. План не изменяется; чтобы изменить ставку, кошелькам назначают новый план.
+// @Tags         Interest
+// @Accept       json
+// @Produce      json
+// @Param        plan body models.CreateInterestPlanRequest true "Параметры плана"
+// @Success      201  {object}  models.InterestPlan "План создан"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный запрос, ставка или конвенция"
+// @Failure      409  {object}  models.ErrorResponse "План с таким названием уже существует"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/interest/plans [post]
+func (h *InterestHandler) CreateInterestPlan(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateInterestPlanRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (CreateInterestPlan): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	plan, err := h.interestService.CreatePlan(r.Context(), req)
+	if err != nil {
+		log.Printf("Ошибка из сервиса CreatePlan: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, plan)
+}
+
+// ListInterestPlans godoc
+// @Summary      Список процентных планов
+// @Description  Возвращает все процентные планы в порядке создания.
+// @Tags         Interest
+// @Produce      json
+// @Success      200  {object}  models.ListInterestPlansResponse "Процентные планы"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/interest/plans [get]
+func (h *InterestHandler) ListInterestPlans(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.interestService.ListPlans(r.Context())
+	if err != nil {
+		log.Printf("Ошибка из сервиса ListPlans: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// AccrueInterest godoc
+// @Summary      Начислить проценты за день
+// @Description  Начисляет проценты за завершившийся день (UTC) на баланс на конец дня каждого кошелька с процентным планом. Повторный запуск за тот же день ничего не начисляет. Фоновая задача делает это ежедневно за вчерашний день.
+// @Tags         Interest
+// @Produce      json
+// @Param        date query string false "День в формате YYYY-MM-DD (по умолчанию вчера)"
+// @Success      200  {object}  models.InterestAccrualRunResponse "Результат начисления"
+// @Failure      400  {object}  models.ErrorResponse "Некорректная дата"
+// @Failure      422  {object}  models.ErrorResponse "День еще не закончился"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/interest/accruals [post]
+func (h *InterestHandler) AccrueInterest(w http.ResponseWriter, r *http.Request) {
+	day := time.Now().UTC().AddDate(0, 0, -1)
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		var err error
+		day, err = time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			writeRequestError(w, r, "Некорректная дата (ожидается YYYY-MM-DD)")
+			return
+		}
+	}
+
+	resp, err := h.interestService.AccrueInterest(r.Context(), day)
+	if err != nil {
+		log.Printf("Ошибка из сервиса AccrueInterest: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// PostInterest godoc
+// @Summary      Зачислить начисленные проценты
+// @Description  Зачисляет на кошельки все незачисленные начисления по указанный завершившийся месяц включительно: одно движение interest на кошелек, сумма округляется до сотых. Повторный запуск ничего не зачисляет. Фоновая задача делает это за прошлый месяц.
+// @Tags         Interest
+// @Produce      json
+// @Param        month query string false "Месяц в формате YYYY-MM (по умолчанию прошлый)"
+// @Success      200  {object}  models.InterestPostingResponse "Результат зачисления"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный месяц"
+// @Failure      422  {object}  models.ErrorResponse "Месяц еще не закончился"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/interest/postings [post]
+func (h *InterestHandler) PostInterest(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	if monthStr := r.URL.Query().Get("month"); monthStr != "" {
+		var err error
+		month, err = time.Parse("2006-01", monthStr)
+		if err != nil {
+			writeRequestError(w, r, "Некорректный месяц (ожидается YYYY-MM)")
+			return
+		}
+	}
+
+	resp, err := h.interestService.PostInterest(r.Context(), month)
+	if err != nil {
+		log.Printf("Ошибка из сервиса PostInterest: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// GetWalletInterest godoc
+// @Summary      Проценты по кошельку
+// @Description  Возвращает процентный план кошелька, начисленные, но еще не зачисленные проценты и последние ежедневные начисления.
+// @Tags         Interest
+// @Produce      json
+// @Param        number path string true "Номер кошелька"
+// @Success      200  {object}  models.WalletInterestResponse "Проценты по кошельку"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный номер кошелька"
+// @Failure      404  {object}  models.ErrorResponse "Кошелек не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /wallets/{number}/interest [get]
+func (h *InterestHandler) GetWalletInterest(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.interestService.GetWalletInterest(r.Context(), chi.URLParam(r, "number"))
+	if err != nil {
+		log.Printf("Ошибка из сервиса GetWalletInterest: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// SetWalletInterestPlan godoc
+// @Summary      Назначить процентный план кошельку
+// @Description  Назначает кошельку процентный план или отключает начисление (plan_id: null). Назначения хранятся в истории: за каждый день проценты начисляются по плану, действовавшему на конец дня, в том числе при начислении за пропущенные дни. Требует права admin.
+// @Tags         Interest
+// @Accept       json
+// @Produce      json
+// @Param        number path string true "Номер кошелька"
+// @Param        plan body models.SetWalletInterestPlanRequest true "ID плана"
+// @Success      200  {object}  models.WalletInterestResponse "План назначен"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный запрос или номер кошелька"
+// @Failure      403  {object}  models.ErrorResponse "У ключа нет права admin"
+// @Failure      404  {object}  models.ErrorResponse "Кошелек или план не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /wallets/{number}/interest [put]
+func (h *InterestHandler) SetWalletInterestPlan(w http.ResponseWriter, r *http.Request) {
+	var req models.SetWalletInterestPlanRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (SetWalletInterestPlan): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	resp, err := h.interestService.SetWalletPlan(r.Context(), chi.URLParam(r, "number"), req.PlanID)
+	if err != nil {
+		log.Printf("Ошибка из сервиса SetWalletPlan: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,131 @@
+// internal/handlers/limit_handler.go
+package handlers
+
+import (
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// LimitHandler обрабатывает HTTP-запросы управления лимитами на списания.
+type LimitHandler struct {
+	limitService service.LimitService
+}
+
+// NewLimitHandler создает новый экземпляр обработчика лимитов.
+func NewLimitHandler(svc service.LimitService) *LimitHandler {
+	return &LimitHandler{limitService: svc}
+}
+
+// GetTierLimits godoc
+// @Summary      Получить лимиты уровня кошельков
+// @Description  Возвращает лимиты на списания, действующие для всех кошельков указанного уровня. Отсутствующее поле означает, что лимит не установлен.
+// @Tags         Limits
+// @Produce      json
+// @Param        tier path string true "Уровень кошелька (например, standard)"
+// @Success      200  {object}  models.TierLimitsResponse "Лимиты уровня"
+// @Failure      400  {object}  models.ErrorResponse "Некорректное название уровня"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /limits/tiers/{tier} [get]
+func (h *LimitHandler) GetTierLimits(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.limitService.GetTierLimits(r.Context(), chi.URLParam(r, "tier"))
+	if err != nil {
+		log.Printf("Ошибка из сервиса GetTierLimits: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// SetTierLimits godoc
+// @Summary      Установить лимиты уровня кошельков
+// @Description  Полностью заменяет лимиты уровня. Не указанные поля снимают соответствующий лимит.
+// @Tags         Limits
+// @Accept       json
+// @Produce      json
+// @Param        tier path string true "Уровень кошелька (например, standard)"
+// @Param        limits body models.Limits true "Лимиты уровня"
+// @Success      200  {object}  models.TierLimitsResponse "Лимиты сохранены"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный запрос, уровень или отрицательный лимит"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /limits/tiers/{tier} [put]
+func (h *LimitHandler) SetTierLimits(w http.ResponseWriter, r *http.Request) {
+	var limits models.Limits
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&limits); err != nil {
+		log.Printf("Ошибка декодирования JSON (SetTierLimits): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	resp, err := h.limitService.SetTierLimits(r.Context(), chi.URLParam(r, "tier"), limits)
+	if err != nil {
+		log.Printf("Ошибка из сервиса SetTierLimits: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// GetWalletLimits godoc
+// @Summary      Получить лимиты кошелька
+// @Description  Возвращает уровень кошелька, лимиты уровня, индивидуальные лимиты и итоговые лимиты, которые применяются к списаниям.
+// @Tags         Limits
+// @Produce      json
+// @Param        number path string true "Номер кошелька"
+// @Success      200  {object}  models.WalletLimitsResponse "Лимиты кошелька"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный номер кошелька"
+// @Failure      404  {object}  models.ErrorResponse "Кошелек не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /wallets/{number}/limits [get]
+func (h *LimitHandler) GetWalletLimits(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.limitService.GetWalletLimits(r.Context(), chi.URLParam(r, "number"))
+	if err != nil {
+		log.Printf("Ошибка из сервиса GetWalletLimits: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// SetWalletLimits godoc
+// @Summary      Установить лимиты кошелька
+// @Description  Полностью заменяет индивидуальные лимиты кошелька (они имеют приоритет над лимитами уровня) и, если указан, меняет уровень кошелька. Требует права admin.
+// @Tags         Limits
+// @Accept       json
+// @Produce      json
+// @Param        number path string true "Номер кошелька"
+// @Param        limits body models.UpdateWalletLimitsRequest true "Уровень и индивидуальные лимиты"
+// @Success      200  {object}  models.WalletLimitsResponse "Лимиты сохранены"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный запрос, номер кошелька, уровень или отрицательный лимит"
+// @Failure      403  {object}  models.ErrorResponse "У ключа нет права admin"
+// @Failure      404  {object}  models.ErrorResponse "Кошелек не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /wallets/{number}/limits [put]
+func (h *LimitHandler) SetWalletLimits(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateWalletLimitsRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (SetWalletLimits): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	resp, err := h.limitService.SetWalletLimits(r.Context(), chi.URLParam(r, "number"), req)
+	if err != nil {
+		log.Printf("Ошибка из сервиса SetWalletLimits: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,99 @@
+// internal/handlers/movement_handler.go
+package handlers
+
+import (
+	_ "currency-service/internal/models" // Модели используются в аннотациях swag
+	"currency-service/internal/service"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MovementHandler обрабатывает HTTP-запросы, связанные с движениями по кошелькам и их отменой.
+type MovementHandler struct {
+	reversalService service.ReversalService
+}
+
+// NewMovementHandler создает новый экземпляр обработчика движений.
+func NewMovementHandler(svc service.ReversalService) *MovementHandler {
+	return &MovementHandler{reversalService: svc}
+}
+
+// parseMovementID читает ID движения из пути. При ошибке отправляет 400 и возвращает false.
+func parseMovementID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeRequestError(w, r, "Некорректный ID движения")
+		return 0, false
+	}
+	return id, true
+}
+
+// GetMovement godoc
+// @Summary      Получить движение
+// @Description  Возвращает движение по кошельку по ID. Для конвертаций указаны курс и исходная сумма, для отмен - ID отмененного движения (reversal_of), для отмененных движений - ID отмены (reversed_by).
+// @Tags         Movements
+// @Produce      json
+// @Param        id path int true "ID движения"
+// @Success      200  {object}  models.Movement "Движение"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID движения"
+// @Failure      404  {object}  models.ErrorResponse "Движение не найдено"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /movements/{id} [get]
+func (h *MovementHandler) GetMovement(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseMovementID(w, r)
+	if !ok {
+		return
+	}
+
+	movement, err := h.reversalService.GetMovement(r.Context(), id)
+	if err != nil {
+		log.Printf("Ошибка из сервиса GetMovement: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, movement)
+}
+
+// ReverseMovement godoc
+// @Summary      Отменить движение
+// @Description  Записывает движение, компенсирующее пополнение, списание или конвертацию, и связывает его с исходным. Отмена конвертации возвращает сумму по курсу исходной конвертации и в той же транзакции отменяет зачисление получателю и комиссию (linked_reversals); эти движения по отдельности не отменяются. Движение можно отменить только один раз; отмену зачисления нельзя выполнить, если средств на кошельке уже недостаточно. Требует права admin.
+// @Tags         Movements
+// @Produce      json
+// @Param        id path int true "ID отменяемого движения"
+// @Param        Accept-Language header string false "Язык сообщений: ru (по умолчанию), en, kk"
+// @Success      200  {object}  models.ReversalResponse "Движение отменено"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID движения"
+// @Failure      403  {object}  models.ErrorResponse "У ключа нет права admin"
+// @Failure      404  {object}  models.ErrorResponse "Движение не найдено"
+// @Failure      409  {object}  models.ReversalResponse "Движение уже отменено или недостаточно средств для отмены"
+// @Failure      422  {object}  models.ErrorResponse "Движение этого вида нельзя отменить или оно отменяется только вместе с конвертацией"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /movements/{id}/reverse [post]
+func (h *MovementHandler) ReverseMovement(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseMovementID(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.reversalService.ReverseMovement(r.Context(), id)
+	if err != nil {
+		log.Printf("Ошибка из сервиса ReverseMovement: %v\n", err)
+		// Если движение уже отменено или средств не хватает, возвращаем ReversalResponse с сообщением
+		if errors.Is(err, service.ErrMovementAlreadyReversed) || errors.Is(err, service.ErrInsufficientFunds) {
+			resp.Code = errorCode(err)
+			resp.Message = localize(r, resp.Code, resp.Message)
+			writeJSONResponse(w, errorStatus(err), resp)
+			return
+		}
+		writeError(w, r, err)
+		return
+	}
+	resp.Message = localize(r, resp.MessageCode, resp.Message)
+	writeJSONResponse(w, http.StatusOK, resp)
+}
@@ -30,6 +30,7 @@ func NewRateHandler(svc service.RateService) *RateHandler {
 // @Success      201  {object}  models.SuccessResponse "Курс успешно добавлен"
 // @Failure      400  {object}  models.ErrorResponse "Некорректный формат запроса или значение курса"
 // @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
 // @Router       /rates [post]
 func (h *RateHandler) CreateRate(w http.ResponseWriter, r *http.Request) {
 	// Временная структура только для получения value из JSON
@@ -42,7 +43,7 @@ func (h *RateHandler) CreateRate(w http.ResponseWriter, r *http.Request) {
 	err := decoder.Decode(&reqPayload)
 	if err != nil {
 		log.Printf("Ошибка декодирования JSON (CreateRate): %v\n", err)
-		writeJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{Error: "Некорректный формат запроса: " + err.Error()})
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
 		return
 	}
 
@@ -50,13 +51,7 @@ func (h *RateHandler) CreateRate(w http.ResponseWriter, r *http.Request) {
 	err = h.rateService.CreateRate(r.Context(), reqPayload.Value)
 	if err != nil {
 		log.Printf("Ошибка при вызове сервиса CreateRate: %v\n", err)
-		// TODO: Улучшить маппинг ошибок сервиса на HTTP статусы
-		// Пока возвращаем 500 для всех ошибок сервиса, но можно проверять тип ошибки
-		if err.Error() == "курс валюты должен быть положительным числом" {
-			writeJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
-		} else {
-			writeJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{Error: "Внутренняя ошибка сервера"})
-		}
+		writeError(w, r, err)
 		return
 	}
 
@@ -68,10 +63,16 @@ func (h *RateHandler) CreateRate(w http.ResponseWriter, r *http.Request) {
 // @Description  Возвращает среднее значение для последних N курсов валют.
 // @Tags         Rates
 // @Produce      json
-// @Param        limit query int false "Количество последних курсов для расчета (по умолчанию 10)" minimum(1)
+// @Param        limit query int false "Количество последних курсов для расчета (по умолчанию 10, максимум 500)" minimum(1)
+// @Param        If-None-Match header string false "ETag из предыдущего ответа"
+// @Param        If-Modified-Since header string false "Last-Modified из предыдущего ответа"
 // @Success      200  {object}  models.AverageResponse "Средний курс и количество записей"
+// @Header       200  {string}  ETag "Версия курсов"
+// @Header       200  {string}  Last-Modified "Время самого свежего курса"
+// @Success      304  "Курсы не изменились"
 // @Failure      400  {object}  models.ErrorResponse "Некорректное значение параметра 'limit'"
 // @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
 // @Router       /rates/average [get]
 func (h *RateHandler) GetAverageRate(w http.ResponseWriter, r *http.Request) {
 	limitStr := r.URL.Query().Get("limit")
@@ -81,18 +82,95 @@ func (h *RateHandler) GetAverageRate(w http.ResponseWriter, r *http.Request) {
 		limit, err = strconv.Atoi(limitStr)
 		if err != nil || limit <= 0 {
 			log.Printf("Некорректное значение параметра limit: %s\n", limitStr)
-			writeJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{Error: "Некорректное значение параметра 'limit'"})
+			writeRequestError(w, r, "Некорректное значение параметра 'limit'")
 			return
 		}
 	}
 
+	if h.notModified(w, r, formatJSON) {
+		return
+	}
+
 	// Вызов сервисного слоя
 	avgResponse, err := h.rateService.GetAverageRate(r.Context(), limit)
 	if err != nil {
 		log.Printf("Ошибка при вызове сервиса GetAverageRate: %v\n", err)
-		writeJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{Error: "Внутренняя ошибка сервера"})
+		writeError(w, r, err)
 		return
 	}
 
 	writeJSONResponse(w, http.StatusOK, avgResponse)
 }
+
+// rateExportColumns - колонки CSV-выгрузки курсов.
+var rateExportColumns = []string{"value", "timestamp"}
+
+// ListRates godoc
+// @Summary      Получить историю курсов
+// @Description  Возвращает последние курсы, начиная с самого свежего. Формат ответа выбирается параметром format или заголовком Accept (text/csv, application/x-ndjson); CSV и NDJSON отдаются потоком по мере чтения из БД и по умолчанию содержат всю историю. Выгрузка может длиться до 10 минут; в трейлерах X-Export-Status (complete или truncated) и X-Export-Rows сообщается, полный ли файл: без трейлера complete выгрузка неполная.
+// @Tags         Rates
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        limit query int false "Количество последних курсов (по умолчанию 10, максимум 500; для CSV и NDJSON - все, без ограничения)" minimum(1)
+// @Param        format query string false "Формат ответа (приоритетнее заголовка Accept)" Enums(json, csv, ndjson)
+// @Param        If-None-Match header string false "ETag из предыдущего ответа"
+// @Param        If-Modified-Since header string false "Last-Modified из предыдущего ответа"
+// @Success      200  {array}   models.Rate "Курсы, начиная с самого свежего"
+// @Header       200  {string}  ETag "Версия курсов и формат ответа"
+// @Header       200  {string}  Last-Modified "Время самого свежего курса"
+// @Success      304  "Курсы не изменились"
+// @Failure      400  {object}  models.ErrorResponse "Некорректное значение параметра 'limit' или 'format'"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /rates [get]
+func (h *RateHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Accept") // Формат ответа зависит от заголовка Accept
+	format, err := responseFormat(r)
+	if err != nil {
+		writeRequestError(w, r, err.Error())
+		return
+	}
+	limit := 0 // Значение по умолчанию выбирает сервис
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			log.Printf("Некорректное значение параметра limit: %s\n", limitStr)
+			writeRequestError(w, r, "Некорректное значение параметра 'limit'")
+			return
+		}
+	}
+
+	if h.notModified(w, r, format) {
+		return
+	}
+
+	if format != formatJSON {
+		export := newExportWriter(w, format, "rates", rateExportColumns)
+		err := h.rateService.ExportRates(r.Context(), limit, func(rate models.Rate) error {
+			return export.writeRow([]string{formatExportFloat(rate.Value), formatExportTime(rate.Timestamp)}, rate)
+		})
+		export.finish(r, err)
+		return
+	}
+
+	rates, err := h.rateService.ListRates(r.Context(), limit)
+	if err != nil {
+		log.Printf("Ошибка при вызове сервиса ListRates: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	if rates == nil {
+		rates = []models.Rate{}
+	}
+	writeJSONResponse(w, http.StatusOK, rates)
+}
+
+// notModified добавляет в ответ ETag и Last-Modified по версии курсов и отвечает 304, если у клиента
+// актуальные данные. Если версию получить не удалось, ответ формируется как обычно.
+func (h *RateHandler) notModified(w http.ResponseWriter, r *http.Request, format string) bool {
+	version, err := h.rateService.GetRatesVersion(r.Context())
+	if err != nil {
+		log.Printf("Ошибка получения версии курсов: %v\n", err)
+		return false
+	}
+	return checkNotModified(w, r, dataETag(version, format), version.LastModified)
+}
@@ -0,0 +1,84 @@
+// internal/handlers/ratelimit.go
+package handlers
+
+import (
+	"currency-service/internal/ratelimit"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// clientIP возвращает IP-адрес клиента (RemoteAddr уже исправлен middleware.RealIP).
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimitClient возвращает, чью квоту расходует запрос: API-ключа, пользователя с JWT
+// или, для запросов без Principal, IP-адреса клиента.
+func rateLimitClient(r *http.Request) string {
+	if id := principalID(r.Context()); id != "" {
+		return id
+	}
+	return "ip:" + clientIP(r)
+}
+
+// ceilSeconds округляет длительность до целых секунд вверх для заголовков.
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// RateLimit ограничивает частоту запросов каждого клиента по квотам limiter и добавляет
+// заголовки RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset и RateLimit-Policy.
+// При исчерпании квоты отвечает 429 с Retry-After. Если хранилище квот недоступно, запрос пропускается.
+// Должен применяться после Authenticate, иначе все запросы считаются по IP.
+func RateLimit(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return rateLimit(rateLimitClient, func(r *http.Request, client string) (ratelimit.Result, error) {
+		return limiter.Allow(r.Context(), client, r)
+	})
+}
+
+// RateLimitByIP ограничивает частоту всех запросов с одного IP-адреса по квоте адреса limiter
+// (заголовки и ответ 429 - как у RateLimit). Применяется до Authenticate: запросы без ключа
+// или с недействительным ключом тоже расходуют квоту и не могут бесконечно нагружать проверку ключей.
+func RateLimitByIP(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return rateLimit(clientIP, func(r *http.Request, ip string) (ratelimit.Result, error) {
+		return limiter.AllowIP(r.Context(), ip)
+	})
+}
+
+// rateLimit списывает запрос клиента, определенного clientOf, через allow.
+func rateLimit(clientOf func(*http.Request) string, allow func(*http.Request, string) (ratelimit.Result, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := clientOf(r)
+			result, err := allow(r, client)
+			if err != nil {
+				log.Printf("Ошибка проверки квоты %s для %s %s, запрос пропущен: %v\n", client, r.Method, r.URL.Path, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !result.Limit.IsZero() {
+				header := w.Header()
+				header.Set("RateLimit-Limit", strconv.Itoa(result.Limit.Requests))
+				header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+				header.Set("RateLimit-Reset", ceilSeconds(result.Reset))
+				header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%s", result.Limit.Requests, ceilSeconds(result.Limit.Period)))
+			}
+			if !result.Allowed {
+				log.Printf("Отклонен запрос %s %s: клиент %s превысил квоту %v\n", r.Method, r.URL.Path, client, result.Limit)
+				w.Header().Set("Retry-After", ceilSeconds(result.RetryAfter))
+				writeError(w, r, ratelimit.ErrRateLimited)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
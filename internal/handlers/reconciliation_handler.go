@@ -0,0 +1,104 @@
+// internal/handlers/reconciliation_handler.go
+package handlers
+
+import (
+	_ "currency-service/internal/models" // Модели используются в аннотациях swag
+	"currency-service/internal/service"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ReconciliationHandler обрабатывает административные HTTP-запросы сверки балансов.
+type ReconciliationHandler struct {
+	reconciliationService service.ReconciliationService
+}
+
+// NewReconciliationHandler создает новый экземпляр обработчика сверки.
+func NewReconciliationHandler(svc service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{reconciliationService: svc}
+}
+
+// RunReconciliation godoc
+// @Summary      Запустить сверку балансов
+// @Description  Пересчитывает баланс каждого кошелька по истории движений, сравнивает с сохраненным балансом и сохраняет найденные расхождения.
+// @Tags         Admin
+// @Produce      json
+// @Success      200  {object}  models.ReconciliationReportResponse "Результат сверки"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/reconciliation/runs [post]
+func (h *ReconciliationHandler) RunReconciliation(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.reconciliationService.Reconcile(r.Context())
+	if err != nil {
+		log.Printf("Ошибка из сервиса Reconcile: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// ListReconciliationRuns godoc
+// @Summary      Список сверок
+// @Description  Возвращает последние сверки балансов (новые первыми) с количеством проверенных кошельков и найденных расхождений.
+// @Tags         Admin
+// @Produce      json
+// @Param        limit query int false "Количество сверок (по умолчанию 20)" minimum(1)
+// @Success      200  {object}  models.ListReconciliationRunsResponse "Список сверок"
+// @Failure      400  {object}  models.ErrorResponse "Некорректное значение параметра 'limit'"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/reconciliation/runs [get]
+func (h *ReconciliationHandler) ListReconciliationRuns(w http.ResponseWriter, r *http.Request) {
+	limit := 20 // Значение по умолчанию
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			writeRequestError(w, r, "Некорректное значение параметра 'limit'")
+			return
+		}
+	}
+
+	resp, err := h.reconciliationService.ListRuns(r.Context(), limit)
+	if err != nil {
+		log.Printf("Ошибка из сервиса ListRuns: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// GetReconciliationReport godoc
+// @Summary      Отчет о сверке
+// @Description  Возвращает сверку и найденные ею расхождения. Вместо ID можно указать latest для последней сверки.
+// @Tags         Admin
+// @Produce      json
+// @Param        id path string true "ID сверки или latest"
+// @Success      200  {object}  models.ReconciliationReportResponse "Отчет о сверке"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID сверки"
+// @Failure      404  {object}  models.ErrorResponse "Сверка не найдена"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /admin/reconciliation/runs/{id} [get]
+func (h *ReconciliationHandler) GetReconciliationReport(w http.ResponseWriter, r *http.Request) {
+	var runID int64 // 0 - последняя сверка
+	if idStr := chi.URLParam(r, "id"); idStr != "latest" {
+		var err error
+		runID, err = strconv.ParseInt(idStr, 10, 64)
+		if err != nil || runID <= 0 {
+			writeRequestError(w, r, "Некорректный ID сверки")
+			return
+		}
+	}
+
+	resp, err := h.reconciliationService.GetReport(r.Context(), runID)
+	if err != nil {
+		log.Printf("Ошибка из сервиса GetReport: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
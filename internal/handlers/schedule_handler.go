@@ -0,0 +1,146 @@
+// internal/handlers/schedule_handler.go
+package handlers
+
+import (
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ScheduleHandler обрабатывает HTTP-запросы, связанные с запланированными операциями (постоянными поручениями).
+type ScheduleHandler struct {
+	scheduleService service.ScheduleService
+}
+
+// NewScheduleHandler создает новый экземпляр обработчика расписаний.
+func NewScheduleHandler(svc service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{scheduleService: svc}
+}
+
+// parseScheduleID читает ID расписания из пути. При ошибке сам отправляет ответ 400.
+func parseScheduleID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeRequestError(w, r, "Некорректный ID расписания")
+		return 0, false
+	}
+	return id, true
+}
+
+// CreateSchedule godoc
+// @Summary      Создать запланированную операцию
+// @Description  Создает однократную или повторяющуюся операцию с кошельком (balance_update, transfer или convert). Повторение: once, daily, weekly, monthly (опорная точка - start_at) или cron (5 полей, UTC). Операции выполняет фоновая задача через сервис кошельков; при нехватке средств запуск повторяется, а после нескольких неудач подряд расписание отключается. Запуски выполняются с правами создателя расписания и заново проверяют владельца кошелька.
+// @Tags         Schedules
+// @Accept       json
+// @Produce      json
+// @Param        schedule body models.CreateScheduleRequest true "Операция, сумма и правило повторения"
+// @Success      201  {object}  models.Schedule "Расписание создано"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный запрос, операция, сумма или правило повторения"
+// @Failure      403  {object}  models.ErrorResponse "Для списания с кошелька владельца нужен user_id владельца"
+// @Failure      404  {object}  models.ErrorResponse "Кошелек не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /schedules [post]
+func (h *ScheduleHandler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateScheduleRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (CreateSchedule): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+	req.CreatedBy, req.Staff = principalID(r.Context()), isStaff(r.Context())
+
+	schedule, err := h.scheduleService.CreateSchedule(r.Context(), req)
+	if err != nil {
+		log.Printf("Ошибка из сервиса CreateSchedule: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, schedule)
+}
+
+// GetSchedule godoc
+// @Summary      Получить расписание
+// @Description  Возвращает расписание по ID, включая время следующего запуска, статус и число неудач подряд.
+// @Tags         Schedules
+// @Produce      json
+// @Param        id path int true "ID расписания"
+// @Success      200  {object}  models.Schedule "Расписание"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID расписания"
+// @Failure      404  {object}  models.ErrorResponse "Расписание не найдено"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /schedules/{id} [get]
+func (h *ScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+	schedule, err := h.scheduleService.GetSchedule(r.Context(), id)
+	if err != nil {
+		log.Printf("Ошибка из сервиса GetSchedule: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, schedule)
+}
+
+// ListScheduleRuns godoc
+// @Summary      История запусков расписания
+// @Description  Возвращает результаты всех запусков расписания, новые первыми.
+// @Tags         Schedules
+// @Produce      json
+// @Param        id path int true "ID расписания"
+// @Success      200  {object}  models.ListScheduleRunsResponse "История запусков"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID расписания"
+// @Failure      404  {object}  models.ErrorResponse "Расписание не найдено"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /schedules/{id}/runs [get]
+func (h *ScheduleHandler) ListScheduleRuns(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+	resp, err := h.scheduleService.ListScheduleRuns(r.Context(), id)
+	if err != nil {
+		log.Printf("Ошибка из сервиса ListScheduleRuns: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// CancelSchedule godoc
+// @Summary      Отменить расписание
+// @Description  Отключает активное расписание. История запусков сохраняется.
+// @Tags         Schedules
+// @Produce      json
+// @Param        id path int true "ID расписания"
+// @Success      200  {object}  models.Schedule "Расписание отключено"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID расписания"
+// @Failure      404  {object}  models.ErrorResponse "Расписание не найдено"
+// @Failure      409  {object}  models.ErrorResponse "Расписание уже выполнено или отключено"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /schedules/{id} [delete]
+func (h *ScheduleHandler) CancelSchedule(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+	schedule, err := h.scheduleService.CancelSchedule(r.Context(), id)
+	if err != nil {
+		log.Printf("Ошибка из сервиса CancelSchedule: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, schedule)
+}
@@ -0,0 +1,149 @@
+// internal/handlers/tests/apikey_handler_test.go
+package handlers_test
+
+import (
+	"context"
+	"currency-service/internal/models"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты API-ключей и проверки прав ---
+
+// issueTestKey выпускает ключ с указанными правами в обход HTTP.
+func issueTestKey(t *testing.T, scopes ...string) models.IssueAPIKeyResponse {
+	t.Helper()
+	issued, err := testAPIKeySvc.IssueAPIKey(context.Background(), models.IssueAPIKeyRequest{Name: t.Name(), Scopes: scopes})
+	require.NoError(t, err)
+	return issued
+}
+
+// withKey заменяет ключ запроса (пустой ключ - запрос без заголовка Authorization).
+func withKey(req *http.Request, key string) *http.Request {
+	req.Header.Del("Authorization")
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	return req
+}
+
+func TestAPIKeyAuth_MissingKey(t *testing.T) {
+	cleanupTestDB(t)
+
+	req := withKey(createRequest(t, http.MethodGet, "/api/v1/wallets/", nil), "")
+	rr := executeRequest(t, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "UNAUTHORIZED", resp.Code)
+}
+
+func TestAPIKeyAuth_UnknownKey(t *testing.T) {
+	cleanupTestDB(t)
+
+	req := withKey(createRequest(t, http.MethodGet, "/api/v1/wallets/", nil), "csk_unknown")
+	rr := executeRequest(t, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestAPIKeyAuth_InsufficientScope(t *testing.T) {
+	cleanupTestDB(t)
+	readOnly := issueTestKey(t, models.ScopeWalletsRead)
+
+	// Чтение разрешено
+	rr := executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/wallets/", nil), readOnly.Key))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Пополнение требует wallets:write
+	payload := models.UpdateBalanceRequest{WalletNumber: "8800013", Amount: 100}
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload), readOnly.Key))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "INSUFFICIENT_SCOPE", resp.Code)
+
+	// Публикация курса требует rates:write, административные эндпоинты - admin
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": 90}), readOnly.Key))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/admin/api-keys/", nil), readOnly.Key))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	var count int
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM wallets").Scan(&count))
+	assert.Zero(t, count, "Кошелек не должен был создаться")
+}
+
+func TestAPIKeyHandler_IssueListRevoke(t *testing.T) {
+	cleanupTestDB(t)
+
+	// Выпуск
+	payload := models.IssueAPIKeyRequest{Name: "rates-publisher", Scopes: []string{models.ScopeRatesWrite, models.ScopeRatesWrite}}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/admin/api-keys/", payload))
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var issued models.IssueAPIKeyResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &issued))
+	assert.True(t, strings.HasPrefix(issued.Key, issued.Prefix))
+	assert.Equal(t, []string{models.ScopeRatesWrite}, issued.Scopes, "Повторяющиеся права должны схлопываться")
+
+	// В БД хранится только хэш
+	var stored int
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM api_keys WHERE key_hash = $1", issued.Key).Scan(&stored))
+	assert.Zero(t, stored, "Ключ не должен храниться в открытом виде")
+
+	// Новый ключ работает
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": 91.5}), issued.Key))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	// Список без значений ключей
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/admin/api-keys/", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), issued.Key)
+	var list models.ListAPIKeysResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	found := false
+	for _, key := range list.Keys {
+		if key.ID == issued.ID {
+			found = true
+			assert.Nil(t, key.RevokedAt)
+		}
+	}
+	assert.True(t, found, "Выпущенный ключ должен быть в списке")
+
+	// Отзыв
+	rr = executeRequest(t, createRequest(t, http.MethodDelete, "/api/v1/admin/api-keys/"+strconv.FormatInt(issued.ID, 10), nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var revoked models.APIKey
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &revoked))
+	assert.NotNil(t, revoked.RevokedAt)
+
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": 92}), issued.Key))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code, "Отозванный ключ не должен приниматься")
+}
+
+func TestAPIKeyHandler_Issue_InvalidScope(t *testing.T) {
+	cleanupTestDB(t)
+
+	payload := models.IssueAPIKeyRequest{Name: "bad", Scopes: []string{"wallets:delete"}}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/admin/api-keys/", payload))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "INVALID_SCOPE", resp.Code)
+}
+
+func TestAPIKeyHandler_Revoke_NotFound(t *testing.T) {
+	cleanupTestDB(t)
+
+	rr := executeRequest(t, createRequest(t, http.MethodDelete, "/api/v1/admin/api-keys/999999", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
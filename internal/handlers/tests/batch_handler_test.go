@@ -0,0 +1,136 @@
+// internal/handlers/tests/batch_handler_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты для пакетных операций с кошельками ---
+// Используют testRouter и testDB из main_test.go
+
+const (
+	batchEmployer = "8100018"
+	batchAlice    = "8100026"
+	batchBob      = "8100034"
+	batchCarol    = "8100042"
+	batchMissing  = "8100091"
+)
+
+func seedBatchWallets(t *testing.T) {
+	t.Helper()
+	cleanupTestDB(t)
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, 300), ($2, 0), ($3, 0), ($4, 10)",
+		batchEmployer, batchAlice, batchBob, batchCarol)
+	require.NoError(t, err)
+}
+
+func executeBatch(t *testing.T, req models.BatchRequest) (int, models.BatchResponse) {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/batch", req))
+	var resp models.BatchResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp), rr.Body.String())
+	return rr.Code, resp
+}
+
+func TestWalletHandler_Batch_AtomicSuccess(t *testing.T) {
+	seedBatchWallets(t)
+
+	code, resp := executeBatch(t, models.BatchRequest{Operations: []models.BatchOperation{
+		{Type: models.BatchOperationTransfer, WalletNumber: batchEmployer, TargetWalletNumber: batchAlice, Amount: 100},
+		{Type: models.BatchOperationTransfer, WalletNumber: batchEmployer, TargetWalletNumber: batchBob, Amount: 150},
+		{Type: models.BatchOperationDeposit, WalletNumber: batchCarol, Amount: 5},
+		// Использует средства, зачисленные первой операцией
+		{Type: models.BatchOperationWithdrawal, WalletNumber: batchAlice, Amount: 30},
+	}})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.BatchModeAtomic, resp.Mode)
+	assert.True(t, resp.Committed)
+	assert.Equal(t, 4, resp.Succeeded)
+	assert.Equal(t, 0, resp.Failed)
+	require.Len(t, resp.Results, 4)
+	assert.InDelta(t, 50.0, resp.Results[1].Balance, 0.001)
+	assert.InDelta(t, 150.0, resp.Results[1].TargetBalance, 0.001)
+
+	assert.InDelta(t, 50.0, getWalletFromList(t, batchEmployer).Balance, 0.001)
+	assert.InDelta(t, 70.0, getWalletFromList(t, batchAlice).Balance, 0.001)
+	assert.InDelta(t, 150.0, getWalletFromList(t, batchBob).Balance, 0.001)
+	assert.InDelta(t, 15.0, getWalletFromList(t, batchCarol).Balance, 0.001)
+}
+
+func TestWalletHandler_Batch_AtomicRollsBackOnFailure(t *testing.T) {
+	seedBatchWallets(t)
+
+	code, resp := executeBatch(t, models.BatchRequest{Mode: models.BatchModeAtomic, Operations: []models.BatchOperation{
+		{Type: models.BatchOperationTransfer, WalletNumber: batchEmployer, TargetWalletNumber: batchAlice, Amount: 200},
+		{Type: models.BatchOperationTransfer, WalletNumber: batchEmployer, TargetWalletNumber: batchBob, Amount: 200}, // Не хватает средств
+		{Type: models.BatchOperationDeposit, WalletNumber: batchCarol, Amount: 5},
+	}})
+	require.Equal(t, http.StatusConflict, code)
+	assert.False(t, resp.Committed)
+	assert.Equal(t, 0, resp.Succeeded)
+	assert.Equal(t, 1, resp.Failed)
+	require.Len(t, resp.Results, 3)
+	assert.Equal(t, models.BatchItemRolledBack, resp.Results[0].Status)
+	assert.Equal(t, models.BatchItemFailed, resp.Results[1].Status)
+	assert.NotEmpty(t, resp.Results[1].Error)
+	assert.Equal(t, models.BatchItemSkipped, resp.Results[2].Status)
+
+	// Ни одна операция не сохранилась
+	assert.InDelta(t, 300.0, getWalletFromList(t, batchEmployer).Balance, 0.001)
+	assert.InDelta(t, 0.0, getWalletFromList(t, batchAlice).Balance, 0.001)
+	assert.InDelta(t, 10.0, getWalletFromList(t, batchCarol).Balance, 0.001)
+
+	// Некорректная операция отклоняет пакет до выполнения
+	code, resp = executeBatch(t, models.BatchRequest{Operations: []models.BatchOperation{
+		{Type: models.BatchOperationDeposit, WalletNumber: batchAlice, Amount: 10},
+		{Type: "refund", WalletNumber: batchAlice, Amount: 10},
+	}})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, models.BatchItemFailed, resp.Results[1].Status)
+}
+
+func TestWalletHandler_Batch_BestEffort(t *testing.T) {
+	seedBatchWallets(t)
+
+	code, resp := executeBatch(t, models.BatchRequest{Mode: models.BatchModeBestEffort, Operations: []models.BatchOperation{
+		{Type: models.BatchOperationTransfer, WalletNumber: batchEmployer, TargetWalletNumber: batchAlice, Amount: 200},
+		{Type: models.BatchOperationTransfer, WalletNumber: batchEmployer, TargetWalletNumber: batchBob, Amount: 200}, // Не хватает средств
+		{Type: models.BatchOperationDeposit, WalletNumber: batchMissing, Amount: 5},                                   // Кошелек не существует
+		{Type: models.BatchOperationDeposit, WalletNumber: batchCarol, Amount: -1},                                    // Некорректная сумма
+		{Type: models.BatchOperationTransfer, WalletNumber: batchEmployer, TargetWalletNumber: batchBob, Amount: 100},
+	}})
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, resp.Committed)
+	assert.Equal(t, 2, resp.Succeeded)
+	assert.Equal(t, 3, resp.Failed)
+	statuses := make([]string, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		statuses = append(statuses, result.Status)
+	}
+	assert.Equal(t, []string{
+		models.BatchItemSucceeded, models.BatchItemFailed, models.BatchItemFailed, models.BatchItemFailed, models.BatchItemSucceeded,
+	}, statuses)
+
+	assert.InDelta(t, 0.0, getWalletFromList(t, batchEmployer).Balance, 0.001)
+	assert.InDelta(t, 200.0, getWalletFromList(t, batchAlice).Balance, 0.001)
+	assert.InDelta(t, 100.0, getWalletFromList(t, batchBob).Balance, 0.001)
+	assert.InDelta(t, 10.0, getWalletFromList(t, batchCarol).Balance, 0.001)
+}
+
+func TestWalletHandler_Batch_InvalidRequest(t *testing.T) {
+	cleanupTestDB(t)
+
+	for _, req := range []models.BatchRequest{
+		{},
+		{Mode: "sometimes", Operations: []models.BatchOperation{{Type: models.BatchOperationDeposit, WalletNumber: batchAlice, Amount: 1}}},
+	} {
+		rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/batch", req))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+}
@@ -0,0 +1,145 @@
+// internal/handlers/tests/cache_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/handlers"
+	"currency-service/internal/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты HTTP-кэширования: ETag, Last-Modified, условный GET и Cache-Control ---
+
+// conditionalGet выполняет GET с заголовком условия header (If-None-Match или If-Modified-Since).
+func conditionalGet(t *testing.T, url, header, value string) int {
+	t.Helper()
+	req := createRequest(t, http.MethodGet, url, nil)
+	req.Header.Set(header, value)
+	return executeRequest(t, req).Code
+}
+
+func TestCache_AverageRate(t *testing.T) {
+	cleanupTestDB(t)
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": 90}))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/rates/average", nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	etag, lastModified := rr.Header().Get("ETag"), rr.Header().Get("Last-Modified")
+	require.NotEmpty(t, etag)
+	require.NotEmpty(t, lastModified)
+	assert.Equal(t, "private, max-age=30", rr.Header().Get("Cache-Control"), "значение из правила маршрута")
+
+	assert.Equal(t, http.StatusNotModified, conditionalGet(t, "/api/v1/rates/average", "If-None-Match", etag))
+	assert.Equal(t, http.StatusNotModified, conditionalGet(t, "/api/v1/rates/average", "If-Modified-Since", lastModified))
+
+	// Новый курс меняет ETag
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": 100}))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	assert.Equal(t, http.StatusOK, conditionalGet(t, "/api/v1/rates/average", "If-None-Match", etag))
+}
+
+func TestCache_RatesListETagDependsOnFormat(t *testing.T) {
+	cleanupTestDB(t)
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": 90}))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	jsonResp := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/rates/", nil))
+	csvResp := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/rates/?format=csv", nil))
+	require.Equal(t, http.StatusOK, jsonResp.Code)
+	require.Equal(t, http.StatusOK, csvResp.Code)
+	assert.NotEqual(t, jsonResp.Header().Get("ETag"), csvResp.Header().Get("ETag"))
+	assert.Equal(t, "private, no-cache", jsonResp.Header().Get("Cache-Control"), "значение по умолчанию")
+
+	req := createRequest(t, http.MethodGet, "/api/v1/rates/", nil)
+	req.Header.Set("Accept", "text/csv")
+	req.Header.Set("If-None-Match", csvResp.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, executeRequest(t, req).Code)
+}
+
+func TestCache_WalletList(t *testing.T) {
+	cleanupTestDB(t)
+	insertOwnedWallet(t, "9400011", 100, "")
+
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/", nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.NotEmpty(t, rr.Header().Get("Last-Modified"))
+	assert.Equal(t, http.StatusNotModified, conditionalGet(t, "/api/v1/wallets/", "If-None-Match", etag))
+
+	// Холд меняет доступный баланс, а с ним и ETag списка
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/9400011/holds", models.CreateHoldRequest{Amount: 10}))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+	etag = rr.Header().Get("ETag")
+
+	// Как и новый кошелек
+	insertOwnedWallet(t, "9400029", 0, "")
+	assert.Equal(t, http.StatusOK, conditionalGet(t, "/api/v1/wallets/", "If-None-Match", etag))
+}
+
+func TestCache_WalletListVersionFollowsCommitOrder(t *testing.T) {
+	cleanupTestDB(t)
+	insertOwnedWallet(t, "9400037", 100, "")
+	insertOwnedWallet(t, "9400045", 100, "")
+
+	// Первая транзакция начинается раньше второй, а коммитится позже: ее NOW() (время начала) меньше,
+	// чем у уже видимого клиенту изменения, но ETag все равно должен смениться
+	early, err := testDB.Begin()
+	require.NoError(t, err)
+	defer early.Rollback()
+	_, err = early.Exec("SELECT NOW()")
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	_, err = testDB.Exec("UPDATE wallets SET balance = 50 WHERE wallet_number = '9400045'")
+	require.NoError(t, err)
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/", nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	etag := rr.Header().Get("ETag")
+
+	_, err = early.Exec("UPDATE wallets SET balance = 70 WHERE wallet_number = '9400037'")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, conditionalGet(t, "/api/v1/wallets/", "If-None-Match", etag), "изменение еще не закоммичено")
+	require.NoError(t, early.Commit())
+	assert.Equal(t, http.StatusOK, conditionalGet(t, "/api/v1/wallets/", "If-None-Match", etag))
+}
+
+func TestCache_SharedCachingIsRejected(t *testing.T) {
+	// Ответы зависят от ключа, поэтому общим кэшам (прокси, CDN) их хранить нельзя
+	for _, spec := range []string{
+		"GET /api/v1/rates/average=public, max-age=30",
+		"GET /api/v1/rates/average=max-age=30, PUBLIC",
+		"GET /api/v1/wallets=private, s-maxage=60",
+	} {
+		_, err := handlers.ParseCacheControlRules(spec)
+		assert.Error(t, err, spec)
+	}
+	assert.Error(t, handlers.ValidateCacheControl("public, no-cache"))
+	assert.NoError(t, handlers.ValidateCacheControl(testCacheControlDefault))
+
+	rules, err := handlers.ParseCacheControlRules(testCacheControlRoutes)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "private, max-age=30", rules[0].Value)
+}
+
+func TestCache_ErrorsAreNotCached(t *testing.T) {
+	cleanupTestDB(t)
+
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/rates/average?limit=0", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+
+	// Записи не получают Cache-Control
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": 90}))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Empty(t, rr.Header().Get("Cache-Control"))
+}
@@ -0,0 +1,135 @@
+// internal/handlers/tests/export_test.go
+package handlers_test
+
+import (
+	"bufio"
+	"currency-service/internal/models"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты выгрузки списков в CSV и NDJSON ---
+
+func TestExport_RatesCSV(t *testing.T) {
+	cleanupTestDB(t)
+	for _, value := range []float64{90, 95.5, 100} {
+		rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": value}))
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/rates/?format=csv", nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4, "заголовок и все курсы")
+	assert.Equal(t, []string{"value", "timestamp"}, records[0])
+	assert.Equal(t, "100", records[1][0])
+	assert.Equal(t, "95.5", records[2][0])
+
+	// С limit выгружаются только последние курсы
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/rates/?format=csv&limit=1", nil))
+	records, err = csv.NewReader(rr.Body).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+}
+
+func TestExport_RatesJSONByDefault(t *testing.T) {
+	cleanupTestDB(t)
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": 90}))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/rates/", nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var rates []models.Rate
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &rates))
+	require.Len(t, rates, 1)
+	assert.InDelta(t, 90, rates[0].Value, 0.001)
+}
+
+func TestExport_WalletsNDJSONByAccept(t *testing.T) {
+	cleanupTestDB(t)
+	insertOwnedWallet(t, "9300013", 10, "")
+	insertOwnedWallet(t, "9300021", 200, "")
+	insertOwnedWallet(t, "9300039", 300, "")
+
+	req := createRequest(t, http.MethodGet, "/api/v1/wallets/?min_balance=100&sort=wallet_number&order=desc", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	rr := executeRequest(t, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+
+	var numbers []string
+	scanner := bufio.NewScanner(rr.Body)
+	for scanner.Scan() {
+		var wallet models.WalletDetailsResponse
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &wallet))
+		assert.False(t, wallet.CreatedAt.IsZero())
+		numbers = append(numbers, wallet.Number)
+	}
+	assert.Equal(t, []string{"9300039", "9300021"}, numbers)
+}
+
+func TestExport_WalletsCSVIsNotPaginated(t *testing.T) {
+	cleanupTestDB(t)
+	insertOwnedWallet(t, "9300013", 10, "")
+	insertOwnedWallet(t, "9300021", 20, "")
+	insertOwnedWallet(t, "9300047", 30, "")
+
+	// Параметр format приоритетнее Accept
+	req := createRequest(t, http.MethodGet, "/api/v1/wallets/?format=csv&sort=wallet_number", nil)
+	req.Header.Set("Accept", "application/json")
+	rr := executeRequest(t, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, "number", records[0][0])
+	assert.Equal(t, []string{"9300013", "10"}, records[1][:2])
+	assert.Equal(t, "9300047", records[3][0])
+
+	// Пустая выгрузка содержит только заголовок
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/?format=csv&status=closed", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, strings.Count(rr.Body.String(), "\n"))
+}
+
+func TestExport_Errors(t *testing.T) {
+	cleanupTestDB(t)
+
+	// Ошибки до первой строки возвращаются обычным JSON-ответом
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/?format=csv&sort=owner", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/rates/?format=xml", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestExport_TrailersReportCompletion(t *testing.T) {
+	cleanupTestDB(t)
+	insertOwnedWallet(t, "9300013", 10, "")
+	insertOwnedWallet(t, "9300021", 20, "")
+
+	for _, format := range []string{"csv", "ndjson"} {
+		rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/?format="+format, nil))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Equal(t, "X-Export-Status, X-Export-Rows", rr.Header().Get("Trailer"))
+		trailer := rr.Result().Trailer
+		assert.Equal(t, "complete", trailer.Get("X-Export-Status"), format)
+		assert.Equal(t, "2", trailer.Get("X-Export-Rows"), format)
+	}
+
+	// Обычный JSON-ответ трейлеров не содержит
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Trailer"))
+}
@@ -0,0 +1,144 @@
+// internal/handlers/tests/fee_handler_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты для комиссий за конвертацию ---
+// Используют testRouter и testDB из main_test.go
+
+// feeWalletNumber - кошелек комиссий по умолчанию (FEE_WALLET_NUMBER)
+const feeWalletNumber = "9999996"
+
+// insertFeeWallet создает кошелек комиссий: сервер создает его при запуске (EnsureFeeWallet), а cleanupTestDB удаляет.
+func insertFeeWallet(t *testing.T) {
+	t.Helper()
+	insertOwnedWallet(t, feeWalletNumber, 0, "")
+}
+
+func setTestFeeRule(t *testing.T, rule models.FeeRule) models.FeeRule {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPut, "/api/v1/admin/fees", rule))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var saved models.FeeRule
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &saved))
+	return saved
+}
+
+func convertTest(t *testing.T, walletNumber string, amount float64) (int, models.ConvertResponse) {
+	t.Helper()
+	payload := models.ConvertRequest{SourceWalletNumber: walletNumber, AmountToConvert: amount}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/convert", payload))
+	var resp models.ConvertResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp), rr.Body.String())
+	return rr.Code, resp
+}
+
+func TestFeeHandler_ConversionFee(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8300014"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 1000.0)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO rates (value) VALUES ($1)", 2.0)
+	require.NoError(t, err)
+	insertFeeWallet(t)
+
+	// Без правил комиссия не взимается и на кошелек комиссий ничего не зачисляется
+	code, resp := convertTest(t, walletNumber, 10)
+	require.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 0.0, resp.Fee, 0.001)
+	assert.InDelta(t, 980.0, resp.RemainingBalance, 0.001)
+	var count int
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM wallet_movements WHERE wallet_number = $1", feeWalletNumber).Scan(&count))
+	assert.Zero(t, count)
+	assert.InDelta(t, 0.0, getWalletFromList(t, feeWalletNumber).Balance, 0.001)
+
+	// 1% + 0.5, но не больше 5
+	general := setTestFeeRule(t, models.FeeRule{Percent: 1, Fixed: 0.5, MaxFee: floatPtr(5)})
+	assert.Equal(t, models.FeeRuleWildcard, general.Pair)
+	assert.Equal(t, models.FeeRuleWildcard, general.Tier)
+
+	code, resp = convertTest(t, walletNumber, 100) // 200 по курсу, комиссия 2.5
+	require.Equal(t, http.StatusOK, code, resp.Message)
+	assert.InDelta(t, 200.0, resp.ConvertedAmount, 0.001)
+	assert.InDelta(t, 2.5, resp.Fee, 0.001)
+	assert.InDelta(t, 202.5, resp.TotalDebited, 0.001)
+	assert.InDelta(t, 777.5, resp.RemainingBalance, 0.001)
+	assert.InDelta(t, 777.5, getWalletFromList(t, walletNumber).Balance, 0.001)
+	assert.InDelta(t, 2.5, getWalletFromList(t, feeWalletNumber).Balance, 0.001)
+
+	code, resp = convertTest(t, walletNumber, 300) // 600 по курсу, комиссия ограничена 5
+	require.Equal(t, http.StatusOK, code, resp.Message)
+	assert.InDelta(t, 5.0, resp.Fee, 0.001)
+	assert.InDelta(t, 172.5, getWalletFromList(t, walletNumber).Balance, 0.001)
+	assert.InDelta(t, 7.5, getWalletFromList(t, feeWalletNumber).Balance, 0.001)
+
+	// Правило для пары и уровня точнее общего
+	setTestFeeRule(t, models.FeeRule{Pair: "USD/RUB", Tier: "standard", Fixed: 1})
+	code, resp = convertTest(t, walletNumber, 50)
+	require.Equal(t, http.StatusOK, code, resp.Message)
+	assert.InDelta(t, 1.0, resp.Fee, 0.001)
+	assert.InDelta(t, 71.5, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// Сумма по курсу хватает, но с комиссией - нет
+	code, resp = convertTest(t, walletNumber, 35.5)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.InDelta(t, 71.5, getWalletFromList(t, walletNumber).Balance, 0.001)
+	assert.InDelta(t, 8.5, getWalletFromList(t, feeWalletNumber).Balance, 0.001)
+}
+
+func TestFeeHandler_ConversionDoesNotCreateFeeWallet(t *testing.T) {
+	cleanupTestDB(t)
+	insertOwnedWallet(t, "8300055", 1000, "")
+	_, err := testDB.Exec("INSERT INTO rates (value) VALUES ($1)", 2.0)
+	require.NoError(t, err)
+	setTestFeeRule(t, models.FeeRule{Fixed: 1})
+
+	// Кошелек комиссий создается только при запуске: без него конвертация с комиссией не выполняется
+	code, _ := convertTest(t, "8300055", 10)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.InDelta(t, 1000.0, getWalletFromList(t, "8300055").Balance, 0.001)
+	var count int
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM wallets WHERE wallet_number = $1", feeWalletNumber).Scan(&count))
+	assert.Zero(t, count)
+}
+
+func TestFeeHandler_ManageRules(t *testing.T) {
+	cleanupTestDB(t)
+
+	for _, rule := range []models.FeeRule{
+		{Pair: "usd-rub", Percent: 1},
+		{Tier: "Gold!", Percent: 1},
+		{Percent: 100},
+		{Fixed: -1},
+		{MinFee: floatPtr(5), MaxFee: floatPtr(1)},
+	} {
+		rr := executeRequest(t, createRequest(t, http.MethodPut, "/api/v1/admin/fees", rule))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+
+	first := setTestFeeRule(t, models.FeeRule{Pair: "USD/RUB", Percent: 1})
+	// Повторная установка для той же пары и уровня заменяет правило
+	replaced := setTestFeeRule(t, models.FeeRule{Pair: "USD/RUB", Percent: 2})
+	assert.Equal(t, first.ID, replaced.ID)
+	assert.InDelta(t, 2.0, replaced.Percent, 0.001)
+
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/admin/fees", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list models.ListFeeRulesResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	require.Len(t, list.Rules, 1)
+
+	rr = executeRequest(t, createRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/admin/fees/%d", first.ID), nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	rr = executeRequest(t, createRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/admin/fees/%d", first.ID), nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
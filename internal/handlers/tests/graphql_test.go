@@ -0,0 +1,211 @@
+// internal/handlers/tests/graphql_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/auth"
+	"currency-service/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты GraphQL ---
+
+// executeGraphQL выполняет запрос GraphQL с ключом или токеном credentials и разбирает ответ.
+func executeGraphQL(t *testing.T, credentials, query string, variables map[string]any) models.GraphQLResponse {
+	t.Helper()
+	req := withKey(createRequest(t, http.MethodPost, "/api/v1/graphql", models.GraphQLRequest{Query: query, Variables: variables}), credentials)
+	rr := executeRequest(t, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp models.GraphQLResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	return resp
+}
+
+// graphQLErrorCodes возвращает коды ошибок ответа GraphQL (extensions.code).
+func graphQLErrorCodes(resp models.GraphQLResponse) []string {
+	codes := make([]string, 0, len(resp.Errors))
+	for _, e := range resp.Errors {
+		code, _ := e.Extensions["code"].(string)
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+func TestGraphQL_DashboardQuery(t *testing.T) {
+	cleanupTestDB(t)
+	for _, value := range []float64{90, 100} {
+		rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": value}))
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	for _, amount := range []float64{100, -30} {
+		rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance",
+			models.UpdateBalanceRequest{WalletNumber: "9200015", Amount: amount}))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	resp := executeGraphQL(t, testAPIKey, `query Dashboard($number: String!) {
+		latestRate { value }
+		rates(limit: 5) { value }
+		averageRate(limit: 2) { average count }
+		wallet(number: $number) { number balance movements(limit: 5) { kind amount balanceAfter } }
+		wallets(sortBy: "wallet_number") { wallets { number } nextCursor }
+		missing: wallet(number: "9200023") { number }
+	}`, map[string]any{"number": "9200015"})
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		LatestRate  struct{ Value float64 }
+		Rates       []struct{ Value float64 }
+		AverageRate struct {
+			Average float64
+			Count   int
+		}
+		Wallet struct {
+			Number    string
+			Balance   float64
+			Movements []struct {
+				Kind         string
+				Amount       float64
+				BalanceAfter float64
+			}
+		}
+		Wallets struct {
+			Wallets    []struct{ Number string }
+			NextCursor *string
+		}
+		Missing *struct{ Number string }
+	}
+	raw, err := json.Marshal(resp.Data)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &data))
+
+	assert.InDelta(t, 100, data.LatestRate.Value, 0.001)
+	require.Len(t, data.Rates, 2)
+	assert.InDelta(t, 100, data.Rates[0].Value, 0.001)
+	assert.InDelta(t, 95, data.AverageRate.Average, 0.001)
+	assert.Equal(t, 2, data.AverageRate.Count)
+
+	assert.Equal(t, "9200015", data.Wallet.Number)
+	assert.InDelta(t, 70, data.Wallet.Balance, 0.001)
+	require.Len(t, data.Wallet.Movements, 2)
+	assert.InDelta(t, -30, data.Wallet.Movements[0].Amount, 0.001)
+	assert.InDelta(t, 70, data.Wallet.Movements[0].BalanceAfter, 0.001)
+
+	require.Len(t, data.Wallets.Wallets, 1)
+	assert.Nil(t, data.Wallets.NextCursor)
+	assert.Nil(t, data.Missing)
+}
+
+func TestGraphQL_Mutations(t *testing.T) {
+	cleanupTestDB(t)
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/rates/", map[string]float64{"value": 2}))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	insertOwnedWallet(t, "9200049", 0, "")
+
+	resp := executeGraphQL(t, testAPIKey, `mutation {
+		updateBalance(walletNumber: "9200031", amount: 100) { newBalance version movementId }
+	}`, nil)
+	require.Empty(t, resp.Errors)
+	update := resp.Data.(map[string]any)["updateBalance"].(map[string]any)
+	assert.InDelta(t, 100, update["newBalance"], 0.001)
+	assert.NotNil(t, update["movementId"])
+
+	resp = executeGraphQL(t, testAPIKey, `mutation {
+		convert(sourceWalletNumber: "9200031", amountToConvert: 10, destinationWalletNumber: "9200049") {
+			rateUsed convertedAmount destinationWalletNumber destinationBalance
+		}
+	}`, nil)
+	require.Empty(t, resp.Errors)
+	convert := resp.Data.(map[string]any)["convert"].(map[string]any)
+	assert.InDelta(t, 2, convert["rateUsed"], 0.001)
+	assert.Equal(t, "9200049", convert["destinationWalletNumber"])
+
+	// Ошибки сервиса возвращаются с кодом в extensions, а сами мутации - null
+	resp = executeGraphQL(t, testAPIKey, `mutation {
+		updateBalance(walletNumber: "9200031", amount: -1000) { newBalance }
+	}`, nil)
+	assert.Equal(t, []string{"INSUFFICIENT_FUNDS"}, graphQLErrorCodes(resp))
+	assert.Nil(t, resp.Data.(map[string]any)["updateBalance"])
+	resp = executeGraphQL(t, testAPIKey, `mutation {
+		stale: updateBalance(walletNumber: "9200031", amount: -1, expectedVersion: 1) { newBalance }
+	}`, nil)
+	assert.Equal(t, []string{"VERSION_MISMATCH"}, graphQLErrorCodes(resp))
+
+	// Мутации через GET не выполняются
+	req := createRequest(t, http.MethodGet, "/api/v1/graphql?query="+url.QueryEscape(`mutation { updateBalance(walletNumber: "9200031", amount: 1) { newBalance } }`), nil)
+	rr = executeRequest(t, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var getResp models.GraphQLResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &getResp))
+	assert.Equal(t, []string{"INVALID_REQUEST"}, graphQLErrorCodes(getResp))
+}
+
+func TestGraphQL_Auth(t *testing.T) {
+	cleanupTestDB(t)
+	alice := createTestUser(t, "Алиса", "Иванова")
+	insertOwnedWallet(t, "9200056", 100, alice.ID)
+	insertOwnedWallet(t, "9200023", 100, "")
+
+	// Без ключа запрос отклоняется целиком
+	rr := executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/graphql", models.GraphQLRequest{Query: "{ latestRate { value } }"}), ""))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// Ключ только для курсов: курсы доступны, кошельки и мутации - нет
+	ratesOnly := issueTestKey(t, models.ScopeRatesWrite).Key
+	resp := executeGraphQL(t, ratesOnly, `{ latestRate { value } wallet(number: "9200056") { number } }`, nil)
+	assert.Equal(t, []string{"INSUFFICIENT_SCOPE"}, graphQLErrorCodes(resp))
+	readOnly := issueTestKey(t, models.ScopeWalletsRead).Key
+	resp = executeGraphQL(t, readOnly, `mutation { updateBalance(walletNumber: "9200056", amount: -1) { newBalance } }`, nil)
+	assert.Equal(t, []string{"INSUFFICIENT_SCOPE"}, graphQLErrorCodes(resp))
+
+	// Клиенту доступны только свои кошельки, без списка и без пополнения
+	customer := mintHS256(t, testClaims(auth.RoleCustomer, alice.ID))
+	resp = executeGraphQL(t, customer, `{ wallet(number: "9200056") { number } }`, nil)
+	assert.Empty(t, resp.Errors)
+	resp = executeGraphQL(t, customer, `{ wallet(number: "9200023") { number } }`, nil)
+	assert.Equal(t, []string{"WALLET_OWNER_MISMATCH"}, graphQLErrorCodes(resp))
+	resp = executeGraphQL(t, customer, `{ wallets { nextCursor } }`, nil)
+	assert.Equal(t, []string{"INSUFFICIENT_SCOPE"}, graphQLErrorCodes(resp))
+	resp = executeGraphQL(t, customer, `mutation { updateBalance(walletNumber: "9200056", amount: 10) { newBalance } }`, nil)
+	assert.Equal(t, []string{"INSUFFICIENT_SCOPE"}, graphQLErrorCodes(resp))
+	resp = executeGraphQL(t, customer, `mutation { updateBalance(walletNumber: "9200023", amount: -10) { newBalance } }`, nil)
+	assert.Len(t, resp.Errors, 1)
+	resp = executeGraphQL(t, customer, `mutation { updateBalance(walletNumber: "9200056", amount: -10) { newBalance } }`, nil)
+	assert.Empty(t, resp.Errors)
+}
+
+func TestGraphQL_Limits(t *testing.T) {
+	cleanupTestDB(t)
+	insertOwnedWallet(t, "9200064", 100, "")
+
+	// Несколько мутаций в одном запросе не выполняются: каждое списание - отдельный запрос с квотой
+	resp := executeGraphQL(t, testAPIKey, `mutation {
+		a: updateBalance(walletNumber: "9200064", amount: -1) { newBalance }
+		...More
+	}
+	fragment More on Mutation { b: updateBalance(walletNumber: "9200064", amount: -1) { newBalance } }`, nil)
+	assert.Equal(t, []string{"INVALID_REQUEST"}, graphQLErrorCodes(resp))
+	assert.Nil(t, resp.Data)
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/9200064", nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var wallet models.WalletDetailsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &wallet))
+	assert.InDelta(t, 100, wallet.Balance, 0.001, "ни одна мутация не выполнена")
+
+	// Вложенные списки умножают стоимость запроса, в том числе через переменные
+	resp = executeGraphQL(t, testAPIKey, `{ wallets(limit: 500) { wallets { movements(limit: 500) { id } } } }`, nil)
+	assert.Equal(t, []string{"INVALID_REQUEST"}, graphQLErrorCodes(resp))
+	resp = executeGraphQL(t, testAPIKey, `query($n: Int = 500) { wallets(limit: $n) { wallets { movements(limit: $n) { id } } } }`, nil)
+	assert.Equal(t, []string{"INVALID_REQUEST"}, graphQLErrorCodes(resp))
+	resp = executeGraphQL(t, testAPIKey, `query($n: Int) { wallets(limit: 10) { wallets { movements(limit: $n) { id } } } }`, map[string]any{"n": 5})
+	assert.Empty(t, resp.Errors)
+
+	// История курсов ограничена так же, как страница кошельков
+	resp = executeGraphQL(t, testAPIKey, `{ rates(limit: 1000000) { value } }`, nil)
+	assert.Equal(t, []string{"INVALID_PAGE_SIZE"}, graphQLErrorCodes(resp))
+}
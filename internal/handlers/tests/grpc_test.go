@@ -0,0 +1,231 @@
+// internal/handlers/tests/grpc_test.go
+package handlers_test
+
+import (
+	"context"
+	"currency-service/internal/auth"
+	"currency-service/internal/models"
+	"currency-service/internal/ratelimit"
+	"net/http"
+	"testing"
+	"time"
+
+	currencyv1 "currency-service/api/currency/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// --- Тесты gRPC API ---
+
+// grpcContext возвращает контекст вызова с ключом или токеном в метаданных authorization
+// (пустое значение - вызов без авторизации) и дополнительными парами метаданных kv.
+func grpcContext(credentials string, kv ...string) context.Context {
+	if credentials != "" {
+		kv = append(kv, "authorization", "Bearer "+credentials)
+	}
+	return metadata.NewOutgoingContext(context.Background(), metadata.Pairs(kv...))
+}
+
+// requireGRPCError проверяет код статуса gRPC и код ошибки сервиса в google.rpc.ErrorInfo.
+func requireGRPCError(t *testing.T, err error, code codes.Code, reason string) *status.Status {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok, "ожидался статус gRPC, получено: %v", err)
+	require.Equal(t, code, st.Code(), st.Message())
+	var info *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		if i, ok := detail.(*errdetails.ErrorInfo); ok {
+			info = i
+		}
+	}
+	require.NotNil(t, info, "в статусе нет ErrorInfo")
+	assert.Equal(t, reason, info.Reason)
+	return st
+}
+
+func TestGRPC_Rates(t *testing.T) {
+	cleanupTestDB(t)
+	client := currencyv1.NewRateServiceClient(testGRPCConn)
+	ctx := grpcContext(testAPIKey)
+
+	_, err := client.GetLatestRate(ctx, &currencyv1.GetLatestRateRequest{})
+	requireGRPCError(t, err, codes.NotFound, "NO_RATES")
+
+	for _, value := range []float64{90, 100} {
+		_, err := client.CreateRate(ctx, &currencyv1.CreateRateRequest{Value: value})
+		require.NoError(t, err)
+	}
+	_, err = client.CreateRate(ctx, &currencyv1.CreateRateRequest{Value: -1})
+	requireGRPCError(t, err, codes.InvalidArgument, "RATE_NOT_POSITIVE")
+
+	latest, err := client.GetLatestRate(ctx, &currencyv1.GetLatestRateRequest{})
+	require.NoError(t, err)
+	assert.InDelta(t, 100, latest.Value, 0.001)
+	assert.NotNil(t, latest.Timestamp)
+
+	avg, err := client.GetAverageRate(ctx, &currencyv1.GetAverageRateRequest{Limit: 2})
+	require.NoError(t, err)
+	assert.InDelta(t, 95, avg.Average, 0.001)
+	assert.EqualValues(t, 2, avg.Count)
+}
+
+func TestGRPC_WalletOperations(t *testing.T) {
+	cleanupTestDB(t)
+	client := currencyv1.NewWalletServiceClient(testGRPCConn)
+	ctx := grpcContext(testAPIKey)
+
+	deposit, err := client.UpdateBalance(ctx, &currencyv1.UpdateBalanceRequest{WalletNumber: "9100017", Amount: 100})
+	require.NoError(t, err)
+	assert.InDelta(t, 100, deposit.NewBalance, 0.001)
+	assert.NotZero(t, deposit.MovementId)
+	_, err = client.UpdateBalance(ctx, &currencyv1.UpdateBalanceRequest{WalletNumber: "9100025", Amount: 10})
+	require.NoError(t, err)
+
+	withdrawal, err := client.UpdateBalance(ctx, &currencyv1.UpdateBalanceRequest{WalletNumber: "9100017", Amount: -30})
+	require.NoError(t, err)
+	assert.InDelta(t, 70, withdrawal.NewBalance, 0.001)
+
+	_, err = client.UpdateBalance(ctx, &currencyv1.UpdateBalanceRequest{WalletNumber: "9100017", Amount: -1000})
+	requireGRPCError(t, err, codes.FailedPrecondition, "INSUFFICIENT_FUNDS")
+
+	_, err = client.UpdateBalance(ctx, &currencyv1.UpdateBalanceRequest{WalletNumber: "9100017", Amount: -1, ExpectedVersion: 1})
+	requireGRPCError(t, err, codes.Aborted, "VERSION_MISMATCH")
+
+	wallet, err := client.GetWallet(ctx, &currencyv1.GetWalletRequest{Number: "9100017"})
+	require.NoError(t, err)
+	assert.InDelta(t, 70, wallet.Wallet.Balance, 0.001)
+	assert.NotNil(t, wallet.Wallet.CreatedAt)
+
+	list, err := client.ListWallets(ctx, &currencyv1.ListWalletsRequest{SortBy: models.WalletSortNumber})
+	require.NoError(t, err)
+	require.Len(t, list.Wallets, 2)
+	assert.Equal(t, "9100017", list.Wallets[0].Number)
+
+	// Фильтры те же, что и в GET /wallets
+	list, err = client.ListWallets(ctx, &currencyv1.ListWalletsRequest{MinBalance: wrapperspb.Double(50), MaxBalance: wrapperspb.Double(100)})
+	require.NoError(t, err)
+	require.Len(t, list.Wallets, 1)
+	assert.Equal(t, "9100017", list.Wallets[0].Number)
+	list, err = client.ListWallets(ctx, &currencyv1.ListWalletsRequest{CreatedTo: timestamppb.New(time.Now().Add(-time.Hour))})
+	require.NoError(t, err)
+	assert.Empty(t, list.Wallets)
+	list, err = client.ListWallets(ctx, &currencyv1.ListWalletsRequest{CreatedFrom: timestamppb.New(time.Now().Add(-time.Hour))})
+	require.NoError(t, err)
+	assert.Len(t, list.Wallets, 2)
+	_, err = client.ListWallets(ctx, &currencyv1.ListWalletsRequest{MinBalance: wrapperspb.Double(100), MaxBalance: wrapperspb.Double(50)})
+	requireGRPCError(t, err, codes.InvalidArgument, "INVALID_LIST_FILTER")
+
+	created, err := client.CreateWallet(ctx, &currencyv1.CreateWalletRequest{})
+	require.NoError(t, err)
+	assert.Len(t, created.Number, 7)
+}
+
+func TestGRPC_ErrorsAreLocalized(t *testing.T) {
+	cleanupTestDB(t)
+	client := currencyv1.NewWalletServiceClient(testGRPCConn)
+
+	_, err := client.GetWallet(grpcContext(testAPIKey), &currencyv1.GetWalletRequest{Number: "9100033"})
+	ru := requireGRPCError(t, err, codes.NotFound, "WALLET_NOT_FOUND")
+	_, err = client.GetWallet(grpcContext(testAPIKey, "accept-language", "en"), &currencyv1.GetWalletRequest{Number: "9100033"})
+	en := requireGRPCError(t, err, codes.NotFound, "WALLET_NOT_FOUND")
+	assert.NotEqual(t, ru.Message(), en.Message())
+}
+
+func TestGRPC_Auth(t *testing.T) {
+	cleanupTestDB(t)
+	client := currencyv1.NewWalletServiceClient(testGRPCConn)
+	request := &currencyv1.UpdateBalanceRequest{WalletNumber: "9100041", Amount: 10}
+
+	_, err := client.UpdateBalance(grpcContext(""), request)
+	requireGRPCError(t, err, codes.Unauthenticated, "UNAUTHORIZED")
+
+	_, err = client.UpdateBalance(grpcContext("csk_unknown"), request)
+	requireGRPCError(t, err, codes.Unauthenticated, "UNAUTHORIZED")
+
+	readOnly := issueTestKey(t, models.ScopeWalletsRead).Key
+	_, err = client.UpdateBalance(grpcContext(readOnly), request)
+	requireGRPCError(t, err, codes.PermissionDenied, "INSUFFICIENT_SCOPE")
+
+	customer := mintHS256(t, testClaims(auth.RoleCustomer, "00000000-0000-0000-0000-000000000001"))
+	_, err = client.UpdateBalance(grpcContext(customer), request)
+	requireGRPCError(t, err, codes.PermissionDenied, "INSUFFICIENT_SCOPE")
+
+	operator := mintRS256(t, testClaims(auth.RoleOperator, "operator-1"))
+	_, err = client.UpdateBalance(grpcContext(operator), request)
+	require.NoError(t, err)
+}
+
+func TestGRPC_DebitOwnedWalletRequiresOwnerOrStaff(t *testing.T) {
+	cleanupTestDB(t)
+	client := currencyv1.NewWalletServiceClient(testGRPCConn)
+	alice := createTestUser(t, "Алиса", "Иванова")
+	insertOwnedWallet(t, "8600041", 100, alice.ID)
+	debit := &currencyv1.UpdateBalanceRequest{WalletNumber: "8600041", Amount: -10}
+
+	serviceKey := issueTestKey(t, models.ScopeWalletsRead, models.ScopeWalletsWrite).Key
+	_, err := client.UpdateBalance(grpcContext(serviceKey), debit)
+	requireGRPCError(t, err, codes.PermissionDenied, "WALLET_OWNER_REQUIRED")
+
+	_, err = client.UpdateBalance(grpcContext(serviceKey), &currencyv1.UpdateBalanceRequest{WalletNumber: "8600041", Amount: -10, UserId: alice.ID})
+	require.NoError(t, err)
+	_, err = client.UpdateBalance(grpcContext(testAPIKey), debit)
+	require.NoError(t, err, "ключ с правом admin - сотрудник")
+}
+
+func TestGRPC_RateLimit(t *testing.T) {
+	cleanupTestDB(t)
+	rules := []ratelimit.Rule{{Method: http.MethodPost, Pattern: currencyv1.RateService_GetAverageRate_FullMethodName, Limit: ratelimit.Limit{Requests: 1, Period: time.Minute}}}
+	server := testNewGRPCServer(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 2, Period: time.Minute}, ratelimit.Limit{}, rules))
+	defer server.Stop()
+	conn, err := serveGRPCInMemory(server)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := currencyv1.NewRateServiceClient(conn)
+	key := issueTestKey(t, models.ScopeRatesWrite).Key
+
+	// Общая квота ключа
+	var header metadata.MD
+	_, err = client.CreateRate(grpcContext(key), &currencyv1.CreateRateRequest{Value: 90}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, header.Get("ratelimit-limit"))
+	assert.Equal(t, []string{"1"}, header.Get("ratelimit-remaining"))
+	_, err = client.GetLatestRate(grpcContext(key), &currencyv1.GetLatestRateRequest{})
+	require.NoError(t, err)
+	_, err = client.GetLatestRate(grpcContext(key), &currencyv1.GetLatestRateRequest{}, grpc.Header(&header))
+	requireGRPCError(t, err, codes.ResourceExhausted, "RATE_LIMITED")
+	assert.Equal(t, []string{"30"}, header.Get("retry-after"))
+
+	// Квота метода отдельная, у другого ключа - свои квоты
+	_, err = client.GetAverageRate(grpcContext(key), &currencyv1.GetAverageRateRequest{Limit: 1})
+	require.NoError(t, err)
+	_, err = client.GetAverageRate(grpcContext(key), &currencyv1.GetAverageRateRequest{Limit: 1})
+	requireGRPCError(t, err, codes.ResourceExhausted, "RATE_LIMITED")
+	_, err = client.GetLatestRate(grpcContext(testAPIKey), &currencyv1.GetLatestRateRequest{})
+	require.NoError(t, err)
+}
+
+func TestGRPC_IPRateLimitAppliesBeforeAuthentication(t *testing.T) {
+	cleanupTestDB(t)
+	server := testNewGRPCServer(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{}, ratelimit.Limit{Requests: 2, Period: time.Minute}, nil))
+	defer server.Stop()
+	conn, err := serveGRPCInMemory(server)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := currencyv1.NewRateServiceClient(conn)
+
+	// Перебор ключей упирается в квоту адреса
+	_, err = client.GetLatestRate(grpcContext("csk_guess1"), &currencyv1.GetLatestRateRequest{})
+	requireGRPCError(t, err, codes.Unauthenticated, "UNAUTHORIZED")
+	_, err = client.GetLatestRate(grpcContext("csk_guess2"), &currencyv1.GetLatestRateRequest{})
+	requireGRPCError(t, err, codes.Unauthenticated, "UNAUTHORIZED")
+	_, err = client.GetLatestRate(grpcContext(testAPIKey), &currencyv1.GetLatestRateRequest{})
+	requireGRPCError(t, err, codes.ResourceExhausted, "RATE_LIMITED")
+}
@@ -0,0 +1,139 @@
+// internal/handlers/tests/hold_handler_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты для Hold Handler ---
+// Используют testRouter и testDB из main_test.go
+
+// createTestHold создает холд через API и возвращает его
+func createTestHold(t *testing.T, walletNumber string, payload models.CreateHoldRequest) models.Hold {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/"+walletNumber+"/holds", payload))
+	require.Equal(t, http.StatusCreated, rr.Code, "Не удалось создать холд: %s", rr.Body.String())
+
+	var hold models.Hold
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &hold))
+	return hold
+}
+
+// getWalletFromList находит кошелек в ответе GET /wallets
+func getWalletFromList(t *testing.T, walletNumber string) models.Wallet {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp models.ListWalletsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	for _, w := range resp.Wallets {
+		if w.Number == walletNumber {
+			return w
+		}
+	}
+	t.Fatalf("Кошелек %s не найден в списке", walletNumber)
+	return models.Wallet{}
+}
+
+func TestHoldHandler_AuthorizeReducesAvailableBalance(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "4000014"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+
+	hold := createTestHold(t, walletNumber, models.CreateHoldRequest{Amount: 40, Reference: "auth-1"})
+	assert.Equal(t, models.HoldStatusActive, hold.Status)
+
+	wallet := getWalletFromList(t, walletNumber)
+	assert.InDelta(t, 100.0, wallet.Balance, 0.001, "Учетный баланс не должен меняться")
+	assert.InDelta(t, 60.0, wallet.AvailableBalance, 0.001, "Доступный баланс должен уменьшиться на сумму холда")
+
+	// Списание больше доступного баланса запрещено, даже если учетного хватает
+	payload := models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: -70}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	// Повторный холд с тем же reference отклоняется
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/"+walletNumber+"/holds", models.CreateHoldRequest{Amount: 1, Reference: "auth-1"}))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestHoldHandler_AuthorizeInsufficientFunds(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "4000022"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 10.0)
+	require.NoError(t, err)
+
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/"+walletNumber+"/holds", models.CreateHoldRequest{Amount: 11}))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestHoldHandler_PartialCapture(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "4000030"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+	hold := createTestHold(t, walletNumber, models.CreateHoldRequest{Amount: 50})
+
+	// Нельзя списать больше зарезервированного
+	rr := executeRequest(t, createRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/holds/%d/capture", hold.ID), models.CaptureHoldRequest{Amount: 60}))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = executeRequest(t, createRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/holds/%d/capture", hold.ID), models.CaptureHoldRequest{Amount: 30}))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var captured models.Hold
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &captured))
+	assert.Equal(t, models.HoldStatusCaptured, captured.Status)
+	assert.InDelta(t, 30.0, captured.CapturedAmount, 0.001)
+
+	// Остаток резерва освобожден: учетный и доступный баланс совпадают
+	wallet := getWalletFromList(t, walletNumber)
+	assert.InDelta(t, 70.0, wallet.Balance, 0.001)
+	assert.InDelta(t, 70.0, wallet.AvailableBalance, 0.001)
+
+	// Повторное списание невозможно
+	rr = executeRequest(t, createRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/holds/%d/capture", hold.ID), nil))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestHoldHandler_Void(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "4000048"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+	hold := createTestHold(t, walletNumber, models.CreateHoldRequest{Amount: 25})
+
+	rr := executeRequest(t, createRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/holds/%d/void", hold.ID), nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	wallet := getWalletFromList(t, walletNumber)
+	assert.InDelta(t, 100.0, wallet.Balance, 0.001)
+	assert.InDelta(t, 100.0, wallet.AvailableBalance, 0.001)
+}
+
+func TestHoldHandler_ExpiredHoldIsReleased(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "4000055"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+	hold := createTestHold(t, walletNumber, models.CreateHoldRequest{Amount: 25})
+
+	// Имитируем истечение срока холда
+	_, err = testDB.Exec("UPDATE wallet_holds SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1", hold.ID)
+	require.NoError(t, err)
+
+	wallet := getWalletFromList(t, walletNumber)
+	assert.InDelta(t, 100.0, wallet.AvailableBalance, 0.001, "Истекший холд не должен уменьшать доступный баланс")
+
+	rr := executeRequest(t, createRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/holds/%d/capture", hold.ID), nil))
+	assert.Equal(t, http.StatusConflict, rr.Code, "Истекший холд нельзя списать")
+}
@@ -0,0 +1,201 @@
+// internal/handlers/tests/interest_handler_test.go
+package handlers_test
+
+import (
+	"context"
+	"currency-service/internal/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты для начисления процентов ---
+// Используют testRouter и testDB из main_test.go
+
+func createTestInterestPlan(t *testing.T, req models.CreateInterestPlanRequest) models.InterestPlan {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/admin/interest/plans", req))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var plan models.InterestPlan
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &plan))
+	return plan
+}
+
+func accrueTestInterest(t *testing.T, day time.Time) (int, models.InterestAccrualRunResponse) {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/admin/interest/accruals?date="+day.Format("2006-01-02"), nil))
+	var resp models.InterestAccrualRunResponse
+	if rr.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	}
+	return rr.Code, resp
+}
+
+func postTestInterest(t *testing.T, month time.Time) (int, models.InterestPostingResponse) {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/admin/interest/postings?month="+month.Format("2006-01"), nil))
+	var resp models.InterestPostingResponse
+	if rr.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	}
+	return rr.Code, resp
+}
+
+func TestInterestHandler_AccrueAndPost(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber, withoutPlan := "8400012", "8400020"
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -2, 0)
+	opened := month.AddDate(0, 0, -1)
+
+	// Кошельки открыты до начала месяца, история - одно пополнение
+	for _, number := range []string{walletNumber, withoutPlan} {
+		_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance, created_at) VALUES ($1, 1000, $2)", number, opened)
+		require.NoError(t, err)
+		_, err = testDB.Exec("INSERT INTO wallet_movements (wallet_number, kind, amount, balance_after, created_at) VALUES ($1, 'deposit', 1000, 1000, $2)", number, opened)
+		require.NoError(t, err)
+	}
+
+	// 36% годовых по ACT/360 - ровно 1.00 в день на 1000
+	plan := createTestInterestPlan(t, models.CreateInterestPlanRequest{Name: "Накопительный", AnnualRate: 36, DayCount: models.DayCountActual360})
+	rr := executeRequest(t, createRequest(t, http.MethodPut, "/api/v1/wallets/"+walletNumber+"/interest", models.SetWalletInterestPlanRequest{PlanID: &plan.ID}))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	// План назначен сегодня - прошлые дни не начисляются
+	code, resp := accrueTestInterest(t, month)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, resp.WalletsAccrued)
+
+	_, err := testDB.Exec("UPDATE wallet_interest_plan_history SET assigned_at = $1 WHERE wallet_number = $2", opened, walletNumber)
+	require.NoError(t, err)
+	for _, day := range []time.Time{month, month.AddDate(0, 0, 1)} {
+		code, resp = accrueTestInterest(t, day)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, resp.WalletsAccrued)
+		assert.InDelta(t, 1.0, resp.TotalAccrued, 0.0001)
+	}
+	// Повторный запуск за тот же день ничего не начисляет
+	code, resp = accrueTestInterest(t, month)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, resp.WalletsAccrued)
+
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/"+walletNumber+"/interest", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var interest models.WalletInterestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &interest))
+	require.NotNil(t, interest.Plan)
+	assert.Equal(t, plan.ID, interest.Plan.ID)
+	assert.InDelta(t, 2.0, interest.AccruedUnposted, 0.0001)
+	assert.Len(t, interest.Accruals, 2)
+
+	// Зачисление за месяц - одно движение, повторное ничего не зачисляет
+	code, posting := postTestInterest(t, month)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, posting.WalletsPosted)
+	assert.InDelta(t, 2.0, posting.TotalPosted, 0.001)
+	assert.InDelta(t, 1002.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+	assert.InDelta(t, 1000.0, getWalletFromList(t, withoutPlan).Balance, 0.001)
+
+	code, posting = postTestInterest(t, month)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, posting.WalletsPosted)
+	assert.InDelta(t, 1002.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// Незавершившиеся день и месяц отклоняются
+	code, _ = accrueTestInterest(t, now)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	code, _ = postTestInterest(t, now)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+}
+
+func TestInterestHandler_InvalidPlans(t *testing.T) {
+	cleanupTestDB(t)
+
+	for _, req := range []models.CreateInterestPlanRequest{
+		{Name: "", AnnualRate: 5},
+		{Name: "Отрицательный", AnnualRate: -1},
+		{Name: "Неизвестная конвенция", AnnualRate: 5, DayCount: "ACT/364"},
+	} {
+		rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/admin/interest/plans", req))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, req.Name)
+	}
+
+	plan := createTestInterestPlan(t, models.CreateInterestPlanRequest{Name: "Базовый", AnnualRate: 5})
+	assert.Equal(t, models.DayCountActual365, plan.DayCount)
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/admin/interest/plans", models.CreateInterestPlanRequest{Name: "Базовый", AnnualRate: 6}))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	missingPlan := int64(999999)
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, 0)", "8400012")
+	require.NoError(t, err)
+	rr = executeRequest(t, createRequest(t, http.MethodPut, "/api/v1/wallets/8400012/interest", models.SetWalletInterestPlanRequest{PlanID: &missingPlan}))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestInterestHandler_DailyRunCatchesUpWithPlanHistory(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8400038"
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	month := thisMonth.AddDate(0, -2, 0)
+	opened := month.AddDate(0, 0, -1)
+
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance, created_at) VALUES ($1, 1000, $2)", walletNumber, opened)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO wallet_movements (wallet_number, kind, amount, balance_after, created_at) VALUES ($1, 'deposit', 1000, 1000, $2)", walletNumber, opened)
+	require.NoError(t, err)
+
+	// По ACT/360 на 1000: 1.00 в день по базовому плану и 2.00 по повышенному
+	base := createTestInterestPlan(t, models.CreateInterestPlanRequest{Name: "Базовый", AnnualRate: 36, DayCount: models.DayCountActual360})
+	raised := createTestInterestPlan(t, models.CreateInterestPlanRequest{Name: "Повышенный", AnnualRate: 72, DayCount: models.DayCountActual360})
+	rr := executeRequest(t, createRequest(t, http.MethodPut, "/api/v1/wallets/"+walletNumber+"/interest", models.SetWalletInterestPlanRequest{PlanID: &raised.ID}))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// Базовый план действовал с открытия, повышенный - с середины третьего дня месяца
+	raisedFrom := month.AddDate(0, 0, 2).Add(12 * time.Hour)
+	_, err = testDB.Exec("UPDATE wallet_interest_plan_history SET assigned_at = $1 WHERE wallet_number = $2", raisedFrom, walletNumber)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO wallet_interest_plan_history (wallet_number, plan_id, assigned_at) VALUES ($1, $2, $3)", walletNumber, base.ID, opened)
+	require.NoError(t, err)
+
+	// Фоновая задача не запускалась с открытия: один запуск начисляет все дни и зачисляет каждый месяц отдельно
+	require.NoError(t, testInterestSvc.RunDailyInterest(context.Background()))
+
+	var days int
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM interest_accruals WHERE wallet_number = $1", walletNumber).Scan(&days))
+	assert.Equal(t, int(today.Sub(opened).Hours()/24), days, "начислены все завершившиеся дни")
+	for day, rate := range map[time.Time]float64{opened: 36, month.AddDate(0, 0, 1): 36, month.AddDate(0, 0, 2): 72, thisMonth.AddDate(0, 0, -1): 72} {
+		var annualRate float64
+		require.NoError(t, testDB.QueryRow("SELECT annual_rate FROM interest_accruals WHERE wallet_number = $1 AND accrual_date = $2",
+			walletNumber, day.Format("2006-01-02")).Scan(&annualRate))
+		assert.InDelta(t, rate, annualRate, 0.0001, day.Format("2006-01-02"))
+	}
+
+	var postings int
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM wallet_movements WHERE wallet_number = $1 AND kind = 'interest'", walletNumber).Scan(&postings))
+	assert.Equal(t, 3, postings, "по движению за каждый завершившийся месяц")
+	daysInMonth := int(month.AddDate(0, 1, 0).Sub(month).Hours() / 24)
+	daysLastMonth := int(thisMonth.Sub(month.AddDate(0, 1, 0)).Hours() / 24)
+	expected := 1000 + 1.0 + (2*1.0 + float64(daysInMonth-2)*2) + float64(daysLastMonth)*2
+	assert.InDelta(t, expected, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// Повторный запуск ничего не добавляет
+	require.NoError(t, testInterestSvc.RunDailyInterest(context.Background()))
+	assert.InDelta(t, expected, getWalletFromList(t, walletNumber).Balance, 0.001)
+}
+
+func TestInterestHandler_SetPlanRequiresAdmin(t *testing.T) {
+	cleanupTestDB(t)
+	insertOwnedWallet(t, "8400046", 0, "")
+	plan := createTestInterestPlan(t, models.CreateInterestPlanRequest{Name: "Базовый", AnnualRate: 5})
+
+	operator := issueTestKey(t, models.ScopeWalletsRead, models.ScopeWalletsWrite).Key
+	rr := executeRequest(t, withKey(createRequest(t, http.MethodPut, "/api/v1/wallets/8400046/interest", models.SetWalletInterestPlanRequest{PlanID: &plan.ID}), operator))
+	assert.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+	// Чтение плана доступно без права admin
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/wallets/8400046/interest", nil), operator))
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+}
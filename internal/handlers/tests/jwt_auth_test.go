@@ -0,0 +1,204 @@
+// internal/handlers/tests/jwt_auth_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/auth"
+	"currency-service/internal/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты JWT: роли и проверка владельца кошелька ---
+
+// testClaims возвращает утверждения действующего час токена пользователя userID с ролью role.
+func testClaims(role, userID string) auth.Claims {
+	now := time.Now()
+	return auth.Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			Issuer:    testJWTIssuer,
+			Audience:  jwt.ClaimStrings{testJWTAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+// mintHS256 выпускает токен, подписанный тестовым секретом HS256.
+func mintHS256(t *testing.T, claims auth.Claims) string {
+	t.Helper()
+	token, err := auth.SignHS256(testJWTSecret, claims)
+	require.NoError(t, err)
+	return token
+}
+
+// mintRS256 выпускает токен, подписанный тестовым ключом RS256 из JWKS.
+func mintRS256(t *testing.T, claims auth.Claims) string {
+	t.Helper()
+	token, err := auth.SignRS256(testRSAKey, testRSAKeyID, claims)
+	require.NoError(t, err)
+	return token
+}
+
+// insertOwnedWallet создает кошелек с балансом и владельцем (пустой ownerID - без владельца).
+func insertOwnedWallet(t *testing.T, number string, balance float64, ownerID string) {
+	t.Helper()
+	var owner any
+	if ownerID != "" {
+		owner = ownerID
+	}
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance, owner_id) VALUES ($1, $2, $3)", number, balance, owner)
+	require.NoError(t, err)
+}
+
+func TestJWTAuth_CustomerOwnWallets(t *testing.T) {
+	cleanupTestDB(t)
+	alice := createTestUser(t, "Алиса", "Иванова")
+	bob := createTestUser(t, "Борис", "Смирнов")
+	insertOwnedWallet(t, "8900003", 100, alice.ID)
+	insertOwnedWallet(t, "8900011", 100, bob.ID)
+	token := mintHS256(t, testClaims(auth.RoleCustomer, alice.ID))
+
+	// Чтение своего кошелька и своих данных
+	rr := executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/wallets/8900003", nil), token))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/users/"+alice.ID+"/wallets", nil), token))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Чужие кошелек и пользователь недоступны
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/wallets/8900011", nil), token))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/users/"+bob.ID, nil), token))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Списание со своего кошелька разрешено
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/balance",
+		models.UpdateBalanceRequest{WalletNumber: "8900003", Amount: -10}), token))
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// Списание с чужого кошелька запрещено, даже если указать чужой user_id
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/balance",
+		models.UpdateBalanceRequest{WalletNumber: "8900011", Amount: -10}), token))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/balance",
+		models.UpdateBalanceRequest{WalletNumber: "8900011", Amount: -10, UserID: bob.ID}), token))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Пополнение и эндпоинты не для клиентов запрещены
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/balance",
+		models.UpdateBalanceRequest{WalletNumber: "8900003", Amount: 10}), token))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/wallets/", nil), token))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/admin/fees/", nil), token))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	var aliceBalance, bobBalance float64
+	require.NoError(t, testDB.QueryRow("SELECT balance FROM wallets WHERE wallet_number = '8900003'").Scan(&aliceBalance))
+	require.NoError(t, testDB.QueryRow("SELECT balance FROM wallets WHERE wallet_number = '8900011'").Scan(&bobBalance))
+	assert.InDelta(t, 90, aliceBalance, 0.001)
+	assert.InDelta(t, 100, bobBalance, 0.001)
+}
+
+func TestJWTAuth_CustomerCannotDebitOwnerlessWallet(t *testing.T) {
+	cleanupTestDB(t)
+	alice := createTestUser(t, "Алиса", "Иванова")
+	insertOwnedWallet(t, "8900029", 100, "")
+	token := mintHS256(t, testClaims(auth.RoleCustomer, alice.ID))
+
+	rr := executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/balance",
+		models.UpdateBalanceRequest{WalletNumber: "8900029", Amount: -10}), token))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestJWTAuth_Roles(t *testing.T) {
+	cleanupTestDB(t)
+	operator := mintRS256(t, testClaims(auth.RoleOperator, "operator-1"))
+	admin := mintRS256(t, testClaims(auth.RoleAdmin, "admin-1"))
+
+	// Оператор работает с кошельками, но не с административными эндпоинтами
+	rr := executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/", models.CreateWalletRequest{}), operator))
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/admin/fees/", nil), operator))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Администратору доступно все
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/admin/fees/", nil), admin))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/wallets/", nil), admin))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestJWTAuth_RejectsInvalidTokens(t *testing.T) {
+	cleanupTestDB(t)
+
+	expired := testClaims(auth.RoleAdmin, "admin-1")
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+
+	wrongIssuer := testClaims(auth.RoleAdmin, "admin-1")
+	wrongIssuer.Issuer = "someone-else"
+
+	noExpiry := testClaims(auth.RoleAdmin, "admin-1")
+	noExpiry.ExpiresAt = nil
+
+	unknownRole := testClaims("superuser", "admin-1")
+
+	wrongSecret, err := auth.SignHS256([]byte("another-secret-for-hs256-tokens-0123456789"), testClaims(auth.RoleAdmin, "admin-1"))
+	require.NoError(t, err)
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims(auth.RoleAdmin, "admin-1")).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	tokens := map[string]string{
+		"expired":      mintHS256(t, expired),
+		"wrong issuer": mintHS256(t, wrongIssuer),
+		"no expiry":    mintHS256(t, noExpiry),
+		"unknown role": mintHS256(t, unknownRole),
+		"wrong secret": wrongSecret,
+		"alg none":     unsigned,
+	}
+	for name, token := range tokens {
+		t.Run(name, func(t *testing.T) {
+			rr := executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/admin/fees/", nil), token))
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		})
+	}
+}
+
+func TestJWTAuth_DebitOwnedWalletRequiresOwnerOrStaff(t *testing.T) {
+	cleanupTestDB(t)
+	alice := createTestUser(t, "Алиса", "Иванова")
+	walletNumber := "8600033"
+	insertOwnedWallet(t, walletNumber, 100, alice.ID)
+	debit := models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: -10}
+
+	// Ключ сервиса без права admin не может списать с кошелька клиента, не указав владельца
+	serviceKey := issueTestKey(t, models.ScopeWalletsRead, models.ScopeWalletsWrite).Key
+	rr := executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/balance", debit), serviceKey))
+	assert.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+	assert.Contains(t, rr.Body.String(), "WALLET_OWNER_REQUIRED")
+
+	withOwner := debit
+	withOwner.UserID = alice.ID
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/balance", withOwner), serviceKey))
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	// Пополнение владельца не требует
+	deposit := models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: 5}
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/balance", deposit), serviceKey))
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// Сотрудник списывает без user_id
+	operator := mintHS256(t, testClaims(auth.RoleOperator, "operator-1"))
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodPost, "/api/v1/wallets/balance", debit), operator))
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.InDelta(t, 85.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// То же правило действует в GraphQL
+	resp := executeGraphQL(t, serviceKey, `mutation { updateBalance(walletNumber: "8600033", amount: -1) { newBalance } }`, nil)
+	assert.Equal(t, []string{"WALLET_OWNER_REQUIRED"}, graphQLErrorCodes(resp))
+}
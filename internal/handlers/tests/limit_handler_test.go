@@ -0,0 +1,153 @@
+// internal/handlers/tests/limit_handler_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты для Limit Handler и проверки лимитов при списаниях ---
+// Используют testRouter и testDB из main_test.go
+
+func floatPtr(v float64) *float64 { return &v }
+func intPtr(v int) *int           { return &v }
+
+func TestLimitHandler_TierLimitsAndOverrides(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "5000013"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+
+	tierLimits := models.Limits{MaxSingleWithdrawal: floatPtr(50), DailyConversionCount: intPtr(3)}
+	rr := executeRequest(t, createRequest(t, http.MethodPut, "/api/v1/limits/tiers/standard", tierLimits))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	overrides := models.UpdateWalletLimitsRequest{Overrides: models.Limits{MaxSingleWithdrawal: floatPtr(20)}}
+	rr = executeRequest(t, createRequest(t, http.MethodPut, "/api/v1/wallets/"+walletNumber+"/limits", overrides))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp models.WalletLimitsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, models.DefaultWalletTier, resp.Tier)
+	require.NotNil(t, resp.Effective.MaxSingleWithdrawal)
+	assert.InDelta(t, 20.0, *resp.Effective.MaxSingleWithdrawal, 0.001, "Индивидуальный лимит должен иметь приоритет")
+	require.NotNil(t, resp.Effective.DailyConversionCount)
+	assert.Equal(t, 3, *resp.Effective.DailyConversionCount, "Лимит уровня должен применяться, если нет индивидуального")
+}
+
+func TestWalletHandler_UpdateBalance_MaxSingleWithdrawalExceeded(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "5000021"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO wallet_limit_overrides (wallet_number, max_single_withdrawal) VALUES ($1, $2)", walletNumber, 30.0)
+	require.NoError(t, err)
+
+	payload := models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: -40}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var resp models.LimitExceededResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, models.LimitMaxSingleWithdrawal, resp.Limit)
+	assert.InDelta(t, 30.0, resp.LimitValue, 0.001)
+	assert.Equal(t, "LIMIT_EXCEEDED", resp.Code)
+	assert.Equal(t, models.LimitMaxSingleWithdrawal, resp.Details["limit"])
+	assert.Nil(t, resp.ResetsAt, "Лимит одного списания не обнуляется по времени")
+
+	// Баланс не изменился
+	var dbBalance float64
+	require.NoError(t, testDB.QueryRow("SELECT balance FROM wallets WHERE wallet_number = $1", walletNumber).Scan(&dbBalance))
+	assert.InDelta(t, 100.0, dbBalance, 0.001)
+}
+
+func TestWalletHandler_UpdateBalance_DailyWithdrawalTotalExceeded(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "5000039"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO wallet_limit_overrides (wallet_number, daily_withdrawal_total) VALUES ($1, $2)", walletNumber, 50.0)
+	require.NoError(t, err)
+
+	payload := models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: -30}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var resp models.LimitExceededResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, models.LimitDailyWithdrawalTotal, resp.Limit)
+	assert.InDelta(t, 60.0, resp.Attempted, 0.001)
+	assert.NotNil(t, resp.ResetsAt)
+}
+
+func TestWalletHandler_ConvertAndDeduct_DailyConversionCountExceeded(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "5000047"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO rates (value) VALUES ($1)", 1.0)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO tier_limits (tier, daily_conversion_count) VALUES ($1, $2)", models.DefaultWalletTier, 1)
+	require.NoError(t, err)
+
+	payload := models.ConvertRequest{SourceWalletNumber: walletNumber, AmountToConvert: 1}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/convert", payload))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/convert", payload))
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), models.LimitDailyConversionCount)
+}
+
+func TestHoldHandler_DebitLimitsApplyToHolds(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "5000054"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO wallet_limit_overrides (wallet_number, max_single_withdrawal, daily_withdrawal_total) VALUES ($1, $2, $3)",
+		walletNumber, 40.0, 50.0)
+	require.NoError(t, err)
+
+	// Холд больше лимита одного списания не создается
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/"+walletNumber+"/holds", models.CreateHoldRequest{Amount: 60}))
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), models.LimitMaxSingleWithdrawal)
+
+	// Каждый холд укладывается в лимиты, но вместе списания превышают суточный лимит
+	first := createTestHold(t, walletNumber, models.CreateHoldRequest{Amount: 30})
+	second := createTestHold(t, walletNumber, models.CreateHoldRequest{Amount: 30})
+	rr = executeRequest(t, createRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/holds/%d/capture", first.ID), models.CaptureHoldRequest{}))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = executeRequest(t, createRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/holds/%d/capture", second.ID), models.CaptureHoldRequest{}))
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var resp models.LimitExceededResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, models.LimitDailyWithdrawalTotal, resp.Limit)
+	assert.InDelta(t, 60.0, resp.Attempted, 0.001)
+	assert.InDelta(t, 70.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+}
+
+func TestLimitHandler_WalletOverridesRequireAdmin(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "5000062"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+
+	// Оператор может посмотреть лимиты, но не снять их с кошелька
+	operator := issueTestKey(t, models.ScopeWalletsRead, models.ScopeWalletsWrite).Key
+	overrides := models.UpdateWalletLimitsRequest{Overrides: models.Limits{MaxSingleWithdrawal: floatPtr(1000000)}}
+	rr := executeRequest(t, withKey(createRequest(t, http.MethodPut, "/api/v1/wallets/"+walletNumber+"/limits", overrides), operator))
+	assert.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, "/api/v1/wallets/"+walletNumber+"/limits", nil), operator))
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+}
@@ -0,0 +1,77 @@
+// internal/handlers/tests/localization_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты локализации сообщений по Accept-Language ---
+
+func TestLocalization_UpdateBalance_CreateWallet_Kazakh(t *testing.T) {
+	cleanupTestDB(t)
+
+	payload := models.UpdateBalanceRequest{WalletNumber: "8700015", Amount: 10}
+	req := createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload)
+	req.Header.Set("Accept-Language", "kk-KZ, ru;q=0.5")
+	rr := executeRequest(t, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp models.UpdateBalanceResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "Әмиян сәтті ашылды", resp.Message)
+}
+
+func TestLocalization_UpdateBalance_InsufficientFunds_English(t *testing.T) {
+	cleanupTestDB(t)
+
+	walletNumber := "8700023"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 5.0)
+	require.NoError(t, err)
+
+	payload := models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: -50}
+	req := createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload)
+	req.Header.Set("Accept-Language", "de;q=0.9, en-US;q=0.8")
+	rr := executeRequest(t, req)
+
+	require.Equal(t, http.StatusConflict, rr.Code)
+	var resp models.UpdateBalanceResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "INSUFFICIENT_FUNDS", resp.Code)
+	assert.Equal(t, "insufficient funds", resp.Message)
+}
+
+func TestLocalization_ErrorResponse_English(t *testing.T) {
+	cleanupTestDB(t)
+
+	// Списание с несуществующего кошелька
+	payload := models.UpdateBalanceRequest{WalletNumber: "8700031", Amount: -10}
+	req := createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload)
+	req.Header.Set("Accept-Language", "en")
+	rr := executeRequest(t, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "WITHDRAW_FROM_NONEXISTENT_WALLET", resp.Code)
+	assert.Equal(t, "cannot withdraw from a wallet that does not exist", resp.Error)
+}
+
+func TestLocalization_UnsupportedLanguage_FallsBackToRussian(t *testing.T) {
+	cleanupTestDB(t)
+
+	payload := models.UpdateBalanceRequest{WalletNumber: "8700049", Amount: 10}
+	req := createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload)
+	req.Header.Set("Accept-Language", "fr-FR, de;q=0.7")
+	rr := executeRequest(t, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp models.UpdateBalanceResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Message, "создан")
+}
@@ -3,29 +3,77 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"currency-service/internal/auth"
 	"currency-service/internal/config"
 	"currency-service/internal/database"
+	"currency-service/internal/grpcserver"
 	"currency-service/internal/handlers"
+	"currency-service/internal/models"
+	"currency-service/internal/ratelimit"
 	"currency-service/internal/repository"
 	"currency-service/internal/service"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"log"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	_ "github.com/lib/pq" // DB driver
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 var (
 	testRouter chi.Router
 	testDB     *sql.DB
+	// testScheduleSvc и testInterestSvc нужны тестам, чтобы запускать фоновые задачи напрямую
+	testScheduleSvc service.ScheduleService
+	testInterestSvc service.InterestService
+	// testAPIKeySvc и testAPIKey (ключ со всеми правами) нужны для авторизации тестовых запросов
+	testAPIKeySvc service.APIKeyService
+	testAPIKey    string
+	// testJWTSecret и testRSAKey (kid testRSAKeyID) позволяют тестам выпускать JWT, которые принимает testRouter
+	testJWTSecret []byte
+	testRSAKey    *rsa.PrivateKey
+	// testGRPCConn - соединение с gRPC-сервером, который использует те же сервисы, что и testRouter (без квот)
+	testGRPCConn *grpc.ClientConn
+	// testNewGRPCServer создает gRPC-сервер с теми же сервисами и квотами limiter
+	testNewGRPCServer func(limiter *ratelimit.Limiter) *grpc.Server
 )
 
+const (
+	testRSAKeyID    = "test-rs256"
+	testJWTIssuer   = "currency-service-tests"
+	testJWTAudience = "currency-service"
+
+	// Заголовки Cache-Control тестового роутера
+	testCacheControlDefault = "private, no-cache"
+	testCacheControlRoutes  = "GET /api/v1/rates/average=private, max-age=30"
+)
+
+// serveGRPCInMemory запускает server на соединении в памяти и возвращает клиентское соединение с ним.
+func serveGRPCInMemory(server *grpc.Server) (*grpc.ClientConn, error) {
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	return grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
 // TestMain выполняется один раз перед всеми тестами в пакете.
 func TestMain(m *testing.M) {
 	// 1. Загрузка тестовой конфигурации
@@ -36,6 +84,8 @@ func TestMain(m *testing.M) {
 	os.Setenv("DB_PASSWORD", "test_password") // Пароль тестовой БД
 	os.Setenv("DB_NAME", "currency_db_test")  // Имя тестовой БД
 	os.Setenv("SERVER_PORT", "8081")          // Тестовый порт сервера (не используется напрямую здесь)
+	// Большинство тестов создают кошельки пополнением, поэтому неявное создание включено
+	os.Setenv("WALLET_IMPLICIT_CREATE", "true")
 
 	// Используем функцию загрузки конфига, которая читает переменные окружения
 	cfg := config.LoadConfig()
@@ -61,25 +111,174 @@ func TestMain(m *testing.M) {
 	// 4. Инициализация зависимостей для тестов
 	rateRepo := repository.NewPostgresRateRepository()
 	walletRepo := repository.NewPostgresWalletRepository()
+	userRepo := repository.NewPostgresUserRepository()
+	holdRepo := repository.NewPostgresHoldRepository()
+	movementRepo := repository.NewPostgresMovementRepository()
+	limitRepo := repository.NewPostgresLimitRepository()
+	scheduleRepo := repository.NewPostgresScheduleRepository()
+	reconciliationRepo := repository.NewPostgresReconciliationRepository()
+	feeRepo := repository.NewPostgresFeeRepository()
+	interestRepo := repository.NewPostgresInterestRepository()
+	apiKeyRepo := repository.NewPostgresAPIKeyRepository()
+	conversion := service.ConversionSettings{Pair: cfg.Conversion.Pair, FeeWalletNumber: cfg.Conversion.FeeWalletNumber}
 	rateSvc := service.NewRateService(rateRepo, testDB)
-	walletSvc := service.NewWalletService(walletRepo, rateRepo, testDB)
+	walletSvc := service.NewWalletService(walletRepo, rateRepo, userRepo, movementRepo, limitRepo, feeRepo, testDB, cfg.Wallets.ImplicitCreate, conversion)
+	userSvc := service.NewUserService(userRepo, walletRepo, testDB)
+	limitSvc := service.NewLimitService(limitRepo, walletRepo, testDB)
+	holdSvc := service.NewHoldService(holdRepo, walletRepo, movementRepo, limitRepo, testDB, cfg.Holds.DefaultTTL)
+	testScheduleSvc = service.NewScheduleService(scheduleRepo, walletSvc, testDB, cfg.Scheduler.RetryDelay, cfg.Scheduler.MaxFailures)
+	reconciliationSvc := service.NewReconciliationService(reconciliationRepo, testDB, cfg.Reconciliation.Tolerance)
+	reversalSvc := service.NewReversalService(walletRepo, movementRepo, testDB)
+	feeSvc := service.NewFeeService(feeRepo, testDB)
+	testInterestSvc = service.NewInterestService(interestRepo, walletRepo, movementRepo, testDB)
+	testAPIKeySvc = service.NewAPIKeyService(apiKeyRepo, testDB)
 	rateHandler := handlers.NewRateHandler(rateSvc)
 	walletHandler := handlers.NewWalletHandler(walletSvc)
+	userHandler := handlers.NewUserHandler(userSvc)
+	holdHandler := handlers.NewHoldHandler(holdSvc)
+	limitHandler := handlers.NewLimitHandler(limitSvc)
+	reconciliationHandler := handlers.NewReconciliationHandler(reconciliationSvc)
+	movementHandler := handlers.NewMovementHandler(reversalSvc)
+	feeHandler := handlers.NewFeeHandler(feeSvc)
+	interestHandler := handlers.NewInterestHandler(testInterestSvc)
+	apiKeyHandler := handlers.NewAPIKeyHandler(testAPIKeySvc)
+	scheduleHandler := handlers.NewScheduleHandler(testScheduleSvc)
+	graphQLHandler, err := handlers.NewGraphQLHandler(rateSvc, walletSvc)
+	if err != nil {
+		log.Fatalf("Ошибка инициализации GraphQL: %v", err)
+	}
+
+	// Ключи не очищаются между тестами (cleanupTestDB), поэтому ключ для запросов выпускается один раз
+	if _, err := testDB.Exec("TRUNCATE TABLE api_keys RESTART IDENTITY;"); err != nil {
+		log.Fatalf("Не удалось очистить API-ключи в тестовой БД: %v", err)
+	}
+	issued, err := testAPIKeySvc.IssueAPIKey(context.Background(), models.IssueAPIKeyRequest{Name: "tests", Scopes: models.APIKeyScopes})
+	if err != nil {
+		log.Fatalf("Не удалось выпустить тестовый API-ключ: %v", err)
+	}
+	testAPIKey = issued.Key
+
+	jwtVerifier, cleanupJWTKeys, err := newTestJWTVerifier()
+	if err != nil {
+		log.Fatalf("Не удалось подготовить ключи JWT: %v", err)
+	}
+	defer cleanupJWTKeys()
+
+	cacheControlRules, err := handlers.ParseCacheControlRules(testCacheControlRoutes)
+	if err != nil {
+		log.Fatalf("Некорректные настройки Cache-Control: %v", err)
+	}
 
 	// 5. Настройка роутера
 	testRouter = chi.NewRouter()
+	testRouter.Use(middleware.RequestID)
+	testRouter.Use(handlers.Timeout(60 * time.Second))
 	testRouter.Route("/api/v1", func(r chi.Router) {
+		r.Use(handlers.Authenticate(testAPIKeySvc, jwtVerifier))
+		r.Use(handlers.CacheControl(testCacheControlDefault, cacheControlRules))
 		r.Route("/rates", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes("", models.ScopeRatesWrite))
 			r.Post("/", rateHandler.CreateRate)
+			r.Get("/", rateHandler.ListRates)
 			r.Get("/average", rateHandler.GetAverageRate)
 		})
 		r.Route("/wallets", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeWalletsWrite))
+			// Клиентам доступны только чтение и списания со своих кошельков (владелец проверяется в обработчиках)
 			r.Post("/balance", walletHandler.UpdateBalance)
-			r.Get("/", walletHandler.ListWallets)
 			r.Post("/convert", walletHandler.ConvertAndDeduct)
+			r.Get("/{number}", walletHandler.GetWallet)
+			r.Group(func(r chi.Router) {
+				r.Use(handlers.RejectCustomers)
+				r.Post("/", walletHandler.CreateWallet)
+				r.Get("/", walletHandler.ListWallets)
+				r.Post("/batch", walletHandler.ExecuteBatch)
+				r.Post("/{number}/holds", holdHandler.CreateHold)
+				r.Get("/{number}/limits", limitHandler.GetWalletLimits)
+				r.Get("/{number}/interest", interestHandler.GetWalletInterest)
+				// Индивидуальные лимиты, кредитный лимит, процентный план и статус меняют правила для кошелька
+				// (в том числе позволяют уйти в минус), поэтому их устанавливает только администратор
+				r.Group(func(r chi.Router) {
+					r.Use(handlers.RequireScope(models.ScopeAdmin))
+					r.Put("/{number}/limits", limitHandler.SetWalletLimits)
+					r.Put("/{number}/credit-limit", walletHandler.SetCreditLimit)
+					r.Put("/{number}/status", walletHandler.SetWalletStatus)
+					r.Put("/{number}/interest", interestHandler.SetWalletInterestPlan)
+				})
+			})
+		})
+		r.Route("/limits/tiers", func(r chi.Router) {
+			// Лимиты уровня действуют на все кошельки уровня, поэтому их изменение - административное действие
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeAdmin), handlers.RejectCustomers)
+			r.Get("/{tier}", limitHandler.GetTierLimits)
+			r.Put("/{tier}", limitHandler.SetTierLimits)
+		})
+		r.Route("/holds", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeWalletsWrite), handlers.RejectCustomers)
+			r.Get("/{id}", holdHandler.GetHold)
+			r.Post("/{id}/capture", holdHandler.CaptureHold)
+			r.Post("/{id}/void", holdHandler.VoidHold)
+		})
+		r.Route("/users", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeWalletsWrite))
+			r.With(handlers.RejectCustomers).Post("/", userHandler.CreateUser)
+			r.Get("/{id}", userHandler.GetUser)
+			r.Get("/{id}/wallets", userHandler.ListUserWallets)
+		})
+		r.Route("/schedules", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeWalletsWrite), handlers.RejectCustomers)
+			r.Post("/", scheduleHandler.CreateSchedule)
+			r.Get("/{id}", scheduleHandler.GetSchedule)
+			r.Get("/{id}/runs", scheduleHandler.ListScheduleRuns)
+			r.Delete("/{id}", scheduleHandler.CancelSchedule)
+		})
+		// Права на отдельные поля GraphQL проверяются в резолверах
+		r.Get("/graphql", graphQLHandler.ExecuteQuery)
+		r.Post("/graphql", graphQLHandler.Execute)
+		r.Route("/movements", func(r chi.Router) {
+			r.Use(handlers.RequireReadWriteScopes(models.ScopeWalletsRead, models.ScopeWalletsWrite), handlers.RejectCustomers)
+			r.Get("/{id}", movementHandler.GetMovement)
+			// Отмена возвращает деньги в обход обычных проверок операций, поэтому доступна только администратору
+			r.With(handlers.RequireScope(models.ScopeAdmin)).Post("/{id}/reverse", movementHandler.ReverseMovement)
+		})
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(handlers.RequireScope(models.ScopeAdmin))
+			r.Route("/api-keys", func(r chi.Router) {
+				r.Get("/", apiKeyHandler.ListAPIKeys)
+				r.Post("/", apiKeyHandler.IssueAPIKey)
+				r.Delete("/{id}", apiKeyHandler.RevokeAPIKey)
+			})
+			r.Route("/reconciliation", func(r chi.Router) {
+				r.Post("/runs", reconciliationHandler.RunReconciliation)
+				r.Get("/runs", reconciliationHandler.ListReconciliationRuns)
+				r.Get("/runs/{id}", reconciliationHandler.GetReconciliationReport)
+			})
+			r.Route("/fees", func(r chi.Router) {
+				r.Get("/", feeHandler.ListFeeRules)
+				r.Put("/", feeHandler.SetFeeRule)
+				r.Delete("/{id}", feeHandler.DeleteFeeRule)
+			})
+			r.Route("/interest", func(r chi.Router) {
+				r.Get("/plans", interestHandler.ListInterestPlans)
+				r.Post("/plans", interestHandler.CreateInterestPlan)
+				r.Post("/accruals", interestHandler.AccrueInterest)
+				r.Post("/postings", interestHandler.PostInterest)
+			})
 		})
 	})
 
+	// gRPC-сервер с теми же сервисами, доступный тестам через соединение в памяти
+	testNewGRPCServer = func(limiter *ratelimit.Limiter) *grpc.Server {
+		return grpcserver.NewServer(rateSvc, walletSvc, testAPIKeySvc, jwtVerifier, limiter)
+	}
+	grpcServer := testNewGRPCServer(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{}, ratelimit.Limit{}, nil))
+	defer grpcServer.Stop()
+	testGRPCConn, err = serveGRPCInMemory(grpcServer)
+	if err != nil {
+		log.Fatalf("Не удалось подключиться к тестовому gRPC-серверу: %v", err)
+	}
+	defer testGRPCConn.Close()
+
 	// 6. Запуск тестов
 	log.Println("Запуск тестов...")
 	exitCode := m.Run()
@@ -90,17 +289,69 @@ func TestMain(m *testing.M) {
 
 // --- Вспомогательные функции ---
 
+// newTestJWTVerifier создает секрет HS256 и ключ RS256 (в JWKS-файле) во временном каталоге
+// и возвращает проверку JWT с ними.
+func newTestJWTVerifier() (*auth.JWTVerifier, func(), error) {
+	dir, err := os.MkdirTemp("", "currency-service-jwt")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	testJWTSecret = []byte("test-secret-for-hs256-tokens-0123456789")
+	secretFile := filepath.Join(dir, "hs256.secret")
+	if err := os.WriteFile(secretFile, testJWTSecret, 0o600); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	testRSAKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": testRSAKeyID,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(testRSAKey.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(testRSAKey.E)).Bytes()),
+	}}})
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	jwksFile := filepath.Join(dir, "jwks.json")
+	if err := os.WriteFile(jwksFile, jwks, 0o600); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	verifier, err := auth.NewJWTVerifier(auth.JWTSettings{
+		HS256SecretFile: secretFile,
+		JWKSFile:        jwksFile,
+		Issuer:          testJWTIssuer,
+		Audience:        testJWTAudience,
+	})
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return verifier, cleanup, nil
+}
+
 // cleanupTestDB очищает таблицы перед/после каждого теста
 func cleanupTestDB(t *testing.T) {
 	t.Helper()
 	// Очищаем таблицы в определенном порядке из-за возможных внешних ключей (если появятся)
 	// Сначала таблицы, на которые могут ссылаться, потом основные.
 	// RESTART IDENTITY сбрасывает счетчики SERIAL/IDENTITY.
-	_, err := testDB.Exec("TRUNCATE TABLE wallets, rates RESTART IDENTITY;")
+	_, err := testDB.Exec("TRUNCATE TABLE interest_accruals, wallet_interest_plan_history, interest_plans, conversion_fee_rules, reconciliation_discrepancies, reconciliation_runs, wallet_schedule_runs, wallet_schedules, wallet_limit_overrides, tier_limits, wallet_movements, wallet_holds, wallets, users, rates, rate_limit_buckets RESTART IDENTITY;")
 	require.NoError(t, err, "Ошибка очистки тестовой БД")
 }
 
-// createRequest создает тестовый HTTP запрос
+// createRequest создает тестовый HTTP запрос с ключом testAPIKey
 func createRequest(t *testing.T, method, url string, body interface{}) *http.Request {
 	t.Helper()
 	var reqBody []byte
@@ -112,6 +363,7 @@ func createRequest(t *testing.T, method, url string, body interface{}) *http.Req
 	req, err := http.NewRequest(method, url, bytes.NewBuffer(reqBody))
 	require.NoError(t, err, "Ошибка создания тестового запроса")
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAPIKey)
 	return req
 }
 
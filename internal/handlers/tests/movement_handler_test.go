@@ -0,0 +1,166 @@
+// internal/handlers/tests/movement_handler_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты для Movement Handler (просмотр и отмена движений) ---
+// Используют testRouter и testDB из main_test.go
+
+func updateTestBalance(t *testing.T, walletNumber string, amount float64) models.UpdateBalanceResponse {
+	t.Helper()
+	payload := models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: amount}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var resp models.UpdateBalanceResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.NotZero(t, resp.MovementID)
+	return resp
+}
+
+func reverseTestMovement(t *testing.T, id int64) (int, models.ReversalResponse) {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/movements/%d/reverse", id), nil))
+	var resp models.ReversalResponse
+	if rr.Code == http.StatusOK || rr.Code == http.StatusConflict {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	}
+	return rr.Code, resp
+}
+
+func TestMovementHandler_ReverseDeposit(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8200016"
+	updateTestBalance(t, walletNumber, 100)
+	deposit := updateTestBalance(t, walletNumber, 40)
+
+	code, resp := reverseTestMovement(t, deposit.MovementID)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.MovementKindReversal, resp.Reversal.Kind)
+	assert.InDelta(t, -40.0, resp.Reversal.Amount, 0.001)
+	require.NotNil(t, resp.Reversal.ReversalOf)
+	assert.Equal(t, deposit.MovementID, *resp.Reversal.ReversalOf)
+	assert.InDelta(t, 100.0, resp.NewBalance, 0.001)
+	assert.InDelta(t, 100.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// Исходное движение ссылается на отмену
+	rr := executeRequest(t, createRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/movements/%d", deposit.MovementID), nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var original models.Movement
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &original))
+	require.NotNil(t, original.ReversedBy)
+	assert.Equal(t, resp.Reversal.ID, *original.ReversedBy)
+
+	// Повторная отмена отклоняется
+	code, _ = reverseTestMovement(t, deposit.MovementID)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.InDelta(t, 100.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// Саму отмену отменить нельзя
+	code, _ = reverseTestMovement(t, resp.Reversal.ID)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+	code, _ = reverseTestMovement(t, 999999)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestMovementHandler_ReverseRequiresAdmin(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8200057"
+	deposit := updateTestBalance(t, walletNumber, 100)
+
+	operator := issueTestKey(t, models.ScopeWalletsRead, models.ScopeWalletsWrite).Key
+	rr := executeRequest(t, withKey(createRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/movements/%d/reverse", deposit.MovementID), nil), operator))
+	assert.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+	assert.InDelta(t, 100.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// Просмотр движения остается доступным оператору
+	rr = executeRequest(t, withKey(createRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/movements/%d", deposit.MovementID), nil), operator))
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+}
+
+func TestMovementHandler_ReverseDeposit_InsufficientFunds(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8200024"
+	deposit := updateTestBalance(t, walletNumber, 100)
+	updateTestBalance(t, walletNumber, -80)
+
+	code, resp := reverseTestMovement(t, deposit.MovementID)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.NotEmpty(t, resp.Message)
+	assert.InDelta(t, 20.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// Отмена не записана - после пополнения ее можно выполнить
+	updateTestBalance(t, walletNumber, 80)
+	code, _ = reverseTestMovement(t, deposit.MovementID)
+	assert.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 0.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+}
+
+func TestMovementHandler_ReverseConversionUsesOriginalRate(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8200032"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 500.0)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO rates (value) VALUES ($1)", 2.5)
+	require.NoError(t, err)
+
+	payload := models.ConvertRequest{SourceWalletNumber: walletNumber, AmountToConvert: 40}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/convert", payload))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var converted models.ConvertResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &converted))
+	require.NotZero(t, converted.MovementID)
+	assert.InDelta(t, 400.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// Курс изменился, но отмена использует курс исходной конвертации
+	_, err = testDB.Exec("INSERT INTO rates (value) VALUES ($1)", 3.0)
+	require.NoError(t, err)
+
+	code, resp := reverseTestMovement(t, converted.MovementID)
+	require.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 100.0, resp.Reversal.Amount, 0.001)
+	require.NotNil(t, resp.Reversal.Rate)
+	assert.InDelta(t, 2.5, *resp.Reversal.Rate, 0.001)
+	assert.InDelta(t, 500.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+}
+
+func TestMovementHandler_ReverseConversionWithFee(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8200040"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 500.0)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO rates (value) VALUES ($1)", 2.0)
+	require.NoError(t, err)
+	insertFeeWallet(t)
+	setTestFeeRule(t, models.FeeRule{Fixed: 3})
+
+	code, converted := convertTest(t, walletNumber, 50) // 100 по курсу и комиссия 3
+	require.Equal(t, http.StatusOK, code, converted.Message)
+	assert.InDelta(t, 397.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+	assert.InDelta(t, 3.0, getWalletFromList(t, feeWalletNumber).Balance, 0.001)
+
+	// Комиссию нельзя вернуть отдельно от конвертации
+	var feeMovementID int64
+	require.NoError(t, testDB.QueryRow("SELECT id FROM wallet_movements WHERE kind = $1 AND conversion_id = $2",
+		models.MovementKindConversionFee, converted.MovementID).Scan(&feeMovementID))
+	code, _ = reverseTestMovement(t, feeMovementID)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+	// Отмена конвертации возвращает и сумму, и комиссию в одной транзакции
+	code, resp := reverseTestMovement(t, converted.MovementID)
+	require.Equal(t, http.StatusOK, code, resp.Message)
+	require.Len(t, resp.LinkedReversals, 2)
+	assert.InDelta(t, 500.0, resp.NewBalance, 0.001)
+	assert.InDelta(t, 500.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+	assert.InDelta(t, 0.0, getWalletFromList(t, feeWalletNumber).Balance, 0.001)
+}
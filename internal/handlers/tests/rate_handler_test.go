@@ -45,18 +45,30 @@ func TestRateHandler_CreateRate_InvalidValue(t *testing.T) {
 	cleanupTestDB(t)
 
 	// Попытка добавить некорректное значение (0 или отрицательное)
-	// Сервис должен вернуть ошибку, но хендлер может вернуть 500, если сервис не обработал специфично
-	// В нашей реализации сервис вернет ошибку, которую хендлер обернет в 500.
-	// TODO: Улучшить обработку ошибок в хендлере CreateRate для возврата 400 Bad Request
 	rateValue := -10.0
 	payload := map[string]float64{"value": rateValue}
 
 	req := createRequest(t, http.MethodPost, "/api/v1/rates", payload)
 	rr := executeRequest(t, req)
 
-	// Пока ожидаем 500, но в идеале должно быть 400
-	assert.Equal(t, http.StatusInternalServerError, rr.Code, "Ожидался статус Internal Server Error (500)")
-	// Можно добавить проверку тела ошибки, если оно стандартизировано
+	assert.Equal(t, http.StatusBadRequest, rr.Code, "Ожидался статус Bad Request (400)")
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "RATE_NOT_POSITIVE", resp.Code)
+	assert.NotEmpty(t, resp.Error)
+	assert.NotEmpty(t, resp.RequestID, "В ответе должен быть ID запроса")
+}
+
+func TestRateHandler_CreateRate_MalformedBody(t *testing.T) {
+	cleanupTestDB(t)
+
+	req := createRequest(t, http.MethodPost, "/api/v1/rates", map[string]string{"value": "abc"})
+	rr := executeRequest(t, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "INVALID_REQUEST", resp.Code)
 }
 
 func TestRateHandler_GetAverageRate(t *testing.T) {
@@ -0,0 +1,137 @@
+// internal/handlers/tests/ratelimit_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/handlers"
+	"currency-service/internal/models"
+	"currency-service/internal/ratelimit"
+	"currency-service/internal/repository"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты ограничения частоты запросов ---
+
+// newRateLimitedRouter возвращает роутер, который проверяет ключ, расходует квоту limiter и отвечает 204.
+func newRateLimitedRouter(limiter *ratelimit.Limiter) http.Handler {
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(handlers.Authenticate(testAPIKeySvc, nil))
+		r.Use(handlers.RateLimit(limiter))
+		ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }
+		r.Get("/wallets/{number}", ok)
+		r.Post("/wallets/convert", ok)
+	})
+	return r
+}
+
+// newIPRateLimitedRouter возвращает роутер, который расходует квоту IP-адреса limiter до проверки ключа.
+func newIPRateLimitedRouter(limiter *ratelimit.Limiter) http.Handler {
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(handlers.RateLimitByIP(limiter))
+		r.Use(handlers.Authenticate(testAPIKeySvc, nil))
+		r.Use(handlers.RateLimit(limiter))
+		r.Get("/wallets/{number}", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	})
+	return r
+}
+
+// serveRateLimited выполняет запрос method path с ключом key через router.
+func serveRateLimited(t *testing.T, router http.Handler, method, path, key string) *httptest.ResponseRecorder {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, withKey(createRequest(t, method, path, nil), key))
+	return rr
+}
+
+func TestRateLimit_ExhaustsQuota(t *testing.T) {
+	cleanupTestDB(t)
+	key := issueTestKey(t, models.ScopeWalletsRead).Key
+	router := newRateLimitedRouter(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 3, Period: time.Minute}, ratelimit.Limit{}, nil))
+
+	for i := 0; i < 3; i++ {
+		rr := serveRateLimited(t, router, http.MethodGet, "/api/v1/wallets/8700015", key)
+		require.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, "3", rr.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, strconv.Itoa(2-i), rr.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "3;w=60", rr.Header().Get("RateLimit-Policy"))
+	}
+
+	rr := serveRateLimited(t, router, http.MethodGet, "/api/v1/wallets/8700015", key)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "20", rr.Header().Get("Retry-After")) // Один токен восстанавливается за 60/3 секунд
+	var resp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "RATE_LIMITED", resp.Code)
+}
+
+func TestRateLimit_SeparateClientsAndRoutes(t *testing.T) {
+	cleanupTestDB(t)
+	first := issueTestKey(t, models.ScopeWalletsRead, models.ScopeWalletsWrite).Key
+	second := issueTestKey(t, models.ScopeWalletsRead).Key
+	rules, err := ratelimit.ParseRules("POST /api/v1/wallets/convert=1/m")
+	require.NoError(t, err)
+	router := newRateLimitedRouter(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 1, Period: time.Minute}, ratelimit.Limit{}, rules))
+
+	// Общая квота и квота конвертации расходуются отдельно
+	assert.Equal(t, http.StatusNoContent, serveRateLimited(t, router, http.MethodGet, "/api/v1/wallets/8700015", first).Code)
+	assert.Equal(t, http.StatusNoContent, serveRateLimited(t, router, http.MethodPost, "/api/v1/wallets/convert", first).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(t, router, http.MethodGet, "/api/v1/wallets/8700023", first).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(t, router, http.MethodPost, "/api/v1/wallets/convert", first).Code)
+
+	// У другого ключа своя квота
+	assert.Equal(t, http.StatusNoContent, serveRateLimited(t, router, http.MethodGet, "/api/v1/wallets/8700015", second).Code)
+}
+
+func TestRateLimit_PostgresStoreSharedAcrossReplicas(t *testing.T) {
+	cleanupTestDB(t)
+	key := issueTestKey(t, models.ScopeWalletsRead).Key
+	limit := ratelimit.Limit{Requests: 2, Period: time.Hour}
+	repo := repository.NewPostgresRateLimitRepository()
+	// Две "реплики" с отдельными ограничителями, но общей таблицей ведер
+	replicaA := newRateLimitedRouter(ratelimit.NewLimiter(ratelimit.NewPostgresStore(testDB, repo), limit, ratelimit.Limit{}, nil))
+	replicaB := newRateLimitedRouter(ratelimit.NewLimiter(ratelimit.NewPostgresStore(testDB, repo), limit, ratelimit.Limit{}, nil))
+
+	assert.Equal(t, http.StatusNoContent, serveRateLimited(t, replicaA, http.MethodGet, "/api/v1/wallets/8700015", key).Code)
+	rr := serveRateLimited(t, replicaB, http.MethodGet, "/api/v1/wallets/8700015", key)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(t, replicaA, http.MethodGet, "/api/v1/wallets/8700015", key).Code)
+
+	var buckets int
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM rate_limit_buckets").Scan(&buckets))
+	assert.Equal(t, 1, buckets)
+}
+
+func TestRateLimit_IPQuotaAppliesBeforeAuthentication(t *testing.T) {
+	cleanupTestDB(t)
+	key := issueTestKey(t, models.ScopeWalletsRead).Key
+	router := newIPRateLimitedRouter(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{}, ratelimit.Limit{Requests: 2, Period: time.Minute}, nil))
+	serveFrom := func(remoteAddr, key string) *httptest.ResponseRecorder {
+		req := withKey(createRequest(t, http.MethodGet, "/api/v1/wallets/8700015", nil), key)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Перебор ключей с одного адреса упирается в квоту адреса
+	assert.Equal(t, http.StatusUnauthorized, serveFrom("203.0.113.7:40000", "csk_guess1").Code)
+	assert.Equal(t, http.StatusUnauthorized, serveFrom("203.0.113.7:40001", "csk_guess2").Code)
+	rr := serveFrom("203.0.113.7:40002", key)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, "квота адреса общая для всех ключей")
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+
+	// У другого адреса своя квота
+	assert.Equal(t, http.StatusNoContent, serveFrom("198.51.100.2:40000", key).Code)
+}
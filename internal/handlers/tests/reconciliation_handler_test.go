@@ -0,0 +1,87 @@
+// internal/handlers/tests/reconciliation_handler_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты для Reconciliation Handler ---
+// Используют testRouter и testDB из main_test.go
+
+func runTestReconciliation(t *testing.T) models.ReconciliationReportResponse {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/admin/reconciliation/runs", nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var report models.ReconciliationReportResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	return report
+}
+
+func TestReconciliationHandler_DetectsDiscrepancy(t *testing.T) {
+	cleanupTestDB(t)
+
+	// Балансы, измененные через API, сходятся с историей движений
+	for _, payload := range []models.UpdateBalanceRequest{
+		{WalletNumber: "7000011", Amount: 100},
+		{WalletNumber: "7000029", Amount: 50},
+		{WalletNumber: "7000011", Amount: -30},
+	} {
+		rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	report := runTestReconciliation(t)
+	assert.Equal(t, 2, report.Run.WalletsChecked)
+	assert.Equal(t, 0, report.Run.DiscrepanciesFound)
+	assert.Empty(t, report.Discrepancies)
+	require.NotNil(t, report.Run.FinishedAt)
+
+	// Изменение баланса в обход движений должно попасть в отчет
+	_, err := testDB.Exec("UPDATE wallets SET balance = balance + 5 WHERE wallet_number = $1", "7000011")
+	require.NoError(t, err)
+
+	report = runTestReconciliation(t)
+	assert.Equal(t, 1, report.Run.DiscrepanciesFound)
+	require.Len(t, report.Discrepancies, 1)
+	d := report.Discrepancies[0]
+	assert.Equal(t, "7000011", d.WalletNumber)
+	assert.InDelta(t, 75.0, d.StoredBalance, 0.001)
+	assert.InDelta(t, 70.0, d.ComputedBalance, 0.001)
+	assert.InDelta(t, 5.0, d.Difference, 0.001)
+
+	// Отчет сохраняется и доступен по ID и как последний
+	for _, id := range []string{fmt.Sprintf("%d", report.Run.ID), "latest"} {
+		rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/admin/reconciliation/runs/"+id, nil))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var saved models.ReconciliationReportResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &saved))
+		assert.Equal(t, report.Run.ID, saved.Run.ID)
+		require.Len(t, saved.Discrepancies, 1)
+		assert.Equal(t, "7000011", saved.Discrepancies[0].WalletNumber)
+	}
+
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/admin/reconciliation/runs?limit=10", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var runs models.ListReconciliationRunsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &runs))
+	require.Len(t, runs.Runs, 2)
+	assert.Equal(t, report.Run.ID, runs.Runs[0].ID, "Последняя сверка должна быть первой")
+}
+
+func TestReconciliationHandler_ReportNotFound(t *testing.T) {
+	cleanupTestDB(t)
+
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/admin/reconciliation/runs/latest", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/admin/reconciliation/runs/abc", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
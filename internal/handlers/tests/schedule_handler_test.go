@@ -0,0 +1,208 @@
+// internal/handlers/tests/schedule_handler_test.go
+package handlers_test
+
+import (
+	"context"
+	"currency-service/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты для Schedule Handler, перевода между кошельками и выполнения расписаний ---
+// Используют testRouter, testDB и testScheduleSvc из main_test.go
+
+func createTestSchedule(t *testing.T, payload models.CreateScheduleRequest) models.Schedule {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/schedules", payload))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var schedule models.Schedule
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &schedule))
+	return schedule
+}
+
+// makeScheduleDue переносит следующий запуск расписания в прошлое, чтобы не ждать его наступления.
+func makeScheduleDue(t *testing.T, id int64) {
+	t.Helper()
+	_, err := testDB.Exec("UPDATE wallet_schedules SET next_run_at = NOW() - INTERVAL '1 second' WHERE id = $1", id)
+	require.NoError(t, err)
+}
+
+func getTestSchedule(t *testing.T, id int64) models.Schedule {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/schedules/%d", id), nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var schedule models.Schedule
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &schedule))
+	return schedule
+}
+
+func TestScheduleHandler_OnceScheduleRuns(t *testing.T) {
+	cleanupTestDB(t)
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2), ($3, $4)", "6000038", 100.0, "6000046", 0.0)
+	require.NoError(t, err)
+
+	schedule := createTestSchedule(t, models.CreateScheduleRequest{
+		Operation:          models.ScheduleOperationTransfer,
+		WalletNumber:       "6000038",
+		TargetWalletNumber: "6000046",
+		Amount:             25,
+		Recurrence:         models.ScheduleRecurrenceOnce,
+	})
+	assert.Equal(t, models.ScheduleStatusActive, schedule.Status)
+	require.NotNil(t, schedule.NextRunAt)
+
+	makeScheduleDue(t, schedule.ID)
+	processed, err := testScheduleSvc.RunDueSchedules(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	assert.InDelta(t, 75.0, getWalletFromList(t, "6000038").Balance, 0.001)
+	assert.InDelta(t, 25.0, getWalletFromList(t, "6000046").Balance, 0.001)
+
+	updated := getTestSchedule(t, schedule.ID)
+	assert.Equal(t, models.ScheduleStatusCompleted, updated.Status)
+	assert.Nil(t, updated.NextRunAt)
+
+	// Повторный тик не выполняет завершенное расписание
+	processed, err = testScheduleSvc.RunDueSchedules(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, processed)
+
+	rr := executeRequest(t, createRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/schedules/%d/runs", schedule.ID), nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var runs models.ListScheduleRunsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &runs))
+	require.Len(t, runs.Runs, 1)
+	assert.Equal(t, models.ScheduleRunSucceeded, runs.Runs[0].Status)
+}
+
+func TestScheduleHandler_InsufficientFundsRetriesThenDisables(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "6000053"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 10.0)
+	require.NoError(t, err)
+
+	schedule := createTestSchedule(t, models.CreateScheduleRequest{
+		Operation:    models.ScheduleOperationBalanceUpdate,
+		WalletNumber: walletNumber,
+		Amount:       -50,
+		Recurrence:   models.ScheduleRecurrenceDaily,
+	})
+
+	// Первая неудача: расписание остается активным и будет повторено позже
+	makeScheduleDue(t, schedule.ID)
+	_, err = testScheduleSvc.RunDueSchedules(context.Background())
+	require.NoError(t, err)
+	updated := getTestSchedule(t, schedule.ID)
+	assert.Equal(t, models.ScheduleStatusActive, updated.Status)
+	assert.Equal(t, 1, updated.ConsecutiveFailures)
+
+	// После MaxFailures (по умолчанию 3) неудач подряд расписание отключается
+	for i := 0; i < 2; i++ {
+		makeScheduleDue(t, schedule.ID)
+		_, err = testScheduleSvc.RunDueSchedules(context.Background())
+		require.NoError(t, err)
+	}
+	updated = getTestSchedule(t, schedule.ID)
+	assert.Equal(t, models.ScheduleStatusDisabled, updated.Status)
+	assert.Equal(t, 3, updated.ConsecutiveFailures)
+	assert.NotEmpty(t, updated.DisabledReason)
+	assert.InDelta(t, 10.0, getWalletFromList(t, walletNumber).Balance, 0.001, "Баланс не должен измениться")
+
+	rr := executeRequest(t, createRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/schedules/%d/runs", schedule.ID), nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var runs models.ListScheduleRunsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &runs))
+	require.Len(t, runs.Runs, 3)
+	for _, run := range runs.Runs {
+		assert.Equal(t, models.ScheduleRunFailed, run.Status)
+	}
+}
+
+func TestScheduleHandler_CreateInvalidAndCancel(t *testing.T) {
+	cleanupTestDB(t)
+
+	bad := models.CreateScheduleRequest{
+		Operation:    models.ScheduleOperationBalanceUpdate,
+		WalletNumber: "6000061",
+		Amount:       10,
+		Recurrence:   models.ScheduleRecurrenceCron,
+		CronExpr:     "61 * * * *",
+	}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/schedules", bad))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Расписание для несуществующего кошелька не создается
+	bad.CronExpr = "0 9 * * 1-5"
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/schedules", bad))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", "6000061", 0.0)
+	require.NoError(t, err)
+	schedule := createTestSchedule(t, bad)
+	require.NotNil(t, schedule.NextRunAt)
+	assert.Equal(t, 9, schedule.NextRunAt.UTC().Hour())
+
+	rr = executeRequest(t, createRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/schedules/%d", schedule.ID), nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, models.ScheduleStatusDisabled, getTestSchedule(t, schedule.ID).Status)
+
+	rr = executeRequest(t, createRequest(t, http.MethodDelete, fmt.Sprintf("/api/v1/schedules/%d", schedule.ID), nil))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestScheduleHandler_RunsWithCreatorRights(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "6000079"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+
+	// Ключ без права admin - не сотрудник: списывать с кошелька владельца без user_id он не может
+	key := issueTestKey(t, models.ScopeWalletsRead, models.ScopeWalletsWrite)
+	req := withKey(createRequest(t, http.MethodPost, "/api/v1/schedules", models.CreateScheduleRequest{
+		Operation:    models.ScheduleOperationBalanceUpdate,
+		WalletNumber: walletNumber,
+		Amount:       -10,
+		Recurrence:   models.ScheduleRecurrenceDaily,
+	}), key.Key)
+	rr := executeRequest(t, req)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var schedule models.Schedule
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &schedule))
+	assert.Equal(t, fmt.Sprintf("key:%d", key.ID), schedule.CreatedBy)
+	assert.False(t, schedule.CreatedByStaff)
+
+	// У кошелька появился владелец уже после создания расписания: запуск проверяет права заново
+	owner := createTestUser(t, "Расписание", "Владелец")
+	_, err = testDB.Exec("UPDATE wallets SET owner_id = $1 WHERE wallet_number = $2", owner.ID, walletNumber)
+	require.NoError(t, err)
+
+	makeScheduleDue(t, schedule.ID)
+	_, err = testScheduleSvc.RunDueSchedules(context.Background())
+	require.NoError(t, err)
+	updated := getTestSchedule(t, schedule.ID)
+	assert.Equal(t, models.ScheduleStatusDisabled, updated.Status)
+	assert.NotEmpty(t, updated.DisabledReason)
+	assert.InDelta(t, 100.0, getWalletFromList(t, walletNumber).Balance, 0.001, "Баланс не должен измениться")
+
+	// Расписание сотрудника (testAPIKey с правом admin) списывает без user_id
+	staffSchedule := createTestSchedule(t, models.CreateScheduleRequest{
+		Operation:    models.ScheduleOperationBalanceUpdate,
+		WalletNumber: walletNumber,
+		Amount:       -10,
+		Recurrence:   models.ScheduleRecurrenceOnce,
+	})
+	assert.True(t, staffSchedule.CreatedByStaff)
+	makeScheduleDue(t, staffSchedule.ID)
+	_, err = testScheduleSvc.RunDueSchedules(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, models.ScheduleStatusCompleted, getTestSchedule(t, staffSchedule.ID).Status)
+	assert.InDelta(t, 90.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+}
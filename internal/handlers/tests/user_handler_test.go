@@ -0,0 +1,109 @@
+// internal/handlers/tests/user_handler_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты для User Handler ---
+// Используют testRouter и testDB из main_test.go
+
+// createTestUser создает пользователя через API и возвращает его
+func createTestUser(t *testing.T, firstName, lastName string) models.User {
+	t.Helper()
+	payload := models.CreateUserRequest{FirstName: firstName, LastName: lastName}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/users", payload))
+	require.Equal(t, http.StatusCreated, rr.Code, "Не удалось создать пользователя: %s", rr.Body.String())
+
+	var user models.User
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &user))
+	return user
+}
+
+func TestUserHandler_CreateAndGetUser(t *testing.T) {
+	cleanupTestDB(t)
+
+	user := createTestUser(t, "Иван", "Петров")
+	assert.NotEmpty(t, user.ID, "ID пользователя должен быть сгенерирован")
+
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/users/"+user.ID, nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var got models.User
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, user.ID, got.ID)
+	assert.Equal(t, "Иван", got.FirstName)
+	assert.Equal(t, "Петров", got.LastName)
+}
+
+func TestUserHandler_CreateUser_EmptyName(t *testing.T) {
+	cleanupTestDB(t)
+
+	payload := models.CreateUserRequest{FirstName: "Иван"}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/users", payload))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUserHandler_GetUser_NotFoundAndInvalid(t *testing.T) {
+	cleanupTestDB(t)
+
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/users/00000000-0000-0000-0000-000000000000", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/users/not-a-uuid", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUserHandler_ListUserWallets(t *testing.T) {
+	cleanupTestDB(t)
+	user := createTestUser(t, "Анна", "Смирнова")
+
+	// Кошелек создается через API с указанием владельца
+	payload := models.UpdateBalanceRequest{WalletNumber: "3000015", Amount: 10, UserID: user.ID}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	require.Equal(t, http.StatusOK, rr.Code)
+	// Кошелек без владельца не должен попасть в список
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", "3000023", 5.0)
+	require.NoError(t, err)
+
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/users/"+user.ID+"/wallets", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp models.ListWalletsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Wallets, 1)
+	assert.Equal(t, "3000015", resp.Wallets[0].Number)
+	assert.Equal(t, user.ID, resp.Wallets[0].OwnerID)
+}
+
+func TestWalletHandler_ConvertAndDeduct_OwnerMismatch(t *testing.T) {
+	cleanupTestDB(t)
+	owner := createTestUser(t, "Анна", "Смирнова")
+	stranger := createTestUser(t, "Петр", "Иванов")
+
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance, owner_id) VALUES ($1, $2, $3)", "3000031", 500.0, owner.ID)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO rates (value) VALUES ($1)", 2.0)
+	require.NoError(t, err)
+
+	// Чужой пользователь не может конвертировать
+	payload := models.ConvertRequest{SourceWalletNumber: "3000031", AmountToConvert: 1, UserID: stranger.ID}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/convert", payload))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Владелец с неверной фамилией тоже получает отказ
+	payload = models.ConvertRequest{SourceWalletNumber: "3000031", AmountToConvert: 1, UserID: owner.ID, LastName: "Иванова"}
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/convert", payload))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Владелец конвертирует успешно
+	payload = models.ConvertRequest{SourceWalletNumber: "3000031", AmountToConvert: 1, UserID: owner.ID, FirstName: "Анна", LastName: "Смирнова"}
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/convert", payload))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
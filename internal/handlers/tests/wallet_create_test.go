@@ -0,0 +1,99 @@
+// internal/handlers/tests/wallet_create_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/handlers"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+	"currency-service/internal/service"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты создания кошельков с номером, выделенным сервером, и проверки контрольной цифры ---
+// Используют testRouter и testDB из main_test.go
+
+// luhnValid проверяет контрольную цифру номера независимо от кода сервиса.
+func luhnValid(number string) bool {
+	sum := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if (len(number)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func TestWalletHandler_CreateWallet(t *testing.T) {
+	cleanupTestDB(t)
+	user := createTestUser(t, "Иван", "Иванов")
+
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets", models.CreateWalletRequest{UserID: user.ID}))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var wallet models.Wallet
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &wallet))
+	assert.Len(t, wallet.Number, 7)
+	assert.True(t, luhnValid(wallet.Number), "Номер %s должен иметь корректную контрольную цифру", wallet.Number)
+	assert.Equal(t, user.ID, wallet.OwnerID)
+	assert.InDelta(t, 0.0, wallet.Balance, 0.001)
+
+	// Пустое тело создает кошелек без владельца; номера не повторяются
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets", nil))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var second models.Wallet
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &second))
+	assert.NotEqual(t, wallet.Number, second.Number)
+	assert.Empty(t, second.OwnerID)
+}
+
+func TestWalletHandler_UpdateBalance_InvalidCheckDigit(t *testing.T) {
+	cleanupTestDB(t)
+
+	// 1000017 - корректный номер; опечатка в последней цифре должна отклоняться, а не создавать кошелек
+	payload := models.UpdateBalanceRequest{WalletNumber: "1000018", Amount: 10}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "контрольная цифра")
+
+	var count int
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM wallets").Scan(&count))
+	assert.Equal(t, 0, count)
+
+	// Кошелек, созданный до проверки контрольной цифры, пополняется как обычно
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", "1000018", 5.0)
+	require.NoError(t, err)
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+}
+
+func TestWalletHandler_UpdateBalance_ImplicitCreateDisabled(t *testing.T) {
+	cleanupTestDB(t)
+
+	walletSvc := service.NewWalletService(repository.NewPostgresWalletRepository(), repository.NewPostgresRateRepository(),
+		repository.NewPostgresUserRepository(), repository.NewPostgresMovementRepository(), repository.NewPostgresLimitRepository(),
+		repository.NewPostgresFeeRepository(), testDB, false, service.ConversionSettings{Pair: "USD/RUB"})
+	walletHandler := handlers.NewWalletHandler(walletSvc)
+
+	payload := models.UpdateBalanceRequest{WalletNumber: "1000017", Amount: 10}
+	rr := httptest.NewRecorder()
+	walletHandler.UpdateBalance(rr, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Существующий кошелек пополняется как обычно
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", "1000017", 5.0)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	walletHandler.UpdateBalance(rr, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+}
@@ -0,0 +1,82 @@
+// internal/handlers/tests/wallet_credit_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты для кредитных лимитов кошельков ---
+// Используют testRouter и testDB из main_test.go
+
+func setTestCreditLimit(t *testing.T, walletNumber string, limit float64) (int, models.Wallet) {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPut, "/api/v1/wallets/"+walletNumber+"/credit-limit", models.SetCreditLimitRequest{CreditLimit: limit}))
+	var wallet models.Wallet
+	if rr.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &wallet))
+	}
+	return rr.Code, wallet
+}
+
+func withdrawTest(t *testing.T, walletNumber string, amount float64) int {
+	t.Helper()
+	payload := models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: -amount}
+	return executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload)).Code
+}
+
+func TestWalletHandler_CreditLimit(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8500019"
+	updateTestBalance(t, walletNumber, 50)
+
+	// Без лимита баланс не опускается ниже нуля
+	assert.Equal(t, http.StatusConflict, withdrawTest(t, walletNumber, 80))
+
+	code, wallet := setTestCreditLimit(t, walletNumber, 100)
+	require.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 100.0, wallet.CreditLimit, 0.001)
+	assert.InDelta(t, 100.0, wallet.AvailableCredit, 0.001)
+
+	require.Equal(t, http.StatusOK, withdrawTest(t, walletNumber, 80))
+	wallet = getWalletFromList(t, walletNumber)
+	assert.InDelta(t, -30.0, wallet.Balance, 0.001)
+	assert.InDelta(t, 70.0, wallet.AvailableCredit, 0.001)
+
+	// Ниже -лимита списать нельзя
+	assert.Equal(t, http.StatusConflict, withdrawTest(t, walletNumber, 80))
+	assert.InDelta(t, -30.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// Лимит нельзя уменьшить ниже использованного кредита
+	code, _ = setTestCreditLimit(t, walletNumber, 20)
+	assert.Equal(t, http.StatusConflict, code)
+	code, wallet = setTestCreditLimit(t, walletNumber, 30)
+	require.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 0.0, wallet.AvailableCredit, 0.001)
+
+	code, _ = setTestCreditLimit(t, walletNumber, -1)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = setTestCreditLimit(t, "8500027", 10)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	updateTestBalance(t, walletNumber, 30)
+	assert.InDelta(t, 0.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+}
+
+func TestWalletHandler_CreditLimitRequiresAdmin(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8500035"
+	updateTestBalance(t, walletNumber, 10)
+
+	// Права на операции с кошельками недостаточно, чтобы разрешить себе овердрафт
+	operator := issueTestKey(t, models.ScopeWalletsRead, models.ScopeWalletsWrite).Key
+	req := withKey(createRequest(t, http.MethodPut, "/api/v1/wallets/"+walletNumber+"/credit-limit", models.SetCreditLimitRequest{CreditLimit: 1000}), operator)
+	assert.Equal(t, http.StatusForbidden, executeRequest(t, req).Code)
+	assert.InDelta(t, 0.0, getWalletFromList(t, walletNumber).CreditLimit, 0.001)
+	assert.Equal(t, http.StatusConflict, withdrawTest(t, walletNumber, 500))
+}
@@ -2,10 +2,13 @@
 package handlers_test // Пакет тот же, что и у main_test.go
 
 import (
+	"currency-service/internal/database"
 	"currency-service/internal/models" // Импортируем нужные модели
 	"encoding/json"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,7 +20,7 @@ import (
 func TestWalletHandler_UpdateBalance_CreateWallet(t *testing.T) {
 	cleanupTestDB(t) // Очищаем БД перед тестом
 
-	walletNumber := "1234567"
+	walletNumber := "1245679"
 	initialAmount := 100.50
 
 	payload := models.UpdateBalanceRequest{
@@ -46,9 +49,42 @@ func TestWalletHandler_UpdateBalance_CreateWallet(t *testing.T) {
 	assert.InDelta(t, initialAmount, balance, 0.001, "Баланс в БД не совпадает")
 }
 
+func TestWalletHandler_UpdateBalance_ConcurrentCreate(t *testing.T) {
+	cleanupTestDB(t)
+
+	walletNumber := "8600017"
+	const requests = 8
+	const amount = 10.0
+
+	// Все запросы пополняют еще не существующий кошелек: один создает его,
+	// остальные после гонки на вставке должны быть повторены как обновления
+	codes := make([]int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := createRequest(t, http.MethodPost, "/api/v1/wallets/balance", models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: amount})
+			codes[i] = executeRequest(t, req).Code
+		}(i)
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		assert.Equal(t, http.StatusOK, code, "Запрос %d должен выполниться успешно", i)
+	}
+
+	var balance float64
+	var movements int
+	require.NoError(t, testDB.QueryRow("SELECT balance FROM wallets WHERE wallet_number = $1", walletNumber).Scan(&balance))
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM wallet_movements WHERE wallet_number = $1", walletNumber).Scan(&movements))
+	assert.InDelta(t, requests*amount, balance, 0.001, "Все пополнения должны быть учтены")
+	assert.Equal(t, requests, movements, "На каждое пополнение должно быть одно движение")
+}
+
 func TestWalletHandler_UpdateBalance_DepositExisting(t *testing.T) {
 	cleanupTestDB(t)
-	walletNumber := "7654321"
+	walletNumber := "7643216"
 	initialBalance := 50.0
 	depositAmount := 25.50
 
@@ -83,7 +119,7 @@ func TestWalletHandler_UpdateBalance_DepositExisting(t *testing.T) {
 
 func TestWalletHandler_UpdateBalance_WithdrawExisting_Success(t *testing.T) {
 	cleanupTestDB(t)
-	walletNumber := "1112233"
+	walletNumber := "1122332"
 	initialBalance := 100.0
 	withdrawAmount := -30.0 // Отрицательное значение для списания
 
@@ -117,7 +153,7 @@ func TestWalletHandler_UpdateBalance_WithdrawExisting_Success(t *testing.T) {
 
 func TestWalletHandler_UpdateBalance_WithdrawExisting_InsufficientFunds(t *testing.T) {
 	cleanupTestDB(t)
-	walletNumber := "4445566"
+	walletNumber := "4455663"
 	initialBalance := 20.0
 	withdrawAmount := -50.0 // Пытаемся списать больше, чем есть
 
@@ -141,6 +177,7 @@ func TestWalletHandler_UpdateBalance_WithdrawExisting_InsufficientFunds(t *testi
 	assert.Equal(t, walletNumber, resp.WalletNumber)
 	assert.InDelta(t, initialBalance, resp.NewBalance, 0.001, "Баланс не должен был измениться")
 	assert.Contains(t, resp.Message, "недостаточно средств на счете", "Ожидалось сообщение о недостатке средств")
+	assert.Equal(t, "INSUFFICIENT_FUNDS", resp.Code)
 
 	// Проверка БД (баланс не должен измениться)
 	var dbBalance float64
@@ -202,9 +239,180 @@ func TestWalletHandler_ListWallets(t *testing.T) {
 	}
 }
 
+func TestWalletHandler_ListWallets_PaginationFiltersAndSort(t *testing.T) {
+	cleanupTestDB(t)
+
+	balances := map[string]float64{"1100001": 5, "1100002": 50, "1100003": 15, "1100004": 40, "1100005": 30}
+	for number, balance := range balances {
+		_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", number, balance)
+		require.NoError(t, err)
+	}
+	_, err := testDB.Exec("UPDATE wallets SET status = 'frozen' WHERE wallet_number = $1", "1100004")
+	require.NoError(t, err)
+
+	// Обходим все страницы по 2 кошелька с сортировкой по убыванию баланса и фильтром min_balance
+	var numbers []string
+	url := "/api/v1/wallets?limit=2&sort=balance&order=desc&min_balance=10"
+	for page := 0; page < 5; page++ {
+		rr := executeRequest(t, createRequest(t, http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var resp models.ListWalletsResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.LessOrEqual(t, len(resp.Wallets), 2)
+		for _, w := range resp.Wallets {
+			numbers = append(numbers, w.Number)
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		url = "/api/v1/wallets?limit=2&sort=balance&order=desc&min_balance=10&cursor=" + resp.NextCursor
+	}
+	assert.Equal(t, []string{"1100002", "1100004", "1100005", "1100003"}, numbers)
+
+	// Фильтр по статусу
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets?status=frozen", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp models.ListWalletsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Wallets, 1)
+	assert.Equal(t, "1100004", resp.Wallets[0].Number)
+	assert.Equal(t, models.WalletStatusFrozen, resp.Wallets[0].Status)
+}
+
+func TestWalletHandler_ListWallets_InvalidParams(t *testing.T) {
+	cleanupTestDB(t)
+
+	for _, url := range []string{
+		"/api/v1/wallets?limit=0",
+		"/api/v1/wallets?limit=1000",
+		"/api/v1/wallets?sort=owner",
+		"/api/v1/wallets?order=up",
+		"/api/v1/wallets?status=deleted",
+		"/api/v1/wallets?min_balance=abc",
+		"/api/v1/wallets?min_balance=10&max_balance=5",
+		"/api/v1/wallets?created_from=yesterday",
+		"/api/v1/wallets?cursor=not-a-cursor",
+	} {
+		rr := executeRequest(t, createRequest(t, http.MethodGet, url, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, url)
+	}
+}
+
+func TestWalletHandler_GetWallet_AsOf(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "1200013"
+
+	// Кошелек создан через API (движение deposit), затем история сдвигается в прошлое
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: 100}))
+	require.Equal(t, http.StatusOK, rr.Code)
+	_, err := testDB.Exec("UPDATE wallets SET created_at = NOW() - INTERVAL '2 days' WHERE wallet_number = $1", walletNumber)
+	require.NoError(t, err)
+	_, err = testDB.Exec("UPDATE wallet_movements SET created_at = NOW() - INTERVAL '2 days' WHERE wallet_number = $1", walletNumber)
+	require.NoError(t, err)
+
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: -30}))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/"+walletNumber, nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp models.WalletDetailsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.InDelta(t, 70.0, resp.Balance, 0.001)
+	assert.False(t, resp.CreatedAt.IsZero())
+	assert.False(t, resp.UpdatedAt.IsZero())
+	assert.Nil(t, resp.BalanceAsOf)
+
+	asOf := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/"+walletNumber+"?as_of="+asOf, nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	resp = models.WalletDetailsResponse{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.NotNil(t, resp.BalanceAsOf)
+	assert.InDelta(t, 100.0, *resp.BalanceAsOf, 0.001, "Списание произошло после as_of")
+	assert.InDelta(t, 70.0, resp.Balance, 0.001)
+
+	// Момент до создания кошелька и несуществующий кошелек
+	before := time.Now().Add(-72 * time.Hour).UTC().Format(time.RFC3339)
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/"+walletNumber+"?as_of="+before, nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/1299999", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestWalletHandler_GetWallet_AsOfOpeningBalance(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "1200005"
+
+	// Кошелек без истории движений (создан до ее появления), последнее изменение - час назад
+	_, err := testDB.Exec(`INSERT INTO wallets (wallet_number, balance, created_at, updated_at)
+		VALUES ($1, 100, NOW() - INTERVAL '2 days', NOW() - INTERVAL '1 hour')`, walletNumber)
+	require.NoError(t, err)
+	require.NoError(t, database.MigrateSchema(testDB))
+
+	// Начальный баланс действует с момента создания кошелька, а не с последнего изменения
+	asOf := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/"+walletNumber+"?as_of="+asOf, nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp models.WalletDetailsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.NotNil(t, resp.BalanceAsOf)
+	assert.InDelta(t, 100.0, *resp.BalanceAsOf, 0.001)
+}
+
+func TestWalletHandler_UpdateBalance_IfMatch(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8000010"
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)", walletNumber, 100.0)
+	require.NoError(t, err)
+
+	// Чтение отдает версию кошелька как ETag
+	rr := executeRequest(t, createRequest(t, http.MethodGet, "/api/v1/wallets/"+walletNumber, nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	assert.Equal(t, `"1"`, etag)
+
+	// Обновление с актуальной версией проходит и возвращает новую версию
+	payload := models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: -10}
+	req := createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload)
+	req.Header.Set("If-Match", etag)
+	rr = executeRequest(t, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+	var resp models.UpdateBalanceResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Version)
+
+	// Повтор со старой версией отклоняется, баланс не меняется
+	req = createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload)
+	req.Header.Set("If-Match", etag)
+	rr = executeRequest(t, req)
+	require.Equal(t, http.StatusPreconditionFailed, rr.Code, rr.Body.String())
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.InDelta(t, 90.0, resp.NewBalance, 0.001)
+	assert.InDelta(t, 90.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+
+	// Слабый ETag не подходит для If-Match
+	req = createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload)
+	req.Header.Set("If-Match", `W/"2"`)
+	rr = executeRequest(t, req)
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+
+	// If-Match для несуществующего кошелька не выполняется
+	req = createRequest(t, http.MethodPost, "/api/v1/wallets/balance", models.UpdateBalanceRequest{WalletNumber: "8000028", Amount: 10})
+	req.Header.Set("If-Match", `"1"`)
+	rr = executeRequest(t, req)
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+
+	// Без If-Match обновление проходит как раньше
+	rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", payload))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
 func TestWalletHandler_ConvertAndDeduct_Success(t *testing.T) {
 	cleanupTestDB(t)
-	walletNumber := "2223344"
+	walletNumber := "2233443"
 	initialBalance := 200.0
 	rateValue := 90.5 // Пример курса
 	amountToConvert := 1.5
@@ -248,7 +456,7 @@ func TestWalletHandler_ConvertAndDeduct_Success(t *testing.T) {
 
 func TestWalletHandler_ConvertAndDeduct_InsufficientFunds(t *testing.T) {
 	cleanupTestDB(t)
-	walletNumber := "5556677"
+	walletNumber := "5566773"
 	initialBalance := 10.0
 	rateValue := 90.0
 	amountToConvert := 15.0 // Больше, чем на балансе
@@ -284,6 +492,68 @@ func TestWalletHandler_ConvertAndDeduct_InsufficientFunds(t *testing.T) {
 	assert.InDelta(t, initialBalance, dbBalance, 0.001)
 }
 
+func TestWalletHandler_ConvertAndDeduct_Destination(t *testing.T) {
+	cleanupTestDB(t)
+	owner := createTestUser(t, "Анна", "Смирнова")
+	stranger := createTestUser(t, "Петр", "Иванов")
+	source, destination, foreign := "8300022", "8300030", "8300048"
+
+	_, err := testDB.Exec("INSERT INTO wallets (wallet_number, balance, owner_id) VALUES ($1, 500, $3), ($2, 10, $3), ($4, 0, $5)",
+		source, destination, owner.ID, foreign, stranger.ID)
+	require.NoError(t, err)
+	_, err = testDB.Exec("INSERT INTO rates (value) VALUES ($1)", 2.0)
+	require.NoError(t, err)
+
+	payload := models.ConvertRequest{SourceWalletNumber: source, DestinationWalletNumber: destination, AmountToConvert: 100, UserID: owner.ID}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/convert", payload))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp models.ConvertResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.InDelta(t, 200.0, resp.ConvertedAmount, 0.001)
+	assert.InDelta(t, 300.0, resp.RemainingBalance, 0.001)
+	assert.Equal(t, destination, resp.DestinationWalletNumber)
+	assert.InDelta(t, 210.0, resp.DestinationBalance, 0.001)
+	require.NotZero(t, resp.DestinationMovementID)
+	assert.InDelta(t, 300.0, getWalletFromList(t, source).Balance, 0.001)
+	assert.InDelta(t, 210.0, getWalletFromList(t, destination).Balance, 0.001)
+
+	// Зачисление получателю отдельно не отменяется - только вместе со списанием
+	code, _ := reverseTestMovement(t, resp.DestinationMovementID)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.InDelta(t, 210.0, getWalletFromList(t, destination).Balance, 0.001)
+
+	code, reversal := reverseTestMovement(t, resp.MovementID)
+	require.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 500.0, reversal.NewBalance, 0.001)
+	require.Len(t, reversal.LinkedReversals, 1)
+	assert.Equal(t, destination, reversal.LinkedReversals[0].WalletNumber)
+	assert.InDelta(t, -200.0, reversal.LinkedReversals[0].Amount, 0.001)
+	assert.InDelta(t, 500.0, getWalletFromList(t, source).Balance, 0.001)
+	assert.InDelta(t, 10.0, getWalletFromList(t, destination).Balance, 0.001)
+
+	// Повторная отмена не проходит ни через списание, ни через зачисление
+	code, _ = reverseTestMovement(t, resp.MovementID)
+	assert.Equal(t, http.StatusConflict, code)
+	code, _ = reverseTestMovement(t, resp.DestinationMovementID)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+	// Получатель другого владельца, несуществующий получатель и тот же кошелек отклоняются без списания
+	for _, tc := range []struct {
+		destination string
+		status      int
+	}{
+		{foreign, http.StatusForbidden},
+		{"8300055", http.StatusNotFound},
+		{source, http.StatusBadRequest},
+	} {
+		payload.DestinationWalletNumber = tc.destination
+		rr = executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/convert", payload))
+		assert.Equal(t, tc.status, rr.Code, tc.destination)
+	}
+	assert.InDelta(t, 500.0, getWalletFromList(t, source).Balance, 0.001)
+	assert.InDelta(t, 0.0, getWalletFromList(t, foreign).Balance, 0.001)
+}
+
 // TODO: Добавить тесты для других случаев ConvertAndDeduct:
 // - Кошелек не найден (StatusNotFound)
 // - Курс не найден (StatusServiceUnavailable)
@@ -0,0 +1,101 @@
+// internal/handlers/tests/wallet_status_test.go
+package handlers_test
+
+import (
+	"currency-service/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Тесты заморозки и закрытия кошельков ---
+// Используют testRouter и testDB из main_test.go
+
+func setTestWalletStatus(t *testing.T, walletNumber, status string) (int, models.Wallet) {
+	t.Helper()
+	rr := executeRequest(t, createRequest(t, http.MethodPut, "/api/v1/wallets/"+walletNumber+"/status", models.SetWalletStatusRequest{Status: status}))
+	var wallet models.Wallet
+	if rr.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &wallet))
+	}
+	return rr.Code, wallet
+}
+
+func TestWalletHandler_FrozenWalletRejectsOperations(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber, otherNumber := "8800021", "8800039"
+	updateTestBalance(t, walletNumber, 100)
+	updateTestBalance(t, otherNumber, 10)
+	hold := createTestHold(t, walletNumber, models.CreateHoldRequest{Amount: 10})
+
+	code, wallet := setTestWalletStatus(t, walletNumber, models.WalletStatusFrozen)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.WalletStatusFrozen, wallet.Status)
+
+	// Пополнение, списание, холд и списание холда отклоняются
+	for _, amount := range []float64{10, -10} {
+		rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: amount}))
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Contains(t, rr.Body.String(), "WALLET_FROZEN")
+	}
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/"+walletNumber+"/holds", models.CreateHoldRequest{Amount: 1}))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	rr = executeRequest(t, createRequest(t, http.MethodPost, fmt.Sprintf("/api/v1/holds/%d/capture", hold.ID), models.CaptureHoldRequest{}))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	// В пакете отклоняются и операции с кошельком-получателем
+	_, resp := executeBatch(t, models.BatchRequest{Mode: models.BatchModeBestEffort, Operations: []models.BatchOperation{
+		{Type: models.BatchOperationDeposit, WalletNumber: walletNumber, Amount: 5},
+		{Type: models.BatchOperationTransfer, WalletNumber: otherNumber, TargetWalletNumber: walletNumber, Amount: 5},
+	}})
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, "WALLET_FROZEN", resp.Results[0].Code)
+	assert.Equal(t, "WALLET_FROZEN", resp.Results[1].Code)
+	assert.InDelta(t, 100.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+	assert.InDelta(t, 10.0, getWalletFromList(t, otherNumber).Balance, 0.001)
+
+	// После разморозки операции снова проходят
+	code, _ = setTestWalletStatus(t, walletNumber, models.WalletStatusActive)
+	require.Equal(t, http.StatusOK, code)
+	updateTestBalance(t, walletNumber, -20)
+	assert.InDelta(t, 80.0, getWalletFromList(t, walletNumber).Balance, 0.001)
+}
+
+func TestWalletHandler_CloseWallet(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8800047"
+	updateTestBalance(t, walletNumber, 30)
+
+	// Кошелек с деньгами закрыть нельзя
+	code, _ := setTestWalletStatus(t, walletNumber, models.WalletStatusClosed)
+	assert.Equal(t, http.StatusConflict, code)
+	updateTestBalance(t, walletNumber, -30)
+	code, wallet := setTestWalletStatus(t, walletNumber, models.WalletStatusClosed)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.WalletStatusClosed, wallet.Status)
+
+	// Закрытие окончательно
+	rr := executeRequest(t, createRequest(t, http.MethodPost, "/api/v1/wallets/balance", models.UpdateBalanceRequest{WalletNumber: walletNumber, Amount: 10}))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "WALLET_CLOSED")
+	code, _ = setTestWalletStatus(t, walletNumber, models.WalletStatusActive)
+	assert.Equal(t, http.StatusConflict, code)
+
+	code, _ = setTestWalletStatus(t, walletNumber, "deleted")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestWalletHandler_WalletStatusRequiresAdmin(t *testing.T) {
+	cleanupTestDB(t)
+	walletNumber := "8900037"
+	updateTestBalance(t, walletNumber, 10)
+
+	key := issueTestKey(t, models.ScopeWalletsRead, models.ScopeWalletsWrite)
+	req := withKey(createRequest(t, http.MethodPut, "/api/v1/wallets/"+walletNumber+"/status", models.SetWalletStatusRequest{Status: models.WalletStatusFrozen}), key.Key)
+	assert.Equal(t, http.StatusForbidden, executeRequest(t, req).Code)
+	assert.Equal(t, models.WalletStatusActive, getWalletFromList(t, walletNumber).Status)
+}
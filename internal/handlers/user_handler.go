@@ -0,0 +1,109 @@
+// internal/handlers/user_handler.go
+package handlers
+
+import (
+	"currency-service/internal/models"
+	"currency-service/internal/service"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// UserHandler обрабатывает HTTP-запросы, связанные с пользователями.
+type UserHandler struct {
+	userService service.UserService
+}
+
+// NewUserHandler создает новый экземпляр обработчика пользователей.
+func NewUserHandler(svc service.UserService) *UserHandler {
+	return &UserHandler{userService: svc}
+}
+
+// CreateUser godoc
+// @Summary      Создать пользователя
+// @Description  Создает владельца кошельков. ID пользователя генерируется сервером.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        user body models.CreateUserRequest true "Имя и фамилия пользователя"
+// @Success      201  {object}  models.User "Пользователь создан"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный формат запроса или пустое имя"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /users [post]
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateUserRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (CreateUser): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	user, err := h.userService.CreateUser(r.Context(), req)
+	if err != nil {
+		log.Printf("Ошибка из сервиса CreateUser: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, user)
+}
+
+// GetUser godoc
+// @Summary      Получить пользователя
+// @Description  Возвращает пользователя по его ID.
+// @Tags         Users
+// @Produce      json
+// @Param        id path string true "ID пользователя (UUID)"
+// @Success      200  {object}  models.User "Пользователь"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID пользователя"
+// @Failure      404  {object}  models.ErrorResponse "Пользователь не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /users/{id} [get]
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := checkCustomerUser(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.userService.GetUser(r.Context(), id)
+	if err != nil {
+		log.Printf("Ошибка из сервиса GetUser: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, user)
+}
+
+// ListUserWallets godoc
+// @Summary      Получить кошельки пользователя
+// @Description  Возвращает все кошельки, владельцем которых является пользователь.
+// @Tags         Users
+// @Produce      json
+// @Param        id path string true "ID пользователя (UUID)"
+// @Success      200  {object}  models.ListWalletsResponse "Кошельки пользователя"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный ID пользователя"
+// @Failure      404  {object}  models.ErrorResponse "Пользователь не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/wallets [get]
+func (h *UserHandler) ListUserWallets(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := checkCustomerUser(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp, err := h.userService.ListUserWallets(r.Context(), id)
+	if err != nil {
+		log.Printf("Ошибка из сервиса ListUserWallets: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
@@ -6,8 +6,15 @@ import (
 	"currency-service/internal/service"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
 )
 
 // WalletHandler обрабатывает HTTP-запросы, связанные с кошельками.
@@ -34,15 +41,23 @@ func writeJSONResponse(w http.ResponseWriter, statusCode int, payload interface{
 
 // UpdateBalance godoc
 // @Summary      Создать кошелек или обновить баланс
-// @Description  Создает новый кошелек с указанным балансом (если сумма положительная) или обновляет баланс существующего кошелька. Положительная сумма - пополнение, отрицательная - списание. Списание с несуществующего кошелька или до отрицательного баланса невозможно.
+// @Description  Создает новый кошелек с указанным балансом (если сумма положительная и включено неявное создание WALLET_IMPLICIT_CREATE, по умолчанию выключено; номер нового кошелька должен иметь корректную контрольную цифру по алгоритму Луна) или обновляет баланс существующего кошелька. Положительная сумма - пополнение, отрицательная - списание. Списание с несуществующего кошелька или ниже кредитного лимита (без лимита - ниже нуля) невозможно. Если указан user_id, новый кошелек привязывается к этому пользователю, а для существующего проверяется, что пользователь - его владелец. Списание с кошелька, у которого есть владелец, без user_id доступно только сотрудникам (роль operator или admin, API-ключ с правом admin).
 // @Tags         Wallets
 // @Accept       json
 // @Produce      json
 // @Param        balance_update body models.UpdateBalanceRequest true "Данные для обновления баланса"
+// @Param        If-Match header string false "ETag кошелька из GET /wallets/{number}. Если версия изменилась, обновление отклоняется с 412"
+// @Param        Accept-Language header string false "Язык сообщений: ru (по умолчанию), en, kk"
 // @Success      200  {object}  models.UpdateBalanceResponse "Баланс успешно обновлен"
-// @Failure      400  {object}  models.ErrorResponse "Некорректный формат запроса, номера кошелька или суммы"
+// @Header       200  {string}  ETag "Версия кошелька после обновления"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный формат запроса, номера кошелька (в том числе контрольной цифры нового номера) или суммы"
+// @Failure      403  {object}  models.ErrorResponse "Кошелек не принадлежит указанному пользователю или для списания нужен user_id владельца"
+// @Failure      404  {object}  models.ErrorResponse "Указанный пользователь не найден или кошелек не найден (если неявное создание кошельков выключено)"
 // @Failure      409  {object}  models.UpdateBalanceResponse "Конфликт бизнес-логики (например, недостаточно средств)"
+// @Failure      412  {object}  models.UpdateBalanceResponse "Версия из If-Match не совпадает с текущей (в ответе актуальные баланс и версия)"
+// @Failure      422  {object}  models.LimitExceededResponse "Превышен лимит на списания"
 // @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
 // @Router       /wallets/balance [post]
 func (h *WalletHandler) UpdateBalance(w http.ResponseWriter, r *http.Request) {
 	var req models.UpdateBalanceRequest
@@ -51,76 +66,332 @@ func (h *WalletHandler) UpdateBalance(w http.ResponseWriter, r *http.Request) {
 	err := decoder.Decode(&req)
 	if err != nil {
 		log.Printf("Ошибка декодирования JSON (UpdateBalance): %v\n", err)
-		writeJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{Error: "Некорректный формат запроса: " + err.Error()})
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
 		return
 	}
 
-	resp, err := h.walletService.UpdateBalance(r.Context(), req)
+	// If-Match с ETag, который не может быть версией кошелька, не выполнится ни при каком состоянии
+	expectedVersion, ok := parseIfMatch(r)
+	if !ok {
+		writeError(w, r, service.ErrVersionMismatch)
+		return
+	}
+	req.ExpectedVersion = expectedVersion
 
-	statusCode := http.StatusOK
-	errorPayload := models.ErrorResponse{} // Используем для ошибок 400 и 500
+	// Клиенты могут только списывать со своих кошельков
+	if customerID(r.Context()) != "" && req.Amount > 0 {
+		writeError(w, r, fmt.Errorf("%w: пополнение недоступно клиентам", service.ErrInsufficientScope))
+		return
+	}
+	if req.RequireOwner, err = bindCustomerOwner(r.Context(), &req.UserID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	req.Staff = isStaff(r.Context())
+
+	resp, err := h.walletService.UpdateBalance(r.Context(), req)
 
 	if err != nil {
 		log.Printf("Ошибка из сервиса UpdateBalance: %v\n", err)
-		switch {
-		case errors.Is(err, service.ErrInvalidWalletNumber):
-			statusCode = http.StatusBadRequest
-			errorPayload.Error = err.Error()
-		case errors.Is(err, service.ErrWithdrawNonExistent):
-			statusCode = http.StatusBadRequest // Считаем это ошибкой запроса
-			errorPayload.Error = err.Error()
-		case errors.Is(err, service.ErrInsufficientFunds):
-			statusCode = http.StatusConflict // 409 - возвращаем структуру ответа с текущим балансом
-		case errors.Is(err, service.ErrNegativeDeposit):
-			statusCode = http.StatusBadRequest
-			errorPayload.Error = err.Error()
-		default:
-			statusCode = http.StatusInternalServerError
-			errorPayload.Error = "Внутренняя ошибка сервера"
+		// При нехватке средств (409) и несовпадении версии (412) возвращаем структуру ответа с текущими балансом и версией
+		if errors.Is(err, service.ErrInsufficientFunds) || errors.Is(err, service.ErrVersionMismatch) {
+			resp.Code = errorCode(err)
+			resp.Message = localize(r, resp.Code, resp.Message)
+			setWalletETag(w, resp.Version)
+			writeJSONResponse(w, errorStatus(err), resp)
+			return
 		}
+		writeError(w, r, err)
+		return
+	}
+
+	resp.Message = localize(r, resp.MessageCode, resp.Message)
+	setWalletETag(w, resp.Version)
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// CreateWallet godoc
+// @Summary      Создать кошелек
+// @Description  Создает кошелек с нулевым балансом. Номер выделяет сервер: 7 цифр, последняя - контрольная цифра по алгоритму Луна. Если указан user_id, кошелек привязывается к этому пользователю.
+// @Tags         Wallets
+// @Accept       json
+// @Produce      json
+// @Param        wallet body models.CreateWalletRequest false "Владелец и уровень кошелька"
+// @Success      201  {object}  models.Wallet "Кошелек создан"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный формат запроса, ID пользователя или уровень"
+// @Failure      404  {object}  models.ErrorResponse "Указанный пользователь не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /wallets [post]
+func (h *WalletHandler) CreateWallet(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateWalletRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	// Тело необязательно: пустое тело создает кошелек без владельца
+	if err := decoder.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Printf("Ошибка декодирования JSON (CreateWallet): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	wallet, err := h.walletService.CreateWallet(r.Context(), req)
+	if err != nil {
+		log.Printf("Ошибка из сервиса CreateWallet: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, wallet)
+}
+
+// parseListWalletsFilter читает параметры списка кошельков из query-строки.
+func parseListWalletsFilter(r *http.Request) (models.ListWalletsFilter, error) {
+	q := r.URL.Query()
+	filter := models.ListWalletsFilter{
+		Cursor: q.Get("cursor"),
+		Status: q.Get("status"),
+		SortBy: q.Get("sort"),
+		Order:  q.Get("order"),
 	}
 
-	// Отправляем ответ
-	if statusCode == http.StatusOK || statusCode == http.StatusConflict {
-		// Для 200 OK и 409 Conflict возвращаем структуру UpdateBalanceResponse
-		writeJSONResponse(w, statusCode, resp)
-	} else {
-		// Для 400 и 500 возвращаем стандартную ErrorResponse
-		writeJSONResponse(w, statusCode, errorPayload)
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("некорректное значение параметра 'limit'")
+		}
+		filter.Limit = limit
+	}
+	for name, dst := range map[string]**float64{"min_balance": &filter.MinBalance, "max_balance": &filter.MaxBalance} {
+		if v := q.Get(name); v != "" {
+			value, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return filter, fmt.Errorf("некорректное значение параметра '%s'", name)
+			}
+			*dst = &value
+		}
 	}
+	for name, dst := range map[string]**time.Time{"created_from": &filter.CreatedFrom, "created_to": &filter.CreatedTo} {
+		if v := q.Get(name); v != "" {
+			value, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, fmt.Errorf("некорректное значение параметра '%s' (ожидается RFC 3339)", name)
+			}
+			*dst = &value
+		}
+	}
+	return filter, nil
 }
 
+// walletExportColumns - колонки CSV-выгрузки кошельков (те же имена, что в JSON).
+var walletExportColumns = []string{"number", "balance", "available_balance", "credit_limit", "available_credit", "owner_id", "tier", "status", "version", "created_at", "updated_at"}
+
 // ListWallets godoc
-// @Summary      Получить список всех кошельков
-// @Description  Возвращает массив всех зарегистрированных кошельков с их балансами.
+// @Summary      Получить список кошельков
+// @Description  Возвращает страницу кошельков с их балансами: balance - учетный баланс, available_balance - доступный баланс за вычетом активных холдов. Для получения следующей страницы передайте next_cursor из ответа в параметре cursor с теми же фильтрами и сортировкой.
+// @Description  Формат ответа выбирается параметром format или заголовком Accept (text/csv, application/x-ndjson). CSV и NDJSON отдаются потоком по мере чтения из БД, содержат время создания и обновления кошельков и не разбиваются на страницы: выгружаются все подходящие кошельки (или первые limit). Выгрузка может длиться до 10 минут; в трейлерах X-Export-Status (complete или truncated) и X-Export-Rows сообщается, полный ли файл: без трейлера complete выгрузка неполная.
 // @Tags         Wallets
-// @Produce      json
-// @Success      200  {object}  models.ListWalletsResponse "Список кошельков"
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        limit query int false "Размер страницы (по умолчанию 50, максимум 500; для CSV и NDJSON - без ограничения)" minimum(1)
+// @Param        cursor query string false "Курсор следующей страницы (next_cursor из предыдущего ответа)"
+// @Param        min_balance query number false "Баланс не меньше"
+// @Param        max_balance query number false "Баланс не больше"
+// @Param        status query string false "Статус кошелька" Enums(active, frozen, closed)
+// @Param        created_from query string false "Создан не раньше (RFC 3339, включительно)"
+// @Param        created_to query string false "Создан раньше (RFC 3339, не включительно)"
+// @Param        sort query string false "Ключ сортировки (по умолчанию created_at)" Enums(created_at, balance, wallet_number)
+// @Param        order query string false "Порядок сортировки (по умолчанию asc)" Enums(asc, desc)
+// @Param        format query string false "Формат ответа (приоритетнее заголовка Accept)" Enums(json, csv, ndjson)
+// @Param        If-None-Match header string false "ETag из предыдущего ответа"
+// @Param        If-Modified-Since header string false "Last-Modified из предыдущего ответа"
+// @Success      200  {object}  models.ListWalletsResponse "Страница кошельков"
+// @Header       200  {string}  ETag "Версия списка кошельков и формат ответа"
+// @Header       200  {string}  Last-Modified "Время последнего изменения кошельков или их холдов"
+// @Success      304  "Кошельки не изменились"
+// @Failure      400  {object}  models.ErrorResponse "Некорректные параметры фильтрации, сортировки, формата или курсор"
 // @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
 // @Router       /wallets [get]
 func (h *WalletHandler) ListWallets(w http.ResponseWriter, r *http.Request) {
-	resp, err := h.walletService.ListWallets(r.Context())
+	filter, err := parseListWalletsFilter(r)
+	if err != nil {
+		log.Printf("Некорректные параметры ListWallets: %v\n", err)
+		writeRequestError(w, r, err.Error())
+		return
+	}
+	w.Header().Add("Vary", "Accept") // Формат ответа зависит от заголовка Accept
+	format, err := responseFormat(r)
+	if err != nil {
+		writeRequestError(w, r, err.Error())
+		return
+	}
+
+	if version, err := h.walletService.GetWalletsVersion(r.Context()); err != nil {
+		log.Printf("Ошибка получения версии списка кошельков: %v\n", err)
+	} else if checkNotModified(w, r, dataETag(version, format), version.LastModified) {
+		return
+	}
+
+	if format != formatJSON {
+		export := newExportWriter(w, format, "wallets", walletExportColumns)
+		err := h.walletService.ExportWallets(r.Context(), filter, func(wallet models.Wallet) error {
+			record := []string{
+				wallet.Number,
+				formatExportFloat(wallet.Balance),
+				formatExportFloat(wallet.AvailableBalance),
+				formatExportFloat(wallet.CreditLimit),
+				formatExportFloat(wallet.AvailableCredit),
+				wallet.OwnerID,
+				wallet.Tier,
+				wallet.Status,
+				strconv.FormatInt(wallet.Version, 10),
+				formatExportTime(wallet.CreatedAt),
+				formatExportTime(wallet.UpdatedAt),
+			}
+			return export.writeRow(record, models.WalletDetailsResponse{Wallet: wallet, CreatedAt: wallet.CreatedAt, UpdatedAt: wallet.UpdatedAt})
+		})
+		export.finish(r, err)
+		return
+	}
+
+	resp, err := h.walletService.ListWallets(r.Context(), filter)
 	if err != nil {
 		log.Printf("Ошибка из сервиса ListWallets: %v\n", err)
-		writeJSONResponse(w, http.StatusInternalServerError, models.ErrorResponse{Error: "Не удалось получить список кошельков"})
+		writeError(w, r, err)
 		return
 	}
 	writeJSONResponse(w, http.StatusOK, resp)
 }
 
+// GetWallet godoc
+// @Summary      Получить кошелек
+// @Description  Возвращает кошелек по номеру с временем создания и последнего обновления. Если указан as_of, дополнительно возвращает учетный баланс на этот момент, восстановленный по истории движений.
+// @Tags         Wallets
+// @Produce      json
+// @Param        number path string true "Номер кошелька"
+// @Param        as_of query string false "Момент, на который нужен баланс (RFC 3339)"
+// @Success      200  {object}  models.WalletDetailsResponse "Кошелек"
+// @Header       200  {string}  ETag "Версия кошелька для If-Match в POST /wallets/balance"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный номер кошелька или as_of"
+// @Failure      404  {object}  models.ErrorResponse "Кошелек не найден"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /wallets/{number} [get]
+func (h *WalletHandler) GetWallet(w http.ResponseWriter, r *http.Request) {
+	var asOf *time.Time
+	if v := r.URL.Query().Get("as_of"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeRequestError(w, r, "Некорректное значение параметра 'as_of' (ожидается RFC 3339)")
+			return
+		}
+		asOf = &t
+	}
+
+	resp, err := h.walletService.GetWallet(r.Context(), chi.URLParam(r, "number"), asOf)
+	if err != nil {
+		log.Printf("Ошибка из сервиса GetWallet: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	// Клиенту доступны только его кошельки
+	if id := customerID(r.Context()); id != "" && !strings.EqualFold(resp.OwnerID, id) {
+		writeError(w, r, service.ErrWalletOwnerMismatch)
+		return
+	}
+	setWalletETag(w, resp.Version)
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// SetCreditLimit godoc
+// @Summary      Установить кредитный лимит кошелька
+// @Description  Разрешает кошельку овердрафт: баланс может опускаться до -credit_limit. 0 возвращает нулевой порог. Лимит нельзя уменьшить ниже уже использованного кредита. В ответе available_credit - неиспользованная часть лимита. Требует права admin.
+// @Tags         Wallets
+// @Accept       json
+// @Produce      json
+// @Param        number path string true "Номер кошелька"
+// @Param        credit_limit body models.SetCreditLimitRequest true "Кредитный лимит"
+// @Success      200  {object}  models.Wallet "Лимит установлен"
+// @Header       200  {string}  ETag "Новая версия кошелька"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный запрос, номер кошелька или отрицательный лимит"
+// @Failure      403  {object}  models.ErrorResponse "У ключа нет права admin"
+// @Failure      404  {object}  models.ErrorResponse "Кошелек не найден"
+// @Failure      409  {object}  models.ErrorResponse "Лимит меньше уже использованного кредита"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /wallets/{number}/credit-limit [put]
+func (h *WalletHandler) SetCreditLimit(w http.ResponseWriter, r *http.Request) {
+	var req models.SetCreditLimitRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (SetCreditLimit): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	wallet, err := h.walletService.SetCreditLimit(r.Context(), chi.URLParam(r, "number"), req.CreditLimit)
+	if err != nil {
+		log.Printf("Ошибка из сервиса SetCreditLimit: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	setWalletETag(w, wallet.Version)
+	writeJSONResponse(w, http.StatusOK, wallet)
+}
+
+// SetWalletStatus godoc
+// @Summary      Изменить статус кошелька
+// @Description  Замораживает (frozen), размораживает (active) или закрывает (closed) кошелек. С замороженным кошельком нельзя проводить операции (пополнения, списания, переводы, конвертации, холды, отмены движений) до разморозки. Закрыть можно только кошелек с нулевым балансом и без активных холдов; закрытие окончательно. Требует права admin.
+// @Tags         Wallets
+// @Accept       json
+// @Produce      json
+// @Param        number path string true "Номер кошелька"
+// @Param        status body models.SetWalletStatusRequest true "Новый статус"
+// @Success      200  {object}  models.Wallet "Статус изменен"
+// @Header       200  {string}  ETag "Новая версия кошелька"
+// @Failure      400  {object}  models.ErrorResponse "Некорректный запрос, номер кошелька или статус"
+// @Failure      403  {object}  models.ErrorResponse "У ключа нет права admin"
+// @Failure      404  {object}  models.ErrorResponse "Кошелек не найден"
+// @Failure      409  {object}  models.ErrorResponse "Кошелек уже закрыт или при закрытии на нем остались средства или холды"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /wallets/{number}/status [put]
+func (h *WalletHandler) SetWalletStatus(w http.ResponseWriter, r *http.Request) {
+	var req models.SetWalletStatusRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (SetWalletStatus): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	wallet, err := h.walletService.SetWalletStatus(r.Context(), chi.URLParam(r, "number"), req.Status)
+	if err != nil {
+		log.Printf("Ошибка из сервиса SetWalletStatus: %v\n", err)
+		writeError(w, r, err)
+		return
+	}
+	setWalletETag(w, wallet.Version)
+	writeJSONResponse(w, http.StatusOK, wallet)
+}
+
 // ConvertAndDeduct godoc
 // @Summary      Конвертировать и списать сумму с кошелька
-// @Description  Получает самый свежий курс, конвертирует указанную сумму и списывает ее с баланса указанного кошелька. Возвращает остаток на счете и результат конвертации.
+// @Description  Получает самый свежий курс, конвертирует указанную сумму и списывает ее с баланса указанного кошелька. Если указан destination_wallet_number, сконвертированная сумма в той же транзакции зачисляется на этот кошелек (он должен существовать и принадлежать тому же владельцу или не иметь владельца), и в ответе возвращаются балансы обоих кошельков. Если у кошелька есть владелец, user_id (и first_name/last_name, если указаны) должны совпадать с ним. Возвращает остаток на счете и результат конвертации.
 // @Tags         Wallets
 // @Accept       json
 // @Produce      json
 // @Param        conversion_request body models.ConvertRequest true "Данные для конвертации и списания"
+// @Param        Accept-Language header string false "Язык сообщений: ru (по умолчанию), en, kk"
 // @Success      200  {object}  models.ConvertResponse "Конвертация и списание прошли успешно"
 // @Failure      400  {object}  models.ErrorResponse "Некорректный формат запроса, номера кошелька или суммы"
-// @Failure      404  {object}  models.ErrorResponse "Указанный кошелек не найден"
+// @Failure      403  {object}  models.ErrorResponse "Кошелек-источник не принадлежит указанному пользователю или получатель принадлежит другому владельцу"
+// @Failure      404  {object}  models.ErrorResponse "Кошелек-источник или получатель не найден"
 // @Failure      409  {object}  models.ConvertResponse "Конфликт: недостаточно средств на кошельке"
+// @Failure      422  {object}  models.LimitExceededResponse "Превышен лимит на списания или количество конвертаций"
 // @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
 // @Failure      503  {object}  models.ErrorResponse "Не удалось получить актуальный курс валют"
+// @Security     ApiKeyAuth
 // @Router       /wallets/convert [post]
 func (h *WalletHandler) ConvertAndDeduct(w http.ResponseWriter, r *http.Request) {
 	var req models.ConvertRequest
@@ -129,42 +400,85 @@ func (h *WalletHandler) ConvertAndDeduct(w http.ResponseWriter, r *http.Request)
 	err := decoder.Decode(&req)
 	if err != nil {
 		log.Printf("Ошибка декодирования JSON (ConvertAndDeduct): %v\n", err)
-		writeJSONResponse(w, http.StatusBadRequest, models.ErrorResponse{Error: "Некорректный формат запроса: " + err.Error()})
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
+
+	requireOwner, err := bindCustomerOwner(r.Context(), &req.UserID)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
+	req.RequireOwner = requireOwner
 
 	resp, err := h.walletService.ConvertAndDeduct(r.Context(), req)
+	if err != nil {
+		log.Printf("Ошибка из сервиса ConvertAndDeduct: %v\n", err)
+		// При нехватке средств возвращаем ConvertResponse с сообщением
+		if errors.Is(err, service.ErrInsufficientFunds) {
+			resp.Code = errorCode(err)
+			resp.Message = localize(r, resp.Code, resp.Message)
+			writeJSONResponse(w, errorStatus(err), resp)
+			return
+		}
+		writeError(w, r, err)
+		return
+	}
+	resp.Message = localize(r, resp.MessageCode, resp.Message)
+	writeJSONResponse(w, http.StatusOK, resp)
+}
 
-	statusCode := http.StatusOK
-	errorPayload := models.ErrorResponse{}
+// ExecuteBatch godoc
+// @Summary      Выполнить пакет операций с кошельками
+// @Description  Выполняет список пополнений (deposit), списаний (withdrawal) и переводов (transfer) в одной транзакции и возвращает результат каждой операции. Все кошельки должны существовать. Операции выполняются по порядку и видят результат предыдущих. Для списаний и переводов с кошелька, у которого есть владелец, user_id должен совпадать с владельцем; применяются лимиты. В режиме atomic (по умолчанию) ошибка любой операции отменяет весь пакет; в режиме best_effort неудачные операции пропускаются, а остальные фиксируются.
+// @Tags         Wallets
+// @Accept       json
+// @Produce      json
+// @Param        batch_request body models.BatchRequest true "Операции пакета и режим выполнения"
+// @Param        Accept-Language header string false "Язык сообщений: ru (по умолчанию), en, kk"
+// @Success      200  {object}  models.BatchResponse "Пакет выполнен (в best_effort - возможно, частично)"
+// @Failure      400  {object}  models.BatchResponse "Некорректная операция в режиме atomic (для ошибок пакета целиком - ErrorResponse)"
+// @Failure      403  {object}  models.BatchResponse "Кошелек операции не принадлежит указанному пользователю (atomic)"
+// @Failure      404  {object}  models.BatchResponse "Кошелек операции не найден (atomic)"
+// @Failure      409  {object}  models.BatchResponse "Недостаточно средств для операции (atomic)"
+// @Failure      422  {object}  models.BatchResponse "Превышен лимит на списания (atomic)"
+// @Failure      500  {object}  models.ErrorResponse "Внутренняя ошибка сервера"
+// @Security     ApiKeyAuth
+// @Router       /wallets/batch [post]
+func (h *WalletHandler) ExecuteBatch(w http.ResponseWriter, r *http.Request) {
+	var req models.BatchRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		log.Printf("Ошибка декодирования JSON (ExecuteBatch): %v\n", err)
+		writeRequestError(w, r, "Некорректный формат запроса: "+err.Error())
+		return
+	}
 
+	resp, err := h.walletService.ExecuteBatch(r.Context(), req)
 	if err != nil {
-		log.Printf("Ошибка из сервиса ConvertAndDeduct: %v\n", err)
-		switch {
-		case errors.Is(err, service.ErrInvalidWalletNumber):
-			statusCode = http.StatusBadRequest
-			errorPayload.Error = err.Error()
-		case err.Error() == "сумма для конвертации должна быть положительной":
-			statusCode = http.StatusBadRequest
-			errorPayload.Error = err.Error()
-		case errors.Is(err, service.ErrWalletNotFound):
-			statusCode = http.StatusNotFound
-			errorPayload.Error = err.Error()
-		case errors.Is(err, service.ErrInsufficientFunds):
-			statusCode = http.StatusConflict // Возвращаем ConvertResponse с сообщением
-		case errors.Is(err, service.ErrRateNotAvailable):
-			statusCode = http.StatusServiceUnavailable
-			errorPayload.Error = err.Error()
-		default:
-			statusCode = http.StatusInternalServerError
-			errorPayload.Error = "Внутренняя ошибка сервера при конвертации"
+		log.Printf("Ошибка из сервиса ExecuteBatch: %v\n", err)
+		var itemErr *service.BatchItemError
+		if !errors.As(err, &itemErr) {
+			// Ошибка пакета целиком - результатов по операциям нет
+			writeError(w, r, err)
+			return
 		}
+
+		// Пакет отменен из-за операции itemErr.Index - статус по ее причине, в теле результаты всех операций
+		localizeBatchResults(r, resp.Results)
+		writeJSONResponse(w, errorStatus(err), resp)
+		return
 	}
+	localizeBatchResults(r, resp.Results)
+	writeJSONResponse(w, http.StatusOK, resp)
+}
 
-	// Отправляем ответ
-	if statusCode == http.StatusOK || statusCode == http.StatusConflict {
-		writeJSONResponse(w, statusCode, resp)
-	} else {
-		writeJSONResponse(w, statusCode, errorPayload)
+// localizeBatchResults переводит тексты ошибок операций пакета на язык запроса.
+func localizeBatchResults(r *http.Request, results []models.BatchOperationResult) {
+	for i := range results {
+		if results[i].Code != "" {
+			results[i].Error = localize(r, results[i].Code, results[i].Error)
+		}
 	}
 }
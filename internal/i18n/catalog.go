@@ -0,0 +1,230 @@
+// internal/i18n/catalog.go
+package i18n
+
+// catalog - переводы сообщений API, ключ - код ошибки (см. internal/apperrors) или код сообщения об успехе
+// (models.Message*). Исходные русские тексты находятся рядом с ошибками и в сервисах, поэтому здесь их нет.
+// В отличие от исходных текстов, переводы не содержат значений из запроса: их клиент получает в полях ответа.
+var catalog = map[Lang]map[string]string{
+	English: {
+		// Общие
+		"INTERNAL_ERROR":  "Internal server error",
+		"INVALID_REQUEST": "Invalid request body or parameter",
+
+		// Доступ
+		"UNAUTHORIZED":         "a valid API key is required",
+		"INSUFFICIENT_SCOPE":   "API key does not have the required scope",
+		"API_KEY_NOT_FOUND":    "API key not found",
+		"INVALID_API_KEY_NAME": "API key name must be from 1 to 64 characters long",
+		"INVALID_SCOPE":        "unknown API key scope",
+		"RATE_LIMITED":         "too many requests, please retry later",
+
+		// Курсы валют
+		"RATE_NOT_POSITIVE":  "exchange rate must be a positive number",
+		"NO_RATES":           "no exchange rates have been registered",
+		"RATE_NOT_AVAILABLE": "current exchange rate is not available",
+
+		// Кошельки
+		"WALLET_NOT_FOUND":                 "wallet not found",
+		"WALLET_EXISTS":                    "wallet with this number already exists",
+		"INSUFFICIENT_FUNDS":               "insufficient funds",
+		"INVALID_WALLET_NUMBER":            "invalid wallet number format (7 digits required)",
+		"INVALID_CHECK_DIGIT":              "invalid wallet number format (7 digits required): wrong check digit",
+		"INVALID_DEPOSIT_AMOUNT":           "amount for creating a wallet must be positive",
+		"WITHDRAW_FROM_NONEXISTENT_WALLET": "cannot withdraw from a wallet that does not exist",
+		"INVALID_TRANSFER_AMOUNT":          "transfer amount must be positive",
+		"SAME_WALLET_TRANSFER":             "cannot transfer funds to the same wallet",
+		"TARGET_WALLET_NOT_FOUND":          "recipient wallet not found",
+		"INVALID_CONVERT_AMOUNT":           "amount to convert must be positive",
+		"SAME_WALLET_CONVERSION":           "conversion destination wallet must differ from the source wallet",
+		"DESTINATION_OWNER_MISMATCH":       "wallet does not belong to the specified user: destination wallet belongs to another owner",
+		"INVALID_PAGE_SIZE":                "invalid page size",
+		"INVALID_CURSOR":                   "invalid page cursor",
+		"INVALID_SORT":                     "invalid sort (allowed: created_at, balance, wallet_number; order asc or desc)",
+		"INVALID_WALLET_STATUS":            "invalid wallet status (allowed: active, frozen, closed)",
+		"INVALID_LIST_FILTER":              "invalid filter: lower bound is greater than upper bound",
+		"AS_OF_IN_FUTURE":                  "as_of cannot be in the future",
+		"AS_OF_BEFORE_CREATION":            "the wallet did not exist yet at as_of",
+		"WALLET_NUMBER_SPACE_EXHAUSTED":    "could not allocate a free wallet number, please try again",
+		"VERSION_MISMATCH":                 "wallet was modified by another request, fetch the current version and retry",
+		"INVALID_CREDIT_LIMIT":             "credit limit must be a non-negative number",
+		"CREDIT_LIMIT_IN_USE":              "credit limit cannot be lower than the credit already used",
+		"WALLET_FROZEN":                    "wallet is frozen",
+		"WALLET_CLOSED":                    "wallet is closed",
+		"WALLET_NOT_EMPTY":                 "only a wallet with zero balance and no active holds can be closed",
+
+		// Пользователи
+		"USER_NOT_FOUND":        "user not found",
+		"INVALID_USER_ID":       "invalid user ID format (UUID required)",
+		"INVALID_USER_NAME":     "user first and last name are required",
+		"WALLET_OWNER_MISMATCH": "wallet does not belong to the specified user",
+		"WALLET_OWNER_REQUIRED": "wallet does not belong to the specified user: user_id of the owner is required to debit the wallet",
+
+		// Пакетные операции
+		"EMPTY_BATCH":             "batch contains no operations",
+		"BATCH_TOO_LARGE":         "batch contains too many operations",
+		"INVALID_BATCH_MODE":      "invalid batch mode (allowed: atomic, best_effort)",
+		"INVALID_BATCH_OPERATION": "invalid operation type (allowed: deposit, withdrawal, transfer)",
+		"INVALID_BATCH_AMOUNT":    "operation amount must be positive",
+
+		// Холды
+		"HOLD_NOT_FOUND":           "hold not found",
+		"HOLD_NOT_ACTIVE":          "hold has already been captured, voided or has expired",
+		"INVALID_HOLD_AMOUNT":      "hold amount must be positive",
+		"INVALID_HOLD_TTL":         "hold lifetime must be positive",
+		"CAPTURE_EXCEEDS_HOLD":     "capture amount exceeds the held amount",
+		"DUPLICATE_HOLD_REFERENCE": "a hold with this reference already exists for this wallet",
+
+		// Лимиты
+		"LIMIT_EXCEEDED": "wallet operation limit exceeded",
+		"INVALID_TIER":   "invalid tier name (latin letters, digits, '_' and '-', up to 32 characters)",
+		"INVALID_LIMIT":  "limit value cannot be negative",
+
+		// Расписания
+		"SCHEDULE_NOT_FOUND":         "schedule not found",
+		"SCHEDULE_NOT_ACTIVE":        "schedule has already completed or is disabled",
+		"INVALID_SCHEDULE_OPERATION": "invalid schedule operation (allowed: balance_update, transfer, convert)",
+		"INVALID_SCHEDULE_AMOUNT":    "invalid schedule operation amount",
+		"INVALID_RECURRENCE":         "invalid recurrence rule (allowed: once, daily, weekly, monthly, cron)",
+		"INVALID_CRON_EXPR":          "invalid cron expression (5 fields expected: minute hour day month weekday)",
+		"SCHEDULE_HAS_NO_RUNS":       "the cron expression never fires",
+
+		// Движения и сверка
+		"MOVEMENT_NOT_FOUND":          "movement not found",
+		"MOVEMENT_ALREADY_REVERSED":   "movement has already been reversed",
+		"MOVEMENT_NOT_REVERSIBLE":     "movements of this kind cannot be reversed (allowed: deposit, withdrawal, conversion)",
+		"MOVEMENT_PART_OF_CONVERSION": "the movement is part of a conversion and is reversed only together with it: reverse the conversion debit movement",
+		"RECONCILIATION_NOT_FOUND":    "reconciliation not found",
+
+		// Комиссии
+		"FEE_RULE_NOT_FOUND": "fee rule not found",
+		"INVALID_PAIR":       "invalid currency pair (expected BASE/QUOTE, e.g. USD/RUB, or *)",
+		"INVALID_FEE_RULE":   "invalid fee rule: percent must be between 0 and 100, amounts non-negative, min_fee not greater than max_fee",
+
+		// Проценты
+		"INTEREST_PLAN_NOT_FOUND":   "interest plan not found",
+		"INTEREST_PLAN_EXISTS":      "an interest plan with this name already exists",
+		"INVALID_INTEREST_PLAN":     "invalid interest plan: a name up to 64 characters and an annual rate from 0 to 100 are required",
+		"INVALID_DAY_COUNT":         "invalid day count convention (allowed: ACT/365, ACT/360, ACT/ACT, 30/360)",
+		"INTEREST_DAY_NOT_CLOSED":   "interest can only be accrued for a day that has ended",
+		"INTEREST_MONTH_NOT_CLOSED": "interest can only be posted for a month that has ended",
+
+		// Сообщения об успехе
+		"WALLET_CREATED":       "Wallet created successfully",
+		"BALANCE_DEPOSITED":    "Balance topped up successfully",
+		"BALANCE_WITHDRAWN":    "Withdrawal completed successfully",
+		"CONVERSION_COMPLETED": "Conversion and withdrawal completed successfully",
+		"TRANSFER_COMPLETED":   "Transfer completed successfully",
+		"MOVEMENT_REVERSED":    "Movement reversed successfully",
+	},
+	Kazakh: {
+		// Общие
+		"INTERNAL_ERROR":  "Сервердің ішкі қатесі",
+		"INVALID_REQUEST": "Сұраныс денесі немесе параметрі дұрыс емес",
+
+		// Доступ
+		"UNAUTHORIZED":         "жарамды API кілті қажет",
+		"INSUFFICIENT_SCOPE":   "API кілтінде қажетті құқық жоқ",
+		"API_KEY_NOT_FOUND":    "API кілті табылмады",
+		"INVALID_API_KEY_NAME": "API кілтінің атауы 1-ден 64 таңбаға дейін болуы керек",
+		"INVALID_SCOPE":        "API кілтінің белгісіз құқығы",
+		"RATE_LIMITED":         "сұраныстар тым көп, кейінірек қайталаңыз",
+
+		// Курсы валют
+		"RATE_NOT_POSITIVE":  "валюта бағамы оң сан болуы керек",
+		"NO_RATES":           "жүйеде тіркелген валюта бағамдары жоқ",
+		"RATE_NOT_AVAILABLE": "өзекті валюта бағамын алу мүмкін болмады",
+
+		// Кошельки
+		"WALLET_NOT_FOUND":                 "әмиян табылмады",
+		"WALLET_EXISTS":                    "мұндай нөмірлі әмиян бұрыннан бар",
+		"INSUFFICIENT_FUNDS":               "шотта қаражат жеткіліксіз",
+		"INVALID_WALLET_NUMBER":            "әмиян нөмірінің пішімі дұрыс емес (7 сан қажет)",
+		"INVALID_CHECK_DIGIT":              "әмиян нөмірінің пішімі дұрыс емес (7 сан қажет): бақылау саны қате",
+		"INVALID_DEPOSIT_AMOUNT":           "әмиян ашуға арналған сома оң болуы керек",
+		"WITHDRAW_FROM_NONEXISTENT_WALLET": "жоқ әмияннан қаражат шешу мүмкін емес",
+		"INVALID_TRANSFER_AMOUNT":          "аударым сомасы оң болуы керек",
+		"SAME_WALLET_TRANSFER":             "қаражатты сол әмиянның өзіне аудару мүмкін емес",
+		"TARGET_WALLET_NOT_FOUND":          "алушының әмияны табылмады",
+		"INVALID_CONVERT_AMOUNT":           "айырбастау сомасы оң болуы керек",
+		"SAME_WALLET_CONVERSION":           "айырбастау алушысының әмияны көз әмияннан өзгеше болуы керек",
+		"DESTINATION_OWNER_MISMATCH":       "әмиян көрсетілген пайдаланушыға тиесілі емес: алушы әмиян басқа иесіне тиесілі",
+		"INVALID_PAGE_SIZE":                "бет өлшемі дұрыс емес",
+		"INVALID_CURSOR":                   "бет курсоры дұрыс емес",
+		"INVALID_SORT":                     "сұрыптау дұрыс емес (рұқсат етілген: created_at, balance, wallet_number; реті asc немесе desc)",
+		"INVALID_WALLET_STATUS":            "әмиян күйі дұрыс емес (рұқсат етілген: active, frozen, closed)",
+		"INVALID_LIST_FILTER":              "сүзгі дұрыс емес: төменгі шек жоғарғы шектен үлкен",
+		"AS_OF_IN_FUTURE":                  "as_of сәті болашақта болмауы керек",
+		"AS_OF_BEFORE_CREATION":            "as_of сәтінде әмиян әлі болмаған",
+		"WALLET_NUMBER_SPACE_EXHAUSTED":    "бос әмиян нөмірін бөлу мүмкін болмады, қайталап көріңіз",
+		"VERSION_MISMATCH":                 "әмиянды басқа сұраныс өзгертті, өзекті нұсқасын алып, қайталаңыз",
+		"INVALID_CREDIT_LIMIT":             "несие лимиті теріс емес сан болуы керек",
+		"CREDIT_LIMIT_IN_USE":              "несие лимиті пайдаланылған несиеден аз болмауы керек",
+		"WALLET_FROZEN":                    "әмиян бұғатталған",
+		"WALLET_CLOSED":                    "әмиян жабылған",
+		"WALLET_NOT_EMPTY":                 "тек нөлдік балансы бар және белсенді холдтары жоқ әмиянды жабуға болады",
+
+		// Пользователи
+		"USER_NOT_FOUND":        "пайдаланушы табылмады",
+		"INVALID_USER_ID":       "пайдаланушы ID пішімі дұрыс емес (UUID қажет)",
+		"INVALID_USER_NAME":     "пайдаланушының аты мен тегі міндетті",
+		"WALLET_OWNER_MISMATCH": "әмиян көрсетілген пайдаланушыға тиесілі емес",
+		"WALLET_OWNER_REQUIRED": "әмиян көрсетілген пайдаланушыға тиесілі емес: әмияннан шешу үшін иесінің user_id көрсетілуі керек",
+
+		// Пакетные операции
+		"EMPTY_BATCH":             "топтамада операциялар жоқ",
+		"BATCH_TOO_LARGE":         "топтамада операциялар тым көп",
+		"INVALID_BATCH_MODE":      "топтама режимі дұрыс емес (рұқсат етілген: atomic, best_effort)",
+		"INVALID_BATCH_OPERATION": "операция түрі дұрыс емес (рұқсат етілген: deposit, withdrawal, transfer)",
+		"INVALID_BATCH_AMOUNT":    "операция сомасы оң болуы керек",
+
+		// Холды
+		"HOLD_NOT_FOUND":           "холд табылмады",
+		"HOLD_NOT_ACTIVE":          "холд бұрын шешілген, жойылған немесе мерзімі өткен",
+		"INVALID_HOLD_AMOUNT":      "холд сомасы оң болуы керек",
+		"INVALID_HOLD_TTL":         "холдтың әрекет ету мерзімі оң болуы керек",
+		"CAPTURE_EXCEEDS_HOLD":     "шешу сомасы резервтелген сомадан асады",
+		"DUPLICATE_HOLD_REFERENCE": "бұл әмиянда мұндай reference бар холд бұрыннан бар",
+
+		// Лимиты
+		"LIMIT_EXCEEDED": "әмиян бойынша операциялар лимиті асып кетті",
+		"INVALID_TIER":   "деңгей атауы дұрыс емес (латын әріптері, сандар, '_' және '-', 32 таңбаға дейін)",
+		"INVALID_LIMIT":  "лимит мәні теріс болмауы керек",
+
+		// Расписания
+		"SCHEDULE_NOT_FOUND":         "кесте табылмады",
+		"SCHEDULE_NOT_ACTIVE":        "кесте бұрын орындалған немесе өшірілген",
+		"INVALID_SCHEDULE_OPERATION": "кесте операциясы дұрыс емес (рұқсат етілген: balance_update, transfer, convert)",
+		"INVALID_SCHEDULE_AMOUNT":    "кесте операциясының сомасы дұрыс емес",
+		"INVALID_RECURRENCE":         "қайталану ережесі дұрыс емес (рұқсат етілген: once, daily, weekly, monthly, cron)",
+		"INVALID_CRON_EXPR":          "cron өрнегі дұрыс емес (5 өріс күтіледі: минут сағат күн ай апта_күні)",
+		"SCHEDULE_HAS_NO_RUNS":       "cron өрнегі бойынша бірде-бір іске қосу табылмады",
+
+		// Движения и сверка
+		"MOVEMENT_NOT_FOUND":          "қозғалыс табылмады",
+		"MOVEMENT_ALREADY_REVERSED":   "қозғалыс бұрын кері қайтарылған",
+		"MOVEMENT_NOT_REVERSIBLE":     "мұндай түрдегі қозғалысты кері қайтару мүмкін емес (рұқсат етілген: deposit, withdrawal, conversion)",
+		"MOVEMENT_PART_OF_CONVERSION": "қозғалыс конвертацияның бөлігі және тек онымен бірге кері қайтарылады: конвертацияның есептен шығару қозғалысын кері қайтарыңыз",
+		"RECONCILIATION_NOT_FOUND":    "салыстыру табылмады",
+
+		// Комиссии
+		"FEE_RULE_NOT_FOUND": "комиссия ережесі табылмады",
+		"INVALID_PAIR":       "валюта жұбы дұрыс емес (BASE/QUOTE күтіледі, мысалы USD/RUB, немесе *)",
+		"INVALID_FEE_RULE":   "комиссия ережесі дұрыс емес: пайыз 0-ден 100-ге дейін, сомалар теріс емес, min_fee max_fee-ден аспауы керек",
+
+		// Проценты
+		"INTEREST_PLAN_NOT_FOUND":   "пайыздық жоспар табылмады",
+		"INTEREST_PLAN_EXISTS":      "мұндай атаулы пайыздық жоспар бұрыннан бар",
+		"INVALID_INTEREST_PLAN":     "пайыздық жоспар дұрыс емес: 64 таңбаға дейінгі атау және 0-ден 100-ге дейінгі жылдық мөлшерлеме қажет",
+		"INVALID_DAY_COUNT":         "күндерді есептеу әдісі дұрыс емес (рұқсат етілген: ACT/365, ACT/360, ACT/ACT, 30/360)",
+		"INTEREST_DAY_NOT_CLOSED":   "пайыздарды тек аяқталған күн үшін есептеуге болады",
+		"INTEREST_MONTH_NOT_CLOSED": "пайыздарды тек аяқталған ай үшін есепке алуға болады",
+
+		// Сообщения об успехе
+		"WALLET_CREATED":       "Әмиян сәтті ашылды",
+		"BALANCE_DEPOSITED":    "Баланс сәтті толықтырылды",
+		"BALANCE_WITHDRAWN":    "Қаражат сәтті шешілді",
+		"CONVERSION_COMPLETED": "Айырбастау және шешу сәтті орындалды",
+		"TRANSFER_COMPLETED":   "Аударым сәтті орындалды",
+		"MOVEMENT_REVERSED":    "Қозғалыс сәтті кері қайтарылды",
+	},
+}
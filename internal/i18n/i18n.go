@@ -0,0 +1,82 @@
+// internal/i18n/i18n.go
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang - язык сообщений API.
+type Lang string
+
+// Поддерживаемые языки
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+	Kazakh  Lang = "kk"
+)
+
+// Default - язык, на котором написаны исходные тексты сообщений в коде. Используется, если клиент
+// не указал язык или ни один из указанных не поддерживается.
+const Default = Russian
+
+// FromAcceptLanguage выбирает язык по заголовку Accept-Language (RFC 9110): поддерживаемый язык
+// с наибольшим весом q, при равных весах - указанный раньше. Региональные варианты (en-US) сводятся к языку.
+func FromAcceptLanguage(header string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		lang := Lang(base)
+		if base == "*" {
+			lang = Default
+		}
+		if !Supported(lang) {
+			continue
+		}
+		candidates = append(candidates, candidate{lang: lang, q: q})
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// Supported сообщает, есть ли сообщения на языке lang.
+func Supported(lang Lang) bool {
+	if lang == Default {
+		return true
+	}
+	_, ok := catalog[lang]
+	return ok
+}
+
+// Message возвращает сообщение с кодом code на языке lang. source - исходный текст сообщения (на языке Default):
+// он возвращается для языка по умолчанию, так как может быть подробнее шаблона каталога, а также если
+// перевода для кода нет.
+func Message(lang Lang, code, source string) string {
+	if lang == Default {
+		return source
+	}
+	if text, ok := catalog[lang][code]; ok {
+		return text
+	}
+	return source
+}
@@ -0,0 +1,36 @@
+// internal/jobs/runner.go
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Task - функция фоновой задачи, выполняемая на каждом тике.
+type Task func(ctx context.Context) error
+
+// RunPeriodic выполняет task каждые interval, пока не будет отменен ctx.
+// Ошибки задачи логируются и не прерывают цикл. Блокирует вызывающую горутину.
+func RunPeriodic(ctx context.Context, name string, interval time.Duration, task Task) {
+	if interval <= 0 {
+		log.Printf("Фоновая задача %s отключена (интервал %v)", name, interval)
+		return
+	}
+
+	log.Printf("Фоновая задача %s запущена с интервалом %v", name, interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Фоновая задача %s остановлена", name)
+			return
+		case <-ticker.C:
+			if err := task(ctx); err != nil {
+				log.Printf("Ошибка фоновой задачи %s: %v", name, err)
+			}
+		}
+	}
+}
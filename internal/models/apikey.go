@@ -0,0 +1,42 @@
+// internal/models/apikey.go
+package models
+
+import "time"
+
+// Права (scopes) API-ключей
+const (
+	ScopeRatesWrite   = "rates:write"   // Публикация курсов
+	ScopeWalletsRead  = "wallets:read"  // Чтение кошельков, пользователей, холдов, расписаний и движений
+	ScopeWalletsWrite = "wallets:write" // Операции с кошельками и связанными с ними объектами
+	ScopeAdmin        = "admin"         // Административные эндпоинты (/admin/...), в том числе управление ключами
+)
+
+// APIKeyScopes - все права, которые можно выдать ключу.
+var APIKeyScopes = []string{ScopeRatesWrite, ScopeWalletsRead, ScopeWalletsWrite, ScopeAdmin}
+
+// APIKey описывает API-ключ. Сам ключ не хранится - только его хэш, поэтому показать его повторно нельзя.
+type APIKey struct {
+	ID        int64      `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`                       // Назначение ключа (например, имя сервиса-клиента)
+	Prefix    string     `json:"prefix" db:"prefix"`                   // Начало ключа, чтобы его можно было опознать
+	Scopes    []string   `json:"scopes" db:"scopes"`                   // Права ключа
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`           // Время выпуска
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"` // Время отзыва (nil - ключ действует)
+}
+
+// IssueAPIKeyRequest представляет запрос на выпуск API-ключа.
+type IssueAPIKeyRequest struct {
+	Name   string   `json:"name" example:"billing-service"`
+	Scopes []string `json:"scopes" example:"wallets:read,wallets:write"`
+}
+
+// IssueAPIKeyResponse представляет выпущенный ключ. Поле key возвращается только один раз.
+type IssueAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key" example:"csk_Xb3kq9ZtWm1Ua7cR2vLp0sYdEf4gHi5jKl6nOo8pQr9"`
+}
+
+// ListAPIKeysResponse представляет список API-ключей.
+type ListAPIKeysResponse struct {
+	Keys []APIKey `json:"keys"`
+}
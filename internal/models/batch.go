@@ -0,0 +1,61 @@
+// internal/models/batch.go
+package models
+
+// Типы операций пакета
+const (
+	BatchOperationDeposit    = "deposit"
+	BatchOperationWithdrawal = "withdrawal"
+	BatchOperationTransfer   = "transfer"
+)
+
+// Режимы выполнения пакета
+const (
+	BatchModeAtomic     = "atomic"      // Все операции выполняются или ни одна (по умолчанию)
+	BatchModeBestEffort = "best_effort" // Каждая операция фиксируется отдельно, ошибки не отменяют остальные
+)
+
+// Статусы операций пакета
+const (
+	BatchItemSucceeded  = "succeeded"   // Операция выполнена (и зафиксирована вместе с пакетом)
+	BatchItemFailed     = "failed"      // Операция не выполнена, причина в error
+	BatchItemRolledBack = "rolled_back" // Операция выполнилась, но отменена из-за ошибки другой операции (atomic)
+	BatchItemSkipped    = "skipped"     // Операция не выполнялась, так как пакет уже отменен (atomic)
+)
+
+// BatchOperation представляет одну операцию пакета.
+type BatchOperation struct {
+	Type               string  `json:"type"`                           // deposit, withdrawal или transfer
+	WalletNumber       string  `json:"wallet_number"`                  // Кошелек для пополнения/списания или источник перевода
+	TargetWalletNumber string  `json:"target_wallet_number,omitempty"` // Получатель (только для transfer)
+	Amount             float64 `json:"amount"`                         // Положительная сумма
+}
+
+// BatchRequest представляет тело запроса POST /wallets/batch.
+type BatchRequest struct {
+	Mode       string           `json:"mode,omitempty"`    // atomic (по умолчанию) или best_effort
+	UserID     string           `json:"user_id,omitempty"` // Должен совпадать с владельцем кошельков, с которых списываются средства
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperationResult представляет результат одной операции пакета.
+type BatchOperationResult struct {
+	Index              int     `json:"index"` // Позиция операции в запросе (с 0)
+	Type               string  `json:"type"`
+	WalletNumber       string  `json:"wallet_number"`
+	TargetWalletNumber string  `json:"target_wallet_number,omitempty"`
+	Amount             float64 `json:"amount"`
+	Status             string  `json:"status"`                   // succeeded, failed, rolled_back или skipped
+	Balance            float64 `json:"balance,omitempty"`        // Баланс кошелька после операции
+	TargetBalance      float64 `json:"target_balance,omitempty"` // Баланс получателя после перевода
+	Error              string  `json:"error,omitempty"`          // Причина неудачи (для failed)
+	Code               string  `json:"code,omitempty"`           // Код ошибки (для failed)
+}
+
+// BatchResponse представляет результат выполнения пакета.
+type BatchResponse struct {
+	Mode      string                 `json:"mode"`
+	Committed bool                   `json:"committed"` // Зафиксированы ли изменения (в atomic - все или ничего)
+	Succeeded int                    `json:"succeeded"` // Количество зафиксированных операций
+	Failed    int                    `json:"failed"`    // Количество операций со статусом failed
+	Results   []BatchOperationResult `json:"results"`
+}
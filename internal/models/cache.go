@@ -0,0 +1,10 @@
+// internal/models/cache.go
+package models
+
+import "time"
+
+// DataVersion описывает состояние набора данных для HTTP-кэширования (ETag и Last-Modified).
+type DataVersion struct {
+	Tag          string    // Меняется при любом изменении данных
+	LastModified time.Time // Время последнего изменения (нулевое, если данных нет)
+}
@@ -3,10 +3,23 @@ package models
 
 // ErrorResponse стандартная структура для ответа об ошибке API.
 type ErrorResponse struct {
-	Error string `json:"error" example:"Сообщение об ошибке"` // Содержит текст ошибки для клиента
+	Error     string         `json:"error" example:"Сообщение об ошибке"`            // Содержит текст ошибки для клиента
+	Code      string         `json:"code" example:"INSUFFICIENT_FUNDS"`              // Стабильный код ошибки (см. internal/apperrors)
+	Details   map[string]any `json:"details,omitempty"`                              // Дополнительные сведения (зависят от кода)
+	RequestID string         `json:"request_id,omitempty" example:"host/abc-000001"` // ID запроса для поиска в логах
 }
 
 // SuccessResponse стандартная структура для простого успешного ответа.
 type SuccessResponse struct {
 	Message string `json:"message" example:"Операция выполнена успешно"` // Сообщение об успехе
 }
+
+// Коды сообщений об успешном выполнении операций - ключи каталога переводов (internal/i18n) для полей message.
+const (
+	MessageWalletCreated       = "WALLET_CREATED"
+	MessageBalanceDeposited    = "BALANCE_DEPOSITED"
+	MessageBalanceWithdrawn    = "BALANCE_WITHDRAWN"
+	MessageConversionCompleted = "CONVERSION_COMPLETED"
+	MessageTransferCompleted   = "TRANSFER_COMPLETED"
+	MessageMovementReversed    = "MOVEMENT_REVERSED"
+)
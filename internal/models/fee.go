@@ -0,0 +1,26 @@
+// internal/models/fee.go
+package models
+
+import "time"
+
+// FeeRuleWildcard - значение пары или уровня в правиле комиссии, подходящее для любой пары или уровня.
+const FeeRuleWildcard = "*"
+
+// FeeRule описывает комиссию за конвертацию: процент от суммы плюс фиксированная часть,
+// ограниченные снизу и сверху. Из подходящих правил применяется самое точное:
+// сначала по паре, затем по уровню кошелька.
+type FeeRule struct {
+	ID        int64     `json:"id" db:"id"`
+	Pair      string    `json:"pair" db:"pair"`                       // Валютная пара (например, USD/RUB) или * для любой
+	Tier      string    `json:"tier" db:"tier"`                       // Уровень кошелька или * для любого
+	Percent   float64   `json:"percent" db:"percent"`                 // Процент от суммы списания по курсу
+	Fixed     float64   `json:"fixed" db:"fixed"`                     // Фиксированная часть комиссии
+	MinFee    *float64  `json:"min_fee,omitempty" db:"min_fee"`       // Минимальная комиссия (nil - без ограничения)
+	MaxFee    *float64  `json:"max_fee,omitempty" db:"max_fee"`       // Максимальная комиссия (nil - без ограничения)
+	UpdatedAt time.Time `json:"updated_at,omitempty" db:"updated_at"` // Время последнего изменения
+}
+
+// ListFeeRulesResponse представляет список правил комиссий.
+type ListFeeRulesResponse struct {
+	Rules []FeeRule `json:"rules"`
+}
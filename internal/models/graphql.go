@@ -0,0 +1,22 @@
+// internal/models/graphql.go
+package models
+
+// GraphQLRequest представляет запрос GraphQL.
+type GraphQLRequest struct {
+	Query         string         `json:"query" example:"{ latestRate { value timestamp } wallet(number: \"1234566\") { balance movements(limit: 5) { kind amount } } }"`
+	Variables     map[string]any `json:"variables,omitempty"`     // Значения переменных запроса
+	OperationName string         `json:"operationName,omitempty"` // Какую операцию выполнить, если в запросе их несколько
+}
+
+// GraphQLError представляет ошибку выполнения запроса GraphQL.
+type GraphQLError struct {
+	Message    string         `json:"message"`              // Текст ошибки на языке запроса
+	Path       []any          `json:"path,omitempty"`       // Путь к полю, при разрешении которого произошла ошибка
+	Extensions map[string]any `json:"extensions,omitempty"` // code - стабильный код ошибки, details - дополнительные сведения
+}
+
+// GraphQLResponse представляет ответ на запрос GraphQL.
+type GraphQLResponse struct {
+	Data   any            `json:"data,omitempty"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
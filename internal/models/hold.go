@@ -0,0 +1,38 @@
+// internal/models/hold.go
+package models
+
+import "time"
+
+// Статусы холда (резервирования средств)
+const (
+	HoldStatusActive   = "active"   // Средства зарезервированы
+	HoldStatusCaptured = "captured" // Средства списаны (полностью или частично), остаток освобожден
+	HoldStatusVoided   = "voided"   // Холд отменен, средства освобождены
+	HoldStatusExpired  = "expired"  // Срок холда истек, средства освобождены фоновой задачей
+)
+
+// Hold представляет резервирование средств на кошельке.
+// Активный холд уменьшает доступный баланс, но не учетный баланс кошелька.
+type Hold struct {
+	ID             int64     `json:"id" db:"id"`
+	WalletNumber   string    `json:"wallet_number" db:"wallet_number"`
+	Amount         float64   `json:"amount" db:"amount"`                   // Зарезервированная сумма
+	CapturedAmount float64   `json:"captured_amount" db:"captured_amount"` // Фактически списанная сумма (после capture)
+	Reference      string    `json:"reference,omitempty" db:"reference"`   // Внешний идентификатор операции (например, ID авторизации карты)
+	Status         string    `json:"status" db:"status"`
+	ExpiresAt      time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateHoldRequest представляет тело запроса на резервирование средств.
+type CreateHoldRequest struct {
+	Amount           float64 `json:"amount"`
+	Reference        string  `json:"reference,omitempty"`
+	ExpiresInSeconds int     `json:"expires_in_seconds,omitempty"` // Если не указано, используется срок по умолчанию из конфигурации
+}
+
+// CaptureHoldRequest представляет тело запроса на списание зарезервированных средств.
+type CaptureHoldRequest struct {
+	Amount float64 `json:"amount,omitempty"` // Если не указано - списывается вся зарезервированная сумма
+}
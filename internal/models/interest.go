@@ -0,0 +1,75 @@
+// internal/models/interest.go
+package models
+
+import "time"
+
+// Конвенции подсчета дней для начисления процентов
+const (
+	DayCountActual365 = "ACT/365" // Каждый день - 1/365 года
+	DayCountActual360 = "ACT/360" // Каждый день - 1/360 года
+	DayCountActualAct = "ACT/ACT" // Каждый день - 1/365 или 1/366 года в зависимости от високосности
+	DayCount30360     = "30/360"  // Месяц считается за 30 дней, год - за 360 (31-е число не начисляется, конец февраля добирает до 30)
+)
+
+// InterestPlan описывает процентный план: годовую ставку и конвенцию подсчета дней.
+// План не изменяется после создания; чтобы изменить ставку, кошельку назначают новый план.
+type InterestPlan struct {
+	ID         int64     `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	AnnualRate float64   `json:"annual_rate" db:"annual_rate"` // Годовая ставка в процентах (5 - это 5% годовых)
+	DayCount   string    `json:"day_count" db:"day_count"`     // ACT/365, ACT/360, ACT/ACT или 30/360
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateInterestPlanRequest представляет тело запроса на создание процентного плана.
+type CreateInterestPlanRequest struct {
+	Name       string  `json:"name"`
+	AnnualRate float64 `json:"annual_rate"`         // Годовая ставка в процентах
+	DayCount   string  `json:"day_count,omitempty"` // По умолчанию ACT/365
+}
+
+// ListInterestPlansResponse представляет список процентных планов.
+type ListInterestPlansResponse struct {
+	Plans []InterestPlan `json:"plans"`
+}
+
+// InterestAccrual - проценты, начисленные кошельку за один день на баланс на конец этого дня.
+// Начисления накапливаются и раз в месяц зачисляются на кошелек одним движением.
+type InterestAccrual struct {
+	WalletNumber string     `json:"wallet_number" db:"wallet_number"`
+	AccrualDate  time.Time  `json:"accrual_date" db:"accrual_date"` // День начисления (UTC)
+	PlanID       int64      `json:"plan_id" db:"plan_id"`
+	Balance      float64    `json:"balance" db:"balance"`         // Баланс на конец дня
+	AnnualRate   float64    `json:"annual_rate" db:"annual_rate"` // Ставка плана на момент начисления
+	DayCount     string     `json:"day_count" db:"day_count"`
+	Amount       float64    `json:"amount" db:"amount"`                     // Начислено за день (без округления)
+	PostedAt     *time.Time `json:"posted_at,omitempty" db:"posted_at"`     // Когда начисление зачислено на кошелек
+	MovementID   *int64     `json:"movement_id,omitempty" db:"movement_id"` // Движение, которым зачислены проценты
+}
+
+// SetWalletInterestPlanRequest представляет тело запроса на назначение процентного плана кошельку.
+type SetWalletInterestPlanRequest struct {
+	PlanID *int64 `json:"plan_id"` // null - отключить начисление процентов
+}
+
+// WalletInterestResponse представляет процентный план кошелька и его начисления.
+type WalletInterestResponse struct {
+	WalletNumber    string            `json:"wallet_number"`
+	Plan            *InterestPlan     `json:"plan,omitempty"`   // Нет, если проценты не начисляются
+	AccruedUnposted float64           `json:"accrued_unposted"` // Начислено, но еще не зачислено на кошелек
+	Accruals        []InterestAccrual `json:"accruals"`         // Последние начисления (новые первыми)
+}
+
+// InterestAccrualRunResponse представляет результат начисления процентов за день.
+type InterestAccrualRunResponse struct {
+	Date           string  `json:"date"`            // День начисления (YYYY-MM-DD)
+	WalletsAccrued int     `json:"wallets_accrued"` // Скольким кошелькам начислено (повторный запуск за тот же день дает 0)
+	TotalAccrued   float64 `json:"total_accrued"`
+}
+
+// InterestPostingResponse представляет результат зачисления начисленных процентов на кошельки.
+type InterestPostingResponse struct {
+	Month         string  `json:"month"`          // Зачислены начисления по этот месяц включительно (YYYY-MM)
+	WalletsPosted int     `json:"wallets_posted"` // Сколько кошельков получили движение interest
+	TotalPosted   float64 `json:"total_posted"`
+}
@@ -0,0 +1,54 @@
+// internal/models/limits.go
+package models
+
+import "time"
+
+// Названия лимитов (возвращаются клиенту при превышении)
+const (
+	LimitMaxSingleWithdrawal    = "max_single_withdrawal"
+	LimitDailyWithdrawalTotal   = "daily_withdrawal_total"
+	LimitMonthlyWithdrawalTotal = "monthly_withdrawal_total"
+	LimitDailyConversionCount   = "daily_conversion_count"
+)
+
+// DefaultWalletTier - уровень, который получают новые кошельки.
+const DefaultWalletTier = "standard"
+
+// Limits описывает лимиты на списания. nil означает, что лимит не установлен.
+type Limits struct {
+	MaxSingleWithdrawal    *float64 `json:"max_single_withdrawal,omitempty"`    // Максимальная сумма одного списания
+	DailyWithdrawalTotal   *float64 `json:"daily_withdrawal_total,omitempty"`   // Максимальная сумма списаний за сутки (UTC)
+	MonthlyWithdrawalTotal *float64 `json:"monthly_withdrawal_total,omitempty"` // Максимальная сумма списаний за календарный месяц (UTC)
+	DailyConversionCount   *int     `json:"daily_conversion_count,omitempty"`   // Максимальное количество конвертаций за сутки (UTC)
+}
+
+// TierLimitsResponse представляет лимиты уровня кошельков.
+type TierLimitsResponse struct {
+	Tier   string `json:"tier"`
+	Limits Limits `json:"limits"`
+}
+
+// WalletLimitsResponse представляет лимиты кошелька: лимиты уровня, индивидуальные и итоговые.
+type WalletLimitsResponse struct {
+	WalletNumber string `json:"wallet_number"`
+	Tier         string `json:"tier"`
+	TierLimits   Limits `json:"tier_limits"`
+	Overrides    Limits `json:"overrides"` // Индивидуальные лимиты кошелька, имеют приоритет над лимитами уровня
+	Effective    Limits `json:"effective"` // Лимиты, которые фактически применяются
+}
+
+// UpdateWalletLimitsRequest представляет тело запроса на изменение лимитов кошелька.
+type UpdateWalletLimitsRequest struct {
+	Tier      string `json:"tier,omitempty"` // Новый уровень кошелька (если не указан - не меняется)
+	Overrides Limits `json:"overrides"`      // Индивидуальные лимиты (полностью заменяют прежние)
+}
+
+// LimitExceededResponse представляет ответ при превышении лимита.
+// Поля лимита дублируют details и оставлены для совместимости.
+type LimitExceededResponse struct {
+	ErrorResponse
+	Limit      string     `json:"limit" example:"daily_withdrawal_total"` // Какой лимит превышен
+	LimitValue float64    `json:"limit_value"`                            // Значение лимита
+	Attempted  float64    `json:"attempted"`                              // Значение с учетом отклоненной операции
+	ResetsAt   *time.Time `json:"resets_at,omitempty"`                    // Когда лимит обнулится (нет для лимита одного списания)
+}
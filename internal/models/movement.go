@@ -0,0 +1,48 @@
+// internal/models/movement.go
+package models
+
+import "time"
+
+// Виды движений по кошельку
+const (
+	MovementKindOpeningBalance = "opening_balance" // Начальный баланс кошельков, созданных до ведения истории движений
+	MovementKindDeposit        = "deposit"         // Пополнение (в том числе при создании кошелька)
+	MovementKindWithdrawal     = "withdrawal"      // Списание через обновление баланса
+	MovementKindConversion     = "conversion"      // Списание при конвертации
+	MovementKindConversionIn   = "conversion_in"   // Зачисление сконвертированной суммы на кошелек-получатель
+	MovementKindHoldCapture    = "hold_capture"    // Списание зарезервированных средств
+	MovementKindTransferOut    = "transfer_out"    // Списание при переводе на другой кошелек
+	MovementKindTransferIn     = "transfer_in"     // Зачисление перевода с другого кошелька
+	MovementKindReversal       = "reversal"        // Отмена другого движения (компенсирующее движение)
+	MovementKindConversionFee  = "conversion_fee"  // Списание комиссии за конвертацию
+	MovementKindFeeIncome      = "fee_income"      // Зачисление комиссии на кошелек комиссий
+	MovementKindInterest       = "interest"        // Зачисление начисленных за месяц процентов
+)
+
+// Movement представляет одно изменение баланса кошелька.
+// Сумма со знаком: положительная - зачисление, отрицательная - списание.
+type Movement struct {
+	ID           int64     `json:"id" db:"id"`
+	WalletNumber string    `json:"wallet_number" db:"wallet_number"`
+	Kind         string    `json:"kind" db:"kind"`
+	Amount       float64   `json:"amount" db:"amount"`
+	BalanceAfter float64   `json:"balance_after" db:"balance_after"`           // Учетный баланс кошелька после движения
+	Rate         *float64  `json:"rate,omitempty" db:"rate"`                   // Курс конвертации (только для conversion, conversion_in и их отмены)
+	SourceAmount *float64  `json:"source_amount,omitempty" db:"source_amount"` // Сумма до конвертации (только для conversion, conversion_in и их отмены)
+	ReversalOf   *int64    `json:"reversal_of,omitempty" db:"reversal_of"`     // ID отмененного движения (только для reversal)
+	ConversionID *int64    `json:"conversion_id,omitempty" db:"conversion_id"` // ID списания конвертации, частью которой является движение (conversion_in, conversion_fee, fee_income)
+	ReversedBy   *int64    `json:"reversed_by,omitempty" db:"-"`               // ID движения, которым отменено это движение
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// ReversalResponse представляет результат отмены движения.
+type ReversalResponse struct {
+	Original   Movement `json:"original"`    // Отмененное движение
+	Reversal   Movement `json:"reversal"`    // Компенсирующее движение
+	NewBalance float64  `json:"new_balance"` // Учетный баланс кошелька после отмены
+	// Отмены остальных движений конвертации (зачисление получателю, комиссия), выполненные вместе с отменой списания
+	LinkedReversals []Movement `json:"linked_reversals,omitempty"`
+	Message         string     `json:"message"`
+	Code            string     `json:"code,omitempty"` // Код ошибки (при 409)
+	MessageCode     string     `json:"-"`              // Код сообщения об успехе для перевода message
+}
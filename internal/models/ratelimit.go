@@ -0,0 +1,11 @@
+// internal/models/ratelimit.go
+package models
+
+import "time"
+
+// RateLimitBucket - ведро токенов ограничения частоты запросов, общее для реплик сервиса.
+type RateLimitBucket struct {
+	Key       string    `json:"key" db:"key"`               // Клиент и маршрут, к которым относится квота
+	Tokens    float64   `json:"tokens" db:"tokens"`         // Оставшиеся токены на момент UpdatedAt
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"` // Время последнего списания (по часам БД)
+}
@@ -0,0 +1,36 @@
+// internal/models/reconciliation.go
+package models
+
+import "time"
+
+// ReconciliationRun представляет один проход сверки балансов кошельков с историей движений.
+type ReconciliationRun struct {
+	ID                 int64      `json:"id" db:"id"`
+	StartedAt          time.Time  `json:"started_at" db:"started_at"`
+	FinishedAt         *time.Time `json:"finished_at,omitempty" db:"finished_at"`       // Нет у незавершенной сверки
+	WalletsChecked     int        `json:"wallets_checked" db:"wallets_checked"`         // Сколько кошельков проверено
+	DiscrepanciesFound int        `json:"discrepancies_found" db:"discrepancies_found"` // Сколько расхождений найдено
+	Error              string     `json:"error,omitempty" db:"error"`                   // Ошибка, если сверка не завершилась
+}
+
+// Discrepancy представляет расхождение между сохраненным балансом кошелька и суммой его движений.
+type Discrepancy struct {
+	ID              int64     `json:"id" db:"id"`
+	RunID           int64     `json:"run_id" db:"run_id"`
+	WalletNumber    string    `json:"wallet_number" db:"wallet_number"`
+	StoredBalance   float64   `json:"stored_balance" db:"stored_balance"`     // wallets.balance
+	ComputedBalance float64   `json:"computed_balance" db:"computed_balance"` // Сумма движений по кошельку
+	Difference      float64   `json:"difference" db:"difference"`             // stored_balance - computed_balance
+	DetectedAt      time.Time `json:"detected_at" db:"detected_at"`
+}
+
+// ListReconciliationRunsResponse представляет список последних сверок.
+type ListReconciliationRunsResponse struct {
+	Runs []ReconciliationRun `json:"runs"`
+}
+
+// ReconciliationReportResponse представляет результат сверки и найденные расхождения.
+type ReconciliationReportResponse struct {
+	Run           ReconciliationRun `json:"run"`
+	Discrepancies []Discrepancy     `json:"discrepancies"`
+}
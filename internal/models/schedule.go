@@ -0,0 +1,85 @@
+// internal/models/schedule.go
+package models
+
+import "time"
+
+// Операции, которые можно запланировать
+const (
+	ScheduleOperationBalanceUpdate = "balance_update" // Пополнение/списание (как POST /wallets/balance)
+	ScheduleOperationTransfer      = "transfer"       // Перевод на другой кошелек
+	ScheduleOperationConvert       = "convert"        // Конвертация и списание (как POST /wallets/convert)
+)
+
+// Правила повторения расписания
+const (
+	ScheduleRecurrenceOnce    = "once"    // Однократно в start_at
+	ScheduleRecurrenceDaily   = "daily"   // Каждый день во время start_at
+	ScheduleRecurrenceWeekly  = "weekly"  // Каждую неделю в день недели и время start_at
+	ScheduleRecurrenceMonthly = "monthly" // Каждый месяц в число и время start_at (или последний день месяца)
+	ScheduleRecurrenceCron    = "cron"    // По cron-выражению из 5 полей (минута час день месяц день_недели), UTC
+)
+
+// Статусы расписания
+const (
+	ScheduleStatusActive    = "active"    // Ожидает следующего запуска
+	ScheduleStatusCompleted = "completed" // Однократное расписание выполнено
+	ScheduleStatusDisabled  = "disabled"  // Отключено (пользователем или после повторяющихся ошибок)
+)
+
+// Статусы запуска расписания
+const (
+	ScheduleRunSucceeded = "succeeded"
+	ScheduleRunFailed    = "failed"
+)
+
+// Schedule представляет запланированную (в том числе повторяющуюся) операцию с кошельком.
+type Schedule struct {
+	ID                  int64      `json:"id" db:"id"`
+	Operation           string     `json:"operation" db:"operation"`
+	WalletNumber        string     `json:"wallet_number" db:"wallet_number"`                         // Кошелек, с которым выполняется операция (источник перевода)
+	TargetWalletNumber  string     `json:"target_wallet_number,omitempty" db:"target_wallet_number"` // Кошелек получателя (только для transfer)
+	Amount              float64    `json:"amount" db:"amount"`                                       // Сумма операции (для balance_update может быть отрицательной)
+	UserID              string     `json:"user_id,omitempty" db:"user_id"`                           // Пользователь, от имени которого выполняется операция
+	Recurrence          string     `json:"recurrence" db:"recurrence"`                               // once, daily, weekly, monthly или cron
+	CronExpr            string     `json:"cron_expr,omitempty" db:"cron_expr"`                       // Только для recurrence = cron
+	StartAt             time.Time  `json:"start_at" db:"start_at"`                                   // Первый запуск и опорная точка для daily/weekly/monthly
+	NextRunAt           *time.Time `json:"next_run_at,omitempty" db:"next_run_at"`                   // Время следующего запуска (нет у завершенных)
+	LastRunAt           *time.Time `json:"last_run_at,omitempty" db:"last_run_at"`                   // Время последнего запуска
+	Status              string     `json:"status" db:"status"`                                       // active, completed или disabled
+	ConsecutiveFailures int        `json:"consecutive_failures" db:"consecutive_failures"`           // Количество неудачных запусков подряд
+	DisabledReason      string     `json:"disabled_reason,omitempty" db:"disabled_reason"`           // Почему расписание отключено
+	CreatedBy           string     `json:"created_by,omitempty" db:"created_by"`                     // Кто создал расписание: key:<ID> API-ключа или user:<sub> пользователя
+	CreatedByStaff      bool       `json:"created_by_staff" db:"created_by_staff"`                   // Создатель - сотрудник: запуски могут списывать с кошелька владельца без user_id
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateScheduleRequest представляет тело запроса на создание расписания.
+type CreateScheduleRequest struct {
+	Operation          string     `json:"operation"`
+	WalletNumber       string     `json:"wallet_number"`
+	TargetWalletNumber string     `json:"target_wallet_number,omitempty"`
+	Amount             float64    `json:"amount"`
+	UserID             string     `json:"user_id,omitempty"`
+	Recurrence         string     `json:"recurrence"`
+	CronExpr           string     `json:"cron_expr,omitempty"`
+	StartAt            *time.Time `json:"start_at,omitempty"` // По умолчанию - текущее время
+	// CreatedBy и Staff - кто создает расписание и сотрудник ли он (см. UpdateBalanceRequest.Staff).
+	// Сохраняются в расписании: запуски выполняются с правами создателя.
+	CreatedBy string `json:"-"`
+	Staff     bool   `json:"-"`
+}
+
+// ScheduleRun представляет результат одного запуска расписания.
+type ScheduleRun struct {
+	ID         int64     `json:"id" db:"id"`
+	ScheduleID int64     `json:"schedule_id" db:"schedule_id"`
+	Status     string    `json:"status" db:"status"`         // succeeded или failed
+	Error      string    `json:"error,omitempty" db:"error"` // Текст ошибки при неудаче
+	RanAt      time.Time `json:"ran_at" db:"ran_at"`
+}
+
+// ListScheduleRunsResponse представляет историю запусков расписания.
+type ListScheduleRunsResponse struct {
+	Runs []ScheduleRun `json:"runs"`
+}
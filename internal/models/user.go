@@ -0,0 +1,18 @@
+// internal/models/user.go
+package models
+
+import "time"
+
+// User представляет владельца кошельков.
+type User struct {
+	ID        string    `json:"id" db:"id"` // UUID пользователя, генерируется БД
+	FirstName string    `json:"first_name" db:"first_name"`
+	LastName  string    `json:"last_name" db:"last_name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateUserRequest представляет тело запроса на создание пользователя.
+type CreateUserRequest struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
@@ -3,39 +3,136 @@ package models
 
 import "time"
 
+// Статусы кошелька (меняет администратор через PUT /wallets/{number}/status)
+const (
+	WalletStatusActive = "active" // Обычный кошелек
+	WalletStatusFrozen = "frozen" // Заморожен: операции запрещены до разморозки
+	WalletStatusClosed = "closed" // Закрыт окончательно (только с нулевым балансом)
+)
+
 // Wallet представляет кошелек пользователя.
 type Wallet struct {
-	Number    string    `json:"number" db:"wallet_number"` // Номер кошелька (7 знаков)
-	Balance   float64   `json:"balance" db:"balance"`      // Баланс кошелька
-	CreatedAt time.Time `json:"-" db:"created_at"`         // Время создания (не отдаем в JSON)
-	UpdatedAt time.Time `json:"-" db:"updated_at"`         // Время последнего обновления (не отдаем в JSON)
+	Number           string    `json:"number" db:"wallet_number"`        // Номер кошелька (7 знаков)
+	Balance          float64   `json:"balance" db:"balance"`             // Учетный баланс кошелька (включая зарезервированные средства)
+	AvailableBalance float64   `json:"available_balance" db:"-"`         // Доступный баланс: учетный минус активные холды (может быть отрицательным в пределах кредита)
+	CreditLimit      float64   `json:"credit_limit" db:"credit_limit"`   // Разрешенный овердрафт: баланс может опускаться до -credit_limit
+	AvailableCredit  float64   `json:"available_credit" db:"-"`          // Неиспользованная часть кредитного лимита
+	OwnerID          string    `json:"owner_id,omitempty" db:"owner_id"` // ID владельца (пусто у кошельков, созданных без пользователя)
+	Tier             string    `json:"tier" db:"tier"`                   // Уровень кошелька, определяет лимиты по умолчанию
+	Status           string    `json:"status" db:"status"`               // active, frozen или closed
+	Version          int64     `json:"version" db:"version"`             // Увеличивается при каждом изменении кошелька, отдается как ETag
+	CreatedAt        time.Time `json:"-" db:"created_at"`                // Время создания (не отдаем в JSON)
+	UpdatedAt        time.Time `json:"-" db:"updated_at"`                // Время последнего обновления (не отдаем в JSON)
+}
+
+// SetWalletStatusRequest представляет тело запроса на смену статуса кошелька.
+type SetWalletStatusRequest struct {
+	Status string `json:"status" example:"frozen"` // active, frozen или closed
+}
+
+// SetCreditLimitRequest представляет тело запроса на установку кредитного лимита кошелька.
+type SetCreditLimitRequest struct {
+	CreditLimit float64 `json:"credit_limit"` // 0 - без овердрафта
+}
+
+// CreateWalletRequest представляет тело запроса на создание кошелька (POST /wallets).
+// Номер кошелька выделяет сервер.
+type CreateWalletRequest struct {
+	UserID string `json:"user_id,omitempty"` // Владелец кошелька (необязательно)
+	Tier   string `json:"tier,omitempty"`    // Уровень кошелька (по умолчанию standard)
 }
 
 // UpdateBalanceRequest представляет тело запроса на обновление баланса.
 type UpdateBalanceRequest struct {
 	WalletNumber string  `json:"wallet_number"`
-	Amount       float64 `json:"amount"` // Может быть положительным (пополнение) или отрицательным (списание)
+	Amount       float64 `json:"amount"`            // Может быть положительным (пополнение) или отрицательным (списание)
+	UserID       string  `json:"user_id,omitempty"` // Владелец кошелька (необязательно). При создании кошелек привязывается к нему
+	// ExpectedVersion - версия кошелька из заголовка If-Match. Если задана и не совпадает с текущей,
+	// обновление отклоняется, чтобы не затереть параллельное изменение.
+	ExpectedVersion *int64 `json:"-"`
+	// RequireOwner - кошелек должен принадлежать UserID, кошельки без владельца не подходят (запросы клиентов).
+	RequireOwner bool `json:"-"`
+	// Staff - запрос сотрудника (оператора или администратора): списание с кошелька, у которого есть владелец,
+	// допускается без UserID. Остальным для такого списания нужно указать UserID владельца.
+	Staff bool `json:"-"`
 }
 
 // UpdateBalanceResponse представляет ответ после обновления баланса.
 type UpdateBalanceResponse struct {
 	WalletNumber string  `json:"wallet_number"`
 	NewBalance   float64 `json:"new_balance"`
-	Message      string  `json:"message,omitempty"` // Сообщение об успехе или ошибке (например, недостаточно средств)
+	Version      int64   `json:"version,omitempty"`     // Версия кошелька после обновления
+	MovementID   int64   `json:"movement_id,omitempty"` // ID записанного движения (для отмены через POST /movements/{id}/reverse)
+	Message      string  `json:"message,omitempty"`     // Сообщение об успехе или ошибке (например, недостаточно средств)
+	Code         string  `json:"code,omitempty"`        // Код ошибки (при 409 и 412)
+	MessageCode  string  `json:"-"`                     // Код сообщения об успехе (models.Message*) для перевода message
+}
+
+// WalletDetailsResponse представляет ответ GET /wallets/{number}: кошелек с временем создания и обновления
+// и, если запрошен, баланс на момент as_of, восстановленный по истории движений.
+type WalletDetailsResponse struct {
+	Wallet
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	AsOf        *time.Time `json:"as_of,omitempty"`         // Момент, на который рассчитан balance_as_of
+	BalanceAsOf *float64   `json:"balance_as_of,omitempty"` // Учетный баланс на момент as_of
 }
 
 // ListWalletsResponse представляет ответ со списком кошельков.
 type ListWalletsResponse struct {
-	Wallets []Wallet `json:"wallets"`
+	Wallets    []Wallet `json:"wallets"`
+	NextCursor string   `json:"next_cursor,omitempty"` // Курсор следующей страницы (пусто на последней странице)
+}
+
+// Ключи сортировки списка кошельков
+const (
+	WalletSortCreatedAt = "created_at"
+	WalletSortBalance   = "balance"
+	WalletSortNumber    = "wallet_number"
+)
+
+// Направления сортировки
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// ListWalletsFilter - параметры запроса списка кошельков (GET /wallets).
+// Пустые и nil поля означают отсутствие фильтра.
+type ListWalletsFilter struct {
+	Limit       int        // Размер страницы
+	Cursor      string     // Курсор из next_cursor предыдущей страницы
+	MinBalance  *float64   // Баланс не меньше
+	MaxBalance  *float64   // Баланс не больше
+	Status      string     // Статус кошелька
+	CreatedFrom *time.Time // Создан не раньше (включительно)
+	CreatedTo   *time.Time // Создан раньше (не включительно)
+	SortBy      string     // created_at, balance или wallet_number
+	Order       string     // asc или desc
+}
+
+// WalletCursor - позиция последнего кошелька страницы: значение ключа сортировки и номер кошелька.
+// Номер кошелька делает порядок однозначным при равных значениях ключа.
+type WalletCursor struct {
+	SortBy    string    `json:"s"`
+	Order     string    `json:"o"`
+	Balance   float64   `json:"b,omitempty"`
+	CreatedAt time.Time `json:"c,omitempty"`
+	Number    string    `json:"n"`
 }
 
 // ConvertRequest представляет тело запроса на конвертацию.
 type ConvertRequest struct {
-	FirstName          string  `json:"first_name"` // Пока не используется в логике, но есть в запросе
-	LastName           string  `json:"last_name"`  // Пока не используется в логике, но есть в запросе
-	UserID             string  `json:"user_id"`    // Пока не используется в логике, но есть в запросе
+	FirstName          string  `json:"first_name"` // Если указано, должно совпадать с именем владельца кошелька
+	LastName           string  `json:"last_name"`  // Если указано, должно совпадать с фамилией владельца кошелька
+	UserID             string  `json:"user_id"`    // Должен совпадать с владельцем кошелька-источника
 	AmountToConvert    float64 `json:"amount_to_convert"`
 	SourceWalletNumber string  `json:"source_wallet_number"`
+	// Кошелек, на который зачисляется сконвертированная сумма (например, кошелек того же владельца в другой валюте).
+	// Если не указан, сумма только списывается.
+	DestinationWalletNumber string `json:"destination_wallet_number,omitempty"`
+	// RequireOwner - кошелек-источник должен принадлежать UserID, кошельки без владельца не подходят (запросы клиентов).
+	RequireOwner bool `json:"-"`
 }
 
 // ConvertResponse представляет ответ после попытки конвертации.
@@ -44,5 +141,36 @@ type ConvertResponse struct {
 	RemainingBalance   float64 `json:"remaining_balance,omitempty"` // Поле будет заполнено при успехе
 	ConvertedAmount    float64 `json:"converted_amount,omitempty"`  // Поле будет заполнено при успехе
 	RateUsed           float64 `json:"rate_used,omitempty"`         // Поле будет заполнено при успехе
-	Message            string  `json:"message"`                     // Сообщение об успехе или ошибке
+	Fee                float64 `json:"fee"`                         // Комиссия за конвертацию (списывается сверх суммы по курсу)
+	TotalDebited       float64 `json:"total_debited,omitempty"`     // Всего списано: сумма по курсу и комиссия
+	MovementID         int64   `json:"movement_id,omitempty"`       // ID движения списания (для отмены через POST /movements/{id}/reverse)
+	// Поля кошелька-получателя заполняются, если он указан в запросе
+	DestinationWalletNumber string  `json:"destination_wallet_number,omitempty"`
+	DestinationBalance      float64 `json:"destination_balance,omitempty"`     // Баланс кошелька-получателя после зачисления
+	DestinationMovementID   int64   `json:"destination_movement_id,omitempty"` // ID движения зачисления
+	Message                 string  `json:"message"`                           // Сообщение об успехе или ошибке
+	Code                    string  `json:"code,omitempty"`                    // Код ошибки (при 409)
+	MessageCode             string  `json:"-"`                                 // Код сообщения об успехе для перевода message
+}
+
+// TransferRequest представляет тело запроса на перевод между кошельками.
+type TransferRequest struct {
+	FromWalletNumber string  `json:"from_wallet_number"`
+	ToWalletNumber   string  `json:"to_wallet_number"`
+	Amount           float64 `json:"amount"`
+	UserID           string  `json:"user_id,omitempty"` // Должен совпадать с владельцем кошелька-источника (если он есть)
+	// RequireOwner - кошелек-источник должен принадлежать UserID, кошельки без владельца не подходят (запросы клиентов).
+	RequireOwner bool `json:"-"`
+}
+
+// TransferResponse представляет ответ после попытки перевода.
+type TransferResponse struct {
+	FromWalletNumber string  `json:"from_wallet_number"`
+	ToWalletNumber   string  `json:"to_wallet_number"`
+	Amount           float64 `json:"amount"`
+	FromBalance      float64 `json:"from_balance,omitempty"` // Баланс источника после перевода (или текущий при ошибке)
+	ToBalance        float64 `json:"to_balance,omitempty"`   // Баланс получателя после перевода
+	Message          string  `json:"message"`
+	Code             string  `json:"code,omitempty"` // Код ошибки (при 409)
+	MessageCode      string  `json:"-"`              // Код сообщения об успехе для перевода message
 }
@@ -0,0 +1,93 @@
+// internal/ratelimit/limit.go
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit - квота: не больше Requests запросов за Period. Реализуется ведром токенов емкостью Requests,
+// которое пополняется равномерно, так что короткий всплеск до Requests запросов допустим.
+// Нулевой Limit означает отсутствие ограничения.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// IsZero сообщает, что квота не задана.
+func (l Limit) IsZero() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%v", l.Requests, l.Period)
+}
+
+// refillRate - скорость пополнения ведра в токенах в секунду.
+func (l Limit) refillRate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// ParseLimit разбирает квоту вида "N/s", "N/m" или "N/h" (например, "30/m"). Пустая строка и "0" - без ограничения.
+func ParseLimit(spec string) (Limit, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "0" {
+		return Limit{}, nil
+	}
+	count, unit, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("некорректная квота %q (ожидается N/s, N/m или N/h)", spec)
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || requests < 0 {
+		return Limit{}, fmt.Errorf("некорректное число запросов в квоте %q", spec)
+	}
+	periods := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}
+	period, ok := periods[strings.TrimSpace(unit)]
+	if !ok {
+		return Limit{}, fmt.Errorf("некорректная единица времени в квоте %q (ожидается s, m или h)", spec)
+	}
+	return Limit{Requests: requests, Period: period}, nil
+}
+
+// Result - результат попытки выполнить запрос в рамках квоты.
+type Result struct {
+	Allowed    bool
+	Limit      Limit
+	Remaining  int           // Сколько запросов еще можно выполнить сразу
+	Reset      time.Duration // Через сколько ведро полностью восстановится
+	RetryAfter time.Duration // Через сколько появится токен (только если запрос отклонен)
+}
+
+// bucket - состояние ведра токенов на момент UpdatedAt.
+type bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// take пополняет ведро на момент now и забирает из него токен, если он есть.
+func take(b bucket, limit Limit, now time.Time) (bucket, Result) {
+	capacity := float64(limit.Requests)
+	if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+elapsed.Seconds()*limit.refillRate())
+	}
+	b.UpdatedAt = now
+
+	result := Result{Limit: limit}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.Tokens) / limit.refillRate())
+	}
+	result.Remaining = int(math.Floor(b.Tokens))
+	result.Reset = secondsToDuration((capacity - b.Tokens) / limit.refillRate())
+	return b, result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,136 @@
+// internal/ratelimit/limiter.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"currency-service/internal/apperrors"
+)
+
+// ErrRateLimited возвращается, когда клиент исчерпал квоту.
+var ErrRateLimited = apperrors.New(apperrors.CodeRateLimited, "слишком много запросов, повторите позже")
+
+// Store хранит ведра токенов.
+type Store interface {
+	// Take забирает токен из ведра key с квотой limit.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Cleanup удаляет ведра, к которым не обращались дольше idle (они уже полностью восстановились).
+	Cleanup(ctx context.Context, idle time.Duration) error
+}
+
+// Rule - квота для маршрута. Pattern - путь, в котором {параметр} соответствует одному сегменту.
+type Rule struct {
+	Method  string
+	Pattern string
+	Limit   Limit
+}
+
+func (r Rule) matches(method, path string) bool {
+	if r.Method != method {
+		return false
+	}
+	patternSegments := strings.Split(strings.Trim(r.Pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseRules разбирает квоты маршрутов вида "POST /api/v1/wallets/convert=30/m; GET /api/v1/wallets/{number}=120/m".
+func ParseRules(spec string) ([]Rule, error) {
+	var rules []Rule
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		route, limitSpec, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("некорректная квота маршрута %q (ожидается \"МЕТОД /путь=N/m\")", part)
+		}
+		method, pattern, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !strings.HasPrefix(strings.TrimSpace(pattern), "/") {
+			return nil, fmt.Errorf("некорректный маршрут %q (ожидается \"МЕТОД /путь\")", route)
+		}
+		limit, err := ParseLimit(limitSpec)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, Rule{Method: strings.ToUpper(method), Pattern: strings.TrimSpace(pattern), Limit: limit})
+	}
+	return rules, nil
+}
+
+// Limiter выбирает квоту запроса и списывает ее в хранилище. У каждого клиента отдельное ведро
+// для каждого маршрута со своей квотой и одно общее ведро для остальных маршрутов.
+// Кроме того, у каждого IP-адреса есть ведро на все запросы, которое расходуется до проверки ключа (AllowIP).
+type Limiter struct {
+	store        Store
+	defaultLimit Limit
+	ipLimit      Limit
+	rules        []Rule
+}
+
+// NewLimiter создает ограничитель. Нулевая defaultLimit - маршруты без своей квоты не ограничиваются,
+// нулевая ipLimit - запросы с одного IP-адреса не ограничиваются.
+func NewLimiter(store Store, defaultLimit, ipLimit Limit, rules []Rule) *Limiter {
+	return &Limiter{store: store, defaultLimit: defaultLimit, ipLimit: ipLimit, rules: rules}
+}
+
+// AllowIP списывает запрос с IP-адреса ip из квоты адреса. Если квоты нет,
+// возвращает Result с нулевым Limit и Allowed = true.
+func (l *Limiter) AllowIP(ctx context.Context, ip string) (Result, error) {
+	if l.ipLimit.IsZero() {
+		return Result{Allowed: true}, nil
+	}
+	return l.store.Take(ctx, "ip:"+ip+" all", l.ipLimit)
+}
+
+// Allow списывает запрос клиента client из квоты маршрута. Если квоты для маршрута нет,
+// возвращает Result с нулевым Limit и Allowed = true.
+func (l *Limiter) Allow(ctx context.Context, client string, r *http.Request) (Result, error) {
+	return l.AllowRoute(ctx, client, r.Method, r.URL.Path)
+}
+
+// AllowRoute списывает вызов method path клиента client, как Allow. Нужен вызовам не по HTTP
+// (gRPC-метод передается как POST /пакет.Сервис/Метод).
+func (l *Limiter) AllowRoute(ctx context.Context, client, method, path string) (Result, error) {
+	limit, bucketKey := l.defaultLimit, client+" *"
+	for _, rule := range l.rules {
+		if rule.matches(method, path) {
+			limit, bucketKey = rule.Limit, client+" "+rule.Method+" "+rule.Pattern
+			break
+		}
+	}
+	if limit.IsZero() {
+		return Result{Allowed: true}, nil
+	}
+	return l.store.Take(ctx, bucketKey, limit)
+}
+
+// Cleanup удаляет ведра, которые уже полностью восстановились при любой из квот.
+func (l *Limiter) Cleanup(ctx context.Context) error {
+	idle := max(l.defaultLimit.Period, l.ipLimit.Period)
+	for _, rule := range l.rules {
+		if rule.Limit.Period > idle {
+			idle = rule.Limit.Period
+		}
+	}
+	if idle <= 0 {
+		return nil
+	}
+	return l.store.Cleanup(ctx, idle)
+}
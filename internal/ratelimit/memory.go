@@ -0,0 +1,47 @@
+// internal/ratelimit/memory.go
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore хранит ведра в памяти процесса: квоты действуют отдельно в каждой реплике сервиса.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]bucket
+}
+
+// NewMemoryStore создает хранилище ведер в памяти.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]bucket)}
+}
+
+// Take забирает токен из ведра key. Новое ведро создается полным.
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = bucket{Tokens: float64(limit.Requests), UpdatedAt: now}
+	}
+	b, result := take(b, limit, now)
+	s.buckets[key] = b
+	return result, nil
+}
+
+// Cleanup удаляет ведра, к которым не обращались дольше idle.
+func (s *MemoryStore) Cleanup(_ context.Context, idle time.Duration) error {
+	before := time.Now().Add(-idle)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, b := range s.buckets {
+		if b.UpdatedAt.Before(before) {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// internal/ratelimit/postgres.go
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+)
+
+// PostgresStore хранит ведра в таблице rate_limit_buckets: квоты общие для всех реплик сервиса.
+// Списание выполняется в короткой транзакции с блокировкой строки ведра, время берется из часов БД.
+type PostgresStore struct {
+	db   *sql.DB
+	repo repository.RateLimitRepository
+}
+
+// NewPostgresStore создает хранилище ведер в Postgres.
+func NewPostgresStore(db *sql.DB, repo repository.RateLimitRepository) *PostgresStore {
+	return &PostgresStore{db: db, repo: repo}
+}
+
+// Take забирает токен из ведра key. Новое ведро создается полным.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("не удалось начать транзакцию: %w", err)
+	}
+	defer tx.Rollback() // После Commit откат ничего не делает
+
+	stored, now, err := s.repo.LockBucket(ctx, tx, key, float64(limit.Requests))
+	if err != nil {
+		return Result{}, err
+	}
+	b, result := take(bucket{Tokens: stored.Tokens, UpdatedAt: stored.UpdatedAt}, limit, now)
+	if err := s.repo.SaveBucket(ctx, tx, models.RateLimitBucket{Key: key, Tokens: b.Tokens, UpdatedAt: b.UpdatedAt}); err != nil {
+		return Result{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return Result{}, fmt.Errorf("не удалось зафиксировать транзакцию: %w", err)
+	}
+	return result, nil
+}
+
+// Cleanup удаляет ведра, к которым не обращались дольше idle.
+func (s *PostgresStore) Cleanup(ctx context.Context, idle time.Duration) error {
+	deleted, err := s.repo.DeleteIdleBuckets(ctx, s.db, idle)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("Удалено неактивных ведер ограничения запросов: %d\n", deleted)
+	}
+	return nil
+}
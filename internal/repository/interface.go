@@ -4,6 +4,7 @@ package repository
 import (
 	"context"
 	"database/sql" // Понадобится для транзакций
+	"time"
 
 	"currency-service/internal/models"
 )
@@ -25,20 +26,214 @@ type RateRepository interface {
 	GetLatestRates(ctx context.Context, db DBTX, limit int) ([]models.Rate, error) // <-- Принимает DBTX
 	// GetLatestRate получает самый свежий курс
 	GetLatestRate(ctx context.Context, db DBTX) (models.Rate, error) // <-- Новый метод
+	// StreamRates передает в fn последние limit курсов (0 - все), начиная с самого свежего,
+	// по мере чтения строк результата, не собирая их в память. Ошибка fn прерывает чтение.
+	StreamRates(ctx context.Context, db DBTX, limit int, fn func(models.Rate) error) error
+	// GetRatesVersion возвращает состояние курсов: ID и количество курсов и время самого свежего курса.
+	GetRatesVersion(ctx context.Context, db DBTX) (models.DataVersion, error)
 }
 
 // (!!!) WalletRepository определяет методы для работы с кошельками.
 type WalletRepository interface {
 	// GetWalletByNumber находит кошелек по номеру. Возвращает sql.ErrNoRows, если не найден.
 	GetWalletByNumber(ctx context.Context, db DBTX, number string) (models.Wallet, error)
-	// GetAllWallets получает все кошельки.
-	GetAllWallets(ctx context.Context, db DBTX) ([]models.Wallet, error)
+	// ListWallets получает страницу кошельков с фильтрами и сортировкой из filter.
+	// after - позиция последнего кошелька предыдущей страницы (nil для первой страницы).
+	ListWallets(ctx context.Context, db DBTX, filter models.ListWalletsFilter, after *models.WalletCursor) ([]models.Wallet, error)
+	// StreamWallets передает в fn кошельки с фильтрами и сортировкой из filter по мере чтения строк результата.
+	// filter.Limit = 0 - без ограничения количества. Ошибка fn прерывает чтение.
+	StreamWallets(ctx context.Context, db DBTX, filter models.ListWalletsFilter, after *models.WalletCursor, fn func(models.Wallet) error) error
+	// GetWalletsVersion возвращает состояние списка кошельков: версии кошельков, количество холдов и время
+	// последнего изменения с учетом истечения активных холдов, от которого зависит доступный баланс.
+	GetWalletsVersion(ctx context.Context, db DBTX) (models.DataVersion, error)
+	// GetWalletsByOwner получает все кошельки указанного пользователя.
+	GetWalletsByOwner(ctx context.Context, db DBTX, ownerID string) ([]models.Wallet, error)
 	// CreateWallet создает новый кошелек.
 	CreateWallet(ctx context.Context, db DBTX, wallet models.Wallet) error
 	// UpdateWalletBalance обновляет баланс кошелька.
 	// Важно: этот метод должен использоваться внутри транзакции для безопасности.
 	UpdateWalletBalance(ctx context.Context, db DBTX, number string, newBalance float64) error
+	// UpdateWalletTier меняет уровень кошелька. Возвращает sql.ErrNoRows, если кошелек не найден.
+	UpdateWalletTier(ctx context.Context, db DBTX, number string, tier string) error
+	// UpdateCreditLimit меняет кредитный лимит кошелька. Возвращает sql.ErrNoRows, если кошелек не найден.
+	UpdateCreditLimit(ctx context.Context, db DBTX, number string, limit float64) error
+	// UpdateWalletStatus меняет статус кошелька. Возвращает sql.ErrNoRows, если кошелек не найден.
+	UpdateWalletStatus(ctx context.Context, db DBTX, number, status string) error
+	// EnsureWallet создает пустой кошелек, если кошелька с таким номером нет. Гонки создания безопасны.
+	EnsureWallet(ctx context.Context, db DBTX, number string) error
 	// GetWalletByNumberForUpdate находит кошелек по номеру с блокировкой строки (SELECT ... FOR UPDATE).
 	// Используется внутри транзакций для предотвращения гонок обновлений.
 	GetWalletByNumberForUpdate(ctx context.Context, tx *sql.Tx, number string) (models.Wallet, error)
 }
+
+// UserRepository определяет методы для работы с пользователями (владельцами кошельков).
+type UserRepository interface {
+	// CreateUser создает пользователя и возвращает его с заполненными ID и CreatedAt.
+	CreateUser(ctx context.Context, db DBTX, user models.User) (models.User, error)
+	// GetUserByID находит пользователя по ID. Возвращает sql.ErrNoRows, если не найден.
+	GetUserByID(ctx context.Context, db DBTX, id string) (models.User, error)
+}
+
+// HoldRepository определяет методы для работы с холдами (резервированием средств на кошельках).
+type HoldRepository interface {
+	// CreateHold создает холд и возвращает его с заполненными ID и временными метками.
+	CreateHold(ctx context.Context, db DBTX, hold models.Hold) (models.Hold, error)
+	// GetHoldByID находит холд по ID. Возвращает sql.ErrNoRows, если не найден.
+	GetHoldByID(ctx context.Context, db DBTX, id int64) (models.Hold, error)
+	// GetHoldByIDForUpdate находит холд по ID с блокировкой строки (SELECT ... FOR UPDATE).
+	GetHoldByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (models.Hold, error)
+	// UpdateHoldStatus меняет статус холда и сумму фактического списания.
+	UpdateHoldStatus(ctx context.Context, db DBTX, id int64, status string, capturedAmount float64) error
+	// ExpireHolds переводит все активные холды с истекшим сроком в статус expired и возвращает их количество.
+	ExpireHolds(ctx context.Context, db DBTX) (int64, error)
+}
+
+// MovementRepository определяет методы для работы с историей движений по кошелькам.
+type MovementRepository interface {
+	// CreateMovement записывает движение по кошельку и возвращает его с заполненными ID и CreatedAt.
+	// Должен вызываться в той же транзакции, что и изменение баланса.
+	CreateMovement(ctx context.Context, db DBTX, movement models.Movement) (models.Movement, error)
+	// GetMovementByID находит движение по ID. Возвращает sql.ErrNoRows, если не найдено.
+	GetMovementByID(ctx context.Context, db DBTX, id int64) (models.Movement, error)
+	// GetMovementByIDForUpdate находит движение по ID с блокировкой строки (SELECT ... FOR UPDATE).
+	GetMovementByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (models.Movement, error)
+	// ListConversionLegsForUpdate возвращает движения с conversion_id = conversionID (по возрастанию ID) с блокировкой строк.
+	ListConversionLegsForUpdate(ctx context.Context, tx *sql.Tx, conversionID int64) ([]models.Movement, error)
+	// SumDebitsSince возвращает сумму списаний (по модулю) по кошельку начиная с момента since.
+	SumDebitsSince(ctx context.Context, db DBTX, number string, since time.Time) (float64, error)
+	// CountMovementsSince возвращает количество движений указанного вида по кошельку начиная с момента since.
+	CountMovementsSince(ctx context.Context, db DBTX, number string, kind string, since time.Time) (int, error)
+	// SumMovementsUntil возвращает сумму всех движений по кошельку до момента at включительно (баланс на момент at).
+	SumMovementsUntil(ctx context.Context, db DBTX, number string, at time.Time) (float64, error)
+	// ListWalletMovements возвращает последние limit движений по кошельку, начиная с самого нового.
+	ListWalletMovements(ctx context.Context, db DBTX, number string, limit int) ([]models.Movement, error)
+}
+
+// LimitRepository определяет методы для работы с лимитами уровней и индивидуальными лимитами кошельков.
+type LimitRepository interface {
+	// GetTierLimits возвращает лимиты уровня. Возвращает sql.ErrNoRows, если лимиты не заданы.
+	GetTierLimits(ctx context.Context, db DBTX, tier string) (models.Limits, error)
+	// UpsertTierLimits создает или заменяет лимиты уровня.
+	UpsertTierLimits(ctx context.Context, db DBTX, tier string, limits models.Limits) error
+	// GetWalletLimitOverrides возвращает индивидуальные лимиты кошелька. Возвращает sql.ErrNoRows, если они не заданы.
+	GetWalletLimitOverrides(ctx context.Context, db DBTX, number string) (models.Limits, error)
+	// UpsertWalletLimitOverrides создает или заменяет индивидуальные лимиты кошелька.
+	UpsertWalletLimitOverrides(ctx context.Context, db DBTX, number string, limits models.Limits) error
+}
+
+// ScheduleRepository определяет методы для работы с расписаниями операций и историей их запусков.
+type ScheduleRepository interface {
+	// CreateSchedule создает расписание и возвращает его с заполненными ID и временными метками.
+	CreateSchedule(ctx context.Context, db DBTX, schedule models.Schedule) (models.Schedule, error)
+	// GetScheduleByID находит расписание по ID. Возвращает sql.ErrNoRows, если не найдено.
+	GetScheduleByID(ctx context.Context, db DBTX, id int64) (models.Schedule, error)
+	// GetScheduleByIDForUpdate находит расписание по ID с блокировкой строки.
+	GetScheduleByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (models.Schedule, error)
+	// ListDueScheduleIDs возвращает ID активных расписаний, время запуска которых наступило.
+	ListDueScheduleIDs(ctx context.Context, db DBTX, limit int) ([]int64, error)
+	// LockDueSchedule блокирует расписание, если оно все еще активно и его время наступило.
+	// Строки, уже заблокированные другим обработчиком, пропускаются (SKIP LOCKED) - возвращается sql.ErrNoRows.
+	LockDueSchedule(ctx context.Context, tx *sql.Tx, id int64) (models.Schedule, error)
+	// UpdateScheduleState сохраняет состояние расписания после запуска или отмены.
+	UpdateScheduleState(ctx context.Context, db DBTX, schedule models.Schedule) error
+	// CreateScheduleRun записывает результат запуска расписания.
+	CreateScheduleRun(ctx context.Context, db DBTX, run models.ScheduleRun) error
+	// GetScheduleRuns возвращает историю запусков расписания (новые первыми).
+	GetScheduleRuns(ctx context.Context, db DBTX, scheduleID int64) ([]models.ScheduleRun, error)
+}
+
+// ReconciliationRepository определяет методы для сверки балансов с историей движений и хранения ее результатов.
+type ReconciliationRepository interface {
+	// CountWallets возвращает количество кошельков.
+	CountWallets(ctx context.Context, db DBTX) (int, error)
+	// FindDiscrepancies сравнивает баланс каждого кошелька с суммой его движений и возвращает
+	// кошельки, у которых расхождение по модулю больше tolerance.
+	FindDiscrepancies(ctx context.Context, db DBTX, tolerance float64) ([]models.Discrepancy, error)
+	// CreateRun создает запись о начале сверки.
+	CreateRun(ctx context.Context, db DBTX) (models.ReconciliationRun, error)
+	// FinishRun сохраняет итоги сверки.
+	FinishRun(ctx context.Context, db DBTX, run models.ReconciliationRun) error
+	// CreateDiscrepancy сохраняет найденное расхождение.
+	CreateDiscrepancy(ctx context.Context, db DBTX, discrepancy models.Discrepancy) error
+	// GetRunByID находит сверку по ID. Возвращает sql.ErrNoRows, если не найдена.
+	GetRunByID(ctx context.Context, db DBTX, id int64) (models.ReconciliationRun, error)
+	// GetLatestRun возвращает последнюю сверку. Возвращает sql.ErrNoRows, если сверок не было.
+	GetLatestRun(ctx context.Context, db DBTX) (models.ReconciliationRun, error)
+	// ListRuns возвращает последние limit сверок (новые первыми).
+	ListRuns(ctx context.Context, db DBTX, limit int) ([]models.ReconciliationRun, error)
+	// GetRunDiscrepancies возвращает расхождения, найденные сверкой.
+	GetRunDiscrepancies(ctx context.Context, db DBTX, runID int64) ([]models.Discrepancy, error)
+}
+
+// FeeRepository определяет методы для работы с правилами комиссий за конвертацию.
+type FeeRepository interface {
+	// ListFeeRules возвращает все правила комиссий.
+	ListFeeRules(ctx context.Context, db DBTX) ([]models.FeeRule, error)
+	// UpsertFeeRule создает или заменяет правило для пары и уровня и возвращает сохраненное правило.
+	UpsertFeeRule(ctx context.Context, db DBTX, rule models.FeeRule) (models.FeeRule, error)
+	// DeleteFeeRule удаляет правило. Возвращает sql.ErrNoRows, если правило не найдено.
+	DeleteFeeRule(ctx context.Context, db DBTX, id int64) error
+	// FindFeeRule возвращает самое точное правило для пары и уровня (с учетом '*').
+	// Возвращает sql.ErrNoRows, если подходящих правил нет.
+	FindFeeRule(ctx context.Context, db DBTX, pair, tier string) (models.FeeRule, error)
+}
+
+// InterestRepository определяет методы для работы с процентными планами и начислениями.
+// Дни передаются как time.Time в UTC; учитывается только дата.
+type InterestRepository interface {
+	// CreatePlan сохраняет новый процентный план.
+	CreatePlan(ctx context.Context, db DBTX, plan models.InterestPlan) (models.InterestPlan, error)
+	// ListPlans возвращает все процентные планы.
+	ListPlans(ctx context.Context, db DBTX) ([]models.InterestPlan, error)
+	// GetPlanByID находит план по ID. Возвращает sql.ErrNoRows, если план не найден.
+	GetPlanByID(ctx context.Context, db DBTX, id int64) (models.InterestPlan, error)
+	// GetWalletPlanID возвращает ID плана кошелька (nil - план не назначен).
+	// Возвращает sql.ErrNoRows, если кошелек не найден.
+	GetWalletPlanID(ctx context.Context, db DBTX, number string) (*int64, error)
+	// SetWalletPlan назначает кошельку план (nil - отключает проценты) и сохраняет назначение в истории.
+	// Возвращает sql.ErrNoRows, если кошелек не найден.
+	SetWalletPlan(ctx context.Context, db DBTX, number string, planID *int64) error
+	// ListAccrualCandidates возвращает кошельки с планом на конец дня day, которым еще не начислены проценты
+	// за этот день, с балансом на конец дня (по последнему движению до конца дня). Сумма начисления не заполнена.
+	ListAccrualCandidates(ctx context.Context, db DBTX, day time.Time) ([]models.InterestAccrual, error)
+	// GetLastAccrualDate возвращает последний день с начислениями (nil - начислений еще не было).
+	GetLastAccrualDate(ctx context.Context, db DBTX) (*time.Time, error)
+	// GetFirstPlanAssignment возвращает время первого назначения плана (nil - планы не назначались).
+	GetFirstPlanAssignment(ctx context.Context, db DBTX) (*time.Time, error)
+	// GetFirstUnpostedAccrualDate возвращает самый ранний день с незачисленным начислением (nil - все зачислено).
+	GetFirstUnpostedAccrualDate(ctx context.Context, db DBTX) (*time.Time, error)
+	// CreateAccrual сохраняет начисление. Возвращает false, если начисление за этот день уже есть.
+	CreateAccrual(ctx context.Context, db DBTX, accrual models.InterestAccrual) (bool, error)
+	// ListWalletsWithUnpostedAccruals возвращает кошельки с незачисленными начислениями до дня before (не включая).
+	ListWalletsWithUnpostedAccruals(ctx context.Context, db DBTX, before time.Time) ([]string, error)
+	// SumUnpostedAccruals возвращает сумму и количество незачисленных начислений кошелька до дня before (не включая).
+	SumUnpostedAccruals(ctx context.Context, db DBTX, number string, before time.Time) (float64, int, error)
+	// MarkAccrualsPosted отмечает незачисленные начисления кошелька до дня before зачисленными движением movementID.
+	MarkAccrualsPosted(ctx context.Context, db DBTX, number string, before time.Time, movementID *int64) error
+	// ListWalletAccruals возвращает последние limit начислений кошелька (новые первыми).
+	ListWalletAccruals(ctx context.Context, db DBTX, number string, limit int) ([]models.InterestAccrual, error)
+}
+
+// APIKeyRepository определяет методы для работы с API-ключами.
+type APIKeyRepository interface {
+	// CreateAPIKey сохраняет ключ с хэшем keyHash и возвращает сохраненный ключ.
+	CreateAPIKey(ctx context.Context, db DBTX, key models.APIKey, keyHash string) (models.APIKey, error)
+	// ListAPIKeys возвращает все ключи, включая отозванные.
+	ListAPIKeys(ctx context.Context, db DBTX) ([]models.APIKey, error)
+	// GetActiveAPIKeyByHash возвращает неотозванный ключ по хэшу. Возвращает sql.ErrNoRows, если такого нет.
+	GetActiveAPIKeyByHash(ctx context.Context, db DBTX, keyHash string) (models.APIKey, error)
+	// RevokeAPIKey отзывает ключ (повторный отзыв не меняет время отзыва) и возвращает его.
+	// Возвращает sql.ErrNoRows, если ключ не найден.
+	RevokeAPIKey(ctx context.Context, db DBTX, id int64) (models.APIKey, error)
+}
+
+// RateLimitRepository определяет методы для работы с ведрами токенов ограничения частоты запросов.
+type RateLimitRepository interface {
+	// LockBucket блокирует ведро key (SELECT ... FOR UPDATE), при отсутствии создавая его с tokens токенами,
+	// и возвращает его вместе с текущим временем БД - общими часами для всех реплик.
+	LockBucket(ctx context.Context, tx *sql.Tx, key string, tokens float64) (bucket models.RateLimitBucket, now time.Time, err error)
+	// SaveBucket сохраняет состояние ведра.
+	SaveBucket(ctx context.Context, db DBTX, bucket models.RateLimitBucket) error
+	// DeleteIdleBuckets удаляет ведра, не менявшиеся дольше idle по часам БД, и возвращает их количество.
+	DeleteIdleBuckets(ctx context.Context, db DBTX, idle time.Duration) (int64, error)
+}
@@ -0,0 +1,101 @@
+// --- internal/repository/postgres_apikey_repository.go ---
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"currency-service/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type postgresAPIKeyRepository struct {
+	// Пустая структура, так как *sql.DB передается в методы
+}
+
+// NewPostgresAPIKeyRepository создает новый экземпляр репозитория API-ключей.
+func NewPostgresAPIKeyRepository() APIKeyRepository {
+	return &postgresAPIKeyRepository{}
+}
+
+// apiKeyColumns - список колонок API-ключа (порядок важен для scanAPIKey). Хэш ключа не выбирается.
+const apiKeyColumns = "id, name, prefix, scopes, created_at, revoked_at"
+
+// scanAPIKey читает API-ключ из строки результата, выбранной по apiKeyColumns.
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var key models.APIKey
+	var revokedAt sql.NullTime
+	if err := row.Scan(&key.ID, &key.Name, &key.Prefix, pq.Array(&key.Scopes), &key.CreatedAt, &revokedAt); err != nil {
+		return models.APIKey{}, err
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
+
+// CreateAPIKey сохраняет ключ с хэшем keyHash.
+func (r *postgresAPIKeyRepository) CreateAPIKey(ctx context.Context, db DBTX, key models.APIKey, keyHash string) (models.APIKey, error) {
+	query := "INSERT INTO api_keys (name, prefix, key_hash, scopes) VALUES ($1, $2, $3, $4) RETURNING " + apiKeyColumns
+	saved, err := scanAPIKey(db.QueryRowContext(ctx, query, key.Name, key.Prefix, keyHash, pq.Array(key.Scopes)))
+	if err != nil {
+		log.Printf("Ошибка сохранения API-ключа %s в БД: %v\n", key.Name, err)
+		return models.APIKey{}, fmt.Errorf("ошибка выполнения запроса INSERT (api key): %w", err)
+	}
+	log.Printf("API-ключ #%d (%s, %s) выпущен\n", saved.ID, saved.Name, saved.Prefix)
+	return saved, nil
+}
+
+// ListAPIKeys возвращает все ключи в порядке выпуска.
+func (r *postgresAPIKeyRepository) ListAPIKeys(ctx context.Context, db DBTX) ([]models.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("Ошибка получения API-ключей из БД: %v\n", err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (api keys): %w", err)
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return keys, fmt.Errorf("ошибка сканирования строки api_keys: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по результатам api_keys: %w", err)
+	}
+	return keys, nil
+}
+
+// GetActiveAPIKeyByHash возвращает неотозванный ключ по хэшу.
+func (r *postgresAPIKeyRepository) GetActiveAPIKeyByHash(ctx context.Context, db DBTX, keyHash string) (models.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL"
+	key, err := scanAPIKey(db.QueryRowContext(ctx, query, keyHash))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка поиска API-ключа в БД: %v\n", err)
+		}
+		return models.APIKey{}, err
+	}
+	return key, nil
+}
+
+// RevokeAPIKey отзывает ключ. Время отзыва уже отозванного ключа не меняется.
+func (r *postgresAPIKeyRepository) RevokeAPIKey(ctx context.Context, db DBTX, id int64) (models.APIKey, error) {
+	query := "UPDATE api_keys SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP) WHERE id = $1 RETURNING " + apiKeyColumns
+	key, err := scanAPIKey(db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка отзыва API-ключа %d в БД: %v\n", id, err)
+		}
+		return models.APIKey{}, err
+	}
+	log.Printf("API-ключ #%d (%s) отозван\n", key.ID, key.Name)
+	return key, nil
+}
@@ -0,0 +1,116 @@
+// --- internal/repository/postgres_fee_repository.go ---
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"currency-service/internal/models"
+)
+
+type postgresFeeRepository struct {
+	// Пустая структура, так как *sql.DB передается в методы
+}
+
+// NewPostgresFeeRepository создает новый экземпляр репозитория комиссий.
+func NewPostgresFeeRepository() FeeRepository {
+	return &postgresFeeRepository{}
+}
+
+// feeRuleColumns - список колонок правила комиссии (порядок важен для scanFeeRule).
+const feeRuleColumns = "id, pair, tier, percent, fixed, min_fee, max_fee, updated_at"
+
+// scanFeeRule читает правило комиссии из строки результата, выбранной по feeRuleColumns.
+func scanFeeRule(row rowScanner) (models.FeeRule, error) {
+	var rule models.FeeRule
+	var minFee, maxFee sql.NullFloat64
+	if err := row.Scan(&rule.ID, &rule.Pair, &rule.Tier, &rule.Percent, &rule.Fixed, &minFee, &maxFee, &rule.UpdatedAt); err != nil {
+		return models.FeeRule{}, err
+	}
+	if minFee.Valid {
+		rule.MinFee = &minFee.Float64
+	}
+	if maxFee.Valid {
+		rule.MaxFee = &maxFee.Float64
+	}
+	return rule, nil
+}
+
+// ListFeeRules возвращает все правила комиссий: сначала общие, затем более точные.
+func (r *postgresFeeRepository) ListFeeRules(ctx context.Context, db DBTX) ([]models.FeeRule, error) {
+	query := "SELECT " + feeRuleColumns + " FROM conversion_fee_rules ORDER BY pair, tier"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("Ошибка получения правил комиссий из БД: %v\n", err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (fee rules): %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.FeeRule
+	for rows.Next() {
+		rule, err := scanFeeRule(rows)
+		if err != nil {
+			return rules, fmt.Errorf("ошибка сканирования строки conversion_fee_rules: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по результатам conversion_fee_rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpsertFeeRule создает или заменяет правило для пары и уровня.
+func (r *postgresFeeRepository) UpsertFeeRule(ctx context.Context, db DBTX, rule models.FeeRule) (models.FeeRule, error) {
+	query := `INSERT INTO conversion_fee_rules (pair, tier, percent, fixed, min_fee, max_fee) VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (pair, tier) DO UPDATE SET
+            percent = EXCLUDED.percent,
+            fixed = EXCLUDED.fixed,
+            min_fee = EXCLUDED.min_fee,
+            max_fee = EXCLUDED.max_fee,
+            updated_at = CURRENT_TIMESTAMP
+        RETURNING ` + feeRuleColumns
+	saved, err := scanFeeRule(db.QueryRowContext(ctx, query, rule.Pair, rule.Tier, rule.Percent, rule.Fixed, rule.MinFee, rule.MaxFee))
+	if err != nil {
+		log.Printf("Ошибка сохранения правила комиссии %s/%s в БД: %v\n", rule.Pair, rule.Tier, err)
+		return models.FeeRule{}, fmt.Errorf("ошибка выполнения запроса UPSERT (fee rule): %w", err)
+	}
+	log.Printf("Правило комиссии для пары %s и уровня %s сохранено\n", saved.Pair, saved.Tier)
+	return saved, nil
+}
+
+// DeleteFeeRule удаляет правило комиссии.
+func (r *postgresFeeRepository) DeleteFeeRule(ctx context.Context, db DBTX, id int64) error {
+	result, err := db.ExecContext(ctx, "DELETE FROM conversion_fee_rules WHERE id = $1", id)
+	if err != nil {
+		log.Printf("Ошибка удаления правила комиссии %d из БД: %v\n", id, err)
+		return fmt.Errorf("ошибка выполнения запроса DELETE (fee rule): %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка проверки результата DELETE: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// FindFeeRule возвращает самое точное правило: точная пара важнее точного уровня,
+// а '*' подходит для любой пары или уровня.
+func (r *postgresFeeRepository) FindFeeRule(ctx context.Context, db DBTX, pair, tier string) (models.FeeRule, error) {
+	query := "SELECT " + feeRuleColumns + ` FROM conversion_fee_rules
+        WHERE pair IN ($1, '*') AND tier IN ($2, '*')
+        ORDER BY (pair = '*'), (tier = '*')
+        LIMIT 1`
+	rule, err := scanFeeRule(db.QueryRowContext(ctx, query, pair, tier))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка поиска правила комиссии %s/%s в БД: %v\n", pair, tier, err)
+		}
+		return models.FeeRule{}, err
+	}
+	return rule, nil
+}
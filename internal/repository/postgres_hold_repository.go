@@ -0,0 +1,123 @@
+// --- internal/repository/postgres_hold_repository.go ---
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ErrDuplicateHoldReference возвращается при попытке создать второй холд с тем же reference на кошельке.
+var ErrDuplicateHoldReference = apperrors.New(apperrors.CodeDuplicateHoldReference, "холд с таким reference уже существует для этого кошелька")
+
+type postgresHoldRepository struct {
+	// Пустая структура, так как *sql.DB передается в методы
+}
+
+// NewPostgresHoldRepository создает новый экземпляр репозитория холдов.
+func NewPostgresHoldRepository() HoldRepository {
+	return &postgresHoldRepository{}
+}
+
+// holdColumns - список колонок, которые читаются при выборке холда (порядок важен для scanHold).
+const holdColumns = "id, wallet_number, amount, captured_amount, reference, status, expires_at, created_at, updated_at"
+
+// scanHold читает холд из строки результата, выбранной по holdColumns.
+func scanHold(row rowScanner) (models.Hold, error) {
+	var hold models.Hold
+	err := row.Scan(&hold.ID, &hold.WalletNumber, &hold.Amount, &hold.CapturedAmount, &hold.Reference,
+		&hold.Status, &hold.ExpiresAt, &hold.CreatedAt, &hold.UpdatedAt)
+	if err != nil {
+		return models.Hold{}, err
+	}
+	return hold, nil
+}
+
+// CreateHold создает холд. ID и временные метки заполняет БД.
+func (r *postgresHoldRepository) CreateHold(ctx context.Context, db DBTX, hold models.Hold) (models.Hold, error) {
+	query := `INSERT INTO wallet_holds (wallet_number, amount, reference, status, expires_at)
+        VALUES ($1, $2, $3, $4, $5) RETURNING ` + holdColumns
+	row := db.QueryRowContext(ctx, query, hold.WalletNumber, hold.Amount, hold.Reference, hold.Status, hold.ExpiresAt)
+
+	created, err := scanHold(row)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // 23505 - unique_violation
+			log.Printf("Попытка создать дублирующийся холд %q для кошелька %s\n", hold.Reference, hold.WalletNumber)
+			return models.Hold{}, ErrDuplicateHoldReference
+		}
+		log.Printf("Ошибка создания холда для кошелька %s в БД: %v\n", hold.WalletNumber, err)
+		return models.Hold{}, fmt.Errorf("ошибка выполнения запроса INSERT (hold): %w", err)
+	}
+	log.Printf("Холд %d на сумму %.2f создан для кошелька %s\n", created.ID, created.Amount, created.WalletNumber)
+	return created, nil
+}
+
+// GetHoldByID находит холд по ID.
+func (r *postgresHoldRepository) GetHoldByID(ctx context.Context, db DBTX, id int64) (models.Hold, error) {
+	query := "SELECT " + holdColumns + " FROM wallet_holds WHERE id = $1"
+	hold, err := scanHold(db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		// Ошибку sql.ErrNoRows обрабатываем в сервисе
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения холда %d из БД: %v\n", id, err)
+		}
+		return models.Hold{}, err
+	}
+	return hold, nil
+}
+
+// GetHoldByIDForUpdate находит холд по ID с блокировкой строки (ДЛЯ ТРАНЗАКЦИЙ).
+func (r *postgresHoldRepository) GetHoldByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (models.Hold, error) {
+	query := "SELECT " + holdColumns + " FROM wallet_holds WHERE id = $1 FOR UPDATE"
+	hold, err := scanHold(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения холда %d из БД (FOR UPDATE): %v\n", id, err)
+		}
+		return models.Hold{}, err
+	}
+	return hold, nil
+}
+
+// UpdateHoldStatus меняет статус холда и сумму фактического списания.
+func (r *postgresHoldRepository) UpdateHoldStatus(ctx context.Context, db DBTX, id int64, status string, capturedAmount float64) error {
+	query := "UPDATE wallet_holds SET status = $1, captured_amount = $2 WHERE id = $3"
+	result, err := db.ExecContext(ctx, query, status, capturedAmount, id)
+	if err != nil {
+		log.Printf("Ошибка обновления статуса холда %d в БД: %v\n", id, err)
+		return fmt.Errorf("ошибка выполнения запроса UPDATE (hold status): %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка проверки результата UPDATE: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	log.Printf("Холд %d переведен в статус %s\n", id, status)
+	return nil
+}
+
+// ExpireHolds освобождает все активные холды с истекшим сроком.
+func (r *postgresHoldRepository) ExpireHolds(ctx context.Context, db DBTX) (int64, error) {
+	query := "UPDATE wallet_holds SET status = $1 WHERE status = $2 AND expires_at <= NOW()"
+	result, err := db.ExecContext(ctx, query, models.HoldStatusExpired, models.HoldStatusActive)
+	if err != nil {
+		log.Printf("Ошибка освобождения истекших холдов в БД: %v\n", err)
+		return 0, fmt.Errorf("ошибка выполнения запроса UPDATE (expire holds): %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка проверки результата UPDATE: %w", err)
+	}
+	return rowsAffected, nil
+}
@@ -0,0 +1,308 @@
+// --- internal/repository/postgres_interest_repository.go ---
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"currency-service/internal/models"
+)
+
+// dateLayout - формат дня, в котором даты передаются в колонки DATE.
+const dateLayout = "2006-01-02"
+
+type postgresInterestRepository struct {
+	// Пустая структура, так как *sql.DB передается в методы
+}
+
+// NewPostgresInterestRepository создает новый экземпляр репозитория процентов.
+func NewPostgresInterestRepository() InterestRepository {
+	return &postgresInterestRepository{}
+}
+
+// interestPlanColumns - список колонок процентного плана (порядок важен для scanInterestPlan).
+const interestPlanColumns = "id, name, annual_rate, day_count, created_at"
+
+// scanInterestPlan читает процентный план из строки результата, выбранной по interestPlanColumns.
+func scanInterestPlan(row rowScanner) (models.InterestPlan, error) {
+	var plan models.InterestPlan
+	if err := row.Scan(&plan.ID, &plan.Name, &plan.AnnualRate, &plan.DayCount, &plan.CreatedAt); err != nil {
+		return models.InterestPlan{}, err
+	}
+	return plan, nil
+}
+
+// interestAccrualColumns - список колонок начисления (порядок важен для scanInterestAccrual).
+const interestAccrualColumns = "wallet_number, accrual_date, plan_id, balance, annual_rate, day_count, amount, posted_at, movement_id"
+
+// scanInterestAccrual читает начисление из строки результата, выбранной по interestAccrualColumns.
+func scanInterestAccrual(row rowScanner) (models.InterestAccrual, error) {
+	var a models.InterestAccrual
+	var postedAt sql.NullTime
+	var movementID sql.NullInt64
+	if err := row.Scan(&a.WalletNumber, &a.AccrualDate, &a.PlanID, &a.Balance, &a.AnnualRate, &a.DayCount, &a.Amount,
+		&postedAt, &movementID); err != nil {
+		return models.InterestAccrual{}, err
+	}
+	if postedAt.Valid {
+		a.PostedAt = &postedAt.Time
+	}
+	if movementID.Valid {
+		a.MovementID = &movementID.Int64
+	}
+	return a, nil
+}
+
+// CreatePlan сохраняет новый процентный план.
+func (r *postgresInterestRepository) CreatePlan(ctx context.Context, db DBTX, plan models.InterestPlan) (models.InterestPlan, error) {
+	query := "INSERT INTO interest_plans (name, annual_rate, day_count) VALUES ($1, $2, $3) RETURNING " + interestPlanColumns
+	saved, err := scanInterestPlan(db.QueryRowContext(ctx, query, plan.Name, plan.AnnualRate, plan.DayCount))
+	if err != nil {
+		log.Printf("Ошибка создания процентного плана %s: %v\n", plan.Name, err)
+		return models.InterestPlan{}, fmt.Errorf("ошибка выполнения запроса INSERT (interest plan): %w", err)
+	}
+	return saved, nil
+}
+
+// ListPlans возвращает все процентные планы в порядке создания.
+func (r *postgresInterestRepository) ListPlans(ctx context.Context, db DBTX) ([]models.InterestPlan, error) {
+	query := "SELECT " + interestPlanColumns + " FROM interest_plans ORDER BY id"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("Ошибка получения процентных планов из БД: %v\n", err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (interest plans): %w", err)
+	}
+	defer rows.Close()
+
+	var plans []models.InterestPlan
+	for rows.Next() {
+		plan, err := scanInterestPlan(rows)
+		if err != nil {
+			return plans, fmt.Errorf("ошибка сканирования строки interest_plans: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по результатам interest_plans: %w", err)
+	}
+	return plans, nil
+}
+
+// GetPlanByID находит план по ID.
+func (r *postgresInterestRepository) GetPlanByID(ctx context.Context, db DBTX, id int64) (models.InterestPlan, error) {
+	query := "SELECT " + interestPlanColumns + " FROM interest_plans WHERE id = $1"
+	plan, err := scanInterestPlan(db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения процентного плана %d из БД: %v\n", id, err)
+		}
+		return models.InterestPlan{}, err
+	}
+	return plan, nil
+}
+
+// GetWalletPlanID возвращает ID плана кошелька.
+func (r *postgresInterestRepository) GetWalletPlanID(ctx context.Context, db DBTX, number string) (*int64, error) {
+	var planID sql.NullInt64
+	err := db.QueryRowContext(ctx, "SELECT interest_plan_id FROM wallets WHERE wallet_number = $1", number).Scan(&planID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения процентного плана кошелька %s: %v\n", number, err)
+		}
+		return nil, err
+	}
+	if !planID.Valid {
+		return nil, nil
+	}
+	return &planID.Int64, nil
+}
+
+// SetWalletPlan назначает кошельку план и записывает назначение в историю одним запросом:
+// проценты за день начисляются по плану, действовавшему на конец дня.
+func (r *postgresInterestRepository) SetWalletPlan(ctx context.Context, db DBTX, number string, planID *int64) error {
+	query := `
+        WITH updated AS (
+            UPDATE wallets SET interest_plan_id = $1 WHERE wallet_number = $2 RETURNING wallet_number
+        )
+        INSERT INTO wallet_interest_plan_history (wallet_number, plan_id)
+        SELECT wallet_number, $1::bigint FROM updated`
+	result, err := db.ExecContext(ctx, query, planID, number)
+	if err != nil {
+		log.Printf("Ошибка назначения процентного плана кошельку %s: %v\n", number, err)
+		return fmt.Errorf("ошибка выполнения запроса UPDATE (wallet interest plan): %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества обновленных строк: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListAccrualCandidates возвращает кошельки, которым нужно начислить проценты за день day.
+// План берется из истории назначений на конец дня, поэтому при начислении за прошлые дни
+// используется план, действовавший в тот день, а не текущий.
+func (r *postgresInterestRepository) ListAccrualCandidates(ctx context.Context, db DBTX, day time.Time) ([]models.InterestAccrual, error) {
+	query := `
+        SELECT w.wallet_number, p.id, eod.balance_after::double precision, p.annual_rate, p.day_count
+        FROM wallets w
+        CROSS JOIN LATERAL (
+            SELECT h.plan_id FROM wallet_interest_plan_history h
+            WHERE h.wallet_number = w.wallet_number AND h.assigned_at < $2
+            ORDER BY h.assigned_at DESC, h.id DESC
+            LIMIT 1
+        ) assignment
+        JOIN interest_plans p ON p.id = assignment.plan_id
+        CROSS JOIN LATERAL (
+            SELECT m.balance_after FROM wallet_movements m
+            WHERE m.wallet_number = w.wallet_number AND m.created_at < $2
+            ORDER BY m.created_at DESC, m.id DESC
+            LIMIT 1
+        ) eod
+        WHERE NOT EXISTS (
+            SELECT 1 FROM interest_accruals a WHERE a.wallet_number = w.wallet_number AND a.accrual_date = $1
+        )
+        ORDER BY w.wallet_number`
+	rows, err := db.QueryContext(ctx, query, day.Format(dateLayout), day.AddDate(0, 0, 1))
+	if err != nil {
+		log.Printf("Ошибка поиска кошельков для начисления процентов: %v\n", err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (accrual candidates): %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []models.InterestAccrual
+	for rows.Next() {
+		a := models.InterestAccrual{AccrualDate: day}
+		if err := rows.Scan(&a.WalletNumber, &a.PlanID, &a.Balance, &a.AnnualRate, &a.DayCount); err != nil {
+			return candidates, fmt.Errorf("ошибка сканирования кошелька для начисления: %w", err)
+		}
+		candidates = append(candidates, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по кошелькам для начисления: %w", err)
+	}
+	return candidates, nil
+}
+
+// GetLastAccrualDate возвращает последний день, за который есть начисления.
+func (r *postgresInterestRepository) GetLastAccrualDate(ctx context.Context, db DBTX) (*time.Time, error) {
+	return r.queryDate(ctx, db, "SELECT MAX(accrual_date) FROM interest_accruals", "last accrual date")
+}
+
+// GetFirstPlanAssignment возвращает время первого назначения плана какому-либо кошельку.
+func (r *postgresInterestRepository) GetFirstPlanAssignment(ctx context.Context, db DBTX) (*time.Time, error) {
+	return r.queryDate(ctx, db, "SELECT MIN(assigned_at) FROM wallet_interest_plan_history WHERE plan_id IS NOT NULL", "first plan assignment")
+}
+
+// GetFirstUnpostedAccrualDate возвращает самый ранний день с незачисленным начислением.
+func (r *postgresInterestRepository) GetFirstUnpostedAccrualDate(ctx context.Context, db DBTX) (*time.Time, error) {
+	return r.queryDate(ctx, db, "SELECT MIN(accrual_date) FROM interest_accruals WHERE posted_at IS NULL", "first unposted accrual date")
+}
+
+// queryDate выполняет агрегатный запрос, возвращающий одно время (nil - строк нет).
+func (r *postgresInterestRepository) queryDate(ctx context.Context, db DBTX, query, name string) (*time.Time, error) {
+	var value sql.NullTime
+	if err := db.QueryRowContext(ctx, query).Scan(&value); err != nil {
+		log.Printf("Ошибка получения даты процентов (%s): %v\n", name, err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (%s): %w", name, err)
+	}
+	if !value.Valid {
+		return nil, nil
+	}
+	return &value.Time, nil
+}
+
+// CreateAccrual сохраняет начисление; повторное начисление за тот же день игнорируется.
+func (r *postgresInterestRepository) CreateAccrual(ctx context.Context, db DBTX, a models.InterestAccrual) (bool, error) {
+	query := `INSERT INTO interest_accruals (wallet_number, accrual_date, plan_id, balance, annual_rate, day_count, amount)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (wallet_number, accrual_date) DO NOTHING`
+	result, err := db.ExecContext(ctx, query, a.WalletNumber, a.AccrualDate.Format(dateLayout), a.PlanID, a.Balance, a.AnnualRate, a.DayCount, a.Amount)
+	if err != nil {
+		log.Printf("Ошибка сохранения начисления процентов кошельку %s: %v\n", a.WalletNumber, err)
+		return false, fmt.Errorf("ошибка выполнения запроса INSERT (interest accrual): %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("ошибка получения количества вставленных строк: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ListWalletsWithUnpostedAccruals возвращает кошельки с незачисленными начислениями до дня before.
+func (r *postgresInterestRepository) ListWalletsWithUnpostedAccruals(ctx context.Context, db DBTX, before time.Time) ([]string, error) {
+	query := `SELECT DISTINCT wallet_number FROM interest_accruals
+        WHERE posted_at IS NULL AND accrual_date < $1 ORDER BY wallet_number`
+	rows, err := db.QueryContext(ctx, query, before.Format(dateLayout))
+	if err != nil {
+		log.Printf("Ошибка поиска незачисленных процентов: %v\n", err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (unposted accruals): %w", err)
+	}
+	defer rows.Close()
+
+	var numbers []string
+	for rows.Next() {
+		var number string
+		if err := rows.Scan(&number); err != nil {
+			return numbers, fmt.Errorf("ошибка сканирования номера кошелька: %w", err)
+		}
+		numbers = append(numbers, number)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по незачисленным процентам: %w", err)
+	}
+	return numbers, nil
+}
+
+// SumUnpostedAccruals возвращает сумму и количество незачисленных начислений кошелька до дня before.
+func (r *postgresInterestRepository) SumUnpostedAccruals(ctx context.Context, db DBTX, number string, before time.Time) (float64, int, error) {
+	query := `SELECT COALESCE(SUM(amount), 0), COUNT(*) FROM interest_accruals
+        WHERE wallet_number = $1 AND posted_at IS NULL AND accrual_date < $2`
+	var sum float64
+	var count int
+	if err := db.QueryRowContext(ctx, query, number, before.Format(dateLayout)).Scan(&sum, &count); err != nil {
+		log.Printf("Ошибка подсчета незачисленных процентов кошелька %s: %v\n", number, err)
+		return 0, 0, fmt.Errorf("ошибка выполнения запроса SELECT (sum unposted accruals): %w", err)
+	}
+	return sum, count, nil
+}
+
+// MarkAccrualsPosted отмечает незачисленные начисления кошелька до дня before зачисленными.
+func (r *postgresInterestRepository) MarkAccrualsPosted(ctx context.Context, db DBTX, number string, before time.Time, movementID *int64) error {
+	query := `UPDATE interest_accruals SET posted_at = CURRENT_TIMESTAMP, movement_id = $1
+        WHERE wallet_number = $2 AND posted_at IS NULL AND accrual_date < $3`
+	if _, err := db.ExecContext(ctx, query, movementID, number, before.Format(dateLayout)); err != nil {
+		log.Printf("Ошибка отметки зачисления процентов кошелька %s: %v\n", number, err)
+		return fmt.Errorf("ошибка выполнения запроса UPDATE (interest accruals): %w", err)
+	}
+	return nil
+}
+
+// ListWalletAccruals возвращает последние начисления кошелька.
+func (r *postgresInterestRepository) ListWalletAccruals(ctx context.Context, db DBTX, number string, limit int) ([]models.InterestAccrual, error) {
+	query := "SELECT " + interestAccrualColumns + " FROM interest_accruals WHERE wallet_number = $1 ORDER BY accrual_date DESC LIMIT $2"
+	rows, err := db.QueryContext(ctx, query, number, limit)
+	if err != nil {
+		log.Printf("Ошибка получения начислений кошелька %s: %v\n", number, err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (wallet accruals): %w", err)
+	}
+	defer rows.Close()
+
+	var accruals []models.InterestAccrual
+	for rows.Next() {
+		a, err := scanInterestAccrual(rows)
+		if err != nil {
+			return accruals, fmt.Errorf("ошибка сканирования строки interest_accruals: %w", err)
+		}
+		accruals = append(accruals, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по результатам interest_accruals: %w", err)
+	}
+	return accruals, nil
+}
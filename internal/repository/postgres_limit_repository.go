@@ -0,0 +1,127 @@
+// --- internal/repository/postgres_limit_repository.go ---
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"currency-service/internal/models"
+)
+
+type postgresLimitRepository struct {
+	// Пустая структура, так как *sql.DB передается в методы
+}
+
+// NewPostgresLimitRepository создает новый экземпляр репозитория лимитов.
+func NewPostgresLimitRepository() LimitRepository {
+	return &postgresLimitRepository{}
+}
+
+// limitColumns - колонки лимитов, одинаковые в tier_limits и wallet_limit_overrides (порядок важен для scanLimits).
+const limitColumns = "max_single_withdrawal, daily_withdrawal_total, monthly_withdrawal_total, daily_conversion_count"
+
+// scanLimits читает лимиты из строки результата; NULL превращается в nil (лимит не установлен).
+func scanLimits(row rowScanner) (models.Limits, error) {
+	var single, daily, monthly sql.NullFloat64
+	var conversions sql.NullInt64
+	if err := row.Scan(&single, &daily, &monthly, &conversions); err != nil {
+		return models.Limits{}, err
+	}
+
+	var limits models.Limits
+	if single.Valid {
+		limits.MaxSingleWithdrawal = &single.Float64
+	}
+	if daily.Valid {
+		limits.DailyWithdrawalTotal = &daily.Float64
+	}
+	if monthly.Valid {
+		limits.MonthlyWithdrawalTotal = &monthly.Float64
+	}
+	if conversions.Valid {
+		count := int(conversions.Int64)
+		limits.DailyConversionCount = &count
+	}
+	return limits, nil
+}
+
+// limitArgs превращает лимиты в аргументы запроса в порядке limitColumns (nil -> NULL).
+func limitArgs(limits models.Limits) []interface{} {
+	args := []interface{}{nil, nil, nil, nil}
+	if limits.MaxSingleWithdrawal != nil {
+		args[0] = *limits.MaxSingleWithdrawal
+	}
+	if limits.DailyWithdrawalTotal != nil {
+		args[1] = *limits.DailyWithdrawalTotal
+	}
+	if limits.MonthlyWithdrawalTotal != nil {
+		args[2] = *limits.MonthlyWithdrawalTotal
+	}
+	if limits.DailyConversionCount != nil {
+		args[3] = *limits.DailyConversionCount
+	}
+	return args
+}
+
+// GetTierLimits возвращает лимиты уровня.
+func (r *postgresLimitRepository) GetTierLimits(ctx context.Context, db DBTX, tier string) (models.Limits, error) {
+	query := "SELECT " + limitColumns + " FROM tier_limits WHERE tier = $1"
+	limits, err := scanLimits(db.QueryRowContext(ctx, query, tier))
+	if err != nil {
+		// Ошибку sql.ErrNoRows обрабатываем в сервисе
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения лимитов уровня %s из БД: %v\n", tier, err)
+		}
+		return models.Limits{}, err
+	}
+	return limits, nil
+}
+
+// UpsertTierLimits создает или заменяет лимиты уровня.
+func (r *postgresLimitRepository) UpsertTierLimits(ctx context.Context, db DBTX, tier string, limits models.Limits) error {
+	query := `INSERT INTO tier_limits (tier, ` + limitColumns + `) VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (tier) DO UPDATE SET
+            max_single_withdrawal = EXCLUDED.max_single_withdrawal,
+            daily_withdrawal_total = EXCLUDED.daily_withdrawal_total,
+            monthly_withdrawal_total = EXCLUDED.monthly_withdrawal_total,
+            daily_conversion_count = EXCLUDED.daily_conversion_count`
+	args := append([]interface{}{tier}, limitArgs(limits)...)
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Ошибка сохранения лимитов уровня %s в БД: %v\n", tier, err)
+		return fmt.Errorf("ошибка выполнения запроса UPSERT (tier limits): %w", err)
+	}
+	log.Printf("Лимиты уровня %s сохранены\n", tier)
+	return nil
+}
+
+// GetWalletLimitOverrides возвращает индивидуальные лимиты кошелька.
+func (r *postgresLimitRepository) GetWalletLimitOverrides(ctx context.Context, db DBTX, number string) (models.Limits, error) {
+	query := "SELECT " + limitColumns + " FROM wallet_limit_overrides WHERE wallet_number = $1"
+	limits, err := scanLimits(db.QueryRowContext(ctx, query, number))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения лимитов кошелька %s из БД: %v\n", number, err)
+		}
+		return models.Limits{}, err
+	}
+	return limits, nil
+}
+
+// UpsertWalletLimitOverrides создает или заменяет индивидуальные лимиты кошелька.
+func (r *postgresLimitRepository) UpsertWalletLimitOverrides(ctx context.Context, db DBTX, number string, limits models.Limits) error {
+	query := `INSERT INTO wallet_limit_overrides (wallet_number, ` + limitColumns + `) VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (wallet_number) DO UPDATE SET
+            max_single_withdrawal = EXCLUDED.max_single_withdrawal,
+            daily_withdrawal_total = EXCLUDED.daily_withdrawal_total,
+            monthly_withdrawal_total = EXCLUDED.monthly_withdrawal_total,
+            daily_conversion_count = EXCLUDED.daily_conversion_count`
+	args := append([]interface{}{number}, limitArgs(limits)...)
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Ошибка сохранения лимитов кошелька %s в БД: %v\n", number, err)
+		return fmt.Errorf("ошибка выполнения запроса UPSERT (wallet limits): %w", err)
+	}
+	log.Printf("Индивидуальные лимиты кошелька %s сохранены\n", number)
+	return nil
+}
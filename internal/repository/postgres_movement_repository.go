@@ -0,0 +1,177 @@
+// --- internal/repository/postgres_movement_repository.go ---
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"currency-service/internal/models"
+)
+
+type postgresMovementRepository struct {
+	// Пустая структура, так как *sql.DB передается в методы
+}
+
+// NewPostgresMovementRepository создает новый экземпляр репозитория движений по кошелькам.
+func NewPostgresMovementRepository() MovementRepository {
+	return &postgresMovementRepository{}
+}
+
+// movementColumns - список колонок движения (порядок важен для scanMovement).
+// reversed_by вычисляется по отменяющему движению, если оно есть.
+const movementColumns = `id, wallet_number, kind, amount, balance_after, rate, source_amount, reversal_of, conversion_id,
+    (SELECT r.id FROM wallet_movements r WHERE r.reversal_of = wallet_movements.id), created_at`
+
+// scanMovement читает движение из строки результата, выбранной по movementColumns.
+func scanMovement(row rowScanner) (models.Movement, error) {
+	var movement models.Movement
+	var rate, sourceAmount sql.NullFloat64
+	var reversalOf, conversionID, reversedBy sql.NullInt64
+	if err := row.Scan(&movement.ID, &movement.WalletNumber, &movement.Kind, &movement.Amount, &movement.BalanceAfter,
+		&rate, &sourceAmount, &reversalOf, &conversionID, &reversedBy, &movement.CreatedAt); err != nil {
+		return models.Movement{}, err
+	}
+	if rate.Valid {
+		movement.Rate = &rate.Float64
+	}
+	if sourceAmount.Valid {
+		movement.SourceAmount = &sourceAmount.Float64
+	}
+	if reversalOf.Valid {
+		movement.ReversalOf = &reversalOf.Int64
+	}
+	if conversionID.Valid {
+		movement.ConversionID = &conversionID.Int64
+	}
+	if reversedBy.Valid {
+		movement.ReversedBy = &reversedBy.Int64
+	}
+	return movement, nil
+}
+
+// CreateMovement записывает движение по кошельку.
+func (r *postgresMovementRepository) CreateMovement(ctx context.Context, db DBTX, movement models.Movement) (models.Movement, error) {
+	query := `INSERT INTO wallet_movements (wallet_number, kind, amount, balance_after, rate, source_amount, reversal_of, conversion_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`
+	err := db.QueryRowContext(ctx, query, movement.WalletNumber, movement.Kind, movement.Amount, movement.BalanceAfter,
+		movement.Rate, movement.SourceAmount, movement.ReversalOf, movement.ConversionID).Scan(&movement.ID, &movement.CreatedAt)
+	if err != nil {
+		log.Printf("Ошибка записи движения по кошельку %s в БД: %v\n", movement.WalletNumber, err)
+		return models.Movement{}, fmt.Errorf("ошибка выполнения запроса INSERT (movement): %w", err)
+	}
+	return movement, nil
+}
+
+// GetMovementByID находит движение по ID.
+func (r *postgresMovementRepository) GetMovementByID(ctx context.Context, db DBTX, id int64) (models.Movement, error) {
+	query := "SELECT " + movementColumns + " FROM wallet_movements WHERE id = $1"
+	movement, err := scanMovement(db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения движения %d из БД: %v\n", id, err)
+		}
+		return models.Movement{}, err
+	}
+	return movement, nil
+}
+
+// GetMovementByIDForUpdate находит движение по ID с блокировкой строки.
+// Блокировка сериализует параллельные отмены одного и того же движения.
+func (r *postgresMovementRepository) GetMovementByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (models.Movement, error) {
+	query := "SELECT " + movementColumns + " FROM wallet_movements WHERE id = $1 FOR UPDATE"
+	movement, err := scanMovement(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения движения %d с блокировкой: %v\n", id, err)
+		}
+		return models.Movement{}, err
+	}
+	return movement, nil
+}
+
+// ListConversionLegsForUpdate возвращает движения, связанные со списанием конвертации conversionID
+// (зачисление получателю и комиссию), с блокировкой строк.
+func (r *postgresMovementRepository) ListConversionLegsForUpdate(ctx context.Context, tx *sql.Tx, conversionID int64) ([]models.Movement, error) {
+	query := "SELECT " + movementColumns + " FROM wallet_movements WHERE conversion_id = $1 ORDER BY id FOR UPDATE"
+	rows, err := tx.QueryContext(ctx, query, conversionID)
+	if err != nil {
+		log.Printf("Ошибка получения движений конвертации %d: %v\n", conversionID, err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (conversion legs): %w", err)
+	}
+	defer rows.Close()
+
+	var legs []models.Movement
+	for rows.Next() {
+		movement, err := scanMovement(rows)
+		if err != nil {
+			log.Printf("Ошибка сканирования движения конвертации %d: %v\n", conversionID, err)
+			return nil, fmt.Errorf("ошибка сканирования движения: %w", err)
+		}
+		legs = append(legs, movement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка итерации по движениям конвертации: %w", err)
+	}
+	return legs, nil
+}
+
+// SumDebitsSince возвращает сумму списаний (по модулю) начиная с момента since.
+func (r *postgresMovementRepository) SumDebitsSince(ctx context.Context, db DBTX, number string, since time.Time) (float64, error) {
+	query := "SELECT COALESCE(SUM(-amount), 0) FROM wallet_movements WHERE wallet_number = $1 AND amount < 0 AND created_at >= $2"
+	var sum float64
+	if err := db.QueryRowContext(ctx, query, number, since).Scan(&sum); err != nil {
+		log.Printf("Ошибка подсчета списаний по кошельку %s: %v\n", number, err)
+		return 0, fmt.Errorf("ошибка выполнения запроса SELECT (sum debits): %w", err)
+	}
+	return sum, nil
+}
+
+// CountMovementsSince возвращает количество движений вида kind начиная с момента since.
+func (r *postgresMovementRepository) CountMovementsSince(ctx context.Context, db DBTX, number string, kind string, since time.Time) (int, error) {
+	query := "SELECT COUNT(*) FROM wallet_movements WHERE wallet_number = $1 AND kind = $2 AND created_at >= $3"
+	var count int
+	if err := db.QueryRowContext(ctx, query, number, kind, since).Scan(&count); err != nil {
+		log.Printf("Ошибка подсчета движений %s по кошельку %s: %v\n", kind, number, err)
+		return 0, fmt.Errorf("ошибка выполнения запроса SELECT (count movements): %w", err)
+	}
+	return count, nil
+}
+
+// SumMovementsUntil возвращает сумму всех движений по кошельку с моментом создания не позже at,
+// то есть учетный баланс кошелька на момент at.
+func (r *postgresMovementRepository) SumMovementsUntil(ctx context.Context, db DBTX, number string, at time.Time) (float64, error) {
+	query := "SELECT COALESCE(SUM(amount), 0) FROM wallet_movements WHERE wallet_number = $1 AND created_at <= $2"
+	var sum float64
+	if err := db.QueryRowContext(ctx, query, number, at).Scan(&sum); err != nil {
+		log.Printf("Ошибка расчета баланса кошелька %s на %s: %v\n", number, at.Format(time.RFC3339), err)
+		return 0, fmt.Errorf("ошибка выполнения запроса SELECT (sum movements): %w", err)
+	}
+	return sum, nil
+}
+
+// ListWalletMovements возвращает последние limit движений по кошельку, начиная с самого нового.
+func (r *postgresMovementRepository) ListWalletMovements(ctx context.Context, db DBTX, number string, limit int) ([]models.Movement, error) {
+	query := "SELECT " + movementColumns + " FROM wallet_movements WHERE wallet_number = $1 ORDER BY id DESC LIMIT $2"
+	rows, err := db.QueryContext(ctx, query, number, limit)
+	if err != nil {
+		log.Printf("Ошибка получения движений кошелька %s из БД: %v\n", number, err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (movements): %w", err)
+	}
+	defer rows.Close()
+
+	var movements []models.Movement
+	for rows.Next() {
+		movement, err := scanMovement(rows)
+		if err != nil {
+			return movements, fmt.Errorf("ошибка сканирования строки wallet_movements: %w", err)
+		}
+		movements = append(movements, movement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по результатам wallet_movements: %w", err)
+	}
+	return movements, nil
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log" // Используйте структурированный логгер
+	"strconv"
 
 	"currency-service/internal/models"
 )
@@ -89,3 +90,51 @@ func (r *postgresRateRepository) GetLatestRate(ctx context.Context, db DBTX) (mo
 
 	return rate, nil
 }
+
+// StreamRates читает последние limit курсов (0 - все) и передает их в fn по одному.
+func (r *postgresRateRepository) StreamRates(ctx context.Context, db DBTX, limit int, fn func(models.Rate) error) error {
+	query := "SELECT id, value, timestamp FROM rates ORDER BY timestamp DESC, id DESC"
+	var args []interface{}
+	if limit > 0 {
+		query += " LIMIT $1"
+		args = append(args, limit)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("Ошибка выгрузки курсов из БД: %v\n", err)
+		return fmt.Errorf("ошибка выполнения запроса SELECT (rates export): %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rate models.Rate
+		if err := rows.Scan(&rate.ID, &rate.Value, &rate.Timestamp); err != nil {
+			log.Printf("Ошибка сканирования строки результата (rates): %v\n", err)
+			return fmt.Errorf("ошибка сканирования строки rates: %w", err)
+		}
+		if err := fn(rate); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Ошибка итерации по результатам запроса (rates): %v\n", err)
+		return fmt.Errorf("ошибка после итерации по результатам rates: %w", err)
+	}
+	return nil
+}
+
+// GetRatesVersion получает ID самого свежего курса, количество курсов и время самого свежего курса.
+// Курсы только добавляются, поэтому количество меняется при каждом закоммиченном добавлении,
+// даже если курс с меньшим ID закоммичен позже курса с большим.
+func (r *postgresRateRepository) GetRatesVersion(ctx context.Context, db DBTX) (models.DataVersion, error) {
+	query := "SELECT COALESCE(MAX(id), 0), COUNT(*), MAX(timestamp) FROM rates"
+	var latestID, count int64
+	var latestAt sql.NullTime
+	if err := db.QueryRowContext(ctx, query).Scan(&latestID, &count, &latestAt); err != nil {
+		log.Printf("Ошибка получения версии курсов из БД: %v\n", err)
+		return models.DataVersion{}, fmt.Errorf("ошибка выполнения запроса SELECT (rates version): %w", err)
+	}
+	tag := strconv.FormatInt(latestID, 10) + "." + strconv.FormatInt(count, 10)
+	return models.DataVersion{Tag: tag, LastModified: latestAt.Time}, nil
+}
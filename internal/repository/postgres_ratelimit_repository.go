@@ -0,0 +1,65 @@
+// --- internal/repository/postgres_ratelimit_repository.go ---
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"currency-service/internal/models"
+)
+
+type postgresRateLimitRepository struct {
+	// Пустая структура, так как *sql.DB передается в методы
+}
+
+// NewPostgresRateLimitRepository создает новый экземпляр репозитория ведер ограничения частоты запросов.
+func NewPostgresRateLimitRepository() RateLimitRepository {
+	return &postgresRateLimitRepository{}
+}
+
+// LockBucket блокирует ведро до конца транзакции tx, при отсутствии создавая его полным.
+// Время берется из clock_timestamp(), а не now(): оно не должно застывать на начале транзакции.
+func (r *postgresRateLimitRepository) LockBucket(ctx context.Context, tx *sql.Tx, key string, tokens float64) (models.RateLimitBucket, time.Time, error) {
+	insert := "INSERT INTO rate_limit_buckets (key, tokens, updated_at) VALUES ($1, $2, clock_timestamp()) ON CONFLICT (key) DO NOTHING"
+	if _, err := tx.ExecContext(ctx, insert, key, tokens); err != nil {
+		log.Printf("Ошибка создания ведра %s в БД: %v\n", key, err)
+		return models.RateLimitBucket{}, time.Time{}, fmt.Errorf("ошибка выполнения запроса INSERT (rate limit bucket): %w", err)
+	}
+
+	bucket := models.RateLimitBucket{Key: key}
+	var now time.Time
+	query := "SELECT tokens, updated_at, clock_timestamp() FROM rate_limit_buckets WHERE key = $1 FOR UPDATE"
+	if err := tx.QueryRowContext(ctx, query, key).Scan(&bucket.Tokens, &bucket.UpdatedAt, &now); err != nil {
+		log.Printf("Ошибка блокировки ведра %s в БД: %v\n", key, err)
+		return models.RateLimitBucket{}, time.Time{}, fmt.Errorf("ошибка выполнения запроса SELECT FOR UPDATE (rate limit bucket): %w", err)
+	}
+	return bucket, now, nil
+}
+
+// SaveBucket сохраняет число токенов и время последнего списания.
+func (r *postgresRateLimitRepository) SaveBucket(ctx context.Context, db DBTX, bucket models.RateLimitBucket) error {
+	query := "UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1"
+	if _, err := db.ExecContext(ctx, query, bucket.Key, bucket.Tokens, bucket.UpdatedAt); err != nil {
+		log.Printf("Ошибка сохранения ведра %s в БД: %v\n", bucket.Key, err)
+		return fmt.Errorf("ошибка выполнения запроса UPDATE (rate limit bucket): %w", err)
+	}
+	return nil
+}
+
+// DeleteIdleBuckets удаляет ведра, к которым не обращались дольше idle.
+func (r *postgresRateLimitRepository) DeleteIdleBuckets(ctx context.Context, db DBTX, idle time.Duration) (int64, error) {
+	query := "DELETE FROM rate_limit_buckets WHERE updated_at < clock_timestamp() - make_interval(secs => $1)"
+	result, err := db.ExecContext(ctx, query, idle.Seconds())
+	if err != nil {
+		log.Printf("Ошибка удаления неактивных ведер из БД: %v\n", err)
+		return 0, fmt.Errorf("ошибка выполнения запроса DELETE (rate limit buckets): %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения количества удаленных ведер: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,186 @@
+// --- internal/repository/postgres_reconciliation_repository.go ---
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"currency-service/internal/models"
+)
+
+type postgresReconciliationRepository struct {
+	// Пустая структура, так как *sql.DB передается в методы
+}
+
+// NewPostgresReconciliationRepository создает новый экземпляр репозитория сверок.
+func NewPostgresReconciliationRepository() ReconciliationRepository {
+	return &postgresReconciliationRepository{}
+}
+
+// reconciliationRunColumns - список колонок сверки (порядок важен для scanReconciliationRun).
+const reconciliationRunColumns = "id, started_at, finished_at, wallets_checked, discrepancies_found, error"
+
+// scanReconciliationRun читает сверку из строки результата, выбранной по reconciliationRunColumns.
+func scanReconciliationRun(row rowScanner) (models.ReconciliationRun, error) {
+	var run models.ReconciliationRun
+	var finishedAt sql.NullTime
+	if err := row.Scan(&run.ID, &run.StartedAt, &finishedAt, &run.WalletsChecked, &run.DiscrepanciesFound, &run.Error); err != nil {
+		return models.ReconciliationRun{}, err
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	return run, nil
+}
+
+// CountWallets возвращает количество кошельков.
+func (r *postgresReconciliationRepository) CountWallets(ctx context.Context, db DBTX) (int, error) {
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM wallets").Scan(&count); err != nil {
+		log.Printf("Ошибка подсчета кошельков: %v\n", err)
+		return 0, fmt.Errorf("ошибка выполнения запроса SELECT (count wallets): %w", err)
+	}
+	return count, nil
+}
+
+// FindDiscrepancies пересчитывает балансы всех кошельков по движениям одним запросом.
+// Суммы считаются в double precision, чтобы ошибка округления REAL не накапливалась на длинной истории.
+func (r *postgresReconciliationRepository) FindDiscrepancies(ctx context.Context, db DBTX, tolerance float64) ([]models.Discrepancy, error) {
+	query := `
+        SELECT w.wallet_number, w.balance::double precision, COALESCE(SUM(m.amount::double precision), 0)
+        FROM wallets w
+        LEFT JOIN wallet_movements m ON m.wallet_number = w.wallet_number
+        GROUP BY w.wallet_number, w.balance
+        HAVING ABS(w.balance::double precision - COALESCE(SUM(m.amount::double precision), 0)) > $1
+        ORDER BY w.wallet_number`
+	rows, err := db.QueryContext(ctx, query, tolerance)
+	if err != nil {
+		log.Printf("Ошибка сверки балансов в БД: %v\n", err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (reconciliation): %w", err)
+	}
+	defer rows.Close()
+
+	var discrepancies []models.Discrepancy
+	for rows.Next() {
+		var d models.Discrepancy
+		if err := rows.Scan(&d.WalletNumber, &d.StoredBalance, &d.ComputedBalance); err != nil {
+			return discrepancies, fmt.Errorf("ошибка сканирования строки сверки: %w", err)
+		}
+		d.Difference = d.StoredBalance - d.ComputedBalance
+		discrepancies = append(discrepancies, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по результатам сверки: %w", err)
+	}
+	return discrepancies, nil
+}
+
+// CreateRun создает запись о начале сверки.
+func (r *postgresReconciliationRepository) CreateRun(ctx context.Context, db DBTX) (models.ReconciliationRun, error) {
+	query := "INSERT INTO reconciliation_runs DEFAULT VALUES RETURNING " + reconciliationRunColumns
+	run, err := scanReconciliationRun(db.QueryRowContext(ctx, query))
+	if err != nil {
+		log.Printf("Ошибка создания записи о сверке: %v\n", err)
+		return models.ReconciliationRun{}, fmt.Errorf("ошибка выполнения запроса INSERT (reconciliation run): %w", err)
+	}
+	return run, nil
+}
+
+// FinishRun сохраняет итоги сверки и время ее завершения.
+func (r *postgresReconciliationRepository) FinishRun(ctx context.Context, db DBTX, run models.ReconciliationRun) error {
+	query := `UPDATE reconciliation_runs SET finished_at = $1, wallets_checked = $2, discrepancies_found = $3, error = $4
+        WHERE id = $5`
+	if _, err := db.ExecContext(ctx, query, run.FinishedAt, run.WalletsChecked, run.DiscrepanciesFound, run.Error, run.ID); err != nil {
+		log.Printf("Ошибка сохранения итогов сверки %d: %v\n", run.ID, err)
+		return fmt.Errorf("ошибка выполнения запроса UPDATE (reconciliation run): %w", err)
+	}
+	return nil
+}
+
+// CreateDiscrepancy сохраняет найденное расхождение.
+func (r *postgresReconciliationRepository) CreateDiscrepancy(ctx context.Context, db DBTX, d models.Discrepancy) error {
+	query := `INSERT INTO reconciliation_discrepancies (run_id, wallet_number, stored_balance, computed_balance, difference)
+        VALUES ($1, $2, $3, $4, $5)`
+	if _, err := db.ExecContext(ctx, query, d.RunID, d.WalletNumber, d.StoredBalance, d.ComputedBalance, d.Difference); err != nil {
+		log.Printf("Ошибка сохранения расхождения по кошельку %s: %v\n", d.WalletNumber, err)
+		return fmt.Errorf("ошибка выполнения запроса INSERT (discrepancy): %w", err)
+	}
+	return nil
+}
+
+// GetRunByID находит сверку по ID.
+func (r *postgresReconciliationRepository) GetRunByID(ctx context.Context, db DBTX, id int64) (models.ReconciliationRun, error) {
+	query := "SELECT " + reconciliationRunColumns + " FROM reconciliation_runs WHERE id = $1"
+	run, err := scanReconciliationRun(db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения сверки %d из БД: %v\n", id, err)
+		}
+		return models.ReconciliationRun{}, err
+	}
+	return run, nil
+}
+
+// GetLatestRun возвращает последнюю сверку.
+func (r *postgresReconciliationRepository) GetLatestRun(ctx context.Context, db DBTX) (models.ReconciliationRun, error) {
+	query := "SELECT " + reconciliationRunColumns + " FROM reconciliation_runs ORDER BY id DESC LIMIT 1"
+	run, err := scanReconciliationRun(db.QueryRowContext(ctx, query))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения последней сверки из БД: %v\n", err)
+		}
+		return models.ReconciliationRun{}, err
+	}
+	return run, nil
+}
+
+// ListRuns возвращает последние limit сверок.
+func (r *postgresReconciliationRepository) ListRuns(ctx context.Context, db DBTX, limit int) ([]models.ReconciliationRun, error) {
+	query := "SELECT " + reconciliationRunColumns + " FROM reconciliation_runs ORDER BY id DESC LIMIT $1"
+	rows, err := db.QueryContext(ctx, query, limit)
+	if err != nil {
+		log.Printf("Ошибка получения списка сверок из БД: %v\n", err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (reconciliation runs): %w", err)
+	}
+	defer rows.Close()
+
+	var runs []models.ReconciliationRun
+	for rows.Next() {
+		run, err := scanReconciliationRun(rows)
+		if err != nil {
+			return runs, fmt.Errorf("ошибка сканирования строки reconciliation_runs: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по результатам reconciliation_runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetRunDiscrepancies возвращает расхождения, найденные сверкой.
+func (r *postgresReconciliationRepository) GetRunDiscrepancies(ctx context.Context, db DBTX, runID int64) ([]models.Discrepancy, error) {
+	query := `SELECT id, run_id, wallet_number, stored_balance, computed_balance, difference, detected_at
+        FROM reconciliation_discrepancies WHERE run_id = $1 ORDER BY wallet_number`
+	rows, err := db.QueryContext(ctx, query, runID)
+	if err != nil {
+		log.Printf("Ошибка получения расхождений сверки %d из БД: %v\n", runID, err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (discrepancies): %w", err)
+	}
+	defer rows.Close()
+
+	var discrepancies []models.Discrepancy
+	for rows.Next() {
+		var d models.Discrepancy
+		if err := rows.Scan(&d.ID, &d.RunID, &d.WalletNumber, &d.StoredBalance, &d.ComputedBalance, &d.Difference, &d.DetectedAt); err != nil {
+			return discrepancies, fmt.Errorf("ошибка сканирования строки reconciliation_discrepancies: %w", err)
+		}
+		discrepancies = append(discrepancies, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по результатам reconciliation_discrepancies: %w", err)
+	}
+	return discrepancies, nil
+}
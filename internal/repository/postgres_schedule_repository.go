@@ -0,0 +1,184 @@
+// --- internal/repository/postgres_schedule_repository.go ---
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"currency-service/internal/models"
+)
+
+type postgresScheduleRepository struct {
+	// Пустая структура, так как *sql.DB передается в методы
+}
+
+// NewPostgresScheduleRepository создает новый экземпляр репозитория расписаний.
+func NewPostgresScheduleRepository() ScheduleRepository {
+	return &postgresScheduleRepository{}
+}
+
+// scheduleColumns - список колонок, которые читаются при выборке расписания (порядок важен для scanSchedule).
+const scheduleColumns = `id, operation, wallet_number, target_wallet_number, amount, user_id, recurrence, cron_expr,
+    start_at, next_run_at, last_run_at, status, consecutive_failures, disabled_reason, created_by, created_by_staff, created_at, updated_at`
+
+// scanSchedule читает расписание из строки результата, выбранной по scheduleColumns.
+func scanSchedule(row rowScanner) (models.Schedule, error) {
+	var schedule models.Schedule
+	var nextRunAt, lastRunAt sql.NullTime
+	err := row.Scan(&schedule.ID, &schedule.Operation, &schedule.WalletNumber, &schedule.TargetWalletNumber, &schedule.Amount,
+		&schedule.UserID, &schedule.Recurrence, &schedule.CronExpr, &schedule.StartAt, &nextRunAt, &lastRunAt,
+		&schedule.Status, &schedule.ConsecutiveFailures, &schedule.DisabledReason, &schedule.CreatedBy, &schedule.CreatedByStaff,
+		&schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	if nextRunAt.Valid {
+		schedule.NextRunAt = &nextRunAt.Time
+	}
+	if lastRunAt.Valid {
+		schedule.LastRunAt = &lastRunAt.Time
+	}
+	return schedule, nil
+}
+
+// CreateSchedule создает расписание. ID и временные метки заполняет БД.
+func (r *postgresScheduleRepository) CreateSchedule(ctx context.Context, db DBTX, schedule models.Schedule) (models.Schedule, error) {
+	query := `INSERT INTO wallet_schedules (operation, wallet_number, target_wallet_number, amount, user_id, recurrence, cron_expr,
+            start_at, next_run_at, status, created_by, created_by_staff)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING ` + scheduleColumns
+	row := db.QueryRowContext(ctx, query, schedule.Operation, schedule.WalletNumber, schedule.TargetWalletNumber, schedule.Amount,
+		schedule.UserID, schedule.Recurrence, schedule.CronExpr, schedule.StartAt, schedule.NextRunAt, schedule.Status,
+		schedule.CreatedBy, schedule.CreatedByStaff)
+
+	created, err := scanSchedule(row)
+	if err != nil {
+		log.Printf("Ошибка создания расписания для кошелька %s в БД: %v\n", schedule.WalletNumber, err)
+		return models.Schedule{}, fmt.Errorf("ошибка выполнения запроса INSERT (schedule): %w", err)
+	}
+	log.Printf("Расписание %d (%s, %s) создано для кошелька %s\n", created.ID, created.Operation, created.Recurrence, created.WalletNumber)
+	return created, nil
+}
+
+// GetScheduleByID находит расписание по ID.
+func (r *postgresScheduleRepository) GetScheduleByID(ctx context.Context, db DBTX, id int64) (models.Schedule, error) {
+	query := "SELECT " + scheduleColumns + " FROM wallet_schedules WHERE id = $1"
+	schedule, err := scanSchedule(db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		// Ошибку sql.ErrNoRows обрабатываем в сервисе
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения расписания %d из БД: %v\n", id, err)
+		}
+		return models.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// GetScheduleByIDForUpdate находит расписание по ID с блокировкой строки (ДЛЯ ТРАНЗАКЦИЙ).
+func (r *postgresScheduleRepository) GetScheduleByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (models.Schedule, error) {
+	query := "SELECT " + scheduleColumns + " FROM wallet_schedules WHERE id = $1 FOR UPDATE"
+	schedule, err := scanSchedule(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения расписания %d из БД (FOR UPDATE): %v\n", id, err)
+		}
+		return models.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// ListDueScheduleIDs возвращает ID активных расписаний, время запуска которых наступило.
+func (r *postgresScheduleRepository) ListDueScheduleIDs(ctx context.Context, db DBTX, limit int) ([]int64, error) {
+	query := "SELECT id FROM wallet_schedules WHERE status = $1 AND next_run_at <= NOW() ORDER BY next_run_at ASC LIMIT $2"
+	rows, err := db.QueryContext(ctx, query, models.ScheduleStatusActive, limit)
+	if err != nil {
+		log.Printf("Ошибка получения готовых к запуску расписаний из БД: %v\n", err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (due schedules): %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return ids, fmt.Errorf("ошибка сканирования строки wallet_schedules: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по результатам wallet_schedules: %w", err)
+	}
+	return ids, nil
+}
+
+// LockDueSchedule блокирует расписание, если оно все еще готово к запуску (SKIP LOCKED).
+func (r *postgresScheduleRepository) LockDueSchedule(ctx context.Context, tx *sql.Tx, id int64) (models.Schedule, error) {
+	query := "SELECT " + scheduleColumns + ` FROM wallet_schedules
+        WHERE id = $1 AND status = $2 AND next_run_at <= NOW() FOR UPDATE SKIP LOCKED`
+	schedule, err := scanSchedule(tx.QueryRowContext(ctx, query, id, models.ScheduleStatusActive))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка блокировки расписания %d: %v\n", id, err)
+		}
+		return models.Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// UpdateScheduleState сохраняет время запусков, статус и счетчик ошибок расписания.
+func (r *postgresScheduleRepository) UpdateScheduleState(ctx context.Context, db DBTX, schedule models.Schedule) error {
+	query := `UPDATE wallet_schedules SET next_run_at = $1, last_run_at = $2, status = $3,
+            consecutive_failures = $4, disabled_reason = $5
+        WHERE id = $6`
+	result, err := db.ExecContext(ctx, query, schedule.NextRunAt, schedule.LastRunAt, schedule.Status,
+		schedule.ConsecutiveFailures, schedule.DisabledReason, schedule.ID)
+	if err != nil {
+		log.Printf("Ошибка обновления расписания %d в БД: %v\n", schedule.ID, err)
+		return fmt.Errorf("ошибка выполнения запроса UPDATE (schedule): %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка проверки результата UPDATE: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CreateScheduleRun записывает результат запуска расписания.
+func (r *postgresScheduleRepository) CreateScheduleRun(ctx context.Context, db DBTX, run models.ScheduleRun) error {
+	query := "INSERT INTO wallet_schedule_runs (schedule_id, status, error) VALUES ($1, $2, $3)"
+	if _, err := db.ExecContext(ctx, query, run.ScheduleID, run.Status, run.Error); err != nil {
+		log.Printf("Ошибка записи запуска расписания %d в БД: %v\n", run.ScheduleID, err)
+		return fmt.Errorf("ошибка выполнения запроса INSERT (schedule run): %w", err)
+	}
+	return nil
+}
+
+// GetScheduleRuns возвращает историю запусков расписания (новые первыми).
+func (r *postgresScheduleRepository) GetScheduleRuns(ctx context.Context, db DBTX, scheduleID int64) ([]models.ScheduleRun, error) {
+	query := "SELECT id, schedule_id, status, error, ran_at FROM wallet_schedule_runs WHERE schedule_id = $1 ORDER BY ran_at DESC, id DESC"
+	rows, err := db.QueryContext(ctx, query, scheduleID)
+	if err != nil {
+		log.Printf("Ошибка получения запусков расписания %d из БД: %v\n", scheduleID, err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (schedule runs): %w", err)
+	}
+	defer rows.Close()
+
+	var runs []models.ScheduleRun
+	for rows.Next() {
+		var run models.ScheduleRun
+		if err := rows.Scan(&run.ID, &run.ScheduleID, &run.Status, &run.Error, &run.RanAt); err != nil {
+			log.Printf("Ошибка сканирования строки результата (schedule runs): %v\n", err)
+			return runs, fmt.Errorf("ошибка сканирования строки wallet_schedule_runs: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка после итерации по результатам wallet_schedule_runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,49 @@
+// --- internal/repository/postgres_user_repository.go ---
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"currency-service/internal/models"
+)
+
+type postgresUserRepository struct {
+	// Пустая структура, так как *sql.DB передается в методы
+}
+
+// NewPostgresUserRepository создает новый экземпляр репозитория пользователей.
+func NewPostgresUserRepository() UserRepository {
+	return &postgresUserRepository{}
+}
+
+// CreateUser создает пользователя. ID и время создания заполняет БД.
+func (r *postgresUserRepository) CreateUser(ctx context.Context, db DBTX, user models.User) (models.User, error) {
+	query := "INSERT INTO users (first_name, last_name) VALUES ($1, $2) RETURNING id, created_at"
+	err := db.QueryRowContext(ctx, query, user.FirstName, user.LastName).Scan(&user.ID, &user.CreatedAt)
+	if err != nil {
+		log.Printf("Ошибка создания пользователя в БД: %v\n", err)
+		return models.User{}, fmt.Errorf("ошибка выполнения запроса INSERT (user): %w", err)
+	}
+	log.Printf("Пользователь %s успешно создан\n", user.ID)
+	return user, nil
+}
+
+// GetUserByID находит пользователя по ID.
+func (r *postgresUserRepository) GetUserByID(ctx context.Context, db DBTX, id string) (models.User, error) {
+	query := "SELECT id, first_name, last_name, created_at FROM users WHERE id = $1"
+	row := db.QueryRowContext(ctx, query, id)
+
+	var user models.User
+	err := row.Scan(&user.ID, &user.FirstName, &user.LastName, &user.CreatedAt)
+	if err != nil {
+		// Ошибку sql.ErrNoRows обрабатываем в сервисе
+		if err != sql.ErrNoRows {
+			log.Printf("Ошибка получения пользователя %s из БД: %v\n", id, err)
+		}
+		return models.User{}, err
+	}
+	return user, nil
+}
@@ -7,12 +7,19 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
+	"currency-service/internal/apperrors"
 	"currency-service/internal/models"
 
 	"github.com/lib/pq" // Для обработки ошибок PostgreSQL (например, unique_violation)
 )
 
+// ErrWalletExists возвращается CreateWallet, если кошелек с таким номером уже есть
+// (в том числе когда его успел создать параллельный запрос).
+var ErrWalletExists = apperrors.New(apperrors.CodeWalletExists, "кошелек с таким номером уже существует")
+
 type postgresWalletRepository struct {
 	// Пустая структура, так как *sql.DB передается в методы
 }
@@ -22,13 +29,40 @@ func NewPostgresWalletRepository() WalletRepository {
 	return &postgresWalletRepository{}
 }
 
+// walletColumns - список колонок, которые читаются при выборке кошелька (порядок важен для scanWallet).
+// Доступный баланс вычисляется как учетный баланс минус сумма активных и не истекших холдов.
+const walletColumns = `wallet_number, balance,
+    balance - COALESCE((SELECT SUM(h.amount) FROM wallet_holds h
+        WHERE h.wallet_number = wallets.wallet_number AND h.status = 'active' AND h.expires_at > NOW()), 0),
+    credit_limit, owner_id, tier, status, version, created_at, updated_at`
+
+// rowScanner позволяет сканировать как *sql.Row, так и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanWallet читает кошелек из строки результата, выбранной по walletColumns.
+func scanWallet(row rowScanner) (models.Wallet, error) {
+	var wallet models.Wallet
+	var ownerID sql.NullString // owner_id может быть NULL у кошельков без владельца
+	if err := row.Scan(&wallet.Number, &wallet.Balance, &wallet.AvailableBalance, &wallet.CreditLimit, &ownerID, &wallet.Tier, &wallet.Status, &wallet.Version, &wallet.CreatedAt, &wallet.UpdatedAt); err != nil {
+		return models.Wallet{}, err
+	}
+	wallet.OwnerID = ownerID.String
+	// Кредит расходуется, когда доступный баланс уходит ниже нуля
+	wallet.AvailableCredit = wallet.CreditLimit
+	if wallet.AvailableBalance < 0 {
+		wallet.AvailableCredit = max(wallet.CreditLimit+wallet.AvailableBalance, 0)
+	}
+	return wallet, nil
+}
+
 // GetWalletByNumber находит кошелек по номеру.
 func (r *postgresWalletRepository) GetWalletByNumber(ctx context.Context, db DBTX, number string) (models.Wallet, error) {
-	query := "SELECT wallet_number, balance, created_at, updated_at FROM wallets WHERE wallet_number = $1"
+	query := "SELECT " + walletColumns + " FROM wallets WHERE wallet_number = $1"
 	row := db.QueryRowContext(ctx, query, number)
 
-	var wallet models.Wallet
-	err := row.Scan(&wallet.Number, &wallet.Balance, &wallet.CreatedAt, &wallet.UpdatedAt)
+	wallet, err := scanWallet(row)
 	if err != nil {
 		// Ошибку sql.ErrNoRows обрабатываем в сервисе
 		if err != sql.ErrNoRows {
@@ -41,11 +75,10 @@ func (r *postgresWalletRepository) GetWalletByNumber(ctx context.Context, db DBT
 
 // GetWalletByNumberForUpdate находит кошелек по номеру с блокировкой строки (ДЛЯ ТРАНЗАКЦИЙ).
 func (r *postgresWalletRepository) GetWalletByNumberForUpdate(ctx context.Context, tx *sql.Tx, number string) (models.Wallet, error) {
-	query := "SELECT wallet_number, balance, created_at, updated_at FROM wallets WHERE wallet_number = $1 FOR UPDATE"
+	query := "SELECT " + walletColumns + " FROM wallets WHERE wallet_number = $1 FOR UPDATE"
 	row := tx.QueryRowContext(ctx, query, number) // Используем транзакцию tx
 
-	var wallet models.Wallet
-	err := row.Scan(&wallet.Number, &wallet.Balance, &wallet.CreatedAt, &wallet.UpdatedAt)
+	wallet, err := scanWallet(row)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			log.Printf("Ошибка получения кошелька %s из БД (FOR UPDATE): %v\n", number, err)
@@ -55,27 +88,178 @@ func (r *postgresWalletRepository) GetWalletByNumberForUpdate(ctx context.Contex
 	return wallet, nil
 }
 
-// GetAllWallets получает все кошельки.
-func (r *postgresWalletRepository) GetAllWallets(ctx context.Context, db DBTX) ([]models.Wallet, error) {
-	query := "SELECT wallet_number, balance, created_at, updated_at FROM wallets ORDER BY created_at ASC"
-	rows, err := db.QueryContext(ctx, query)
+// walletSortColumns - допустимые ключи сортировки и соответствующие им колонки (защита от SQL-инъекций).
+var walletSortColumns = map[string]string{
+	models.WalletSortCreatedAt: "created_at",
+	models.WalletSortBalance:   "balance",
+	models.WalletSortNumber:    "wallet_number",
+}
+
+// ListWallets получает страницу кошельков. Фильтры, сортировка и курсор применяются в SQL (keyset-пагинация):
+// следующая страница начинается строго после пары (ключ сортировки, номер кошелька) из курсора.
+func (r *postgresWalletRepository) ListWallets(ctx context.Context, db DBTX, filter models.ListWalletsFilter, after *models.WalletCursor) ([]models.Wallet, error) {
+	query, args, err := listWalletsQuery(filter, after)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Printf("Ошибка получения списка кошельков из БД: %v\n", err)
-		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (all wallets): %w", err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (wallets list): %w", err)
+	}
+	return collectWallets(rows)
+}
+
+// StreamWallets читает кошельки так же, как ListWallets, и передает их в fn по одному.
+func (r *postgresWalletRepository) StreamWallets(ctx context.Context, db DBTX, filter models.ListWalletsFilter, after *models.WalletCursor, fn func(models.Wallet) error) error {
+	query, args, err := listWalletsQuery(filter, after)
+	if err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("Ошибка выгрузки кошельков из БД: %v\n", err)
+		return fmt.Errorf("ошибка выполнения запроса SELECT (wallets export): %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		wallet, err := scanWallet(rows)
+		if err != nil {
+			log.Printf("Ошибка сканирования строки результата (wallets): %v\n", err)
+			return fmt.Errorf("ошибка сканирования строки wallets: %w", err)
+		}
+		if err := fn(wallet); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("Ошибка итерации по результатам запроса (wallets): %v\n", err)
+		return fmt.Errorf("ошибка после итерации по результатам wallets: %w", err)
+	}
+	return nil
+}
+
+// listWalletsQuery строит запрос списка кошельков с фильтрами, сортировкой и курсором.
+// filter.Limit = 0 - без LIMIT.
+func listWalletsQuery(filter models.ListWalletsFilter, after *models.WalletCursor) (string, []interface{}, error) {
+	sortColumn, ok := walletSortColumns[filter.SortBy]
+	if !ok {
+		return "", nil, fmt.Errorf("неизвестный ключ сортировки кошельков: %q", filter.SortBy)
+	}
+	direction, comparison := "ASC", ">"
+	if filter.Order == models.SortOrderDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	var conditions []string
+	var args []interface{}
+	addCondition := func(format string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(format, placeholders...))
+	}
+
+	if filter.MinBalance != nil {
+		addCondition("balance >= $%d", *filter.MinBalance)
+	}
+	if filter.MaxBalance != nil {
+		addCondition("balance <= $%d", *filter.MaxBalance)
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	}
+	if filter.CreatedFrom != nil {
+		addCondition("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCondition("created_at < $%d", *filter.CreatedTo)
+	}
+	if after != nil {
+		switch filter.SortBy {
+		case models.WalletSortBalance:
+			addCondition("(balance, wallet_number) "+comparison+" ($%d, $%d)", after.Balance, after.Number)
+		case models.WalletSortCreatedAt:
+			addCondition("(created_at, wallet_number) "+comparison+" ($%d, $%d)", after.CreatedAt, after.Number)
+		default:
+			addCondition("wallet_number "+comparison+" $%d", after.Number)
+		}
+	}
+
+	query := "SELECT " + walletColumns + " FROM wallets"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if sortColumn == "wallet_number" {
+		query += " ORDER BY wallet_number " + direction
+	} else {
+		query += fmt.Sprintf(" ORDER BY %s %s, wallet_number %s", sortColumn, direction, direction)
 	}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	return query, args, nil
+}
+
+// GetWalletsVersion получает состояние кошельков и холдов. Каждое изменение кошелька увеличивает его version
+// (триггер increment_wallets_version), каждый новый холд - количество холдов, а каждое завершение холда уменьшает
+// количество активных, поэтому тег меняется при любом закоммиченном изменении независимо от порядка коммитов
+// (в отличие от updated_at, равного времени начала транзакции). Истекший, но еще не освобожденный
+// холд учитывается по времени истечения: с этого момента он уже не уменьшает доступный баланс, хотя записи не было.
+func (r *postgresWalletRepository) GetWalletsVersion(ctx context.Context, db DBTX) (models.DataVersion, error) {
+	query := `SELECT w.count, w.versions, h.count, h.active, GREATEST(w.updated_at, h.updated_at), h.expired_at
+        FROM (SELECT COUNT(*) AS count, COALESCE(SUM(version), 0) AS versions, MAX(updated_at) AS updated_at FROM wallets) w,
+             (SELECT COUNT(*) AS count, COUNT(*) FILTER (WHERE status = 'active') AS active, MAX(updated_at) AS updated_at,
+                     MAX(expires_at) FILTER (WHERE status = 'active' AND expires_at <= NOW()) AS expired_at
+              FROM wallet_holds) h`
+	var wallets, versions, holds, activeHolds int64
+	var updatedAt, expiredAt sql.NullTime
+	if err := db.QueryRowContext(ctx, query).Scan(&wallets, &versions, &holds, &activeHolds, &updatedAt, &expiredAt); err != nil {
+		log.Printf("Ошибка получения версии списка кошельков из БД: %v\n", err)
+		return models.DataVersion{}, fmt.Errorf("ошибка выполнения запроса SELECT (wallets version): %w", err)
+	}
+	tag := fmt.Sprintf("%d.%d.%d.%d", wallets, versions, holds, activeHolds)
+	version := models.DataVersion{Tag: tag, LastModified: updatedAt.Time}
+	if expiredAt.Valid {
+		version.Tag += "." + strconv.FormatInt(expiredAt.Time.UnixMicro(), 10)
+		if expiredAt.Time.After(version.LastModified) {
+			version.LastModified = expiredAt.Time
+		}
+	}
+	return version, nil
+}
+
+// GetWalletsByOwner получает все кошельки указанного пользователя.
+func (r *postgresWalletRepository) GetWalletsByOwner(ctx context.Context, db DBTX, ownerID string) ([]models.Wallet, error) {
+	query := "SELECT " + walletColumns + " FROM wallets WHERE owner_id = $1 ORDER BY created_at ASC"
+	rows, err := db.QueryContext(ctx, query, ownerID)
+	if err != nil {
+		log.Printf("Ошибка получения кошельков пользователя %s из БД: %v\n", ownerID, err)
+		return nil, fmt.Errorf("ошибка выполнения запроса SELECT (wallets by owner): %w", err)
+	}
+	return collectWallets(rows)
+}
+
+// collectWallets читает все строки результата в срез кошельков и закрывает rows.
+func collectWallets(rows *sql.Rows) ([]models.Wallet, error) {
 	defer rows.Close()
 
 	var wallets []models.Wallet
 	for rows.Next() {
-		var wallet models.Wallet
-		if err := rows.Scan(&wallet.Number, &wallet.Balance, &wallet.CreatedAt, &wallet.UpdatedAt); err != nil {
+		wallet, err := scanWallet(rows)
+		if err != nil {
 			log.Printf("Ошибка сканирования строки результата (wallets): %v\n", err)
 			return wallets, fmt.Errorf("ошибка сканирования строки wallets: %w", err)
 		}
 		wallets = append(wallets, wallet)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		log.Printf("Ошибка итерации по результатам запроса (wallets): %v\n", err)
 		return nil, fmt.Errorf("ошибка после итерации по результатам wallets: %w", err)
 	}
@@ -85,13 +269,14 @@ func (r *postgresWalletRepository) GetAllWallets(ctx context.Context, db DBTX) (
 
 // CreateWallet создает новый кошелек.
 func (r *postgresWalletRepository) CreateWallet(ctx context.Context, db DBTX, wallet models.Wallet) error {
-	query := "INSERT INTO wallets (wallet_number, balance) VALUES ($1, $2)"
-	_, err := db.ExecContext(ctx, query, wallet.Number, wallet.Balance)
+	query := "INSERT INTO wallets (wallet_number, balance, owner_id) VALUES ($1, $2, $3)"
+	ownerID := sql.NullString{String: wallet.OwnerID, Valid: wallet.OwnerID != ""}
+	_, err := db.ExecContext(ctx, query, wallet.Number, wallet.Balance, ownerID)
 	if err != nil {
 		// Проверка на ошибку уникальности (если кошелек уже существует)
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // 23505 - unique_violation
 			log.Printf("Попытка создать дублирующийся кошелек: %s\n", wallet.Number)
-			return fmt.Errorf("кошелек с номером %s уже существует", wallet.Number) // Возвращаем специфичную ошибку
+			return fmt.Errorf("кошелек %s: %w", wallet.Number, ErrWalletExists) // Возвращаем специфичную ошибку
 		}
 		log.Printf("Ошибка создания кошелька %s в БД: %v\n", wallet.Number, err)
 		return fmt.Errorf("ошибка выполнения запроса INSERT (wallet): %w", err)
@@ -100,6 +285,17 @@ func (r *postgresWalletRepository) CreateWallet(ctx context.Context, db DBTX, wa
 	return nil
 }
 
+// EnsureWallet создает пустой кошелек, если его нет.
+// ON CONFLICT DO NOTHING дожидается параллельной вставки того же номера и не возвращает ошибку.
+func (r *postgresWalletRepository) EnsureWallet(ctx context.Context, db DBTX, number string) error {
+	query := "INSERT INTO wallets (wallet_number, balance) VALUES ($1, 0) ON CONFLICT (wallet_number) DO NOTHING"
+	if _, err := db.ExecContext(ctx, query, number); err != nil {
+		log.Printf("Ошибка создания кошелька %s в БД: %v\n", number, err)
+		return fmt.Errorf("ошибка выполнения запроса INSERT (ensure wallet): %w", err)
+	}
+	return nil
+}
+
 // UpdateWalletBalance обновляет баланс кошелька. Должен вызываться внутри транзакции.
 func (r *postgresWalletRepository) UpdateWalletBalance(ctx context.Context, db DBTX, number string, newBalance float64) error {
 	// Используем db (который должен быть *sql.Tx в этом контексте)
@@ -124,3 +320,60 @@ func (r *postgresWalletRepository) UpdateWalletBalance(ctx context.Context, db D
 	log.Printf("Баланс кошелька %s успешно обновлен на %.2f\n", number, newBalance)
 	return nil
 }
+
+// UpdateCreditLimit меняет кредитный лимит кошелька.
+func (r *postgresWalletRepository) UpdateCreditLimit(ctx context.Context, db DBTX, number string, limit float64) error {
+	query := "UPDATE wallets SET credit_limit = $1 WHERE wallet_number = $2"
+	result, err := db.ExecContext(ctx, query, limit, number)
+	if err != nil {
+		log.Printf("Ошибка обновления кредитного лимита кошелька %s в БД: %v\n", number, err)
+		return fmt.Errorf("ошибка выполнения запроса UPDATE (wallet credit limit): %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка проверки результата UPDATE: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UpdateWalletStatus меняет статус кошелька.
+func (r *postgresWalletRepository) UpdateWalletStatus(ctx context.Context, db DBTX, number, status string) error {
+	query := "UPDATE wallets SET status = $1 WHERE wallet_number = $2"
+	result, err := db.ExecContext(ctx, query, status, number)
+	if err != nil {
+		log.Printf("Ошибка обновления статуса кошелька %s в БД: %v\n", number, err)
+		return fmt.Errorf("ошибка выполнения запроса UPDATE (wallet status): %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка проверки результата UPDATE: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UpdateWalletTier меняет уровень кошелька.
+func (r *postgresWalletRepository) UpdateWalletTier(ctx context.Context, db DBTX, number string, tier string) error {
+	query := "UPDATE wallets SET tier = $1 WHERE wallet_number = $2"
+	result, err := db.ExecContext(ctx, query, tier, number)
+	if err != nil {
+		log.Printf("Ошибка обновления уровня кошелька %s в БД: %v\n", number, err)
+		return fmt.Errorf("ошибка выполнения запроса UPDATE (wallet tier): %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка проверки результата UPDATE: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
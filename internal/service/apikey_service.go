@@ -0,0 +1,162 @@
+// --- internal/service/apikey_service.go ---
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+)
+
+// Ошибки, связанные с API-ключами
+var (
+	ErrUnauthorized       = apperrors.New(apperrors.CodeUnauthorized, "требуется действительный API-ключ")
+	ErrInsufficientScope  = apperrors.New(apperrors.CodeInsufficientScope, "у API-ключа нет нужного права")
+	ErrAPIKeyNotFound     = apperrors.New(apperrors.CodeAPIKeyNotFound, "API-ключ не найден")
+	ErrInvalidAPIKeyName  = apperrors.New(apperrors.CodeInvalidAPIKeyName, "имя API-ключа должно быть от 1 до 64 символов")
+	ErrInvalidAPIKeyScope = apperrors.New(apperrors.CodeInvalidScope, "неизвестное право API-ключа (допустимы rates:write, wallets:read, wallets:write, admin)")
+)
+
+const (
+	// apiKeyTokenPrefix - начало каждого ключа: по нему ключ легко найти в логах и конфигурации
+	// и отличить от других значений заголовка Authorization.
+	apiKeyTokenPrefix = "csk_"
+	// apiKeySecretBytes - число случайных байт ключа.
+	apiKeySecretBytes = 32
+	// apiKeyDisplayLength - сколько первых символов ключа хранится открыто для опознания.
+	apiKeyDisplayLength = len(apiKeyTokenPrefix) + 8
+)
+
+// IsAPIKeyToken сообщает, похоже ли значение на API-ключ этого сервиса.
+func IsAPIKeyToken(token string) bool {
+	return strings.HasPrefix(token, apiKeyTokenPrefix)
+}
+
+// hashAPIKey возвращает хэш ключа для хранения и поиска. Ключ - случайная строка большой длины,
+// поэтому медленные хэш-функции для паролей не нужны.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateAPIKey создает новый случайный ключ.
+func generateAPIKey() (string, error) {
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("не удалось сгенерировать API-ключ: %w", err)
+	}
+	return apiKeyTokenPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// normalizeScopes проверяет права и убирает повторы, сохраняя порядок.
+func normalizeScopes(scopes []string) ([]string, error) {
+	normalized := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		if !isKnownScope(scope) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAPIKeyScope, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
+func isKnownScope(scope string) bool {
+	for _, known := range models.APIKeyScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	db         *sql.DB
+}
+
+// NewAPIKeyService создает новый экземпляр сервиса API-ключей.
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, db *sql.DB) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		db:         db,
+	}
+}
+
+// IssueAPIKey выпускает ключ с указанными правами. В БД сохраняется только хэш ключа.
+func (s *apiKeyService) IssueAPIKey(ctx context.Context, req models.IssueAPIKeyRequest) (models.IssueAPIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > 64 {
+		return models.IssueAPIKeyResponse{}, ErrInvalidAPIKeyName
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return models.IssueAPIKeyResponse{}, err
+	}
+
+	key, err := generateAPIKey()
+	if err != nil {
+		return models.IssueAPIKeyResponse{}, err
+	}
+	saved, err := s.apiKeyRepo.CreateAPIKey(ctx, s.db, models.APIKey{
+		Name:   name,
+		Prefix: key[:apiKeyDisplayLength],
+		Scopes: scopes,
+	}, hashAPIKey(key))
+	if err != nil {
+		return models.IssueAPIKeyResponse{}, fmt.Errorf("не удалось сохранить API-ключ: %w", err)
+	}
+	return models.IssueAPIKeyResponse{APIKey: saved, Key: key}, nil
+}
+
+// ListAPIKeys возвращает все ключи, включая отозванные.
+func (s *apiKeyService) ListAPIKeys(ctx context.Context) (models.ListAPIKeysResponse, error) {
+	keys, err := s.apiKeyRepo.ListAPIKeys(ctx, s.db)
+	if err != nil {
+		return models.ListAPIKeysResponse{}, fmt.Errorf("не удалось получить API-ключи: %w", err)
+	}
+	if keys == nil {
+		keys = []models.APIKey{}
+	}
+	return models.ListAPIKeysResponse{Keys: keys}, nil
+}
+
+// RevokeAPIKey отзывает ключ. Отзыв уже отозванного ключа не является ошибкой.
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, id int64) (models.APIKey, error) {
+	key, err := s.apiKeyRepo.RevokeAPIKey(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.APIKey{}, ErrAPIKeyNotFound
+		}
+		return models.APIKey{}, fmt.Errorf("не удалось отозвать API-ключ: %w", err)
+	}
+	return key, nil
+}
+
+// Authenticate возвращает действующий ключ по его значению или ErrUnauthorized.
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (models.APIKey, error) {
+	if !IsAPIKeyToken(key) {
+		return models.APIKey{}, ErrUnauthorized
+	}
+	found, err := s.apiKeyRepo.GetActiveAPIKeyByHash(ctx, s.db, hashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.APIKey{}, ErrUnauthorized
+		}
+		return models.APIKey{}, fmt.Errorf("не удалось проверить API-ключ: %w", err)
+	}
+	return found, nil
+}
@@ -0,0 +1,120 @@
+// --- internal/service/fee_service.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+)
+
+// pairRegex - валютная пара в виде BASE/QUOTE (например, USD/RUB).
+var pairRegex = regexp.MustCompile(`^[A-Z]{3}/[A-Z]{3}$`)
+
+// Ошибки, связанные с комиссиями
+var (
+	ErrFeeRuleNotFound = apperrors.New(apperrors.CodeFeeRuleNotFound, "правило комиссии не найдено")
+	ErrInvalidPair     = apperrors.New(apperrors.CodeInvalidPair, "некорректная валютная пара (ожидается BASE/QUOTE, например USD/RUB, или *)")
+	ErrInvalidFeeRule  = apperrors.New(apperrors.CodeInvalidFeeRule, "некорректное правило комиссии: процент должен быть от 0 до 100, суммы - неотрицательными, min_fee не больше max_fee")
+)
+
+// ConversionSettings - настройки конвертации.
+type ConversionSettings struct {
+	Pair            string // Валютная пара курса (курсы в rates относятся к ней)
+	FeeWalletNumber string // Кошелек, на который зачисляются комиссии (пусто - комиссии не взимаются)
+}
+
+// Validate проверяет пару и номер кошелька комиссий.
+func (c ConversionSettings) Validate() error {
+	if !pairRegex.MatchString(c.Pair) {
+		return ErrInvalidPair
+	}
+	if c.FeeWalletNumber != "" {
+		if err := validateWalletNumber(c.FeeWalletNumber); err != nil {
+			return fmt.Errorf("кошелек комиссий %s: %w", c.FeeWalletNumber, err)
+		}
+	}
+	return nil
+}
+
+// calculateConversionFee считает комиссию за конвертацию суммы amount по правилу rule.
+// Комиссия округляется до сотых и ограничивается min_fee/max_fee.
+func calculateConversionFee(rule models.FeeRule, amount float64) float64 {
+	fee := amount*rule.Percent/100 + rule.Fixed
+	if rule.MinFee != nil && fee < *rule.MinFee {
+		fee = *rule.MinFee
+	}
+	if rule.MaxFee != nil && fee > *rule.MaxFee {
+		fee = *rule.MaxFee
+	}
+	return math.Round(fee*100) / 100
+}
+
+type feeService struct {
+	feeRepo repository.FeeRepository
+	db      *sql.DB
+}
+
+// NewFeeService создает новый экземпляр сервиса комиссий.
+func NewFeeService(feeRepo repository.FeeRepository, db *sql.DB) FeeService {
+	return &feeService{
+		feeRepo: feeRepo,
+		db:      db,
+	}
+}
+
+// ListFeeRules возвращает все правила комиссий.
+func (s *feeService) ListFeeRules(ctx context.Context) (models.ListFeeRulesResponse, error) {
+	rules, err := s.feeRepo.ListFeeRules(ctx, s.db)
+	if err != nil {
+		return models.ListFeeRulesResponse{}, fmt.Errorf("не удалось получить правила комиссий: %w", err)
+	}
+	if rules == nil {
+		rules = []models.FeeRule{}
+	}
+	return models.ListFeeRulesResponse{Rules: rules}, nil
+}
+
+// SetFeeRule создает или заменяет правило для пары и уровня. Пустые пара и уровень означают '*'.
+func (s *feeService) SetFeeRule(ctx context.Context, rule models.FeeRule) (models.FeeRule, error) {
+	if rule.Pair == "" {
+		rule.Pair = models.FeeRuleWildcard
+	}
+	if rule.Tier == "" {
+		rule.Tier = models.FeeRuleWildcard
+	}
+	if rule.Pair != models.FeeRuleWildcard && !pairRegex.MatchString(rule.Pair) {
+		return models.FeeRule{}, ErrInvalidPair
+	}
+	if rule.Tier != models.FeeRuleWildcard && !tierRegex.MatchString(rule.Tier) {
+		return models.FeeRule{}, ErrInvalidTier
+	}
+	if rule.Percent < 0 || rule.Percent >= 100 || rule.Fixed < 0 ||
+		(rule.MinFee != nil && *rule.MinFee < 0) || (rule.MaxFee != nil && *rule.MaxFee < 0) ||
+		(rule.MinFee != nil && rule.MaxFee != nil && *rule.MinFee > *rule.MaxFee) {
+		return models.FeeRule{}, ErrInvalidFeeRule
+	}
+
+	saved, err := s.feeRepo.UpsertFeeRule(ctx, s.db, rule)
+	if err != nil {
+		return models.FeeRule{}, fmt.Errorf("не удалось сохранить правило комиссии: %w", err)
+	}
+	return saved, nil
+}
+
+// DeleteFeeRule удаляет правило комиссии.
+func (s *feeService) DeleteFeeRule(ctx context.Context, id int64) error {
+	if err := s.feeRepo.DeleteFeeRule(ctx, s.db, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrFeeRuleNotFound
+		}
+		return fmt.Errorf("не удалось удалить правило комиссии: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// --- internal/service/hold_service.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+)
+
+// Ошибки, связанные с холдами
+var (
+	ErrHoldNotFound           = apperrors.New(apperrors.CodeHoldNotFound, "холд не найден")
+	ErrHoldNotActive          = apperrors.New(apperrors.CodeHoldNotActive, "холд уже списан, отменен или истек")
+	ErrInvalidHoldAmount      = apperrors.New(apperrors.CodeInvalidHoldAmount, "сумма холда должна быть положительной")
+	ErrInvalidHoldTTL         = apperrors.New(apperrors.CodeInvalidHoldTTL, "срок действия холда должен быть положительным")
+	ErrCaptureExceedsHold     = apperrors.New(apperrors.CodeCaptureExceedsHold, "сумма списания превышает зарезервированную сумму")
+	ErrDuplicateHoldReference = repository.ErrDuplicateHoldReference
+)
+
+type holdService struct {
+	holdRepo     repository.HoldRepository
+	walletRepo   repository.WalletRepository
+	movementRepo repository.MovementRepository
+	limitRepo    repository.LimitRepository // Для проверки лимитов на списания
+	db           *sql.DB
+	defaultTTL   time.Duration // Срок жизни холда по умолчанию
+}
+
+// NewHoldService создает новый экземпляр сервиса холдов.
+func NewHoldService(holdRepo repository.HoldRepository, walletRepo repository.WalletRepository, movementRepo repository.MovementRepository,
+	limitRepo repository.LimitRepository, db *sql.DB, defaultTTL time.Duration) HoldService {
+	return &holdService{
+		holdRepo:     holdRepo,
+		walletRepo:   walletRepo,
+		movementRepo: movementRepo,
+		limitRepo:    limitRepo,
+		db:           db,
+		defaultTTL:   defaultTTL,
+	}
+}
+
+// AuthorizeHold резервирует средства на кошельке. Учетный баланс не меняется, уменьшается только доступный.
+// Сумма холда должна укладываться в лимиты на списания кошелька.
+func (s *holdService) AuthorizeHold(ctx context.Context, walletNumber string, req models.CreateHoldRequest) (models.Hold, error) {
+	if err := validateWalletNumber(walletNumber); err != nil {
+		return models.Hold{}, err
+	}
+	if req.Amount <= 0 {
+		return models.Hold{}, ErrInvalidHoldAmount
+	}
+	if req.ExpiresInSeconds < 0 {
+		return models.Hold{}, ErrInvalidHoldTTL
+	}
+	ttl := s.defaultTTL
+	if req.ExpiresInSeconds > 0 {
+		ttl = time.Duration(req.ExpiresInSeconds) * time.Second
+	}
+
+	var created models.Hold
+	err := executeTx(ctx, s.db, func(tx *sql.Tx) error {
+		// Блокируем кошелек, чтобы параллельные списания и холды видели согласованный доступный баланс
+		wallet, err := s.walletRepo.GetWalletByNumberForUpdate(ctx, tx, walletNumber)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrWalletNotFound
+			}
+			return fmt.Errorf("ошибка получения кошелька для холда: %w", err)
+		}
+		if err := checkWalletActive(wallet); err != nil {
+			return err
+		}
+
+		if availableFunds(wallet) < req.Amount {
+			return ErrInsufficientFunds
+		}
+		// Холд, который нельзя будет списать из-за лимитов, не резервируем. Окончательно лимиты
+		// проверяются при списании: к тому времени могли пройти другие списания.
+		if err := enforceDebitLimits(ctx, tx, s.limitRepo, s.movementRepo, wallet, req.Amount, false); err != nil {
+			return err
+		}
+
+		created, err = s.holdRepo.CreateHold(ctx, tx, models.Hold{
+			WalletNumber: walletNumber,
+			Amount:       req.Amount,
+			Reference:    req.Reference,
+			Status:       models.HoldStatusActive,
+			ExpiresAt:    time.Now().Add(ttl),
+		})
+		return err
+	})
+	if err != nil {
+		log.Printf("Ошибка в AuthorizeHold после транзакции: %v", err)
+		return models.Hold{}, err
+	}
+	return created, nil
+}
+
+// CaptureHold списывает зарезервированные средства (полностью или частично) и закрывает холд.
+// При частичном списании остаток резерва освобождается. Списываемая сумма проверяется по лимитам на списания.
+func (s *holdService) CaptureHold(ctx context.Context, id int64, req models.CaptureHoldRequest) (models.Hold, error) {
+	if req.Amount < 0 {
+		return models.Hold{}, ErrInvalidHoldAmount
+	}
+
+	var captured models.Hold
+	err := executeTx(ctx, s.db, func(tx *sql.Tx) error {
+		hold, err := s.lockActiveHold(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		amount := req.Amount
+		if amount == 0 {
+			amount = hold.Amount // Полное списание
+		}
+		if amount > hold.Amount {
+			return ErrCaptureExceedsHold
+		}
+
+		wallet, err := s.walletRepo.GetWalletByNumberForUpdate(ctx, tx, hold.WalletNumber)
+		if err != nil {
+			return fmt.Errorf("ошибка получения кошелька для списания холда: %w", err)
+		}
+		// Замороженный кошелек не списывается и по холду: холд можно отменить или дождаться разморозки
+		if err := checkWalletActive(wallet); err != nil {
+			return err
+		}
+		newBalance := wallet.Balance - amount
+		if newBalance < -wallet.CreditLimit {
+			return ErrInsufficientFunds
+		}
+		// Списание холда - такое же списание, как и прямое: сумма учитывается в лимитах
+		if err := enforceDebitLimits(ctx, tx, s.limitRepo, s.movementRepo, wallet, amount, false); err != nil {
+			return err
+		}
+		if err := applyBalanceChange(ctx, tx, s.walletRepo, s.movementRepo, hold.WalletNumber, -amount, newBalance, models.MovementKindHoldCapture); err != nil {
+			return fmt.Errorf("не удалось списать зарезервированные средства: %w", err)
+		}
+		if err := s.holdRepo.UpdateHoldStatus(ctx, tx, id, models.HoldStatusCaptured, amount); err != nil {
+			return fmt.Errorf("не удалось обновить статус холда: %w", err)
+		}
+
+		captured, err = s.holdRepo.GetHoldByID(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		log.Printf("Ошибка в CaptureHold после транзакции: %v", err)
+		return models.Hold{}, err
+	}
+	return captured, nil
+}
+
+// VoidHold отменяет холд без списания средств.
+func (s *holdService) VoidHold(ctx context.Context, id int64) (models.Hold, error) {
+	var voided models.Hold
+	err := executeTx(ctx, s.db, func(tx *sql.Tx) error {
+		if _, err := s.lockActiveHold(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := s.holdRepo.UpdateHoldStatus(ctx, tx, id, models.HoldStatusVoided, 0); err != nil {
+			return fmt.Errorf("не удалось обновить статус холда: %w", err)
+		}
+
+		var err error
+		voided, err = s.holdRepo.GetHoldByID(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		log.Printf("Ошибка в VoidHold после транзакции: %v", err)
+		return models.Hold{}, err
+	}
+	return voided, nil
+}
+
+// GetHold возвращает холд по ID.
+func (s *holdService) GetHold(ctx context.Context, id int64) (models.Hold, error) {
+	hold, err := s.holdRepo.GetHoldByID(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Hold{}, ErrHoldNotFound
+		}
+		return models.Hold{}, fmt.Errorf("не удалось получить холд: %w", err)
+	}
+	return hold, nil
+}
+
+// ReleaseExpiredHolds освобождает все холды с истекшим сроком. Вызывается фоновой задачей.
+func (s *holdService) ReleaseExpiredHolds(ctx context.Context) (int64, error) {
+	released, err := s.holdRepo.ExpireHolds(ctx, s.db)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось освободить истекшие холды: %w", err)
+	}
+	if released > 0 {
+		log.Printf("Освобождено истекших холдов: %d", released)
+	}
+	return released, nil
+}
+
+// lockActiveHold блокирует холд и проверяет, что по нему еще можно выполнять операции.
+func (s *holdService) lockActiveHold(ctx context.Context, tx *sql.Tx, id int64) (models.Hold, error) {
+	hold, err := s.holdRepo.GetHoldByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Hold{}, ErrHoldNotFound
+		}
+		return models.Hold{}, fmt.Errorf("ошибка получения холда: %w", err)
+	}
+	// Истекший холд считается освобожденным, даже если фоновая задача еще не сменила его статус
+	if hold.Status != models.HoldStatusActive || !hold.ExpiresAt.After(time.Now()) {
+		return models.Hold{}, ErrHoldNotActive
+	}
+	return hold, nil
+}
@@ -0,0 +1,346 @@
+// --- internal/service/interest_service.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// walletAccrualsLimit - сколько последних начислений возвращается вместе с планом кошелька.
+const walletAccrualsLimit = 31
+
+// Ошибки, связанные с процентами
+var (
+	ErrInterestPlanNotFound   = apperrors.New(apperrors.CodeInterestPlanNotFound, "процентный план не найден")
+	ErrInterestPlanExists     = apperrors.New(apperrors.CodeInterestPlanExists, "процентный план с таким названием уже существует")
+	ErrInvalidInterestPlan    = apperrors.New(apperrors.CodeInvalidInterestPlan, "некорректный процентный план: нужно название до 64 символов и годовая ставка от 0 до 100")
+	ErrInvalidDayCount        = apperrors.New(apperrors.CodeInvalidDayCount, "некорректная конвенция подсчета дней (допустимо: ACT/365, ACT/360, ACT/ACT, 30/360)")
+	ErrInterestDayNotClosed   = apperrors.New(apperrors.CodeInterestDayNotClosed, "проценты можно начислить только за завершившийся день")
+	ErrInterestMonthNotClosed = apperrors.New(apperrors.CodeInterestMonthNotClosed, "проценты можно зачислить только за завершившийся месяц")
+)
+
+// dayCountFraction возвращает долю года, которую составляет день day по конвенции dayCount.
+func dayCountFraction(dayCount string, day time.Time) float64 {
+	switch dayCount {
+	case models.DayCountActual360:
+		return 1.0 / 360
+	case models.DayCountActualAct:
+		year := day.Year()
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 1.0 / 366
+		}
+		return 1.0 / 365
+	case models.DayCount30360:
+		// Число дней между day и следующим днем по правилу 30/360 (US):
+		// 31-е число не начисляется, последний день февраля добирает месяц до 30 дней
+		next := day.AddDate(0, 0, 1)
+		d1, d2 := day.Day(), next.Day()
+		if d1 == 31 {
+			d1 = 30
+		}
+		if d2 == 31 && d1 == 30 {
+			d2 = 30
+		}
+		days := 360*(next.Year()-day.Year()) + 30*(int(next.Month())-int(day.Month())) + d2 - d1
+		return float64(days) / 360
+	default:
+		return 1.0 / 365
+	}
+}
+
+// startOfDay возвращает начало дня t в UTC.
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// startOfMonth возвращает начало месяца t в UTC.
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+type interestService struct {
+	interestRepo repository.InterestRepository
+	walletRepo   repository.WalletRepository
+	movementRepo repository.MovementRepository
+	db           *sql.DB
+}
+
+// NewInterestService создает новый экземпляр сервиса процентов.
+func NewInterestService(interestRepo repository.InterestRepository, walletRepo repository.WalletRepository,
+	movementRepo repository.MovementRepository, db *sql.DB) InterestService {
+	return &interestService{
+		interestRepo: interestRepo,
+		walletRepo:   walletRepo,
+		movementRepo: movementRepo,
+		db:           db,
+	}
+}
+
+// CreatePlan создает процентный план. Без конвенции подсчета дней используется ACT/365.
+func (s *interestService) CreatePlan(ctx context.Context, req models.CreateInterestPlanRequest) (models.InterestPlan, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 64 || req.AnnualRate < 0 || req.AnnualRate > 100 {
+		return models.InterestPlan{}, ErrInvalidInterestPlan
+	}
+	dayCount := strings.ToUpper(strings.TrimSpace(req.DayCount))
+	switch dayCount {
+	case "":
+		dayCount = models.DayCountActual365
+	case models.DayCountActual365, models.DayCountActual360, models.DayCountActualAct, models.DayCount30360:
+	default:
+		return models.InterestPlan{}, ErrInvalidDayCount
+	}
+
+	plan, err := s.interestRepo.CreatePlan(ctx, s.db, models.InterestPlan{Name: name, AnnualRate: req.AnnualRate, DayCount: dayCount})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return models.InterestPlan{}, ErrInterestPlanExists
+		}
+		return models.InterestPlan{}, fmt.Errorf("не удалось создать процентный план: %w", err)
+	}
+	log.Printf("Создан процентный план %d (%s): %.4f%% годовых, %s\n", plan.ID, plan.Name, plan.AnnualRate, plan.DayCount)
+	return plan, nil
+}
+
+// ListPlans возвращает все процентные планы.
+func (s *interestService) ListPlans(ctx context.Context) (models.ListInterestPlansResponse, error) {
+	plans, err := s.interestRepo.ListPlans(ctx, s.db)
+	if err != nil {
+		return models.ListInterestPlansResponse{}, fmt.Errorf("не удалось получить процентные планы: %w", err)
+	}
+	if plans == nil {
+		plans = []models.InterestPlan{}
+	}
+	return models.ListInterestPlansResponse{Plans: plans}, nil
+}
+
+// GetWalletInterest возвращает план кошелька, незачисленные проценты и последние начисления.
+func (s *interestService) GetWalletInterest(ctx context.Context, number string) (models.WalletInterestResponse, error) {
+	if err := validateWalletNumber(number); err != nil {
+		return models.WalletInterestResponse{}, err
+	}
+	resp := models.WalletInterestResponse{WalletNumber: number}
+
+	planID, err := s.interestRepo.GetWalletPlanID(ctx, s.db, number)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.WalletInterestResponse{}, ErrWalletNotFound
+		}
+		return models.WalletInterestResponse{}, fmt.Errorf("ошибка получения плана кошелька: %w", err)
+	}
+	if planID != nil {
+		plan, err := s.interestRepo.GetPlanByID(ctx, s.db, *planID)
+		if err != nil {
+			return models.WalletInterestResponse{}, fmt.Errorf("ошибка получения процентного плана: %w", err)
+		}
+		resp.Plan = &plan
+	}
+
+	// Незачисленные начисления за все дни, включая будущие месяцы
+	unposted, _, err := s.interestRepo.SumUnpostedAccruals(ctx, s.db, number, startOfDay(time.Now()).AddDate(0, 0, 1))
+	if err != nil {
+		return models.WalletInterestResponse{}, fmt.Errorf("ошибка подсчета начисленных процентов: %w", err)
+	}
+	resp.AccruedUnposted = unposted
+
+	resp.Accruals, err = s.interestRepo.ListWalletAccruals(ctx, s.db, number, walletAccrualsLimit)
+	if err != nil {
+		return models.WalletInterestResponse{}, fmt.Errorf("ошибка получения начислений: %w", err)
+	}
+	if resp.Accruals == nil {
+		resp.Accruals = []models.InterestAccrual{}
+	}
+	return resp, nil
+}
+
+// SetWalletPlan назначает кошельку процентный план (nil - отключает начисление).
+// Назначение сохраняется в истории: за каждый день проценты начисляются по плану,
+// действовавшему на конец этого дня.
+func (s *interestService) SetWalletPlan(ctx context.Context, number string, planID *int64) (models.WalletInterestResponse, error) {
+	if err := validateWalletNumber(number); err != nil {
+		return models.WalletInterestResponse{}, err
+	}
+	if planID != nil {
+		if _, err := s.interestRepo.GetPlanByID(ctx, s.db, *planID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.WalletInterestResponse{}, ErrInterestPlanNotFound
+			}
+			return models.WalletInterestResponse{}, fmt.Errorf("ошибка получения процентного плана: %w", err)
+		}
+	}
+	if err := s.interestRepo.SetWalletPlan(ctx, s.db, number, planID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.WalletInterestResponse{}, ErrWalletNotFound
+		}
+		return models.WalletInterestResponse{}, fmt.Errorf("не удалось назначить процентный план: %w", err)
+	}
+	return s.GetWalletInterest(ctx, number)
+}
+
+// AccrueInterest начисляет проценты за день day (UTC) на баланс каждого кошелька по плану,
+// действовавшему на конец этого дня.
+// Повторный запуск за тот же день ничего не начисляет: начисление за день сохраняется один раз.
+// На неположительный баланс проценты не начисляются (сохраняется нулевое начисление).
+func (s *interestService) AccrueInterest(ctx context.Context, day time.Time) (models.InterestAccrualRunResponse, error) {
+	day = startOfDay(day)
+	resp := models.InterestAccrualRunResponse{Date: day.Format("2006-01-02")}
+	if !day.Before(startOfDay(time.Now())) {
+		return resp, ErrInterestDayNotClosed
+	}
+
+	err := executeTx(ctx, s.db, func(tx *sql.Tx) error {
+		// Транзакция может быть повторена - считаем итоги заново
+		resp.WalletsAccrued, resp.TotalAccrued = 0, 0
+		candidates, err := s.interestRepo.ListAccrualCandidates(ctx, tx, day)
+		if err != nil {
+			return err
+		}
+		for _, accrual := range candidates {
+			if accrual.Balance > 0 {
+				accrual.Amount = accrual.Balance * accrual.AnnualRate / 100 * dayCountFraction(accrual.DayCount, day)
+			}
+			created, err := s.interestRepo.CreateAccrual(ctx, tx, accrual)
+			if err != nil {
+				return err
+			}
+			if created {
+				resp.WalletsAccrued++
+				resp.TotalAccrued += accrual.Amount
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.InterestAccrualRunResponse{}, fmt.Errorf("не удалось начислить проценты за %s: %w", resp.Date, err)
+	}
+
+	log.Printf("Проценты за %s начислены %d кошелькам (всего %.4f)\n", resp.Date, resp.WalletsAccrued, resp.TotalAccrued)
+	return resp, nil
+}
+
+// PostInterest зачисляет на кошельки все незачисленные начисления по месяц month включительно:
+// по одному движению interest на кошелек, сумма округляется до сотых (остаток меньше копейки не переносится).
+// Каждый кошелек зачисляется в своей транзакции под блокировкой, поэтому повторный
+// или параллельный запуск не зачисляет проценты дважды.
+func (s *interestService) PostInterest(ctx context.Context, month time.Time) (models.InterestPostingResponse, error) {
+	month = startOfMonth(month)
+	resp := models.InterestPostingResponse{Month: month.Format("2006-01")}
+	if !month.Before(startOfMonth(time.Now())) {
+		return resp, ErrInterestMonthNotClosed
+	}
+	before := month.AddDate(0, 1, 0)
+
+	numbers, err := s.interestRepo.ListWalletsWithUnpostedAccruals(ctx, s.db, before)
+	if err != nil {
+		return models.InterestPostingResponse{}, fmt.Errorf("не удалось найти незачисленные проценты: %w", err)
+	}
+
+	for _, number := range numbers {
+		var posted float64
+		err := executeTx(ctx, s.db, func(tx *sql.Tx) error {
+			wallet, err := s.walletRepo.GetWalletByNumberForUpdate(ctx, tx, number)
+			if err != nil {
+				return fmt.Errorf("ошибка получения кошелька %s: %w", number, err)
+			}
+			// На замороженный кошелек проценты зачисляются после разморозки (начисления остаются незачисленными),
+			// на закрытый - не зачисляются
+			if statusErr := checkWalletActive(wallet); statusErr != nil {
+				log.Printf("Проценты на кошелек %s не зачислены: %v\n", number, statusErr)
+				return nil
+			}
+			// Сумма считается под блокировкой кошелька: параллельный запуск увидит начисления уже зачисленными
+			sum, count, err := s.interestRepo.SumUnpostedAccruals(ctx, tx, number, before)
+			if err != nil || count == 0 {
+				return err
+			}
+
+			var movementID *int64
+			amount := math.Round(sum*100) / 100
+			if amount > 0 {
+				movement, err := recordMovement(ctx, tx, s.walletRepo, s.movementRepo, models.Movement{
+					WalletNumber: number,
+					Kind:         models.MovementKindInterest,
+					Amount:       amount,
+					BalanceAfter: wallet.Balance + amount,
+				})
+				if err != nil {
+					return fmt.Errorf("не удалось зачислить проценты на кошелек %s: %w", number, err)
+				}
+				movementID = &movement.ID
+				posted = amount
+			}
+			return s.interestRepo.MarkAccrualsPosted(ctx, tx, number, before, movementID)
+		})
+		if err != nil {
+			return resp, err
+		}
+		if posted > 0 {
+			resp.WalletsPosted++
+			resp.TotalPosted += posted
+		}
+	}
+
+	log.Printf("Проценты по %s зачислены на %d кошельков (всего %.2f)\n", resp.Month, resp.WalletsPosted, resp.TotalPosted)
+	return resp, nil
+}
+
+// RunDailyInterest начисляет проценты за все завершившиеся дни после последнего начисления
+// (если начислений еще не было - с дня первого назначения плана) и зачисляет по отдельности
+// каждый завершившийся месяц, в котором остались незачисленные начисления. Так пропущенные запуски
+// (простой сервиса, ошибки) наверстываются при следующем. Вызывается фоновой задачей;
+// оба шага идемпотентны, поэтому задачу можно запускать чаще раза в день.
+func (s *interestService) RunDailyInterest(ctx context.Context) error {
+	today := startOfDay(time.Now())
+
+	var from time.Time
+	lastAccrued, err := s.interestRepo.GetLastAccrualDate(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("не удалось определить последний день начисления процентов: %w", err)
+	}
+	if lastAccrued != nil {
+		from = startOfDay(*lastAccrued).AddDate(0, 0, 1)
+	} else {
+		firstAssigned, err := s.interestRepo.GetFirstPlanAssignment(ctx, s.db)
+		if err != nil {
+			return fmt.Errorf("не удалось определить первое назначение процентного плана: %w", err)
+		}
+		if firstAssigned == nil {
+			return nil
+		}
+		from = startOfDay(*firstAssigned)
+	}
+	for day := from; day.Before(today); day = day.AddDate(0, 0, 1) {
+		if _, err := s.AccrueInterest(ctx, day); err != nil {
+			return err
+		}
+	}
+
+	firstUnposted, err := s.interestRepo.GetFirstUnpostedAccrualDate(ctx, s.db)
+	if err != nil {
+		return fmt.Errorf("не удалось найти незачисленные проценты: %w", err)
+	}
+	if firstUnposted == nil {
+		return nil
+	}
+	for month := startOfMonth(*firstUnposted); month.Before(startOfMonth(today)); month = month.AddDate(0, 1, 0) {
+		if _, err := s.PostInterest(ctx, month); err != nil {
+			return err
+		}
+	}
+	return nil
+}
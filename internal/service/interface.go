@@ -4,6 +4,7 @@ package service
 import (
 	"context"
 	"currency-service/internal/models"
+	"time"
 )
 
 // RateService определяет методы бизнес-логики для работы с курсами валют.
@@ -11,6 +12,12 @@ type RateService interface {
 	CreateRate(ctx context.Context, value float64) error
 	GetAverageRate(ctx context.Context, limit int) (models.AverageResponse, error)
 	GetLatestRate(ctx context.Context) (models.Rate, error) // <-- Новый метод
+	// ListRates возвращает последние limit курсов, начиная с самого свежего.
+	ListRates(ctx context.Context, limit int) ([]models.Rate, error)
+	// ExportRates передает в fn последние limit курсов (0 - все), не собирая их в память.
+	ExportRates(ctx context.Context, limit int, fn func(models.Rate) error) error
+	// GetRatesVersion возвращает состояние курсов (меняется при добавлении курса) для ETag и Last-Modified.
+	GetRatesVersion(ctx context.Context) (models.DataVersion, error)
 }
 
 // (!!!) WalletService определяет методы бизнес-логики для работы с кошельками.
@@ -18,8 +25,139 @@ type WalletService interface {
 	// UpdateBalance создает кошелек или обновляет его баланс.
 	// amount может быть положительным или отрицательным.
 	UpdateBalance(ctx context.Context, req models.UpdateBalanceRequest) (models.UpdateBalanceResponse, error)
-	// ListWallets возвращает список всех кошельков.
-	ListWallets(ctx context.Context) (models.ListWalletsResponse, error)
+	// ListWallets возвращает страницу кошельков с фильтрами, сортировкой и курсором.
+	ListWallets(ctx context.Context, filter models.ListWalletsFilter) (models.ListWalletsResponse, error)
+	// ExportWallets передает в fn кошельки, подходящие под фильтр, не собирая их в память.
+	// filter.Limit = 0 - все кошельки.
+	ExportWallets(ctx context.Context, filter models.ListWalletsFilter, fn func(models.Wallet) error) error
+	// GetWalletsVersion возвращает состояние списка кошельков (меняется при изменении кошельков и холдов)
+	// для ETag и Last-Modified.
+	GetWalletsVersion(ctx context.Context) (models.DataVersion, error)
+	// CreateWallet создает пустой кошелек с номером, выделенным сервером.
+	CreateWallet(ctx context.Context, req models.CreateWalletRequest) (models.Wallet, error)
+	// GetWallet возвращает кошелек по номеру и, если asOf не nil, его баланс на момент asOf.
+	GetWallet(ctx context.Context, number string, asOf *time.Time) (models.WalletDetailsResponse, error)
+	// EnsureFeeWallet создает кошелек комиссий за конвертацию, если его нет (вызывается при запуске).
+	EnsureFeeWallet(ctx context.Context) error
 	// ConvertAndDeduct выполняет конвертацию и списание средств.
 	ConvertAndDeduct(ctx context.Context, req models.ConvertRequest) (models.ConvertResponse, error)
+	// Transfer переводит средства между двумя существующими кошельками.
+	Transfer(ctx context.Context, req models.TransferRequest) (models.TransferResponse, error)
+	// ExecuteBatch выполняет пакет пополнений, списаний и переводов в одной транзакции
+	// (atomic - все или ничего, best_effort - каждая операция фиксируется отдельно).
+	ExecuteBatch(ctx context.Context, req models.BatchRequest) (models.BatchResponse, error)
+	// SetCreditLimit устанавливает кредитный лимит (разрешенный овердрафт) кошелька.
+	SetCreditLimit(ctx context.Context, number string, limit float64) (models.Wallet, error)
+	// SetWalletStatus замораживает, размораживает или закрывает кошелек.
+	SetWalletStatus(ctx context.Context, number, status string) (models.Wallet, error)
+	// ListWalletMovements возвращает последние limit движений по кошельку (0 - значение по умолчанию).
+	ListWalletMovements(ctx context.Context, number string, limit int) ([]models.Movement, error)
+}
+
+// UserService определяет методы бизнес-логики для работы с пользователями.
+type UserService interface {
+	// CreateUser создает нового пользователя.
+	CreateUser(ctx context.Context, req models.CreateUserRequest) (models.User, error)
+	// GetUser возвращает пользователя по ID.
+	GetUser(ctx context.Context, id string) (models.User, error)
+	// ListUserWallets возвращает все кошельки пользователя.
+	ListUserWallets(ctx context.Context, id string) (models.ListWalletsResponse, error)
+}
+
+// HoldService определяет методы бизнес-логики для резервирования средств (холдов).
+type HoldService interface {
+	// AuthorizeHold резервирует средства на кошельке, уменьшая доступный баланс.
+	AuthorizeHold(ctx context.Context, walletNumber string, req models.CreateHoldRequest) (models.Hold, error)
+	// CaptureHold списывает зарезервированные средства полностью или частично.
+	CaptureHold(ctx context.Context, id int64, req models.CaptureHoldRequest) (models.Hold, error)
+	// VoidHold отменяет холд без списания.
+	VoidHold(ctx context.Context, id int64) (models.Hold, error)
+	// GetHold возвращает холд по ID.
+	GetHold(ctx context.Context, id int64) (models.Hold, error)
+	// ReleaseExpiredHolds освобождает холды с истекшим сроком.
+	ReleaseExpiredHolds(ctx context.Context) (int64, error)
+}
+
+// LimitService определяет методы бизнес-логики для управления лимитами на списания.
+type LimitService interface {
+	// GetTierLimits возвращает лимиты уровня кошельков.
+	GetTierLimits(ctx context.Context, tier string) (models.TierLimitsResponse, error)
+	// SetTierLimits заменяет лимиты уровня кошельков.
+	SetTierLimits(ctx context.Context, tier string, limits models.Limits) (models.TierLimitsResponse, error)
+	// GetWalletLimits возвращает лимиты кошелька (уровня, индивидуальные и итоговые).
+	GetWalletLimits(ctx context.Context, number string) (models.WalletLimitsResponse, error)
+	// SetWalletLimits заменяет индивидуальные лимиты кошелька и, если указан, его уровень.
+	SetWalletLimits(ctx context.Context, number string, req models.UpdateWalletLimitsRequest) (models.WalletLimitsResponse, error)
+}
+
+// ScheduleService определяет методы бизнес-логики для запланированных (в том числе повторяющихся) операций.
+type ScheduleService interface {
+	// CreateSchedule создает расписание и вычисляет время первого запуска.
+	CreateSchedule(ctx context.Context, req models.CreateScheduleRequest) (models.Schedule, error)
+	// GetSchedule возвращает расписание по ID.
+	GetSchedule(ctx context.Context, id int64) (models.Schedule, error)
+	// ListScheduleRuns возвращает историю запусков расписания.
+	ListScheduleRuns(ctx context.Context, id int64) (models.ListScheduleRunsResponse, error)
+	// CancelSchedule отключает активное расписание.
+	CancelSchedule(ctx context.Context, id int64) (models.Schedule, error)
+	// RunDueSchedules выполняет расписания, время запуска которых наступило.
+	RunDueSchedules(ctx context.Context) (int, error)
+}
+
+// ReconciliationService определяет методы сверки балансов кошельков с историей движений.
+type ReconciliationService interface {
+	// Reconcile выполняет сверку всех кошельков и сохраняет найденные расхождения.
+	Reconcile(ctx context.Context) (models.ReconciliationReportResponse, error)
+	// ListRuns возвращает последние сверки.
+	ListRuns(ctx context.Context, limit int) (models.ListReconciliationRunsResponse, error)
+	// GetReport возвращает сверку и ее расхождения. runID = 0 означает последнюю сверку.
+	GetReport(ctx context.Context, runID int64) (models.ReconciliationReportResponse, error)
+}
+
+// ReversalService определяет методы бизнес-логики для отмены движений по кошелькам.
+type ReversalService interface {
+	// GetMovement возвращает движение по ID.
+	GetMovement(ctx context.Context, id int64) (models.Movement, error)
+	// ReverseMovement отменяет движение id компенсирующим движением. Повторная отмена невозможна.
+	ReverseMovement(ctx context.Context, id int64) (models.ReversalResponse, error)
+}
+
+// FeeService определяет методы бизнес-логики для управления комиссиями за конвертацию.
+type FeeService interface {
+	// ListFeeRules возвращает все правила комиссий.
+	ListFeeRules(ctx context.Context) (models.ListFeeRulesResponse, error)
+	// SetFeeRule создает или заменяет правило для пары и уровня.
+	SetFeeRule(ctx context.Context, rule models.FeeRule) (models.FeeRule, error)
+	// DeleteFeeRule удаляет правило комиссии.
+	DeleteFeeRule(ctx context.Context, id int64) error
+}
+
+// InterestService определяет методы бизнес-логики для начисления процентов.
+type InterestService interface {
+	// CreatePlan создает процентный план.
+	CreatePlan(ctx context.Context, req models.CreateInterestPlanRequest) (models.InterestPlan, error)
+	// ListPlans возвращает все процентные планы.
+	ListPlans(ctx context.Context) (models.ListInterestPlansResponse, error)
+	// GetWalletInterest возвращает план кошелька, незачисленные проценты и последние начисления.
+	GetWalletInterest(ctx context.Context, number string) (models.WalletInterestResponse, error)
+	// SetWalletPlan назначает кошельку процентный план (nil - отключает начисление).
+	SetWalletPlan(ctx context.Context, number string, planID *int64) (models.WalletInterestResponse, error)
+	// AccrueInterest начисляет проценты за завершившийся день (идемпотентно).
+	AccrueInterest(ctx context.Context, day time.Time) (models.InterestAccrualRunResponse, error)
+	// PostInterest зачисляет начисления по завершившийся месяц включительно (идемпотентно).
+	PostInterest(ctx context.Context, month time.Time) (models.InterestPostingResponse, error)
+	// RunDailyInterest начисляет проценты за все пропущенные дни по вчера и зачисляет начисления за прошлые месяцы.
+	RunDailyInterest(ctx context.Context) error
+}
+
+// APIKeyService определяет методы бизнес-логики для API-ключей.
+type APIKeyService interface {
+	// IssueAPIKey выпускает ключ. Сам ключ возвращается только в ответе на выпуск.
+	IssueAPIKey(ctx context.Context, req models.IssueAPIKeyRequest) (models.IssueAPIKeyResponse, error)
+	// ListAPIKeys возвращает все ключи без их значений.
+	ListAPIKeys(ctx context.Context) (models.ListAPIKeysResponse, error)
+	// RevokeAPIKey отзывает ключ.
+	RevokeAPIKey(ctx context.Context, id int64) (models.APIKey, error)
+	// Authenticate возвращает действующий ключ по его значению.
+	Authenticate(ctx context.Context, key string) (models.APIKey, error)
 }
@@ -0,0 +1,266 @@
+// --- internal/service/limit_service.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+)
+
+// Ошибки, связанные с лимитами
+var (
+	ErrLimitExceeded = apperrors.New(apperrors.CodeLimitExceeded, "превышен лимит операций по кошельку")
+	ErrInvalidTier   = apperrors.New(apperrors.CodeInvalidTier, "некорректное название уровня (латинские буквы, цифры, '_' и '-', до 32 символов)")
+	ErrInvalidLimit  = apperrors.New(apperrors.CodeInvalidLimit, "значение лимита не может быть отрицательным")
+)
+
+// Регулярное выражение для проверки названия уровня кошелька
+var tierRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
+
+// LimitExceededError описывает, какой лимит превышен и когда он обнулится.
+// errors.Is(err, ErrLimitExceeded) возвращает true.
+type LimitExceededError struct {
+	Limit      string     // Название лимита (models.Limit*)
+	LimitValue float64    // Значение лимита
+	Attempted  float64    // Значение с учетом отклоненной операции
+	ResetsAt   *time.Time // Время обнуления; nil для лимита одного списания
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("превышен лимит %s: %.2f (с учетом операции %.2f)", e.Limit, e.LimitValue, e.Attempted)
+}
+
+// Is позволяет сравнивать ошибку с ErrLimitExceeded через errors.Is.
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// ErrorCode возвращает код ошибки для клиентов API.
+func (e *LimitExceededError) ErrorCode() apperrors.Code {
+	return apperrors.CodeLimitExceeded
+}
+
+// ErrorDetails возвращает сведения о превышенном лимите.
+func (e *LimitExceededError) ErrorDetails() map[string]any {
+	details := map[string]any{
+		"limit":       e.Limit,
+		"limit_value": e.LimitValue,
+		"attempted":   e.Attempted,
+	}
+	if e.ResetsAt != nil {
+		details["resets_at"] = e.ResetsAt
+	}
+	return details
+}
+
+type limitService struct {
+	limitRepo  repository.LimitRepository
+	walletRepo repository.WalletRepository
+	db         *sql.DB
+}
+
+// NewLimitService создает новый экземпляр сервиса лимитов.
+func NewLimitService(limitRepo repository.LimitRepository, walletRepo repository.WalletRepository, db *sql.DB) LimitService {
+	return &limitService{
+		limitRepo:  limitRepo,
+		walletRepo: walletRepo,
+		db:         db,
+	}
+}
+
+// GetTierLimits возвращает лимиты уровня (пустые, если не заданы).
+func (s *limitService) GetTierLimits(ctx context.Context, tier string) (models.TierLimitsResponse, error) {
+	if !tierRegex.MatchString(tier) {
+		return models.TierLimitsResponse{}, ErrInvalidTier
+	}
+	limits, err := getLimitsOrEmpty(s.limitRepo.GetTierLimits(ctx, s.db, tier))
+	if err != nil {
+		return models.TierLimitsResponse{}, fmt.Errorf("не удалось получить лимиты уровня: %w", err)
+	}
+	return models.TierLimitsResponse{Tier: tier, Limits: limits}, nil
+}
+
+// SetTierLimits заменяет лимиты уровня.
+func (s *limitService) SetTierLimits(ctx context.Context, tier string, limits models.Limits) (models.TierLimitsResponse, error) {
+	if !tierRegex.MatchString(tier) {
+		return models.TierLimitsResponse{}, ErrInvalidTier
+	}
+	if err := validateLimits(limits); err != nil {
+		return models.TierLimitsResponse{}, err
+	}
+	if err := s.limitRepo.UpsertTierLimits(ctx, s.db, tier, limits); err != nil {
+		return models.TierLimitsResponse{}, fmt.Errorf("не удалось сохранить лимиты уровня: %w", err)
+	}
+	return models.TierLimitsResponse{Tier: tier, Limits: limits}, nil
+}
+
+// GetWalletLimits возвращает лимиты кошелька: уровня, индивидуальные и итоговые.
+func (s *limitService) GetWalletLimits(ctx context.Context, number string) (models.WalletLimitsResponse, error) {
+	if err := validateWalletNumber(number); err != nil {
+		return models.WalletLimitsResponse{}, err
+	}
+	wallet, err := s.walletRepo.GetWalletByNumber(ctx, s.db, number)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.WalletLimitsResponse{}, ErrWalletNotFound
+		}
+		return models.WalletLimitsResponse{}, fmt.Errorf("ошибка получения кошелька: %w", err)
+	}
+	return loadWalletLimits(ctx, s.limitRepo, s.db, wallet)
+}
+
+// SetWalletLimits заменяет индивидуальные лимиты кошелька и (если указан) его уровень.
+func (s *limitService) SetWalletLimits(ctx context.Context, number string, req models.UpdateWalletLimitsRequest) (models.WalletLimitsResponse, error) {
+	if err := validateWalletNumber(number); err != nil {
+		return models.WalletLimitsResponse{}, err
+	}
+	if req.Tier != "" && !tierRegex.MatchString(req.Tier) {
+		return models.WalletLimitsResponse{}, ErrInvalidTier
+	}
+	if err := validateLimits(req.Overrides); err != nil {
+		return models.WalletLimitsResponse{}, err
+	}
+
+	var resp models.WalletLimitsResponse
+	err := executeTx(ctx, s.db, func(tx *sql.Tx) error {
+		wallet, err := s.walletRepo.GetWalletByNumberForUpdate(ctx, tx, number)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrWalletNotFound
+			}
+			return fmt.Errorf("ошибка получения кошелька: %w", err)
+		}
+		if req.Tier != "" && req.Tier != wallet.Tier {
+			if err := s.walletRepo.UpdateWalletTier(ctx, tx, number, req.Tier); err != nil {
+				return fmt.Errorf("не удалось изменить уровень кошелька: %w", err)
+			}
+			wallet.Tier = req.Tier
+		}
+		if err := s.limitRepo.UpsertWalletLimitOverrides(ctx, tx, number, req.Overrides); err != nil {
+			return fmt.Errorf("не удалось сохранить лимиты кошелька: %w", err)
+		}
+		resp, err = loadWalletLimits(ctx, s.limitRepo, tx, wallet)
+		return err
+	})
+	if err != nil {
+		log.Printf("Ошибка в SetWalletLimits после транзакции: %v", err)
+		return models.WalletLimitsResponse{}, err
+	}
+	return resp, nil
+}
+
+// validateLimits проверяет, что ни один лимит не отрицателен.
+func validateLimits(limits models.Limits) error {
+	if (limits.MaxSingleWithdrawal != nil && *limits.MaxSingleWithdrawal < 0) ||
+		(limits.DailyWithdrawalTotal != nil && *limits.DailyWithdrawalTotal < 0) ||
+		(limits.MonthlyWithdrawalTotal != nil && *limits.MonthlyWithdrawalTotal < 0) ||
+		(limits.DailyConversionCount != nil && *limits.DailyConversionCount < 0) {
+		return ErrInvalidLimit
+	}
+	return nil
+}
+
+// getLimitsOrEmpty превращает sql.ErrNoRows от репозитория в пустые лимиты.
+func getLimitsOrEmpty(limits models.Limits, err error) (models.Limits, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Limits{}, nil
+	}
+	return limits, err
+}
+
+// loadWalletLimits читает лимиты уровня и индивидуальные лимиты кошелька и вычисляет итоговые.
+func loadWalletLimits(ctx context.Context, limitRepo repository.LimitRepository, db repository.DBTX, wallet models.Wallet) (models.WalletLimitsResponse, error) {
+	tierLimits, err := getLimitsOrEmpty(limitRepo.GetTierLimits(ctx, db, wallet.Tier))
+	if err != nil {
+		return models.WalletLimitsResponse{}, fmt.Errorf("ошибка получения лимитов уровня: %w", err)
+	}
+	overrides, err := getLimitsOrEmpty(limitRepo.GetWalletLimitOverrides(ctx, db, wallet.Number))
+	if err != nil {
+		return models.WalletLimitsResponse{}, fmt.Errorf("ошибка получения лимитов кошелька: %w", err)
+	}
+
+	// Индивидуальный лимит имеет приоритет над лимитом уровня
+	effective := tierLimits
+	if overrides.MaxSingleWithdrawal != nil {
+		effective.MaxSingleWithdrawal = overrides.MaxSingleWithdrawal
+	}
+	if overrides.DailyWithdrawalTotal != nil {
+		effective.DailyWithdrawalTotal = overrides.DailyWithdrawalTotal
+	}
+	if overrides.MonthlyWithdrawalTotal != nil {
+		effective.MonthlyWithdrawalTotal = overrides.MonthlyWithdrawalTotal
+	}
+	if overrides.DailyConversionCount != nil {
+		effective.DailyConversionCount = overrides.DailyConversionCount
+	}
+
+	return models.WalletLimitsResponse{
+		WalletNumber: wallet.Number,
+		Tier:         wallet.Tier,
+		TierLimits:   tierLimits,
+		Overrides:    overrides,
+		Effective:    effective,
+	}, nil
+}
+
+// enforceDebitLimits проверяет лимиты перед списанием amount (> 0) с кошелька.
+// Должна вызываться в той же транзакции, что и списание, после блокировки кошелька (FOR UPDATE),
+// чтобы параллельные списания не могли вместе превысить лимит.
+// Периоды считаются по UTC: сутки с полуночи, месяц с первого числа.
+func enforceDebitLimits(ctx context.Context, tx *sql.Tx, limitRepo repository.LimitRepository, movementRepo repository.MovementRepository, wallet models.Wallet, amount float64, isConversion bool) error {
+	walletLimits, err := loadWalletLimits(ctx, limitRepo, tx, wallet)
+	if err != nil {
+		return err
+	}
+	limits := walletLimits.Effective
+
+	now := time.Now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	nextDay := dayStart.AddDate(0, 0, 1)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	nextMonth := monthStart.AddDate(0, 1, 0)
+
+	if limits.MaxSingleWithdrawal != nil && amount > *limits.MaxSingleWithdrawal {
+		return &LimitExceededError{Limit: models.LimitMaxSingleWithdrawal, LimitValue: *limits.MaxSingleWithdrawal, Attempted: amount}
+	}
+
+	if limits.DailyWithdrawalTotal != nil {
+		spent, err := movementRepo.SumDebitsSince(ctx, tx, wallet.Number, dayStart)
+		if err != nil {
+			return err
+		}
+		if spent+amount > *limits.DailyWithdrawalTotal {
+			return &LimitExceededError{Limit: models.LimitDailyWithdrawalTotal, LimitValue: *limits.DailyWithdrawalTotal, Attempted: spent + amount, ResetsAt: &nextDay}
+		}
+	}
+
+	if limits.MonthlyWithdrawalTotal != nil {
+		spent, err := movementRepo.SumDebitsSince(ctx, tx, wallet.Number, monthStart)
+		if err != nil {
+			return err
+		}
+		if spent+amount > *limits.MonthlyWithdrawalTotal {
+			return &LimitExceededError{Limit: models.LimitMonthlyWithdrawalTotal, LimitValue: *limits.MonthlyWithdrawalTotal, Attempted: spent + amount, ResetsAt: &nextMonth}
+		}
+	}
+
+	if isConversion && limits.DailyConversionCount != nil {
+		count, err := movementRepo.CountMovementsSince(ctx, tx, wallet.Number, models.MovementKindConversion, dayStart)
+		if err != nil {
+			return err
+		}
+		if count+1 > *limits.DailyConversionCount {
+			return &LimitExceededError{Limit: models.LimitDailyConversionCount, LimitValue: float64(*limits.DailyConversionCount), Attempted: float64(count + 1), ResetsAt: &nextDay}
+		}
+	}
+
+	return nil
+}
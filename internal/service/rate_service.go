@@ -4,13 +4,23 @@ package service
 import (
 	"context"
 	"database/sql" // Понадобится для передачи *sql.DB в репозиторий
+	"errors"
 	"fmt"
 	"log" // Используйте структурированный логгер
 
+	"currency-service/internal/apperrors"
 	"currency-service/internal/models"
 	"currency-service/internal/repository"
 )
 
+var (
+	ErrRateNotPositive = apperrors.New(apperrors.CodeRateNotPositive, "курс валюты должен быть положительным числом")
+	ErrNoRates         = apperrors.New(apperrors.CodeNoRates, "в системе нет зарегистрированных курсов валют")
+)
+
+// maxRatesLimit - сколько последних курсов можно запросить за раз (как размер страницы списка кошельков).
+const maxRatesLimit = maxWalletsPageSize
+
 type rateService struct {
 	repo repository.RateRepository
 	db   *sql.DB // Добавляем зависимость от *sql.DB для передачи в репозиторий
@@ -24,7 +34,7 @@ func NewRateService(repo repository.RateRepository, db *sql.DB) RateService {
 
 func (s *rateService) CreateRate(ctx context.Context, value float64) error {
 	if value <= 0 {
-		return fmt.Errorf("курс валюты должен быть положительным числом")
+		return ErrRateNotPositive
 	}
 
 	rate := models.Rate{
@@ -46,6 +56,9 @@ func (s *rateService) GetAverageRate(ctx context.Context, limit int) (models.Ave
 		limit = 10
 		log.Printf("Лимит не указан или некорректен, используется значение по умолчанию: %d\n", limit)
 	}
+	if limit > maxRatesLimit {
+		return models.AverageResponse{}, ErrInvalidPageSize
+	}
 
 	// Вызываем репозиторий, передавая *sql.DB
 	rates, err := s.repo.GetLatestRates(ctx, s.db, limit)
@@ -76,12 +89,46 @@ func (s *rateService) GetLatestRate(ctx context.Context) (models.Rate, error) {
 	rate, err := s.repo.GetLatestRate(ctx, s.db)
 	if err != nil {
 		log.Printf("Ошибка при вызове GetLatestRate из сервиса: %v\n", err)
-		// Обрабатываем ошибку "нет курсов" отдельно, если нужно
-		if err.Error() == "нет доступных курсов: sql: no rows in result set" {
-			// Можно вернуть кастомную ошибку сервисного уровня
-			return models.Rate{}, fmt.Errorf("в системе нет зарегистрированных курсов валют")
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Rate{}, ErrNoRates
 		}
 		return models.Rate{}, fmt.Errorf("не удалось получить последний курс: %w", err)
 	}
 	return rate, nil
 }
+
+// ListRates возвращает последние limit курсов (история курсов), не больше maxRatesLimit.
+func (s *rateService) ListRates(ctx context.Context, limit int) ([]models.Rate, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxRatesLimit {
+		return nil, ErrInvalidPageSize
+	}
+	rates, err := s.repo.GetLatestRates(ctx, s.db, limit)
+	if err != nil {
+		log.Printf("Ошибка при вызове GetLatestRates из сервиса: %v\n", err)
+		return nil, fmt.Errorf("не удалось получить последние курсы: %w", err)
+	}
+	return rates, nil
+}
+
+// ExportRates передает в fn последние limit курсов (0 - все), начиная с самого свежего, по мере чтения из БД.
+func (s *rateService) ExportRates(ctx context.Context, limit int, fn func(models.Rate) error) error {
+	if limit < 0 {
+		limit = 0
+	}
+	if err := s.repo.StreamRates(ctx, s.db, limit, fn); err != nil {
+		return fmt.Errorf("не удалось выгрузить курсы: %w", err)
+	}
+	return nil
+}
+
+// GetRatesVersion возвращает состояние курсов для HTTP-кэширования.
+func (s *rateService) GetRatesVersion(ctx context.Context) (models.DataVersion, error) {
+	version, err := s.repo.GetRatesVersion(ctx, s.db)
+	if err != nil {
+		return models.DataVersion{}, fmt.Errorf("не удалось получить версию курсов: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,140 @@
+// --- internal/service/reconciliation_service.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+)
+
+// Ошибки, связанные со сверкой
+var (
+	ErrReconciliationNotFound = apperrors.New(apperrors.CodeReconciliationNotFound, "сверка не найдена")
+)
+
+type reconciliationService struct {
+	reconciliationRepo repository.ReconciliationRepository
+	db                 *sql.DB
+	// tolerance - допустимое расхождение баланса с суммой движений.
+	// Балансы хранятся в REAL, поэтому сравнение точным быть не может.
+	tolerance float64
+}
+
+// NewReconciliationService создает новый экземпляр сервиса сверки.
+func NewReconciliationService(reconciliationRepo repository.ReconciliationRepository, db *sql.DB, tolerance float64) ReconciliationService {
+	return &reconciliationService{
+		reconciliationRepo: reconciliationRepo,
+		db:                 db,
+		tolerance:          tolerance,
+	}
+}
+
+// Reconcile пересчитывает баланс каждого кошелька по истории движений и сравнивает его с wallets.balance.
+// Подсчет выполняется в одной транзакции REPEATABLE READ только для чтения, поэтому балансы и движения
+// берутся из одного снимка БД и параллельные операции не дают ложных расхождений.
+func (s *reconciliationService) Reconcile(ctx context.Context) (models.ReconciliationReportResponse, error) {
+	run, err := s.reconciliationRepo.CreateRun(ctx, s.db)
+	if err != nil {
+		return models.ReconciliationReportResponse{}, fmt.Errorf("не удалось начать сверку: %w", err)
+	}
+
+	checked, discrepancies, scanErr := s.scan(ctx)
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+	run.WalletsChecked = checked
+	run.DiscrepanciesFound = len(discrepancies)
+	if scanErr != nil {
+		run.Error = scanErr.Error()
+	}
+
+	err = executeTx(ctx, s.db, func(tx *sql.Tx) error {
+		for i := range discrepancies {
+			discrepancies[i].RunID = run.ID
+			discrepancies[i].DetectedAt = finishedAt
+			if err := s.reconciliationRepo.CreateDiscrepancy(ctx, tx, discrepancies[i]); err != nil {
+				return err
+			}
+		}
+		return s.reconciliationRepo.FinishRun(ctx, tx, run)
+	})
+	if err != nil {
+		return models.ReconciliationReportResponse{}, fmt.Errorf("не удалось сохранить результаты сверки: %w", err)
+	}
+	if scanErr != nil {
+		return models.ReconciliationReportResponse{}, fmt.Errorf("сверка %d завершилась ошибкой: %w", run.ID, scanErr)
+	}
+
+	if len(discrepancies) > 0 {
+		log.Printf("Сверка %d: найдено расхождений - %d (проверено кошельков: %d)\n", run.ID, len(discrepancies), checked)
+	} else {
+		log.Printf("Сверка %d: расхождений нет (проверено кошельков: %d)\n", run.ID, checked)
+	}
+	if discrepancies == nil {
+		discrepancies = []models.Discrepancy{}
+	}
+	return models.ReconciliationReportResponse{Run: run, Discrepancies: discrepancies}, nil
+}
+
+// scan выполняет подсчет кошельков и поиск расхождений в одном снимке БД.
+func (s *reconciliationService) scan(ctx context.Context) (int, []models.Discrepancy, error) {
+	var checked int
+	var discrepancies []models.Discrepancy
+	err := executeTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		checked, err = s.reconciliationRepo.CountWallets(ctx, tx)
+		if err != nil {
+			return err
+		}
+		discrepancies, err = s.reconciliationRepo.FindDiscrepancies(ctx, tx, s.tolerance)
+		return err
+	}, withIsolation(sql.LevelRepeatableRead), readOnly())
+	if err != nil {
+		return checked, nil, err
+	}
+	return checked, discrepancies, nil
+}
+
+// ListRuns возвращает последние сверки.
+func (s *reconciliationService) ListRuns(ctx context.Context, limit int) (models.ListReconciliationRunsResponse, error) {
+	runs, err := s.reconciliationRepo.ListRuns(ctx, s.db, limit)
+	if err != nil {
+		return models.ListReconciliationRunsResponse{}, fmt.Errorf("не удалось получить список сверок: %w", err)
+	}
+	if runs == nil {
+		runs = []models.ReconciliationRun{}
+	}
+	return models.ListReconciliationRunsResponse{Runs: runs}, nil
+}
+
+// GetReport возвращает сверку и найденные ею расхождения.
+func (s *reconciliationService) GetReport(ctx context.Context, runID int64) (models.ReconciliationReportResponse, error) {
+	var run models.ReconciliationRun
+	var err error
+	if runID == 0 {
+		run, err = s.reconciliationRepo.GetLatestRun(ctx, s.db)
+	} else {
+		run, err = s.reconciliationRepo.GetRunByID(ctx, s.db, runID)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ReconciliationReportResponse{}, ErrReconciliationNotFound
+		}
+		return models.ReconciliationReportResponse{}, fmt.Errorf("ошибка получения сверки: %w", err)
+	}
+
+	discrepancies, err := s.reconciliationRepo.GetRunDiscrepancies(ctx, s.db, run.ID)
+	if err != nil {
+		return models.ReconciliationReportResponse{}, fmt.Errorf("не удалось получить расхождения: %w", err)
+	}
+	if discrepancies == nil {
+		discrepancies = []models.Discrepancy{}
+	}
+	return models.ReconciliationReportResponse{Run: run, Discrepancies: discrepancies}, nil
+}
@@ -0,0 +1,179 @@
+// --- internal/service/reversal_service.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// Ошибки, связанные с отменой движений
+var (
+	ErrMovementNotFound         = apperrors.New(apperrors.CodeMovementNotFound, "движение не найдено")
+	ErrMovementAlreadyReversed  = apperrors.New(apperrors.CodeMovementAlreadyReversed, "движение уже отменено")
+	ErrMovementNotReversible    = apperrors.New(apperrors.CodeMovementNotReversible, "движение этого вида нельзя отменить (допустимо: deposit, withdrawal, conversion)")
+	ErrMovementPartOfConversion = apperrors.New(apperrors.CodeMovementPartOfConversion, "движение является частью конвертации и отменяется только вместе с ней: отмените движение списания конвертации")
+)
+
+type reversalService struct {
+	walletRepo   repository.WalletRepository
+	movementRepo repository.MovementRepository
+	db           *sql.DB
+}
+
+// NewReversalService создает новый экземпляр сервиса отмены движений.
+func NewReversalService(walletRepo repository.WalletRepository, movementRepo repository.MovementRepository, db *sql.DB) ReversalService {
+	return &reversalService{
+		walletRepo:   walletRepo,
+		movementRepo: movementRepo,
+		db:           db,
+	}
+}
+
+// GetMovement возвращает движение по ID вместе со ссылками на отмену.
+func (s *reversalService) GetMovement(ctx context.Context, id int64) (models.Movement, error) {
+	movement, err := s.movementRepo.GetMovementByID(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Movement{}, ErrMovementNotFound
+		}
+		return models.Movement{}, fmt.Errorf("ошибка получения движения: %w", err)
+	}
+	return movement, nil
+}
+
+// ReverseMovement записывает движение, компенсирующее движение id, и связывает их.
+// Отмена конвертации возвращает исходную сумму по курсу исходной конвертации, а не по текущему,
+// и в той же транзакции отменяет связанные с ней движения: зачисление получателю и комиссию.
+// Эти движения по отдельности не отменяются - иначе одна из сторон конвертации осталась бы в силе.
+// Отмена зачисления списывает средства и невозможна, если их уже недостаточно (с учетом холдов и кредитного лимита).
+// Лимиты на списания к отменам не применяются: это исправление ошибки, а не операция клиента.
+func (s *reversalService) ReverseMovement(ctx context.Context, id int64) (models.ReversalResponse, error) {
+	var resp models.ReversalResponse
+
+	err := executeTx(ctx, s.db, func(tx *sql.Tx) error {
+		resp.LinkedReversals = nil
+
+		// Блокируем исходное движение, чтобы параллельная отмена ждала завершения этой
+		original, err := s.movementRepo.GetMovementByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrMovementNotFound
+			}
+			return fmt.Errorf("ошибка получения движения: %w", err)
+		}
+		resp.Original = original
+		if original.ReversedBy != nil {
+			return ErrMovementAlreadyReversed
+		}
+
+		legs := []models.Movement{original}
+		switch original.Kind {
+		case models.MovementKindDeposit, models.MovementKindWithdrawal:
+		case models.MovementKindConversion:
+			linked, err := s.movementRepo.ListConversionLegsForUpdate(ctx, tx, original.ID)
+			if err != nil {
+				return fmt.Errorf("ошибка получения движений конвертации: %w", err)
+			}
+			legs = append(legs, linked...)
+		case models.MovementKindConversionIn, models.MovementKindConversionFee, models.MovementKindFeeIncome:
+			return ErrMovementPartOfConversion
+		default:
+			return ErrMovementNotReversible
+		}
+
+		numbers := make([]string, 0, len(legs))
+		for _, leg := range legs {
+			if leg.ReversedBy != nil {
+				return ErrMovementAlreadyReversed
+			}
+			numbers = append(numbers, leg.WalletNumber)
+		}
+		// Кошельки всех движений блокируем в порядке возрастания номеров, как в переводах и конвертациях
+		wallets, err := lockWalletsSorted(ctx, tx, s.walletRepo, numbers...)
+		if err != nil {
+			return err
+		}
+
+		for i, leg := range legs {
+			wallet, found := wallets[leg.WalletNumber]
+			if !found {
+				return fmt.Errorf("кошелек %s движения %d не найден", leg.WalletNumber, leg.ID)
+			}
+			if err := checkWalletActive(wallet); err != nil {
+				return err
+			}
+			amount := reversalAmount(leg)
+			if i == 0 {
+				resp.NewBalance = wallet.Balance
+			}
+			if amount < 0 && availableFunds(wallet)+amount < 0 {
+				return ErrInsufficientFunds
+			}
+
+			reversal, err := recordMovement(ctx, tx, s.walletRepo, s.movementRepo, models.Movement{
+				WalletNumber: leg.WalletNumber,
+				Kind:         models.MovementKindReversal,
+				Amount:       amount,
+				BalanceAfter: wallet.Balance + amount,
+				Rate:         leg.Rate,
+				SourceAmount: leg.SourceAmount,
+				ReversalOf:   &leg.ID,
+			})
+			if err != nil {
+				// Уникальный индекс по reversal_of - отмена уже записана параллельным запросом
+				// (блокировка не спасает: подзапрос reversed_by видит снимок, сделанный до ожидания)
+				var pqErr *pq.Error
+				if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+					return ErrMovementAlreadyReversed
+				}
+				return fmt.Errorf("не удалось записать отмену движения %d: %w", leg.ID, err)
+			}
+			// Списание конвертации и комиссия относятся к одному кошельку - следующая отмена считается от нового баланса
+			wallets[leg.WalletNumber] = withBalanceDelta(wallet, amount)
+
+			if i == 0 {
+				resp.Reversal = reversal
+				resp.Original.ReversedBy = &reversal.ID
+			} else {
+				resp.LinkedReversals = append(resp.LinkedReversals, reversal)
+			}
+		}
+		resp.NewBalance = wallets[original.WalletNumber].Balance
+		return nil
+	})
+	if err != nil {
+		log.Printf("Ошибка в ReverseMovement после транзакции: %v", err)
+		switch {
+		case errors.Is(err, ErrMovementNotFound), errors.Is(err, ErrMovementAlreadyReversed),
+			errors.Is(err, ErrMovementNotReversible), errors.Is(err, ErrMovementPartOfConversion), errors.Is(err, ErrInsufficientFunds):
+			resp.Message = err.Error()
+		default:
+			resp.Message = "Ошибка при отмене движения"
+		}
+		return resp, err
+	}
+
+	log.Printf("Движение %d отменено движением %d (кошелек %s, сумма %.2f)\n", id, resp.Reversal.ID, resp.Reversal.WalletNumber, resp.Reversal.Amount)
+	resp.Message = "Движение успешно отменено"
+	resp.MessageCode = models.MessageMovementReversed
+	return resp, nil
+}
+
+// reversalAmount возвращает сумму движения, компенсирующего movement.
+func reversalAmount(movement models.Movement) float64 {
+	// Списание конвертации пересчитываем по сохраненному курсу. У конвертаций, записанных до появления курса
+	// в движениях, остается сумма самого движения - она и была списана по исходному курсу.
+	if movement.Kind == models.MovementKindConversion && movement.Rate != nil && movement.SourceAmount != nil {
+		return *movement.SourceAmount * *movement.Rate
+	}
+	return -movement.Amount
+}
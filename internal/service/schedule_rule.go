@@ -0,0 +1,217 @@
+// --- internal/service/schedule_rule.go ---
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+)
+
+// ErrInvalidCronExpr возвращается для некорректного cron-выражения.
+var ErrInvalidCronExpr = apperrors.New(apperrors.CodeInvalidCronExpr, "некорректное cron-выражение (ожидается 5 полей: минута час день месяц день_недели)")
+
+// cronSchedule - разобранное cron-выражение. Каждое поле хранит множество допустимых значений.
+type cronSchedule struct {
+	minutes  map[int]bool
+	hours    map[int]bool
+	days     map[int]bool
+	months   map[int]bool
+	weekdays map[int]bool
+	anyDay   bool // Поле "день месяца" равно *
+	anyWday  bool // Поле "день недели" равно *
+}
+
+// parseCron разбирает cron-выражение из 5 полей. Поддерживаются *, */n, a, a-b, a-b/n и списки через запятую.
+// День недели: 0-7 (0 и 7 - воскресенье). Время трактуется в UTC.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, ErrInvalidCronExpr
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := make([]map[int]bool, 5)
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: поле %q", ErrInvalidCronExpr, field)
+		}
+		sets[i] = set
+	}
+	// Воскресенье может быть задано как 7
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &cronSchedule{
+		minutes:  sets[0],
+		hours:    sets[1],
+		days:     sets[2],
+		months:   sets[3],
+		weekdays: sets[4],
+		anyDay:   fields[2] == "*",
+		anyWday:  fields[4] == "*",
+	}, nil
+}
+
+// parseCronField разбирает одно поле cron-выражения в множество значений из [min, max].
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			n, err := strconv.Atoi(part[idx+1:])
+			if err != nil || n <= 0 {
+				return nil, ErrInvalidCronExpr
+			}
+			rangePart, step = part[:idx], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil || a > b {
+				return nil, ErrInvalidCronExpr
+			}
+			lo, hi = a, b
+		default:
+			v, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, ErrInvalidCronExpr
+			}
+			lo, hi = v, v
+			if step > 1 { // "a/n" означает "от a до максимума с шагом n"
+				hi = max
+			}
+		}
+		if lo < min || hi > max {
+			return nil, ErrInvalidCronExpr
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// matchesDay проверяет день. Как в cron: если ограничены и день месяца, и день недели, достаточно совпадения одного из них.
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	dayOK := c.days[t.Day()]
+	wdayOK := c.weekdays[int(t.Weekday())]
+	switch {
+	case c.anyDay && c.anyWday:
+		return true
+	case c.anyDay:
+		return wdayOK
+	case c.anyWday:
+		return dayOK
+	default:
+		return dayOK || wdayOK
+	}
+}
+
+// next возвращает первый момент строго после after, подходящий под выражение.
+func (c *cronSchedule) next(after time.Time) (time.Time, bool) {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	// Ограничиваем поиск, чтобы выражения вроде "0 0 31 2 *" не зацикливали планировщик
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !c.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !c.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !c.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// addMonthsClamped прибавляет months месяцев к start, сохраняя число месяца start
+// или используя последний день месяца, если такого числа в нем нет (31 -> 30, 28/29).
+func addMonthsClamped(start time.Time, months int) time.Time {
+	firstOfMonth := time.Date(start.Year(), start.Month()+time.Month(months), 1,
+		start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := start.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+// nextScheduleRun вычисляет время следующего запуска расписания строго после after.
+// Для daily/weekly/monthly опорной точкой служит start_at. Возвращает nil, если запусков больше не будет.
+func nextScheduleRun(schedule models.Schedule, after time.Time) (*time.Time, error) {
+	start := schedule.StartAt.UTC()
+	var next time.Time
+
+	switch schedule.Recurrence {
+	case models.ScheduleRecurrenceOnce:
+		return nil, nil
+	case models.ScheduleRecurrenceDaily, models.ScheduleRecurrenceWeekly:
+		step := 1
+		if schedule.Recurrence == models.ScheduleRecurrenceWeekly {
+			step = 7
+		}
+		next = start
+		if !next.After(after) {
+			// Перескакиваем сразу к нужному периоду, чтобы не перебирать пропущенные запуски по одному
+			periods := int(after.Sub(start).Hours()/24) / step
+			next = start.AddDate(0, 0, periods*step)
+			for !next.After(after) {
+				next = next.AddDate(0, 0, step)
+			}
+		}
+	case models.ScheduleRecurrenceMonthly:
+		months := 0
+		next = start
+		if !next.After(after) {
+			months = (after.Year()-start.Year())*12 + int(after.Month()) - int(start.Month())
+			if months < 0 {
+				months = 0
+			}
+			next = addMonthsClamped(start, months)
+			for !next.After(after) {
+				months++
+				next = addMonthsClamped(start, months)
+			}
+		}
+	case models.ScheduleRecurrenceCron:
+		cron, err := parseCron(schedule.CronExpr)
+		if err != nil {
+			return nil, err
+		}
+		from := after
+		if start.After(after) {
+			from = start.Add(-time.Minute) // Первый запуск не раньше start_at
+		}
+		var ok bool
+		next, ok = cron.next(from)
+		if !ok {
+			return nil, nil
+		}
+	default:
+		return nil, ErrInvalidRecurrence
+	}
+	return &next, nil
+}
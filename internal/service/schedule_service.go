@@ -0,0 +1,359 @@
+// --- internal/service/schedule_service.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+)
+
+// Ошибки, связанные с расписаниями
+var (
+	ErrScheduleNotFound         = apperrors.New(apperrors.CodeScheduleNotFound, "расписание не найдено")
+	ErrScheduleNotActive        = apperrors.New(apperrors.CodeScheduleNotActive, "расписание уже выполнено или отключено")
+	ErrInvalidScheduleOperation = apperrors.New(apperrors.CodeInvalidScheduleOperation, "некорректная операция расписания (допустимо: balance_update, transfer, convert)")
+	ErrInvalidScheduleAmount    = apperrors.New(apperrors.CodeInvalidScheduleAmount, "некорректная сумма операции расписания")
+	ErrInvalidRecurrence        = apperrors.New(apperrors.CodeInvalidRecurrence, "некорректное правило повторения (допустимо: once, daily, weekly, monthly, cron)")
+	ErrScheduleHasNoRuns        = apperrors.New(apperrors.CodeScheduleHasNoRuns, "по cron-выражению не найдено ни одного запуска")
+)
+
+// dueSchedulesBatchSize - сколько расписаний обрабатывается за один тик фоновой задачи.
+const dueSchedulesBatchSize = 100
+
+type scheduleService struct {
+	scheduleRepo repository.ScheduleRepository
+	walletSvc    WalletService // Операции выполняются через сервис кошельков со всеми его проверками
+	db           *sql.DB
+	retryDelay   time.Duration // Через сколько повторить неудачный запуск
+	maxFailures  int           // После скольких неудач подряд расписание отключается
+}
+
+// NewScheduleService создает новый экземпляр сервиса расписаний.
+func NewScheduleService(scheduleRepo repository.ScheduleRepository, walletSvc WalletService, db *sql.DB,
+	retryDelay time.Duration, maxFailures int) ScheduleService {
+	return &scheduleService{
+		scheduleRepo: scheduleRepo,
+		walletSvc:    walletSvc,
+		db:           db,
+		retryDelay:   retryDelay,
+		maxFailures:  maxFailures,
+	}
+}
+
+// validateScheduleRequest проверяет операцию, кошельки, сумму и правило повторения.
+func validateScheduleRequest(req models.CreateScheduleRequest) error {
+	if err := validateWalletNumber(req.WalletNumber); err != nil {
+		return err
+	}
+	if req.UserID != "" && !userIDRegex.MatchString(req.UserID) {
+		return ErrInvalidUserID
+	}
+
+	switch req.Operation {
+	case models.ScheduleOperationBalanceUpdate:
+		if req.Amount == 0 {
+			return ErrInvalidScheduleAmount
+		}
+	case models.ScheduleOperationTransfer:
+		if err := validateWalletNumber(req.TargetWalletNumber); err != nil {
+			return err
+		}
+		if req.TargetWalletNumber == req.WalletNumber {
+			return ErrSameWalletTransfer
+		}
+		if req.Amount <= 0 {
+			return ErrInvalidScheduleAmount
+		}
+	case models.ScheduleOperationConvert:
+		if req.Amount <= 0 {
+			return ErrInvalidScheduleAmount
+		}
+	default:
+		return ErrInvalidScheduleOperation
+	}
+	if req.Operation != models.ScheduleOperationTransfer && req.TargetWalletNumber != "" {
+		return ErrInvalidScheduleOperation
+	}
+
+	switch req.Recurrence {
+	case models.ScheduleRecurrenceOnce, models.ScheduleRecurrenceDaily,
+		models.ScheduleRecurrenceWeekly, models.ScheduleRecurrenceMonthly:
+		if req.CronExpr != "" {
+			return ErrInvalidRecurrence
+		}
+	case models.ScheduleRecurrenceCron:
+		if _, err := parseCron(req.CronExpr); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidRecurrence
+	}
+	return nil
+}
+
+// CreateSchedule сохраняет новое расписание и вычисляет время его первого запуска.
+func (s *scheduleService) CreateSchedule(ctx context.Context, req models.CreateScheduleRequest) (models.Schedule, error) {
+	if err := validateScheduleRequest(req); err != nil {
+		return models.Schedule{}, err
+	}
+	// Кошелек должен существовать. Запуски выполняются с правами создателя и проверяют их заново,
+	// а здесь право списывать с кошелька владельца без user_id проверяется заранее
+	wallet, err := s.walletSvc.GetWallet(ctx, req.WalletNumber, nil)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	if req.Operation == models.ScheduleOperationBalanceUpdate && req.Amount < 0 && req.UserID == "" && !req.Staff && wallet.OwnerID != "" {
+		return models.Schedule{}, ErrOwnerRequired
+	}
+	if wallet.Status == models.WalletStatusClosed {
+		return models.Schedule{}, ErrWalletClosed
+	}
+
+	startAt := time.Now().UTC().Truncate(time.Second)
+	if req.StartAt != nil {
+		startAt = req.StartAt.UTC()
+	}
+	schedule := models.Schedule{
+		Operation:          req.Operation,
+		WalletNumber:       req.WalletNumber,
+		TargetWalletNumber: req.TargetWalletNumber,
+		Amount:             req.Amount,
+		UserID:             req.UserID,
+		Recurrence:         req.Recurrence,
+		CronExpr:           req.CronExpr,
+		StartAt:            startAt,
+		Status:             models.ScheduleStatusActive,
+		CreatedBy:          req.CreatedBy,
+		CreatedByStaff:     req.Staff,
+	}
+
+	// Первый запуск: в start_at для всех правил, кроме cron (первое совпадение не раньше start_at)
+	firstRun := startAt
+	if req.Recurrence == models.ScheduleRecurrenceCron {
+		next, err := nextScheduleRun(schedule, startAt.Add(-time.Second))
+		if err != nil {
+			return models.Schedule{}, err
+		}
+		if next == nil {
+			return models.Schedule{}, ErrScheduleHasNoRuns
+		}
+		firstRun = *next
+	}
+	schedule.NextRunAt = &firstRun
+
+	return s.scheduleRepo.CreateSchedule(ctx, s.db, schedule)
+}
+
+// GetSchedule возвращает расписание по ID.
+func (s *scheduleService) GetSchedule(ctx context.Context, id int64) (models.Schedule, error) {
+	schedule, err := s.scheduleRepo.GetScheduleByID(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Schedule{}, ErrScheduleNotFound
+		}
+		return models.Schedule{}, fmt.Errorf("ошибка получения расписания: %w", err)
+	}
+	return schedule, nil
+}
+
+// ListScheduleRuns возвращает историю запусков расписания.
+func (s *scheduleService) ListScheduleRuns(ctx context.Context, id int64) (models.ListScheduleRunsResponse, error) {
+	if _, err := s.GetSchedule(ctx, id); err != nil {
+		return models.ListScheduleRunsResponse{}, err
+	}
+	runs, err := s.scheduleRepo.GetScheduleRuns(ctx, s.db, id)
+	if err != nil {
+		return models.ListScheduleRunsResponse{}, fmt.Errorf("не удалось получить историю запусков: %w", err)
+	}
+	if runs == nil {
+		runs = []models.ScheduleRun{}
+	}
+	return models.ListScheduleRunsResponse{Runs: runs}, nil
+}
+
+// CancelSchedule отключает активное расписание по запросу пользователя.
+func (s *scheduleService) CancelSchedule(ctx context.Context, id int64) (models.Schedule, error) {
+	var schedule models.Schedule
+	err := executeTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		schedule, err = s.scheduleRepo.GetScheduleByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrScheduleNotFound
+			}
+			return fmt.Errorf("ошибка получения расписания: %w", err)
+		}
+		if schedule.Status != models.ScheduleStatusActive {
+			return ErrScheduleNotActive
+		}
+
+		schedule.Status = models.ScheduleStatusDisabled
+		schedule.NextRunAt = nil
+		schedule.DisabledReason = "отменено пользователем"
+		return s.scheduleRepo.UpdateScheduleState(ctx, tx, schedule)
+	})
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	log.Printf("Расписание %d отменено\n", id)
+	return schedule, nil
+}
+
+// RunDueSchedules выполняет все расписания, время запуска которых наступило. Возвращает число выполненных запусков.
+// Каждое расписание обрабатывается в своей транзакции с блокировкой SKIP LOCKED, поэтому
+// несколько экземпляров сервиса не выполнят одно и то же расписание дважды.
+func (s *scheduleService) RunDueSchedules(ctx context.Context) (int, error) {
+	ids, err := s.scheduleRepo.ListDueScheduleIDs(ctx, s.db, dueSchedulesBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось получить готовые к запуску расписания: %w", err)
+	}
+
+	processed := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return processed, ctx.Err()
+		}
+		ran, err := s.runSchedule(ctx, id)
+		if err != nil {
+			log.Printf("Ошибка обработки расписания %d: %v\n", id, err)
+			continue
+		}
+		if ran {
+			processed++
+		}
+	}
+	if processed > 0 {
+		log.Printf("Выполнено запусков расписаний: %d\n", processed)
+	}
+	return processed, nil
+}
+
+// runSchedule выполняет один запуск расписания и сохраняет его результат.
+// Операция выполняется в той же транзакции, что и запись запуска: если транзакция откатится или
+// будет повторена после конфликта, операция не останется выполненной без записи о запуске.
+// Возвращает false, если расписание уже обрабатывается другим исполнителем или больше не готово к запуску.
+func (s *scheduleService) runSchedule(ctx context.Context, id int64) (bool, error) {
+	ran := false
+	err := executeTx(ctx, s.db, func(tx *sql.Tx) error {
+		schedule, err := s.scheduleRepo.LockDueSchedule(ctx, tx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		now := time.Now().UTC()
+		opErr := s.executeOperation(withTx(ctx, tx), schedule)
+		if isRetryableTxError(opErr) {
+			// Конфликт прерывает всю транзакцию - повторяем запуск целиком, а не записываем его неудачным
+			return opErr
+		}
+		run := models.ScheduleRun{ScheduleID: schedule.ID, Status: models.ScheduleRunSucceeded}
+		schedule.LastRunAt = &now
+
+		if opErr == nil {
+			schedule.ConsecutiveFailures = 0
+			next, err := nextScheduleRun(schedule, now)
+			if err != nil {
+				return err
+			}
+			schedule.NextRunAt = next
+			if next == nil {
+				schedule.Status = models.ScheduleStatusCompleted
+			}
+		} else {
+			log.Printf("Запуск расписания %d завершился ошибкой: %v\n", schedule.ID, opErr)
+			run.Status = models.ScheduleRunFailed
+			run.Error = opErr.Error()
+			schedule.ConsecutiveFailures++
+			s.applyFailure(&schedule, opErr, now)
+		}
+
+		if err := s.scheduleRepo.CreateScheduleRun(ctx, tx, run); err != nil {
+			return err
+		}
+		if err := s.scheduleRepo.UpdateScheduleState(ctx, tx, schedule); err != nil {
+			return err
+		}
+		ran = true
+		return nil
+	})
+	return ran, err
+}
+
+// applyFailure решает, повторить ли расписание позже или отключить его.
+// Ошибки, которые не исправятся сами (кошелек не найден, чужой кошелек и т.п.), отключают расписание сразу.
+// Нехватка средств, превышение лимитов, заморозка кошелька и временные ошибки повторяются через retryDelay до maxFailures раз подряд.
+func (s *scheduleService) applyFailure(schedule *models.Schedule, opErr error, now time.Time) {
+	switch {
+	case isPermanentScheduleError(opErr):
+		schedule.Status = models.ScheduleStatusDisabled
+		schedule.NextRunAt = nil
+		schedule.DisabledReason = opErr.Error()
+	case s.maxFailures > 0 && schedule.ConsecutiveFailures >= s.maxFailures:
+		schedule.Status = models.ScheduleStatusDisabled
+		schedule.NextRunAt = nil
+		schedule.DisabledReason = fmt.Sprintf("%d неудачных запусков подряд: %v", schedule.ConsecutiveFailures, opErr)
+	default:
+		retryAt := now.Add(s.retryDelay)
+		schedule.NextRunAt = &retryAt
+	}
+	if schedule.Status == models.ScheduleStatusDisabled {
+		log.Printf("Расписание %d отключено: %s\n", schedule.ID, schedule.DisabledReason)
+	}
+}
+
+// isPermanentScheduleError определяет ошибки, при которых повторный запуск не имеет смысла.
+func isPermanentScheduleError(err error) bool {
+	return errors.Is(err, ErrInvalidWalletNumber) ||
+		errors.Is(err, ErrWalletNotFound) ||
+		errors.Is(err, ErrTargetWalletMissing) ||
+		errors.Is(err, ErrWithdrawNonExistent) ||
+		errors.Is(err, ErrSameWalletTransfer) ||
+		errors.Is(err, ErrInvalidTransfer) ||
+		errors.Is(err, ErrInvalidUserID) ||
+		errors.Is(err, ErrUserNotFound) ||
+		errors.Is(err, ErrWalletOwnerMismatch) ||
+		errors.Is(err, ErrOwnerRequired) ||
+		errors.Is(err, ErrWalletClosed) ||
+		errors.Is(err, ErrInvalidScheduleOperation)
+}
+
+// executeOperation выполняет операцию расписания через сервис кошельков с правами создателя расписания:
+// владелец кошелька проверяется при каждом запуске (он мог смениться после создания расписания).
+func (s *scheduleService) executeOperation(ctx context.Context, schedule models.Schedule) error {
+	var err error
+	switch schedule.Operation {
+	case models.ScheduleOperationBalanceUpdate:
+		_, err = s.walletSvc.UpdateBalance(ctx, models.UpdateBalanceRequest{
+			WalletNumber: schedule.WalletNumber,
+			Amount:       schedule.Amount,
+			UserID:       schedule.UserID,
+			Staff:        schedule.CreatedByStaff,
+		})
+	case models.ScheduleOperationTransfer:
+		_, err = s.walletSvc.Transfer(ctx, models.TransferRequest{
+			FromWalletNumber: schedule.WalletNumber,
+			ToWalletNumber:   schedule.TargetWalletNumber,
+			Amount:           schedule.Amount,
+			UserID:           schedule.UserID,
+		})
+	case models.ScheduleOperationConvert:
+		_, err = s.walletSvc.ConvertAndDeduct(ctx, models.ConvertRequest{
+			SourceWalletNumber: schedule.WalletNumber,
+			AmountToConvert:    schedule.Amount,
+			UserID:             schedule.UserID,
+		})
+	default:
+		err = ErrInvalidScheduleOperation
+	}
+	return err
+}
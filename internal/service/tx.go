@@ -0,0 +1,201 @@
+// --- internal/service/tx.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"currency-service/internal/repository"
+
+	"github.com/lib/pq"
+)
+
+// ErrInvalidIsolationLevel возвращается ParseIsolationLevel для неизвестного уровня изоляции.
+var ErrInvalidIsolationLevel = errors.New("неизвестный уровень изоляции (ожидается read_committed, repeatable_read или serializable)")
+
+// TxSettings - настройки транзакций, общие для всех сервисов.
+type TxSettings struct {
+	Isolation   sql.IsolationLevel // Уровень изоляции по умолчанию (sql.LevelDefault - уровень сервера БД)
+	MaxAttempts int                // Сколько раз выполнять транзакцию при повторяемых ошибках (1 - без повторов)
+	BaseDelay   time.Duration      // Пауза перед первым повтором, удваивается с каждой попыткой
+	MaxDelay    time.Duration      // Верхняя граница паузы между попытками
+}
+
+// DefaultTxSettings возвращает настройки транзакций по умолчанию.
+func DefaultTxSettings() TxSettings {
+	return TxSettings{
+		Isolation:   sql.LevelDefault,
+		MaxAttempts: 3,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    200 * time.Millisecond,
+	}
+}
+
+// txSettings задаются один раз при старте через ConfigureTransactions.
+var txSettings = DefaultTxSettings()
+
+// ConfigureTransactions устанавливает настройки транзакций. Вызывается при старте до обработки запросов.
+// Некорректные значения заменяются значениями по умолчанию.
+func ConfigureTransactions(settings TxSettings) {
+	defaults := DefaultTxSettings()
+	if settings.MaxAttempts < 1 {
+		settings.MaxAttempts = defaults.MaxAttempts
+	}
+	if settings.BaseDelay <= 0 {
+		settings.BaseDelay = defaults.BaseDelay
+	}
+	if settings.MaxDelay < settings.BaseDelay {
+		settings.MaxDelay = settings.BaseDelay
+	}
+	txSettings = settings
+}
+
+// ParseIsolationLevel разбирает название уровня изоляции из конфигурации. Пустая строка - уровень сервера БД.
+func ParseIsolationLevel(name string) (sql.IsolationLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "default":
+		return sql.LevelDefault, nil
+	case "read_committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable_read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	default:
+		return sql.LevelDefault, fmt.Errorf("%w: %q", ErrInvalidIsolationLevel, name)
+	}
+}
+
+// txOption изменяет параметры отдельной транзакции.
+type txOption func(*sql.TxOptions)
+
+// withIsolation задает уровень изоляции транзакции вместо уровня по умолчанию.
+func withIsolation(level sql.IsolationLevel) txOption {
+	return func(opts *sql.TxOptions) { opts.Isolation = level }
+}
+
+// txContextKey - ключ контекста, под которым withTx сохраняет транзакцию вызывающего кода.
+type txContextKey struct{}
+
+// withTx возвращает контекст, в котором executeTx выполняет операции сервисов внутри tx, а не в своей транзакции.
+// Так результат операции фиксируется или откатывается вместе с остальными изменениями вызывающего кода.
+func withTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// readOnly делает транзакцию транзакцией только для чтения.
+func readOnly() txOption {
+	return func(opts *sql.TxOptions) { opts.ReadOnly = true }
+}
+
+// isRetryableTxError сообщает, можно ли повторить транзакцию целиком после ошибки:
+// конфликт сериализации (40001), взаимоблокировка (40P01) или гонка при создании кошелька -
+// при повторе кошелек уже найдется и операция выполнится как обновление.
+func isRetryableTxError(err error) bool {
+	if errors.Is(err, repository.ErrWalletExists) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "40001" || pqErr.Code == "40P01" // serialization_failure, deadlock_detected
+	}
+	return false
+}
+
+// retryDelay возвращает паузу перед повтором номер attempt (с 1): экспоненциальный рост
+// с ограничением сверху и случайным разбросом, чтобы конкурирующие запросы не столкнулись снова.
+func retryDelay(settings TxSettings, attempt int) time.Duration {
+	delay := settings.MaxDelay
+	if shift := attempt - 1; shift < 16 {
+		delay = min(settings.BaseDelay<<shift, settings.MaxDelay)
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// executeTx выполняет fn внутри транзакции: коммитит при успехе и откатывает при ошибке.
+// Используется всеми сервисами, которым нужны атомарные операции с кошельками.
+// При повторяемых ошибках (см. isRetryableTxError) транзакция выполняется заново, поэтому fn
+// не должна накапливать состояние между вызовами.
+// Если контекст несет транзакцию вызывающего кода (см. withTx), fn выполняется в ней (см. runNestedTx).
+func executeTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error, opts ...txOption) error {
+	if tx, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return runNestedTx(ctx, tx, fn)
+	}
+
+	settings := txSettings
+	txOpts := &sql.TxOptions{Isolation: settings.Isolation}
+	for _, opt := range opts {
+		opt(txOpts)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, db, txOpts, fn)
+		if err == nil || attempt >= settings.MaxAttempts || !isRetryableTxError(err) {
+			return err
+		}
+		delay := retryDelay(settings, attempt)
+		log.Printf("Повтор транзакции через %v (попытка %d из %d) после ошибки: %v", delay, attempt+1, settings.MaxAttempts, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// runTx выполняет одну попытку транзакции.
+func runTx(ctx context.Context, db *sql.DB, txOpts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, txOpts) // Начинаем транзакцию
+	if err != nil {
+		log.Printf("Ошибка начала транзакции: %v", err)
+		return fmt.Errorf("внутренняя ошибка сервера (tx begin): %w", err)
+	}
+
+	err = fn(tx) // Выполняем переданную функцию внутри транзакции
+
+	if err != nil {
+		// Если функция вернула ошибку, откатываем транзакцию
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Ошибка отката транзакции после ошибки %v: %v", err, rbErr)
+			return fmt.Errorf("внутренняя ошибка сервера (tx rollback after error): %w", err) // Возвращаем исходную ошибку
+		}
+		log.Printf("Транзакция отменена из-за ошибки: %v", err)
+		return err // Возвращаем исходную ошибку (например, ErrInsufficientFunds)
+	}
+
+	// Если функция выполнилась успешно, коммитим транзакцию
+	if err := tx.Commit(); err != nil {
+		log.Printf("Ошибка коммита транзакции: %v", err)
+		return fmt.Errorf("внутренняя ошибка сервера (tx commit): %w", err)
+	}
+
+	log.Println("Транзакция успешно завершена")
+	return nil
+}
+
+// runNestedTx выполняет fn внутри транзакции вызывающего кода под точкой сохранения: при ошибке откатываются
+// только изменения fn, и транзакция может продолжиться (например, чтобы записать неудачный запуск).
+// Фиксация и повторы при конфликтах остаются за внешним executeTx.
+func runNestedTx(ctx context.Context, tx *sql.Tx, fn func(tx *sql.Tx) error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT nested_tx"); err != nil {
+		return fmt.Errorf("внутренняя ошибка сервера (savepoint): %w", err)
+	}
+	if err := fn(tx); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT nested_tx"); rbErr != nil {
+			log.Printf("Ошибка отката к точке сохранения после ошибки %v: %v", err, rbErr)
+			return fmt.Errorf("внутренняя ошибка сервера (rollback to savepoint after error): %w", err)
+		}
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT nested_tx"); err != nil {
+		return fmt.Errorf("внутренняя ошибка сервера (release savepoint): %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// --- internal/service/user_service.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+	"currency-service/internal/repository"
+)
+
+// Ошибки, связанные с пользователями и владением кошельками
+var (
+	ErrUserNotFound        = apperrors.New(apperrors.CodeUserNotFound, "пользователь не найден")
+	ErrInvalidUserID       = apperrors.New(apperrors.CodeInvalidUserID, "некорректный формат ID пользователя (требуется UUID)")
+	ErrInvalidUserName     = apperrors.New(apperrors.CodeInvalidUserName, "имя и фамилия пользователя обязательны")
+	ErrWalletOwnerMismatch = apperrors.New(apperrors.CodeWalletOwnerMismatch, "кошелек не принадлежит указанному пользователю")
+)
+
+// Регулярное выражение для проверки ID пользователя (UUID)
+var userIDRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type userService struct {
+	userRepo   repository.UserRepository
+	walletRepo repository.WalletRepository
+	db         *sql.DB
+}
+
+// NewUserService создает новый экземпляр сервиса пользователей.
+func NewUserService(userRepo repository.UserRepository, walletRepo repository.WalletRepository, db *sql.DB) UserService {
+	return &userService{
+		userRepo:   userRepo,
+		walletRepo: walletRepo,
+		db:         db,
+	}
+}
+
+// CreateUser создает нового пользователя.
+func (s *userService) CreateUser(ctx context.Context, req models.CreateUserRequest) (models.User, error) {
+	firstName := strings.TrimSpace(req.FirstName)
+	lastName := strings.TrimSpace(req.LastName)
+	if firstName == "" || lastName == "" {
+		return models.User{}, ErrInvalidUserName
+	}
+
+	user, err := s.userRepo.CreateUser(ctx, s.db, models.User{FirstName: firstName, LastName: lastName})
+	if err != nil {
+		log.Printf("Ошибка при вызове CreateUser из сервиса: %v\n", err)
+		return models.User{}, fmt.Errorf("не удалось создать пользователя: %w", err)
+	}
+	return user, nil
+}
+
+// GetUser возвращает пользователя по ID.
+func (s *userService) GetUser(ctx context.Context, id string) (models.User, error) {
+	if !userIDRegex.MatchString(id) {
+		return models.User{}, ErrInvalidUserID
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, s.db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, ErrUserNotFound
+		}
+		return models.User{}, fmt.Errorf("не удалось получить пользователя: %w", err)
+	}
+	return user, nil
+}
+
+// ListUserWallets возвращает все кошельки пользователя.
+func (s *userService) ListUserWallets(ctx context.Context, id string) (models.ListWalletsResponse, error) {
+	// Проверяем, что пользователь существует, чтобы отличать "нет кошельков" от "нет пользователя"
+	if _, err := s.GetUser(ctx, id); err != nil {
+		return models.ListWalletsResponse{}, err
+	}
+
+	wallets, err := s.walletRepo.GetWalletsByOwner(ctx, s.db, id)
+	if err != nil {
+		log.Printf("Ошибка получения кошельков пользователя %s в сервисе: %v", id, err)
+		return models.ListWalletsResponse{}, fmt.Errorf("не удалось получить кошельки пользователя: %w", err)
+	}
+	if wallets == nil {
+		wallets = []models.Wallet{} // Отдаем пустой массив, а не null
+	}
+	return models.ListWalletsResponse{Wallets: wallets}, nil
+}
@@ -0,0 +1,281 @@
+// --- internal/service/wallet_batch.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"currency-service/internal/apperrors"
+	"currency-service/internal/models"
+)
+
+// maxBatchOperations - максимальное количество операций в одном пакете.
+const maxBatchOperations = 1000
+
+// Ошибки пакетных операций
+var (
+	ErrEmptyBatch            = apperrors.New(apperrors.CodeEmptyBatch, "пакет не содержит операций")
+	ErrBatchTooLarge         = apperrors.New(apperrors.CodeBatchTooLarge, fmt.Sprintf("пакет не может содержать больше %d операций", maxBatchOperations))
+	ErrInvalidBatchMode      = apperrors.New(apperrors.CodeInvalidBatchMode, "некорректный режим пакета (допустимо: atomic, best_effort)")
+	ErrInvalidBatchOperation = apperrors.New(apperrors.CodeInvalidBatchOperation, "некорректный тип операции (допустимо: deposit, withdrawal, transfer)")
+	ErrInvalidBatchAmount    = apperrors.New(apperrors.CodeInvalidBatchAmount, "сумма операции должна быть положительной")
+)
+
+// BatchItemError - ошибка операции, из-за которой отменен пакет в режиме atomic.
+type BatchItemError struct {
+	Index int   // Позиция операции в запросе
+	Err   error // Причина
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("операция %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// validateBatchOperation проверяет операцию пакета без обращения к БД.
+func validateBatchOperation(op models.BatchOperation) error {
+	switch op.Type {
+	case models.BatchOperationDeposit, models.BatchOperationWithdrawal:
+	case models.BatchOperationTransfer:
+		if err := validateWalletNumber(op.TargetWalletNumber); err != nil {
+			return err
+		}
+		if op.TargetWalletNumber == op.WalletNumber {
+			return ErrSameWalletTransfer
+		}
+	default:
+		return ErrInvalidBatchOperation
+	}
+	if err := validateWalletNumber(op.WalletNumber); err != nil {
+		return err
+	}
+	if op.Amount <= 0 {
+		return ErrInvalidBatchAmount
+	}
+	return nil
+}
+
+// setBatchItemError заполняет текст и код ошибки операции для клиента.
+// Текст ошибок без кода (внутренних) не раскрывается.
+func setBatchItemError(result *models.BatchOperationResult, err error) {
+	code := apperrors.CodeOf(err)
+	result.Code = string(code)
+	if code == apperrors.CodeInternal {
+		result.Error = "Внутренняя ошибка сервера"
+		return
+	}
+	result.Error = err.Error()
+}
+
+// ExecuteBatch выполняет пакет пополнений, списаний и переводов в одной транзакции.
+// Все кошельки пакета блокируются заранее в порядке возрастания номеров, поэтому параллельные пакеты
+// и одиночные операции не приводят к взаимоблокировкам. Операции выполняются в порядке запроса
+// и видят результат предыдущих (например, перевод может использовать только что зачисленные средства).
+// В режиме atomic первая же ошибка отменяет весь пакет и возвращается как *BatchItemError.
+// В режиме best_effort каждая операция выполняется в своей точке сохранения (SAVEPOINT),
+// и ошибка откатывает только ее.
+func (s *walletService) ExecuteBatch(ctx context.Context, req models.BatchRequest) (models.BatchResponse, error) {
+	// 1. Проверка пакета целиком
+	if req.Mode == "" {
+		req.Mode = models.BatchModeAtomic
+	}
+	if req.Mode != models.BatchModeAtomic && req.Mode != models.BatchModeBestEffort {
+		return models.BatchResponse{}, ErrInvalidBatchMode
+	}
+	if len(req.Operations) == 0 {
+		return models.BatchResponse{}, ErrEmptyBatch
+	}
+	if len(req.Operations) > maxBatchOperations {
+		return models.BatchResponse{}, ErrBatchTooLarge
+	}
+	if req.UserID != "" && !userIDRegex.MatchString(req.UserID) {
+		return models.BatchResponse{}, ErrInvalidUserID
+	}
+	atomic := req.Mode == models.BatchModeAtomic
+
+	// 2. Проверка отдельных операций
+	resp := models.BatchResponse{Mode: req.Mode, Results: make([]models.BatchOperationResult, len(req.Operations))}
+	invalid := make([]error, len(req.Operations))
+	numbers := make([]string, 0, len(req.Operations))
+	for i, op := range req.Operations {
+		resp.Results[i] = models.BatchOperationResult{
+			Index:              i,
+			Type:               op.Type,
+			WalletNumber:       op.WalletNumber,
+			TargetWalletNumber: op.TargetWalletNumber,
+			Amount:             op.Amount,
+			Status:             models.BatchItemSkipped,
+		}
+		if err := validateBatchOperation(op); err != nil {
+			if atomic {
+				// Некорректная операция отменяет пакет до начала транзакции
+				resp.Results[i].Status = models.BatchItemFailed
+				setBatchItemError(&resp.Results[i], err)
+				resp.Failed = 1
+				return resp, &BatchItemError{Index: i, Err: err}
+			}
+			invalid[i] = err
+			continue
+		}
+		numbers = append(numbers, op.WalletNumber)
+		if op.Type == models.BatchOperationTransfer {
+			numbers = append(numbers, op.TargetWalletNumber)
+		}
+	}
+
+	// 3. Выполнение в одной транзакции
+	err := s.executeTx(ctx, func(tx *sql.Tx) error {
+		wallets, err := s.lockWallets(ctx, tx, numbers...)
+		if err != nil {
+			return err
+		}
+
+		for i, op := range req.Operations {
+			result := &resp.Results[i]
+			if invalid[i] != nil {
+				result.Status = models.BatchItemFailed
+				setBatchItemError(result, invalid[i])
+				continue
+			}
+
+			if atomic {
+				if opErr := s.applyBatchOperation(ctx, tx, wallets, req.UserID, op, result); opErr != nil {
+					result.Status = models.BatchItemFailed
+					setBatchItemError(result, opErr)
+					return &BatchItemError{Index: i, Err: opErr}
+				}
+				result.Status = models.BatchItemSucceeded
+				continue
+			}
+
+			if _, err := tx.ExecContext(ctx, "SAVEPOINT batch_item"); err != nil {
+				return fmt.Errorf("не удалось создать точку сохранения: %w", err)
+			}
+			if opErr := s.applyBatchOperation(ctx, tx, wallets, req.UserID, op, result); opErr != nil {
+				if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_item"); err != nil {
+					return fmt.Errorf("не удалось откатить операцию %d: %w", i, err)
+				}
+				log.Printf("Операция %d пакета не выполнена: %v\n", i, opErr)
+				result.Status = models.BatchItemFailed
+				setBatchItemError(result, opErr)
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT batch_item"); err != nil {
+				return fmt.Errorf("не удалось зафиксировать операцию %d: %w", i, err)
+			}
+			result.Status = models.BatchItemSucceeded
+		}
+		return nil
+	})
+
+	// 4. Подсчет итогов
+	for i := range resp.Results {
+		result := &resp.Results[i]
+		if err != nil && result.Status == models.BatchItemSucceeded {
+			// Транзакция отменена - выполненные операции не сохранились
+			result.Status = models.BatchItemRolledBack
+			result.Balance, result.TargetBalance = 0, 0
+		}
+		switch result.Status {
+		case models.BatchItemSucceeded:
+			resp.Succeeded++
+		case models.BatchItemFailed:
+			resp.Failed++
+		}
+	}
+	if err != nil {
+		log.Printf("Ошибка в ExecuteBatch после транзакции: %v", err)
+		return resp, err
+	}
+
+	resp.Committed = true
+	log.Printf("Пакет из %d операций (%s) выполнен: успешно - %d, с ошибкой - %d\n", len(req.Operations), req.Mode, resp.Succeeded, resp.Failed)
+	return resp, nil
+}
+
+// applyBatchOperation выполняет одну операцию пакета над заранее заблокированными кошельками.
+// wallets обновляется только после успешной записи в БД, чтобы откат операции в best_effort
+// не оставлял в памяти балансы, которых нет в БД.
+func (s *walletService) applyBatchOperation(ctx context.Context, tx *sql.Tx, wallets map[string]models.Wallet,
+	userID string, op models.BatchOperation, result *models.BatchOperationResult) error {
+	wallet, found := wallets[op.WalletNumber]
+	if !found {
+		return ErrWalletNotFound
+	}
+
+	switch op.Type {
+	case models.BatchOperationDeposit:
+		if err := checkWalletActive(wallet); err != nil {
+			return err
+		}
+		newBalance := wallet.Balance + op.Amount
+		if err := applyBalanceChange(ctx, tx, s.walletRepo, s.movementRepo, wallet.Number, op.Amount, newBalance, models.MovementKindDeposit); err != nil {
+			return fmt.Errorf("не удалось пополнить кошелек: %w", err)
+		}
+		wallets[wallet.Number] = withBalanceDelta(wallet, op.Amount)
+		result.Balance = newBalance
+
+	case models.BatchOperationWithdrawal:
+		if err := s.checkBatchDebit(ctx, tx, wallet, userID, op.Amount); err != nil {
+			return err
+		}
+		newBalance := wallet.Balance - op.Amount
+		if err := applyBalanceChange(ctx, tx, s.walletRepo, s.movementRepo, wallet.Number, -op.Amount, newBalance, models.MovementKindWithdrawal); err != nil {
+			return fmt.Errorf("не удалось списать средства: %w", err)
+		}
+		wallets[wallet.Number] = withBalanceDelta(wallet, -op.Amount)
+		result.Balance = newBalance
+
+	case models.BatchOperationTransfer:
+		target, found := wallets[op.TargetWalletNumber]
+		if !found {
+			return ErrTargetWalletMissing
+		}
+		if err := s.checkBatchDebit(ctx, tx, wallet, userID, op.Amount); err != nil {
+			return err
+		}
+		if err := checkWalletActive(target); err != nil {
+			return err
+		}
+		fromBalance := wallet.Balance - op.Amount
+		toBalance := target.Balance + op.Amount
+		if err := applyBalanceChange(ctx, tx, s.walletRepo, s.movementRepo, wallet.Number, -op.Amount, fromBalance, models.MovementKindTransferOut); err != nil {
+			return fmt.Errorf("не удалось списать средства для перевода: %w", err)
+		}
+		if err := applyBalanceChange(ctx, tx, s.walletRepo, s.movementRepo, target.Number, op.Amount, toBalance, models.MovementKindTransferIn); err != nil {
+			return fmt.Errorf("не удалось зачислить средства перевода: %w", err)
+		}
+		wallets[wallet.Number] = withBalanceDelta(wallet, -op.Amount)
+		wallets[target.Number] = withBalanceDelta(target, op.Amount)
+		result.Balance = fromBalance
+		result.TargetBalance = toBalance
+	}
+	return nil
+}
+
+// checkBatchDebit проверяет, что с кошелька можно списать amount: владелец, статус, доступный остаток с учетом кредита и лимиты.
+// Как и в переводах, для кошелька с владельцем user_id пакета должен с ним совпадать.
+func (s *walletService) checkBatchDebit(ctx context.Context, tx *sql.Tx, wallet models.Wallet, userID string, amount float64) error {
+	if err := s.checkOwner(ctx, tx, wallet, userID, "", ""); err != nil {
+		return err
+	}
+	if err := checkWalletActive(wallet); err != nil {
+		return err
+	}
+	if availableFunds(wallet) < amount {
+		return ErrInsufficientFunds
+	}
+	return enforceDebitLimits(ctx, tx, s.limitRepo, s.movementRepo, wallet, amount, false)
+}
+
+// withBalanceDelta возвращает копию кошелька с измененными учетным и доступным балансом.
+func withBalanceDelta(wallet models.Wallet, delta float64) models.Wallet {
+	wallet.Balance += delta
+	wallet.AvailableBalance += delta
+	return wallet
+}
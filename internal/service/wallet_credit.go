@@ -0,0 +1,50 @@
+// --- internal/service/wallet_credit.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+
+	"currency-service/internal/models"
+)
+
+// SetCreditLimit устанавливает кредитный лимит кошелька: баланс сможет опускаться до -limit.
+// Лимит нельзя уменьшить ниже уже использованного кредита (текущего отрицательного баланса).
+func (s *walletService) SetCreditLimit(ctx context.Context, number string, limit float64) (models.Wallet, error) {
+	if err := validateWalletNumber(number); err != nil {
+		return models.Wallet{}, err
+	}
+	if limit < 0 || math.IsNaN(limit) || math.IsInf(limit, 0) {
+		return models.Wallet{}, ErrInvalidCreditLimit
+	}
+
+	var updated models.Wallet
+	err := s.executeTx(ctx, func(tx *sql.Tx) error {
+		wallet, err := s.walletRepo.GetWalletByNumberForUpdate(ctx, tx, number)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrWalletNotFound
+			}
+			return fmt.Errorf("ошибка получения кошелька: %w", err)
+		}
+		if wallet.Balance < -limit {
+			return ErrCreditLimitInUse
+		}
+		if err := s.walletRepo.UpdateCreditLimit(ctx, tx, number, limit); err != nil {
+			return fmt.Errorf("не удалось обновить кредитный лимит: %w", err)
+		}
+		updated, err = s.walletRepo.GetWalletByNumber(ctx, tx, number)
+		return err
+	})
+	if err != nil {
+		log.Printf("Ошибка в SetCreditLimit после транзакции: %v", err)
+		return models.Wallet{}, err
+	}
+
+	log.Printf("Кредитный лимит кошелька %s установлен: %.2f\n", number, limit)
+	return updated, nil
+}
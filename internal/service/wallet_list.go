@@ -0,0 +1,132 @@
+// --- internal/service/wallet_list.go ---
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"currency-service/internal/models"
+)
+
+// Размер страницы списка кошельков
+const (
+	defaultWalletsPageSize = 50
+	maxWalletsPageSize     = 500
+)
+
+// defaultMovementsPageSize - сколько последних движений кошелька возвращать по умолчанию
+const defaultMovementsPageSize = 10
+
+// normalizeListWalletsFilter проверяет фильтр, подставляет значения по умолчанию и декодирует курсор.
+// Курсор привязан к сортировке, с которой он был выдан: с другой сортировкой он недействителен.
+func normalizeListWalletsFilter(filter *models.ListWalletsFilter) (*models.WalletCursor, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultWalletsPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > maxWalletsPageSize {
+		return nil, ErrInvalidPageSize
+	}
+	return normalizeWalletsQuery(filter)
+}
+
+// normalizeWalletsQuery проверяет фильтры, сортировку и курсор без учета размера страницы.
+func normalizeWalletsQuery(filter *models.ListWalletsFilter) (*models.WalletCursor, error) {
+	if filter.SortBy == "" {
+		filter.SortBy = models.WalletSortCreatedAt
+	}
+	if filter.Order == "" {
+		filter.Order = models.SortOrderAsc
+	}
+	switch filter.SortBy {
+	case models.WalletSortCreatedAt, models.WalletSortBalance, models.WalletSortNumber:
+	default:
+		return nil, ErrInvalidSort
+	}
+	if filter.Order != models.SortOrderAsc && filter.Order != models.SortOrderDesc {
+		return nil, ErrInvalidSort
+	}
+
+	switch filter.Status {
+	case "", models.WalletStatusActive, models.WalletStatusFrozen, models.WalletStatusClosed:
+	default:
+		return nil, ErrInvalidWalletStatus
+	}
+	if filter.MinBalance != nil && filter.MaxBalance != nil && *filter.MinBalance > *filter.MaxBalance {
+		return nil, ErrInvalidListFilter
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && filter.CreatedFrom.After(*filter.CreatedTo) {
+		return nil, ErrInvalidListFilter
+	}
+
+	if filter.Cursor == "" {
+		return nil, nil
+	}
+	cursor, err := decodeWalletCursor(filter.Cursor)
+	if err != nil || cursor.SortBy != filter.SortBy || cursor.Order != filter.Order || !walletNumberRegex.MatchString(cursor.Number) {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// encodeWalletCursor кодирует позицию в непрозрачную для клиента строку.
+func encodeWalletCursor(cursor models.WalletCursor) string {
+	data, _ := json.Marshal(cursor) // Структура из простых полей всегда сериализуется
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeWalletCursor разбирает курсор, выданный encodeWalletCursor.
+func decodeWalletCursor(value string) (models.WalletCursor, error) {
+	var cursor models.WalletCursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+// ListWalletMovements возвращает последние движения по кошельку, начиная с самого нового.
+// Ограничение на количество то же, что и на размер страницы списка кошельков.
+func (s *walletService) ListWalletMovements(ctx context.Context, number string, limit int) ([]models.Movement, error) {
+	if err := validateWalletNumber(number); err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		limit = defaultMovementsPageSize
+	}
+	if limit < 0 || limit > maxWalletsPageSize {
+		return nil, ErrInvalidPageSize
+	}
+	movements, err := s.movementRepo.ListWalletMovements(ctx, s.db, number, limit)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить движения кошелька: %w", err)
+	}
+	return movements, nil
+}
+
+// ExportWallets передает в fn все кошельки, подходящие под фильтр (или первые filter.Limit, если он задан),
+// по мере чтения из БД. Фильтры, сортировка и курсор те же, что у ListWallets.
+func (s *walletService) ExportWallets(ctx context.Context, filter models.ListWalletsFilter, fn func(models.Wallet) error) error {
+	if filter.Limit < 0 {
+		return ErrInvalidPageSize
+	}
+	after, err := normalizeWalletsQuery(&filter)
+	if err != nil {
+		return err
+	}
+	if err := s.walletRepo.StreamWallets(ctx, s.db, filter, after, fn); err != nil {
+		return fmt.Errorf("не удалось выгрузить кошельки: %w", err)
+	}
+	return nil
+}
+
+// GetWalletsVersion возвращает состояние списка кошельков для HTTP-кэширования.
+func (s *walletService) GetWalletsVersion(ctx context.Context) (models.DataVersion, error) {
+	version, err := s.walletRepo.GetWalletsVersion(ctx, s.db)
+	if err != nil {
+		return models.DataVersion{}, fmt.Errorf("не удалось получить версию списка кошельков: %w", err)
+	}
+	return version, nil
+}
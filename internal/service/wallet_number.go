@@ -0,0 +1,62 @@
+// --- internal/service/wallet_number.go ---
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// Номер кошелька - 7 цифр. Номера, которые выделяет сервер, состоят из 6 значащих цифр и контрольной
+// цифры по алгоритму Луна: она ловит опечатку в одной цифре и большинство перестановок соседних цифр.
+// Новый кошелек создается только с корректной контрольной цифрой, поэтому пополнение по ошибочно
+// набранному номеру отклоняется, а не создает кошелек. Кошельки, созданные раньше, могут иметь любые
+// 7 цифр, поэтому при операциях с существующими кошельками проверяется только формат.
+
+// luhnCheckDigit вычисляет контрольную цифру для строки цифр payload.
+func luhnCheckDigit(payload string) byte {
+	sum := 0
+	double := true // Справа налево, начиная с цифры, стоящей перед контрольной
+	for i := len(payload) - 1; i >= 0; i-- {
+		d := int(payload[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// validateWalletNumber проверяет формат номера кошелька (7 цифр).
+func validateWalletNumber(number string) error {
+	if !walletNumberRegex.MatchString(number) {
+		return ErrInvalidWalletNumber
+	}
+	return nil
+}
+
+// validateNewWalletNumber проверяет формат и контрольную цифру номера создаваемого кошелька.
+func validateNewWalletNumber(number string) error {
+	if err := validateWalletNumber(number); err != nil {
+		return err
+	}
+	if luhnCheckDigit(number[:len(number)-1]) != number[len(number)-1] {
+		return ErrInvalidCheckDigit
+	}
+	return nil
+}
+
+// generateWalletNumber создает случайный номер кошелька с корректной контрольной цифрой.
+// Уникальность проверяет вызывающий код при вставке в БД.
+func generateWalletNumber() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", fmt.Errorf("не удалось сгенерировать номер кошелька: %w", err)
+	}
+	payload := fmt.Sprintf("%06d", n.Int64())
+	return payload + string(luhnCheckDigit(payload)), nil
+}
@@ -9,78 +9,155 @@ import (
 	"fmt"
 	"log"
 	"regexp" // Для валидации номера кошелька
+	"sort"
+	"strings"
+	"time"
 
+	"currency-service/internal/apperrors"
 	"currency-service/internal/models"
 	"currency-service/internal/repository"
 )
 
 // Определим кастомные ошибки для лучшей обработки в хендлере
 var (
-	ErrWalletNotFound      = errors.New("кошелек не найден")
-	ErrInsufficientFunds   = errors.New("недостаточно средств на счете")
-	ErrInvalidWalletNumber = errors.New("некорректный формат номера кошелька (требуется 7 цифр)")
-	ErrNegativeDeposit     = errors.New("сумма для создания кошелька должна быть положительной")
-	ErrWithdrawNonExistent = errors.New("нельзя списать средства с несуществующего кошелька")
-	ErrRateNotAvailable    = errors.New("не удалось получить актуальный курс валют")
+	ErrWalletNotFound       = apperrors.New(apperrors.CodeWalletNotFound, "кошелек не найден")
+	ErrInsufficientFunds    = apperrors.New(apperrors.CodeInsufficientFunds, "недостаточно средств на счете")
+	ErrInvalidWalletNumber  = apperrors.New(apperrors.CodeInvalidWalletNumber, "некорректный формат номера кошелька (требуется 7 цифр)")
+	ErrInvalidCheckDigit    = apperrors.Wrap(ErrInvalidWalletNumber, apperrors.CodeInvalidCheckDigit, "неверная контрольная цифра")
+	ErrNegativeDeposit      = apperrors.New(apperrors.CodeInvalidDepositAmount, "сумма для создания кошелька должна быть положительной")
+	ErrWithdrawNonExistent  = apperrors.New(apperrors.CodeWithdrawNonExistent, "нельзя списать средства с несуществующего кошелька")
+	ErrRateNotAvailable     = apperrors.New(apperrors.CodeRateNotAvailable, "не удалось получить актуальный курс валют")
+	ErrInvalidTransfer      = apperrors.New(apperrors.CodeInvalidTransferAmount, "сумма перевода должна быть положительной")
+	ErrSameWalletTransfer   = apperrors.New(apperrors.CodeSameWalletTransfer, "нельзя перевести средства на тот же кошелек")
+	ErrTargetWalletMissing  = apperrors.New(apperrors.CodeTargetWalletNotFound, "кошелек получателя не найден")
+	ErrInvalidPageSize      = apperrors.New(apperrors.CodeInvalidPageSize, fmt.Sprintf("размер страницы должен быть от 1 до %d", maxWalletsPageSize))
+	ErrInvalidCursor        = apperrors.New(apperrors.CodeInvalidCursor, "некорректный курсор страницы")
+	ErrInvalidSort          = apperrors.New(apperrors.CodeInvalidSort, "некорректная сортировка (допустимо: created_at, balance, wallet_number; порядок asc или desc)")
+	ErrInvalidWalletStatus  = apperrors.New(apperrors.CodeInvalidWalletStatus, "некорректный статус кошелька (допустимо: active, frozen, closed)")
+	ErrInvalidListFilter    = apperrors.New(apperrors.CodeInvalidListFilter, "некорректный фильтр: минимальная граница больше максимальной")
+	ErrAsOfInFuture         = apperrors.New(apperrors.CodeAsOfInFuture, "момент as_of не может быть в будущем")
+	ErrAsOfBeforeCreation   = apperrors.New(apperrors.CodeAsOfBeforeCreation, "на момент as_of кошелек еще не существовал")
+	ErrNumberSpaceExhausted = apperrors.New(apperrors.CodeWalletNumberSpaceExhausted, "не удалось выделить свободный номер кошелька, попробуйте еще раз")
+	ErrVersionMismatch      = apperrors.New(apperrors.CodeVersionMismatch, "кошелек был изменен другим запросом, получите актуальную версию и повторите")
+	ErrInvalidConvertAmount = apperrors.New(apperrors.CodeInvalidConvertAmount, "сумма для конвертации должна быть положительной")
+	ErrSameWalletConversion = apperrors.New(apperrors.CodeSameWalletConversion, "кошелек-получатель конвертации должен отличаться от кошелька-источника")
+	ErrDestinationOwner     = apperrors.Wrap(ErrWalletOwnerMismatch, apperrors.CodeDestinationOwnerMismatch, "кошелек-получатель принадлежит другому владельцу")
+	ErrOwnerRequired        = apperrors.Wrap(ErrWalletOwnerMismatch, apperrors.CodeWalletOwnerRequired, "для списания с кошелька владельца нужно указать его user_id")
+	ErrInvalidCreditLimit   = apperrors.New(apperrors.CodeInvalidCreditLimit, "кредитный лимит должен быть неотрицательным числом")
+	ErrCreditLimitInUse     = apperrors.New(apperrors.CodeCreditLimitInUse, "кредитный лимит не может быть меньше уже использованного кредита")
+	ErrWalletFrozen         = apperrors.New(apperrors.CodeWalletFrozen, "кошелек заморожен")
+	ErrWalletClosed         = apperrors.New(apperrors.CodeWalletClosed, "кошелек закрыт")
+	ErrWalletNotEmpty       = apperrors.New(apperrors.CodeWalletNotEmpty, "закрыть можно только кошелек с нулевым балансом и без активных холдов")
 )
 
 // Регулярное выражение для проверки номера кошелька (ровно 7 цифр)
 var walletNumberRegex = regexp.MustCompile(`^\d{7}$`)
 
 type walletService struct {
-	walletRepo repository.WalletRepository
-	rateRepo   repository.RateRepository // Добавляем зависимость для получения курса
-	db         *sql.DB                   // Для управления транзакциями
+	walletRepo   repository.WalletRepository
+	rateRepo     repository.RateRepository     // Добавляем зависимость для получения курса
+	userRepo     repository.UserRepository     // Для проверки владельцев кошельков
+	movementRepo repository.MovementRepository // Для записи истории движений
+	limitRepo    repository.LimitRepository    // Для проверки лимитов на списания
+	feeRepo      repository.FeeRepository      // Для расчета комиссий за конвертацию
+	db           *sql.DB                       // Для управления транзакциями
+	// implicitCreate разрешает UpdateBalance создавать кошелек при пополнении несуществующего номера.
+	// Если выключено, кошельки создаются только через CreateWallet.
+	implicitCreate bool
+	conversion     ConversionSettings
 }
 
 // NewWalletService создает новый экземпляр сервиса кошельков.
-func NewWalletService(walletRepo repository.WalletRepository, rateRepo repository.RateRepository, db *sql.DB) WalletService {
+func NewWalletService(walletRepo repository.WalletRepository, rateRepo repository.RateRepository, userRepo repository.UserRepository,
+	movementRepo repository.MovementRepository, limitRepo repository.LimitRepository, feeRepo repository.FeeRepository,
+	db *sql.DB, implicitCreate bool, conversion ConversionSettings) WalletService {
 	return &walletService{
-		walletRepo: walletRepo,
-		rateRepo:   rateRepo,
-		db:         db,
+		walletRepo:     walletRepo,
+		rateRepo:       rateRepo,
+		userRepo:       userRepo,
+		movementRepo:   movementRepo,
+		limitRepo:      limitRepo,
+		feeRepo:        feeRepo,
+		db:             db,
+		implicitCreate: implicitCreate,
+		conversion:     conversion,
 	}
 }
 
-// Helper function to execute database operations within a transaction
-func (s *walletService) executeTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
-	tx, err := s.db.BeginTx(ctx, nil) // Начинаем транзакцию
-	if err != nil {
-		log.Printf("Ошибка начала транзакции: %v", err)
-		return fmt.Errorf("внутренняя ошибка сервера (tx begin): %w", err)
-	}
+// availableFunds возвращает сумму, которую можно списать с кошелька:
+// доступный баланс (за вычетом холдов) плюс кредитный лимит. Без лимита баланс не опускается ниже нуля.
+func availableFunds(wallet models.Wallet) float64 {
+	return wallet.AvailableBalance + wallet.CreditLimit
+}
 
-	err = fn(tx) // Выполняем переданную функцию внутри транзакции
+// applyBalanceChange устанавливает новый баланс кошелька и записывает движение на сумму amount.
+// Должна вызываться внутри транзакции после блокировки кошелька.
+func applyBalanceChange(ctx context.Context, tx *sql.Tx, walletRepo repository.WalletRepository, movementRepo repository.MovementRepository,
+	number string, amount, newBalance float64, kind string) error {
+	_, err := recordMovement(ctx, tx, walletRepo, movementRepo, models.Movement{
+		WalletNumber: number,
+		Kind:         kind,
+		Amount:       amount,
+		BalanceAfter: newBalance,
+	})
+	return err
+}
 
-	if err != nil {
-		// Если функция вернула ошибку, откатываем транзакцию
-		if rbErr := tx.Rollback(); rbErr != nil {
-			log.Printf("Ошибка отката транзакции после ошибки %v: %v", err, rbErr)
-			return fmt.Errorf("внутренняя ошибка сервера (tx rollback after error): %w", err) // Возвращаем исходную ошибку
-		}
-		log.Printf("Транзакция отменена из-за ошибки: %v", err)
-		return err // Возвращаем исходную ошибку (например, ErrInsufficientFunds)
+// recordMovement устанавливает баланс кошелька movement.BalanceAfter и записывает движение.
+// В отличие от applyBalanceChange возвращает сохраненное движение (с ID) и позволяет указать курс и ссылку на отмененное движение.
+func recordMovement(ctx context.Context, tx *sql.Tx, walletRepo repository.WalletRepository, movementRepo repository.MovementRepository,
+	movement models.Movement) (models.Movement, error) {
+	if err := walletRepo.UpdateWalletBalance(ctx, tx, movement.WalletNumber, movement.BalanceAfter); err != nil {
+		return models.Movement{}, err
 	}
+	return movementRepo.CreateMovement(ctx, tx, movement)
+}
 
-	// Если функция выполнилась успешно, коммитим транзакцию
-	if err := tx.Commit(); err != nil {
-		log.Printf("Ошибка коммита транзакции: %v", err)
-		return fmt.Errorf("внутренняя ошибка сервера (tx commit): %w", err)
+// checkOwner проверяет, что запрос от имени userID (и, если указаны, firstName/lastName)
+// относится к владельцу кошелька. Кошельки без владельца проверку проходят.
+func (s *walletService) checkOwner(ctx context.Context, tx *sql.Tx, wallet models.Wallet, userID, firstName, lastName string) error {
+	if wallet.OwnerID == "" {
+		return nil
+	}
+	if !strings.EqualFold(wallet.OwnerID, userID) {
+		return ErrWalletOwnerMismatch
+	}
+	if firstName == "" && lastName == "" {
+		return nil
 	}
 
-	log.Println("Транзакция успешно завершена")
+	owner, err := s.userRepo.GetUserByID(ctx, tx, wallet.OwnerID)
+	if err != nil {
+		return fmt.Errorf("ошибка получения владельца кошелька: %w", err)
+	}
+	if firstName != "" && !strings.EqualFold(strings.TrimSpace(firstName), owner.FirstName) {
+		return ErrWalletOwnerMismatch
+	}
+	if lastName != "" && !strings.EqualFold(strings.TrimSpace(lastName), owner.LastName) {
+		return ErrWalletOwnerMismatch
+	}
 	return nil
 }
 
+// Helper function to execute database operations within a transaction
+func (s *walletService) executeTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return executeTx(ctx, s.db, fn)
+}
+
 // UpdateBalance создает или обновляет баланс кошелька.
 func (s *walletService) UpdateBalance(ctx context.Context, req models.UpdateBalanceRequest) (models.UpdateBalanceResponse, error) {
 	// 1. Валидация номера кошелька
-	if !walletNumberRegex.MatchString(req.WalletNumber) {
-		return models.UpdateBalanceResponse{}, ErrInvalidWalletNumber
+	if err := validateWalletNumber(req.WalletNumber); err != nil {
+		return models.UpdateBalanceResponse{}, err
+	}
+	if req.UserID != "" && !userIDRegex.MatchString(req.UserID) {
+		return models.UpdateBalanceResponse{}, ErrInvalidUserID
 	}
 
 	var finalBalance float64
-	var message string
+	var finalVersion int64
+	var movementID int64
+	var message, messageCode string
 
 	// 2. Выполняем операцию в транзакции
 	err := s.executeTx(ctx, func(tx *sql.Tx) error {
@@ -90,53 +167,127 @@ func (s *walletService) UpdateBalance(ctx context.Context, req models.UpdateBala
 		if err != nil {
 			// Если кошелек НЕ найден
 			if errors.Is(err, sql.ErrNoRows) {
+				// Клиент ожидал существующий кошелек определенной версии
+				if req.ExpectedVersion != nil {
+					return ErrVersionMismatch
+				}
+				// Неявное создание выключено - кошелек должен быть создан через POST /wallets
+				if !s.implicitCreate {
+					return ErrWalletNotFound
+				}
 				// Создать можно только с положительной суммой
 				if req.Amount <= 0 {
 					return ErrWithdrawNonExistent // Нельзя списать или создать с нулевым/отрицательным балансом
 				}
+				// Новый номер должен иметь корректную контрольную цифру: опечатка не создает кошелек
+				if checkErr := validateNewWalletNumber(req.WalletNumber); checkErr != nil {
+					return checkErr
+				}
+				// Если указан владелец - он должен существовать
+				if req.UserID != "" {
+					if _, userErr := s.userRepo.GetUserByID(ctx, tx, req.UserID); userErr != nil {
+						if errors.Is(userErr, sql.ErrNoRows) {
+							return ErrUserNotFound
+						}
+						return fmt.Errorf("ошибка получения владельца кошелька: %w", userErr)
+					}
+				}
 				// Создаем новый кошелек
 				newWallet := models.Wallet{
 					Number:  req.WalletNumber,
 					Balance: req.Amount,
+					OwnerID: req.UserID,
 				}
 				if createErr := s.walletRepo.CreateWallet(ctx, tx, newWallet); createErr != nil {
-					// Ошибка создания может быть из-за гонки (другой запрос успел создать) - проверяем
-					if createErr.Error() == fmt.Sprintf("кошелек с номером %s уже существует", req.WalletNumber) {
-						// Повторяем попытку получить кошелек, чтобы обработать как обновление
-						// Это редкий случай, но возможный при высокой конкуренции
-						log.Printf("Гонка при создании кошелька %s, повторная попытка...", req.WalletNumber)
-						// Можно было бы сделать goto или рефакторинг, но пока просто вернем ошибку для повтора операции клиентом
-						// или реализовать более сложный механизм повтора внутри сервиса
-						return fmt.Errorf("конфликт при создании кошелька, попробуйте еще раз")
+					// Другой запрос успел создать кошелек: executeTx повторит транзакцию,
+					// и при повторе кошелек будет найден и обновлен
+					if errors.Is(createErr, repository.ErrWalletExists) {
+						log.Printf("Гонка при создании кошелька %s, операция будет повторена как обновление", req.WalletNumber)
 					}
 					return fmt.Errorf("не удалось создать кошелек: %w", createErr)
 				}
+				movement, mvErr := s.movementRepo.CreateMovement(ctx, tx, models.Movement{
+					WalletNumber: newWallet.Number,
+					Kind:         models.MovementKindDeposit,
+					Amount:       newWallet.Balance,
+					BalanceAfter: newWallet.Balance,
+				})
+				if mvErr != nil {
+					return fmt.Errorf("не удалось записать движение по кошельку: %w", mvErr)
+				}
+				movementID = movement.ID
 				finalBalance = newWallet.Balance
-				message = "Кошелек успешно создан"
+				finalVersion = 1 // Версия нового кошелька
+				message, messageCode = "Кошелек успешно создан", models.MessageWalletCreated
 				return nil // Успешное создание
 			}
 			// Другая ошибка при получении кошелька
 			return fmt.Errorf("ошибка получения кошелька: %w", err)
 		}
 
-		// Если кошелек НАЙДЕН - обновляем баланс
+		finalVersion = wallet.Version // Версия на момент ошибки, если обновление не состоится
+
+		// Кошелек изменился с тех пор, как клиент его прочитал
+		if req.ExpectedVersion != nil && *req.ExpectedVersion != wallet.Version {
+			finalBalance = wallet.Balance
+			return ErrVersionMismatch
+		}
+
+		// Если кошелек НАЙДЕН и указан пользователь - он должен быть владельцем
+		if req.RequireOwner && wallet.OwnerID == "" {
+			return ErrWalletOwnerMismatch
+		}
+		if req.UserID != "" {
+			if ownerErr := s.checkOwner(ctx, tx, wallet, req.UserID, "", ""); ownerErr != nil {
+				return ownerErr
+			}
+		} else if req.Amount < 0 && wallet.OwnerID != "" && !req.Staff {
+			// Списать с кошелька владельца без его user_id может только сотрудник
+			return ErrOwnerRequired
+		}
+		if statusErr := checkWalletActive(wallet); statusErr != nil {
+			finalBalance = wallet.Balance
+			return statusErr
+		}
+
+		// Обновляем баланс
+		// Списывать можно только доступные средства (без учета активных холдов) и кредитный лимит
 		newBalance := wallet.Balance + req.Amount
-		if newBalance < 0 {
+		if availableFunds(wallet)+req.Amount < 0 {
 			// Недостаточно средств для списания
 			finalBalance = wallet.Balance // Баланс не меняется
 			message = "Недостаточно средств для списания"
 			return ErrInsufficientFunds // Возвращаем ошибку, транзакция будет отменена
 		}
 
-		// Обновляем баланс в БД
-		if updateErr := s.walletRepo.UpdateWalletBalance(ctx, tx, req.WalletNumber, newBalance); updateErr != nil {
+		// Для списаний проверяем лимиты кошелька
+		kind := models.MovementKindDeposit
+		if req.Amount < 0 {
+			kind = models.MovementKindWithdrawal
+			if limitErr := enforceDebitLimits(ctx, tx, s.limitRepo, s.movementRepo, wallet, -req.Amount, false); limitErr != nil {
+				finalBalance = wallet.Balance // Баланс не меняется
+				return limitErr
+			}
+		}
+
+		// Обновляем баланс в БД и записываем движение
+		movement, updateErr := recordMovement(ctx, tx, s.walletRepo, s.movementRepo, models.Movement{
+			WalletNumber: req.WalletNumber,
+			Kind:         kind,
+			Amount:       req.Amount,
+			BalanceAfter: newBalance,
+		})
+		if updateErr != nil {
 			return fmt.Errorf("не удалось обновить баланс: %w", updateErr)
 		}
+		movementID = movement.ID
 		finalBalance = newBalance
+		// Строка заблокирована, а UPDATE один - триггер увеличил версию ровно на 1
+		finalVersion = wallet.Version + 1
 		if req.Amount >= 0 {
-			message = "Баланс успешно пополнен"
+			message, messageCode = "Баланс успешно пополнен", models.MessageBalanceDeposited
 		} else {
-			message = "Списание успешно выполнено"
+			message, messageCode = "Списание успешно выполнено", models.MessageBalanceWithdrawn
 		}
 		return nil // Успешное обновление
 	})
@@ -151,6 +302,9 @@ func (s *walletService) UpdateBalance(ctx context.Context, req models.UpdateBala
 			userMessage = ErrInsufficientFunds.Error()
 		} else if errors.Is(err, ErrWithdrawNonExistent) {
 			userMessage = ErrWithdrawNonExistent.Error()
+		} else if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrWalletOwnerMismatch) || errors.Is(err, ErrLimitExceeded) ||
+			errors.Is(err, ErrVersionMismatch) || errors.Is(err, ErrWalletFrozen) || errors.Is(err, ErrWalletClosed) {
+			userMessage = err.Error()
 		}
 		// Не возвращаем сам err, если это внутренняя ошибка, а возвращаем userMessage
 		// Но если это 'бизнес-ошибка', то можно ее и вернуть
@@ -159,6 +313,7 @@ func (s *walletService) UpdateBalance(ctx context.Context, req models.UpdateBala
 		return models.UpdateBalanceResponse{
 			WalletNumber: req.WalletNumber,
 			NewBalance:   finalBalance, // Показываем баланс на момент ошибки (если он был прочитан)
+			Version:      finalVersion,
 			Message:      userMessage,
 		}, err // Возвращаем саму ошибку для определения статуса HTTP в хендлере
 	}
@@ -167,33 +322,169 @@ func (s *walletService) UpdateBalance(ctx context.Context, req models.UpdateBala
 	return models.UpdateBalanceResponse{
 		WalletNumber: req.WalletNumber,
 		NewBalance:   finalBalance,
+		Version:      finalVersion,
+		MovementID:   movementID,
 		Message:      message,
+		MessageCode:  messageCode,
 	}, nil
 }
 
-// ListWallets возвращает список всех кошельков.
-func (s *walletService) ListWallets(ctx context.Context) (models.ListWalletsResponse, error) {
-	wallets, err := s.walletRepo.GetAllWallets(ctx, s.db) // Используем *sql.DB напрямую, транзакция не нужна
+// ListWallets возвращает страницу кошельков с учетом фильтров, сортировки и курсора.
+func (s *walletService) ListWallets(ctx context.Context, filter models.ListWalletsFilter) (models.ListWalletsResponse, error) {
+	after, err := normalizeListWalletsFilter(&filter)
+	if err != nil {
+		return models.ListWalletsResponse{}, err
+	}
+
+	// Запрашиваем на один кошелек больше, чтобы понять, есть ли следующая страница
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+	wallets, err := s.walletRepo.ListWallets(ctx, s.db, filter, after) // Используем *sql.DB напрямую, транзакция не нужна
 	if err != nil {
 		log.Printf("Ошибка получения списка кошельков в сервисе: %v", err)
 		return models.ListWalletsResponse{}, fmt.Errorf("не удалось получить список кошельков: %w", err)
 	}
-	return models.ListWalletsResponse{Wallets: wallets}, nil
+
+	resp := models.ListWalletsResponse{Wallets: wallets}
+	if resp.Wallets == nil {
+		resp.Wallets = []models.Wallet{}
+	}
+	if len(wallets) > pageSize {
+		resp.Wallets = wallets[:pageSize]
+		last := resp.Wallets[pageSize-1]
+		resp.NextCursor = encodeWalletCursor(models.WalletCursor{
+			SortBy:    filter.SortBy,
+			Order:     filter.Order,
+			Balance:   last.Balance,
+			CreatedAt: last.CreatedAt,
+			Number:    last.Number,
+		})
+	}
+	return resp, nil
+}
+
+// walletNumberAttempts - сколько случайных номеров пробуется при создании кошелька, прежде чем вернуть ошибку.
+const walletNumberAttempts = 10
+
+// CreateWallet создает кошелек с нулевым балансом и выделенным сервером номером (с контрольной цифрой).
+// При совпадении номера с уже существующим пробуется другой номер.
+func (s *walletService) CreateWallet(ctx context.Context, req models.CreateWalletRequest) (models.Wallet, error) {
+	if req.UserID != "" && !userIDRegex.MatchString(req.UserID) {
+		return models.Wallet{}, ErrInvalidUserID
+	}
+	if req.Tier != "" && !tierRegex.MatchString(req.Tier) {
+		return models.Wallet{}, ErrInvalidTier
+	}
+
+	var created models.Wallet
+	err := s.executeTx(ctx, func(tx *sql.Tx) error {
+		if req.UserID != "" {
+			if _, err := s.userRepo.GetUserByID(ctx, tx, req.UserID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return ErrUserNotFound
+				}
+				return fmt.Errorf("ошибка получения владельца кошелька: %w", err)
+			}
+		}
+
+		for attempt := 0; attempt < walletNumberAttempts; attempt++ {
+			number, err := generateWalletNumber()
+			if err != nil {
+				return err
+			}
+			// Проверяем номер до вставки: ошибка уникальности прервала бы транзакцию
+			if _, err := s.walletRepo.GetWalletByNumber(ctx, tx, number); err == nil {
+				continue
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("ошибка проверки номера кошелька: %w", err)
+			}
+
+			if err := s.walletRepo.CreateWallet(ctx, tx, models.Wallet{Number: number, OwnerID: req.UserID}); err != nil {
+				return fmt.Errorf("не удалось создать кошелек: %w", err)
+			}
+			if req.Tier != "" {
+				if err := s.walletRepo.UpdateWalletTier(ctx, tx, number, req.Tier); err != nil {
+					return fmt.Errorf("не удалось установить уровень кошелька: %w", err)
+				}
+			}
+			created, err = s.walletRepo.GetWalletByNumber(ctx, tx, number)
+			if err != nil {
+				return fmt.Errorf("ошибка получения созданного кошелька: %w", err)
+			}
+			return nil
+		}
+		return ErrNumberSpaceExhausted
+	})
+	if err != nil {
+		return models.Wallet{}, err
+	}
+	return created, nil
+}
+
+// GetWallet возвращает кошелек по номеру. Если указан asOf, баланс на этот момент
+// восстанавливается суммированием движений по кошельку, созданных не позже asOf.
+func (s *walletService) GetWallet(ctx context.Context, number string, asOf *time.Time) (models.WalletDetailsResponse, error) {
+	if err := validateWalletNumber(number); err != nil {
+		return models.WalletDetailsResponse{}, err
+	}
+	if asOf != nil && asOf.After(time.Now()) {
+		return models.WalletDetailsResponse{}, ErrAsOfInFuture
+	}
+
+	wallet, err := s.walletRepo.GetWalletByNumber(ctx, s.db, number)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.WalletDetailsResponse{}, ErrWalletNotFound
+		}
+		return models.WalletDetailsResponse{}, fmt.Errorf("ошибка получения кошелька: %w", err)
+	}
+
+	resp := models.WalletDetailsResponse{Wallet: wallet, CreatedAt: wallet.CreatedAt, UpdatedAt: wallet.UpdatedAt}
+	if asOf == nil {
+		return resp, nil
+	}
+	if asOf.Before(wallet.CreatedAt) {
+		return models.WalletDetailsResponse{}, ErrAsOfBeforeCreation
+	}
+
+	// Для кошельков, созданных до ведения истории, она начинается с движения opening_balance
+	balance, err := s.movementRepo.SumMovementsUntil(ctx, s.db, number, *asOf)
+	if err != nil {
+		return models.WalletDetailsResponse{}, fmt.Errorf("не удалось рассчитать баланс на момент as_of: %w", err)
+	}
+	at := asOf.UTC()
+	resp.AsOf = &at
+	resp.BalanceAsOf = &balance
+	return resp, nil
 }
 
 // ConvertAndDeduct выполняет конвертацию и списание средств.
+// Сверх суммы по курсу списывается комиссия (см. FeeService), которая зачисляется на кошелек комиссий в той же транзакции.
+// Если указан кошелек-получатель, сконвертированная сумма зачисляется на него в той же транзакции.
+// Получатель должен существовать и принадлежать владельцу источника (или не иметь владельца).
 func (s *walletService) ConvertAndDeduct(ctx context.Context, req models.ConvertRequest) (models.ConvertResponse, error) {
 	// 1. Валидация
-	if !walletNumberRegex.MatchString(req.SourceWalletNumber) {
-		return models.ConvertResponse{Message: ErrInvalidWalletNumber.Error()}, ErrInvalidWalletNumber
+	if err := validateWalletNumber(req.SourceWalletNumber); err != nil {
+		return models.ConvertResponse{Message: err.Error()}, err
 	}
 	if req.AmountToConvert <= 0 {
-		err := errors.New("сумма для конвертации должна быть положительной")
-		return models.ConvertResponse{Message: err.Error()}, err
+		return models.ConvertResponse{Message: ErrInvalidConvertAmount.Error()}, ErrInvalidConvertAmount
+	}
+	if req.UserID != "" && !userIDRegex.MatchString(req.UserID) {
+		return models.ConvertResponse{Message: ErrInvalidUserID.Error()}, ErrInvalidUserID
+	}
+	if req.DestinationWalletNumber != "" {
+		if err := validateWalletNumber(req.DestinationWalletNumber); err != nil {
+			return models.ConvertResponse{Message: err.Error()}, err
+		}
+		if req.DestinationWalletNumber == req.SourceWalletNumber {
+			return models.ConvertResponse{Message: ErrSameWalletConversion.Error()}, ErrSameWalletConversion
+		}
 	}
 
 	var finalResponse models.ConvertResponse
 	finalResponse.SourceWalletNumber = req.SourceWalletNumber // Заполняем сразу
+	finalResponse.DestinationWalletNumber = req.DestinationWalletNumber
 
 	// 2. Получаем самый свежий курс (вне транзакции, т.к. курс может меняться)
 	// В реальном приложении курс может кешироваться или браться на момент начала операции
@@ -214,33 +505,175 @@ func (s *walletService) ConvertAndDeduct(ctx context.Context, req models.Convert
 	finalResponse.ConvertedAmount = amountToDeduct
 
 	// 3. Выполняем проверку и списание в транзакции
-	err = s.executeTx(ctx, func(tx *sql.Tx) error {
-		// Получаем кошелек с блокировкой
-		wallet, err := s.walletRepo.GetWalletByNumberForUpdate(ctx, tx, req.SourceWalletNumber)
+	// Комиссия зачисляется на кошелек комиссий в той же транзакции. С кошелька комиссий она не взимается.
+	// Кошелек комиссий создается при запуске (EnsureFeeWallet) и блокируется вместе с остальными кошельками.
+	feeWallet := s.conversion.FeeWalletNumber
+	if feeWallet == req.SourceWalletNumber {
+		feeWallet = ""
+	}
+	lockFeeWallet := false
+	convert := func(tx *sql.Tx) error {
+		// Комиссию считаем до блокировки по уровню кошелька: кошелек комиссий блокируется,
+		// только если комиссия будет взиматься
+		current, err := s.walletRepo.GetWalletByNumber(ctx, tx, req.SourceWalletNumber)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return ErrWalletNotFound
 			}
 			return fmt.Errorf("ошибка получения кошелька для конвертации: %w", err)
 		}
+		fee, err := s.conversionFee(ctx, tx, feeWallet, current.Tier, amountToDeduct)
+		if err != nil {
+			return err
+		}
+
+		numbers := []string{req.SourceWalletNumber}
+		if req.DestinationWalletNumber != "" {
+			numbers = append(numbers, req.DestinationWalletNumber)
+		}
+		if fee > 0 || lockFeeWallet {
+			numbers = append(numbers, feeWallet)
+		}
+		// Блокируем кошельки в порядке возрастания номеров, как в переводах
+		wallets, err := s.lockWallets(ctx, tx, numbers...)
+		if err != nil {
+			return fmt.Errorf("ошибка получения кошелька для конвертации: %w", err)
+		}
+		wallet, found := wallets[req.SourceWalletNumber]
+		if !found {
+			return ErrWalletNotFound
+		}
+		if wallet.Tier != current.Tier {
+			// Уровень изменился до блокировки - пересчитываем комиссию
+			if fee, err = s.conversionFee(ctx, tx, feeWallet, wallet.Tier, amountToDeduct); err != nil {
+				return err
+			}
+			if _, locked := wallets[feeWallet]; fee > 0 && !locked && !lockFeeWallet {
+				// Блокировать кошелек комиссий после остальных нельзя - нарушится порядок блокировок
+				return errFeeWalletNotLocked
+			}
+		}
+		if _, locked := wallets[feeWallet]; fee > 0 && !locked {
+			return fmt.Errorf("кошелек комиссий %s не найден", feeWallet)
+		}
 
-		// Проверяем баланс
-		if wallet.Balance < amountToDeduct {
+		// Конвертировать может только владелец кошелька
+		if req.RequireOwner && wallet.OwnerID == "" {
+			return ErrWalletOwnerMismatch
+		}
+		if err := s.checkOwner(ctx, tx, wallet, req.UserID, req.FirstName, req.LastName); err != nil {
+			return err
+		}
+		if req.DestinationWalletNumber != "" {
+			destination, found := wallets[req.DestinationWalletNumber]
+			if !found {
+				return ErrTargetWalletMissing
+			}
+			if destination.OwnerID != "" && !strings.EqualFold(destination.OwnerID, wallet.OwnerID) {
+				return ErrDestinationOwner
+			}
+			if err := checkWalletActive(destination); err != nil {
+				return err
+			}
+		}
+		if err := checkWalletActive(wallet); err != nil {
+			finalResponse.RemainingBalance = wallet.Balance
+			return err
+		}
+
+		totalDebit := amountToDeduct + fee
+		finalResponse.Fee = fee
+
+		// Проверяем доступный баланс с учетом кредита (зарезервированные холдами средства списывать нельзя)
+		if availableFunds(wallet) < totalDebit {
 			finalResponse.RemainingBalance = wallet.Balance // Показываем текущий баланс
 			return ErrInsufficientFunds
 		}
 
-		// Списываем средства
+		// Проверяем лимиты кошелька (сумма списания вместе с комиссией и количество конвертаций)
+		if limitErr := enforceDebitLimits(ctx, tx, s.limitRepo, s.movementRepo, wallet, totalDebit, true); limitErr != nil {
+			finalResponse.RemainingBalance = wallet.Balance
+			return limitErr
+		}
+
+		// Списываем средства и записываем движение
+		// Курс и исходная сумма сохраняются в движении, чтобы отмена компенсировала списание по тому же курсу
 		newBalance := wallet.Balance - amountToDeduct
-		if updateErr := s.walletRepo.UpdateWalletBalance(ctx, tx, req.SourceWalletNumber, newBalance); updateErr != nil {
+		movement, updateErr := recordMovement(ctx, tx, s.walletRepo, s.movementRepo, models.Movement{
+			WalletNumber: req.SourceWalletNumber,
+			Kind:         models.MovementKindConversion,
+			Amount:       -amountToDeduct,
+			BalanceAfter: newBalance,
+			Rate:         &latestRate.Value,
+			SourceAmount: &req.AmountToConvert,
+		})
+		if updateErr != nil {
 			return fmt.Errorf("не удалось списать средства для конвертации: %w", updateErr)
 		}
+		finalResponse.MovementID = movement.ID
+
+		// Зачисляем сконвертированную сумму получателю с тем же курсом и исходной суммой
+		if req.DestinationWalletNumber != "" {
+			destination := wallets[req.DestinationWalletNumber]
+			credit, creditErr := recordMovement(ctx, tx, s.walletRepo, s.movementRepo, models.Movement{
+				WalletNumber: destination.Number,
+				Kind:         models.MovementKindConversionIn,
+				Amount:       amountToDeduct,
+				BalanceAfter: destination.Balance + amountToDeduct,
+				Rate:         &latestRate.Value,
+				SourceAmount: &req.AmountToConvert,
+				ConversionID: &movement.ID,
+			})
+			if creditErr != nil {
+				return fmt.Errorf("не удалось зачислить сконвертированную сумму: %w", creditErr)
+			}
+			// Получатель может быть и кошельком комиссий - комиссия зачисляется поверх нового баланса
+			wallets[destination.Number] = withBalanceDelta(destination, amountToDeduct)
+			finalResponse.DestinationBalance = credit.BalanceAfter
+			finalResponse.DestinationMovementID = credit.ID
+		}
+
+		// Комиссия - отдельными движениями: списание с кошелька клиента и доход на кошельке комиссий.
+		// Как и зачисление получателю, они связаны со списанием и отменяются вместе с ним.
+		if fee > 0 {
+			newBalance -= fee
+			if _, err := recordMovement(ctx, tx, s.walletRepo, s.movementRepo, models.Movement{
+				WalletNumber: req.SourceWalletNumber,
+				Kind:         models.MovementKindConversionFee,
+				Amount:       -fee,
+				BalanceAfter: newBalance,
+				ConversionID: &movement.ID,
+			}); err != nil {
+				return fmt.Errorf("не удалось списать комиссию за конвертацию: %w", err)
+			}
+			house := wallets[feeWallet]
+			if _, err := recordMovement(ctx, tx, s.walletRepo, s.movementRepo, models.Movement{
+				WalletNumber: feeWallet,
+				Kind:         models.MovementKindFeeIncome,
+				Amount:       fee,
+				BalanceAfter: house.Balance + fee,
+				ConversionID: &movement.ID,
+			}); err != nil {
+				return fmt.Errorf("не удалось зачислить комиссию: %w", err)
+			}
+			if feeWallet == req.DestinationWalletNumber {
+				finalResponse.DestinationBalance = house.Balance + fee
+			}
+		}
 
 		// Заполняем оставшиеся поля ответа при успехе транзакции
+		finalResponse.TotalDebited = totalDebit
 		finalResponse.RemainingBalance = newBalance
 		finalResponse.Message = "Конвертация и списание прошли успешно"
+		finalResponse.MessageCode = models.MessageConversionCompleted
 		return nil // Успех транзакции
-	})
+	}
+	err = s.executeTx(ctx, convert)
+	if errors.Is(err, errFeeWalletNotLocked) {
+		// Уровень кошелька изменился, и теперь взимается комиссия: повторяем с кошельком комиссий в общем порядке блокировок
+		lockFeeWallet = true
+		err = s.executeTx(ctx, convert)
+	}
 
 	// 4. Обработка результата транзакции
 	if err != nil {
@@ -248,8 +681,13 @@ func (s *walletService) ConvertAndDeduct(ctx context.Context, req models.Convert
 		// Заполняем сообщение об ошибке в ответе
 		if errors.Is(err, ErrWalletNotFound) {
 			finalResponse.Message = ErrWalletNotFound.Error()
+		} else if errors.Is(err, ErrTargetWalletMissing) {
+			finalResponse.Message = ErrTargetWalletMissing.Error()
 		} else if errors.Is(err, ErrInsufficientFunds) {
 			finalResponse.Message = ErrInsufficientFunds.Error()
+		} else if errors.Is(err, ErrWalletOwnerMismatch) || errors.Is(err, ErrLimitExceeded) ||
+			errors.Is(err, ErrWalletFrozen) || errors.Is(err, ErrWalletClosed) {
+			finalResponse.Message = err.Error()
 		} else {
 			finalResponse.Message = "Ошибка при выполнении конвертации"
 		}
@@ -260,3 +698,163 @@ func (s *walletService) ConvertAndDeduct(ctx context.Context, req models.Convert
 	// Возвращаем успешный ответ
 	return finalResponse, nil
 }
+
+// errFeeWalletNotLocked - комиссия понадобилась только после блокировки (уровень кошелька изменился),
+// а кошелек комиссий не заблокирован. ConvertAndDeduct повторяет транзакцию, блокируя его вместе с остальными.
+var errFeeWalletNotLocked = errors.New("кошелек комиссий не заблокирован")
+
+// EnsureFeeWallet создает кошелек комиссий, если его нет. Вызывается при запуске:
+// конвертации кошелек комиссий не создают, а только блокируют.
+func (s *walletService) EnsureFeeWallet(ctx context.Context) error {
+	if s.conversion.FeeWalletNumber == "" {
+		return nil
+	}
+	// Существующий кошелек комиссий может иметь номер без контрольной цифры, новый - нет
+	if _, err := s.walletRepo.GetWalletByNumber(ctx, s.db, s.conversion.FeeWalletNumber); errors.Is(err, sql.ErrNoRows) {
+		if err := validateNewWalletNumber(s.conversion.FeeWalletNumber); err != nil {
+			return fmt.Errorf("некорректный номер кошелька комиссий %s: %w", s.conversion.FeeWalletNumber, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("ошибка получения кошелька комиссий %s: %w", s.conversion.FeeWalletNumber, err)
+	}
+	if err := s.walletRepo.EnsureWallet(ctx, s.db, s.conversion.FeeWalletNumber); err != nil {
+		return fmt.Errorf("не удалось создать кошелек комиссий %s: %w", s.conversion.FeeWalletNumber, err)
+	}
+	return nil
+}
+
+// conversionFee возвращает комиссию за конвертацию суммы amount для кошелька уровня tier.
+// Без кошелька комиссий или подходящего правила комиссия равна нулю.
+func (s *walletService) conversionFee(ctx context.Context, db repository.DBTX, feeWallet, tier string, amount float64) (float64, error) {
+	if feeWallet == "" {
+		return 0, nil
+	}
+	rule, err := s.feeRepo.FindFeeRule(ctx, db, s.conversion.Pair, tier)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("ошибка получения правила комиссии: %w", err)
+	}
+	return calculateConversionFee(rule, amount), nil
+}
+
+// Transfer переводит средства между двумя существующими кошельками в одной транзакции.
+// Кошельки блокируются в порядке возрастания номеров, чтобы встречные переводы не приводили к взаимной блокировке.
+func (s *walletService) Transfer(ctx context.Context, req models.TransferRequest) (models.TransferResponse, error) {
+	// 1. Валидация
+	for _, number := range []string{req.FromWalletNumber, req.ToWalletNumber} {
+		if err := validateWalletNumber(number); err != nil {
+			return models.TransferResponse{Message: err.Error()}, err
+		}
+	}
+	if req.FromWalletNumber == req.ToWalletNumber {
+		return models.TransferResponse{Message: ErrSameWalletTransfer.Error()}, ErrSameWalletTransfer
+	}
+	if req.Amount <= 0 {
+		return models.TransferResponse{Message: ErrInvalidTransfer.Error()}, ErrInvalidTransfer
+	}
+	if req.UserID != "" && !userIDRegex.MatchString(req.UserID) {
+		return models.TransferResponse{Message: ErrInvalidUserID.Error()}, ErrInvalidUserID
+	}
+
+	resp := models.TransferResponse{
+		FromWalletNumber: req.FromWalletNumber,
+		ToWalletNumber:   req.ToWalletNumber,
+		Amount:           req.Amount,
+	}
+
+	// 2. Списание и зачисление в одной транзакции
+	err := s.executeTx(ctx, func(tx *sql.Tx) error {
+		wallets, err := s.lockWallets(ctx, tx, req.FromWalletNumber, req.ToWalletNumber)
+		if err != nil {
+			return err
+		}
+		from, fromFound := wallets[req.FromWalletNumber]
+		to, toFound := wallets[req.ToWalletNumber]
+		if !fromFound {
+			return ErrWalletNotFound
+		}
+		if !toFound {
+			return ErrTargetWalletMissing
+		}
+
+		// Переводить может только владелец кошелька-источника
+		if req.RequireOwner && from.OwnerID == "" {
+			return ErrWalletOwnerMismatch
+		}
+		if err := s.checkOwner(ctx, tx, from, req.UserID, "", ""); err != nil {
+			return err
+		}
+		for _, wallet := range []models.Wallet{from, to} {
+			if err := checkWalletActive(wallet); err != nil {
+				return err
+			}
+		}
+		if availableFunds(from) < req.Amount {
+			resp.FromBalance = from.Balance
+			return ErrInsufficientFunds
+		}
+		if err := enforceDebitLimits(ctx, tx, s.limitRepo, s.movementRepo, from, req.Amount, false); err != nil {
+			resp.FromBalance = from.Balance
+			return err
+		}
+
+		resp.FromBalance = from.Balance - req.Amount
+		resp.ToBalance = to.Balance + req.Amount
+		if err := applyBalanceChange(ctx, tx, s.walletRepo, s.movementRepo, from.Number, -req.Amount, resp.FromBalance, models.MovementKindTransferOut); err != nil {
+			return fmt.Errorf("не удалось списать средства для перевода: %w", err)
+		}
+		if err := applyBalanceChange(ctx, tx, s.walletRepo, s.movementRepo, to.Number, req.Amount, resp.ToBalance, models.MovementKindTransferIn); err != nil {
+			return fmt.Errorf("не удалось зачислить средства перевода: %w", err)
+		}
+		return nil
+	})
+
+	// 3. Обработка результата транзакции
+	if err != nil {
+		log.Printf("Ошибка в Transfer после транзакции: %v", err)
+		switch {
+		case errors.Is(err, ErrWalletNotFound), errors.Is(err, ErrTargetWalletMissing), errors.Is(err, ErrInsufficientFunds),
+			errors.Is(err, ErrWalletOwnerMismatch), errors.Is(err, ErrLimitExceeded), errors.Is(err, ErrWalletFrozen), errors.Is(err, ErrWalletClosed):
+			resp.Message = err.Error()
+		default:
+			resp.Message = "Ошибка при выполнении перевода"
+		}
+		resp.ToBalance = 0
+		return resp, err
+	}
+
+	resp.Message = "Перевод успешно выполнен"
+	resp.MessageCode = models.MessageTransferCompleted
+	return resp, nil
+}
+
+// lockWallets блокирует кошельки (SELECT ... FOR UPDATE) в порядке возрастания номеров
+// и возвращает найденные. Отсутствующие кошельки в результат не попадают.
+func (s *walletService) lockWallets(ctx context.Context, tx *sql.Tx, numbers ...string) (map[string]models.Wallet, error) {
+	return lockWalletsSorted(ctx, tx, s.walletRepo, numbers...)
+}
+
+// lockWalletsSorted - lockWallets для сервисов без walletService (отмена движений).
+// Общий порядок блокировок исключает взаимоблокировки между переводами, конвертациями и их отменами.
+func lockWalletsSorted(ctx context.Context, tx *sql.Tx, walletRepo repository.WalletRepository, numbers ...string) (map[string]models.Wallet, error) {
+	sorted := append([]string(nil), numbers...)
+	sort.Strings(sorted)
+
+	wallets := make(map[string]models.Wallet, len(sorted))
+	for _, number := range sorted {
+		if _, locked := wallets[number]; locked {
+			continue
+		}
+		wallet, err := walletRepo.GetWalletByNumberForUpdate(ctx, tx, number)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, fmt.Errorf("ошибка получения кошелька %s: %w", number, err)
+		}
+		wallets[number] = wallet
+	}
+	return wallets, nil
+}
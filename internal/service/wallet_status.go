@@ -0,0 +1,68 @@
+// --- internal/service/wallet_status.go ---
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"currency-service/internal/models"
+)
+
+// checkWalletActive проверяет, что с кошельком можно проводить операции: замороженный кошелек
+// не пополняется и не списывается до разморозки, закрытый - никогда.
+// Вызывается после блокировки кошелька перед любым изменением его баланса или холдов.
+func checkWalletActive(wallet models.Wallet) error {
+	switch wallet.Status {
+	case models.WalletStatusFrozen:
+		return ErrWalletFrozen
+	case models.WalletStatusClosed:
+		return ErrWalletClosed
+	}
+	return nil
+}
+
+// SetWalletStatus меняет статус кошелька. Замороженный кошелек можно разморозить (active) или закрыть.
+// Закрытие окончательно и возможно только при нулевом балансе и без активных холдов, чтобы деньги
+// не остались на кошельке, с которым больше нельзя проводить операции.
+func (s *walletService) SetWalletStatus(ctx context.Context, number, status string) (models.Wallet, error) {
+	if err := validateWalletNumber(number); err != nil {
+		return models.Wallet{}, err
+	}
+	switch status {
+	case models.WalletStatusActive, models.WalletStatusFrozen, models.WalletStatusClosed:
+	default:
+		return models.Wallet{}, ErrInvalidWalletStatus
+	}
+
+	var updated models.Wallet
+	err := s.executeTx(ctx, func(tx *sql.Tx) error {
+		wallet, err := s.walletRepo.GetWalletByNumberForUpdate(ctx, tx, number)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrWalletNotFound
+			}
+			return fmt.Errorf("ошибка получения кошелька: %w", err)
+		}
+		if wallet.Status == models.WalletStatusClosed {
+			return ErrWalletClosed
+		}
+		if status == models.WalletStatusClosed && (wallet.Balance != 0 || wallet.AvailableBalance != wallet.Balance) {
+			return ErrWalletNotEmpty
+		}
+		if err := s.walletRepo.UpdateWalletStatus(ctx, tx, number, status); err != nil {
+			return fmt.Errorf("не удалось обновить статус кошелька: %w", err)
+		}
+		updated, err = s.walletRepo.GetWalletByNumber(ctx, tx, number)
+		return err
+	})
+	if err != nil {
+		log.Printf("Ошибка в SetWalletStatus после транзакции: %v", err)
+		return models.Wallet{}, err
+	}
+
+	log.Printf("Статус кошелька %s изменен: %s\n", number, status)
+	return updated, nil
+}